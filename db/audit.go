@@ -0,0 +1,77 @@
+package db
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	"time"
+)
+
+// AuditCollection is the name of the collection in which the AuditEntry data is stored in
+const AuditCollection = "Audit"
+
+// AuditEntry records a single mutating operation
+type AuditEntry struct {
+	// the uuid of this AuditEntry
+	UUID string `json:"uuid" example:"3fcf7f67-e0ed-4339-99b4-a6765aaa3dc4"`
+	// the short name of the Teacher who performed the operation
+	Actor string `json:"actor" example:"szakall"`
+	// the kind of operation performed
+	Action string `json:"action" example:"update_application"`
+	// the uuid of the Application or Teacher the operation was performed on
+	TargetID string `json:"target_id" example:"3fcf7f67-e0ed-4339-99b4-a6765aaa3dc4"`
+	// the time the operation was performed at
+	Time time.Time `json:"time"`
+	// the changed fields mapped by their name, only present for updates
+	Changes map[string]AuditChange `json:"changes,omitempty"`
+}
+
+// AuditChange is the value of a single field before and after an update
+type AuditChange struct {
+	// the json encoded value before the update
+	Before string `json:"before" example:"1"`
+	// the json encoded value after the update
+	After string `json:"after" example:"2"`
+}
+
+// CreateAuditEntry appends the given entry to the audit log
+// returns true if the entry was stored, false if an error occurred
+func (m MongoDatabaseConnector) CreateAuditEntry(entry AuditEntry) bool {
+	collection := m.client.Database(m.database).Collection(AuditCollection)
+	_, err := collection.InsertOne(m.context, entry)
+	if err != nil {
+		log.Println(err)
+		return false
+	}
+	return true
+}
+
+// GetAuditEntries returns all audit entries of the actor (of all actors if it is empty) performed in between from
+// (inclusive) and to (exclusive), a zero time leaves that side of the range open; the newest entries come first
+func (m MongoDatabaseConnector) GetAuditEntries(actor string, from, to time.Time) (entries []AuditEntry) {
+	filter := bson.M{}
+	if actor != "" {
+		filter["actor"] = actor
+	}
+	timeRange := bson.M{}
+	if !from.IsZero() {
+		timeRange["$gte"] = from
+	}
+	if !to.IsZero() {
+		timeRange["$lt"] = to
+	}
+	if len(timeRange) > 0 {
+		filter["time"] = timeRange
+	}
+	collection := m.client.Database(m.database).Collection(AuditCollection)
+	cursor, err := collection.Find(m.context, filter, options.Find().SetSort(bson.M{"time": -1}))
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if err = cursor.All(m.context, &entries); err != nil {
+		log.Println(err)
+		return
+	}
+	return
+}
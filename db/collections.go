@@ -101,6 +101,10 @@ type Application struct {
 	DestinationAddress string `json:"destination_address" example:"Karl Hönck Heim, Kärnten"`
 	// The timestamp this application was changed last
 	LastChanged time.Time `json:"last_changed"`
+	// The time this application was deleted at, if it was deleted; deleted applications are excluded from listings but retained
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// The version of this application, which is incremented on every update; updates have to provide the version they are based on
+	Version int `json:"version" example:"1"`
 	// Further Details if this is of the kind SchoolEvent, if not this will be empty
 	SchoolEventDetails SchoolEventDetails `json:"school_event_details"`
 	// Further Details if this is of the kind Training, if not this will be empty
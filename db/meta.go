@@ -0,0 +1,44 @@
+package db
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	"time"
+)
+
+// MetaCollection is the name of the collection in which data describing other collections is stored in
+const MetaCollection = "Meta"
+
+// applicationsMarker is the name of the document in the MetaCollection recording the last change of any application
+const applicationsMarker = "applications"
+
+// marker is a document of the MetaCollection recording when a collection was changed the last time
+type marker struct {
+	// the name of the marker
+	Name string `bson:"name"`
+	// the time the collection was changed the last time
+	Modified time.Time `bson:"modified"`
+}
+
+// touchApplications records a change of the applications at the current time in the applications marker,
+// earlier times don't override later ones, so instances with slightly differing clocks don't move it backwards
+func (m MongoDatabaseConnector) touchApplications() {
+	collection := m.client.Database(m.database).Collection(MetaCollection)
+	_, err := collection.UpdateOne(m.context, bson.M{"name": applicationsMarker},
+		bson.M{"$max": bson.M{"modified": time.Now()}}, options.Update().SetUpsert(true))
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// ApplicationsModified returns the time any application was created, changed, deleted or restored the last time,
+// by any instance sharing the database; the zero time is returned if it is unknown or an error occurred
+func (m MongoDatabaseConnector) ApplicationsModified() time.Time {
+	var result marker
+	collection := m.client.Database(m.database).Collection(MetaCollection)
+	if err := collection.FindOne(m.context, bson.M{"name": applicationsMarker}).Decode(&result); err != nil {
+		return time.Time{}
+	}
+	return result.Modified
+}
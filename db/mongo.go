@@ -73,14 +73,19 @@ func (m MongoDatabaseConnector) Close() (ok bool) {
 }
 
 // CreateApplication creates a new application in the collection in the database
+// a uuid is generated if the application has none
 func (m MongoDatabaseConnector) CreateApplication(application Application) bool {
-	application.UUID = uuid.New().String()
+	if application.UUID == "" {
+		application.UUID = uuid.New().String()
+	}
+	application.Version = 1
 	collection := m.client.Database(m.database).Collection(ApplicationCollection)
 	insert, err := collection.InsertOne(m.context, application)
 	if err != nil {
 		log.Println(err)
 		return false
 	}
+	m.touchApplications()
 	log.Println("Inserted a new application with the UUID: ", application.UUID,
 		"; the Title: ", application.Name, "; under the ID: ", insert.InsertedID)
 	return true
@@ -96,10 +101,30 @@ func (m MongoDatabaseConnector) GetApplication(uuid string) (application Applica
 	return application
 }
 
-// GetAllApplications analyzes all applications contained in the collection and returns them as an array
+// GetApplications returns the applications identified by the given uuids, which weren't deleted,
+// uuids without such an application are left out
+func (m MongoDatabaseConnector) GetApplications(uuids []string) []Application {
+	return m.findApplications(bson.M{"uuid": bson.M{"$in": uuids}, "deletedat": nil})
+}
+
+// notDeleted is the filter matching all applications that weren't deleted
+var notDeleted = bson.M{"deletedat": nil}
+
+// GetAllApplications analyzes all applications contained in the collection, which weren't deleted, and returns them as an array
 func (m MongoDatabaseConnector) GetAllApplications() (applications []Application) {
+	return m.findApplications(notDeleted)
+}
+
+// GetAllApplicationsIncludingDeleted analyzes all applications contained in the collection, including the deleted ones,
+// and returns them as an array
+func (m MongoDatabaseConnector) GetAllApplicationsIncludingDeleted() (applications []Application) {
+	return m.findApplications(bson.M{})
+}
+
+// findApplications returns all applications matching the filter
+func (m MongoDatabaseConnector) findApplications(filter bson.M) (applications []Application) {
 	collection := m.client.Database(m.database).Collection(ApplicationCollection)
-	cursor, err := collection.Find(m.context, bson.M{})
+	cursor, err := collection.Find(m.context, filter)
 	if err != nil {
 		log.Println(err)
 		return
@@ -111,9 +136,10 @@ func (m MongoDatabaseConnector) GetAllApplications() (applications []Application
 	return
 }
 
-// GetActiveApplications returns all currently active applications stored in the database
+// GetActiveApplications returns all currently active applications stored in the database, which weren't deleted
 func (m MongoDatabaseConnector) GetActiveApplications() (applications []Application) {
 	filter := bson.M{
+		"deletedat": nil,
 		"progress": bson.M{
 			"$in": []int{
 				Rejected,
@@ -150,7 +176,38 @@ func (m MongoDatabaseConnector) UpdateApplication(uuid string, update Applicatio
 		log.Println(err)
 		return false
 	}
-	return result.ModifiedCount == 1
+	if result.ModifiedCount != 1 {
+		return false
+	}
+	m.touchApplications()
+	return true
+}
+
+// UpdateApplicationIfVersion updates an application with the matching uuid with the data in the update struct,
+// but only if the stored application still has the given version; the version of the update is set to the next one.
+// Applications stored without a version are treated as version 0.
+// returns whether the application was updated and whether it wasn't because its version didn't match (a conflict)
+func (m MongoDatabaseConnector) UpdateApplicationIfVersion(uuid string, update Application, version int) (updated bool, conflict bool) {
+	update.UUID = uuid
+	update.Version = version + 1
+	filter := bson.M{"uuid": uuid, "version": version}
+	if version == 0 {
+		filter = bson.M{"uuid": uuid, "$or": bson.A{bson.M{"version": 0}, bson.M{"version": bson.M{"$exists": false}}}}
+	}
+	collection := m.client.Database(m.database).Collection(ApplicationCollection)
+	result, err := collection.ReplaceOne(m.context, filter, update)
+	if err != nil {
+		log.Println(err)
+		return false, false
+	}
+	if result.MatchedCount == 0 {
+		return false, true
+	}
+	if result.ModifiedCount != 1 {
+		return false, false
+	}
+	m.touchApplications()
+	return true, false
 }
 
 // DeleteApplication deletes an application described by the given uuid
@@ -162,7 +219,45 @@ func (m MongoDatabaseConnector) DeleteApplication(uuid string) bool {
 		log.Println(err)
 		return false
 	}
-	return result.DeletedCount == 1
+	if result.DeletedCount != 1 {
+		return false
+	}
+	m.touchApplications()
+	return true
+}
+
+// SoftDeleteApplication marks an application described by the given uuid as deleted at the current time
+// returns true if an application that wasn't deleted yet was marked, false if not or if an error occurred
+func (m MongoDatabaseConnector) SoftDeleteApplication(uuid string) bool {
+	collection := m.client.Database(m.database).Collection(ApplicationCollection)
+	result, err := collection.UpdateOne(m.context, bson.M{"uuid": uuid, "deletedat": nil},
+		bson.M{"$set": bson.M{"deletedat": time.Now()}})
+	if err != nil {
+		log.Println(err)
+		return false
+	}
+	if result.ModifiedCount != 1 {
+		return false
+	}
+	m.touchApplications()
+	return true
+}
+
+// RestoreApplication removes the deletion mark of a deleted application described by the given uuid
+// returns true if a deleted application was restored, false if not or if an error occurred
+func (m MongoDatabaseConnector) RestoreApplication(uuid string) bool {
+	collection := m.client.Database(m.database).Collection(ApplicationCollection)
+	result, err := collection.UpdateOne(m.context, bson.M{"uuid": uuid, "deletedat": bson.M{"$ne": nil}},
+		bson.M{"$set": bson.M{"deletedat": nil}})
+	if err != nil {
+		log.Println(err)
+		return false
+	}
+	if result.ModifiedCount != 1 {
+		return false
+	}
+	m.touchApplications()
+	return true
 }
 
 // DoesApplicationExist searches the database for a Application identified by a given UUID
@@ -270,7 +365,11 @@ func (m MongoDatabaseConnector) UpdateTeacher(uuid string, update Teacher) bool
 		log.Println(err)
 		return false
 	}
-	return result.ModifiedCount == 1
+	if result.ModifiedCount != 1 {
+		return false
+	}
+	m.touchApplications()
+	return true
 }
 
 // DeleteTeacher deletes one teacher described by a given short name
@@ -282,7 +381,11 @@ func (m MongoDatabaseConnector) DeleteTeacher(uuid string) (ok bool) {
 		log.Println(err)
 		return false
 	}
-	return result.DeletedCount == 1
+	if result.DeletedCount != 1 {
+		return false
+	}
+	m.touchApplications()
+	return true
 }
 
 // Constructs the URI out of the given information of the docker secrets
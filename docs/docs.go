@@ -32,9 +32,137 @@ var doc = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/auditLog": {
+            "get": {
+                "description": "Returns all recorded mutating operations, newest first, optionally restricted to an actor and a date range, only available for administrators",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns the audit log",
+                "operationId": "get-audit-log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short name of the teacher who performed the operations",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day of the operations (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day of the operations (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/db.AuditEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/bulkUpdateApplicationStatus": {
+            "post": {
+                "description": "Sets the progress of the applications matching the given UUIDs to the status, at most 100 at once\nThe status has to be rejected (0), confirmed (3) or done (7); applications in process may be confirmed or rejected by administration and AV, applications with costs in process may be set to done or rejected by PEK as well\nEvery application is updated on its own, the outcome for each UUID is returned: updated, not_found, unauthorized, invalid_transition, conflict or failed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Sets the status of several applications",
+                "operationId": "bulk-update-application-status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "UUIDs of the Applications and their new status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.BulkStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.BulkStatusResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
         "/createApplication": {
             "post": {
-                "description": "Creates the provided application in the system",
+                "description": "Creates the provided application in the system\nIt has to meet the same rules as checked by the validate application endpoint, otherwise 400 is returned with all violations",
                 "consumes": [
                     "application/json"
                 ],
@@ -58,7 +186,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/db.Application"
+                            "$ref": "#/definitions/rest.CreateApplicationRequest"
                         }
                     }
                 ],
@@ -69,14 +197,14 @@ var doc = `{
                             "$ref": "#/definitions/rest.Information"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/rest.Error"
+                            "$ref": "#/definitions/rest.ValidationError"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
@@ -92,7 +220,7 @@ var doc = `{
         },
         "/deleteApplication": {
             "delete": {
-                "description": "Deletes an application identified by a uuid",
+                "description": "Deletes an application identified by a uuid, it is excluded from all listings afterwards but can be restored",
                 "consumes": [
                     "application/json"
                 ],
@@ -152,6 +280,135 @@ var doc = `{
                 }
             }
         },
+        "/exportApplication": {
+            "get": {
+                "description": "Returns all data of the application matching the given UUID as a versioned document, which can be recreated by the import application endpoint\nUploaded files (e.g. receipts) aren't part of the export",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Exports an application",
+                "operationId": "export-application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The UUID of the application to export",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deprecated alias of id, used if id isn't set",
+                        "name": "uuid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ApplicationExport"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/exportMyApplications": {
+            "get": {
+                "description": "Generates the absence form of every application the logged in teacher participates in and streams them as a zip archive, named by the start date and the kind of the application\nThe archive is streamed while the forms are generated: if generating a form fails after the first one was sent, the archive ends early and is invalid",
+                "produces": [
+                    "application/zip"
+                ],
+                "summary": "Downloads the forms of all applications of the logged in teacher",
+                "operationId": "export-my-applications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter to only export applications with this progress",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter to only export applications ending on or after this date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter to only export applications starting on or before this date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
         "/getAbsenceFormForClasses": {
             "get": {
                 "description": "Generates an absence form for classes and returns it",
@@ -317,6 +574,30 @@ var doc = `{
                         "description": "Filter to only show applications of this teacher",
                         "name": "username",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter to only show applications with this progress",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter to only show applications ending on or after this date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter to only show applications starting on or before this date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated json names of the fields of the applications to return (e.g. uuid,kind,progress,start_time), unknown ones are ignored; whole applications are returned if it is omitted",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -329,6 +610,12 @@ var doc = `{
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -340,6 +627,12 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
                     }
                 }
             }
@@ -392,7 +685,7 @@ var doc = `{
         },
         "/getAllApplications": {
             "get": {
-                "description": "Returns all applications as a list of applications",
+                "description": "Returns all applications as a paginated list of applications",
                 "consumes": [
                     "application/json"
                 ],
@@ -415,20 +708,73 @@ var doc = `{
                         "description": "Filter to only show applications of this teacher",
                         "name": "username",
                         "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/db.Application"
-                            }
-                        }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    {
+                        "type": "integer",
+                        "description": "Filter to only show applications with this progress",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter to only show applications ending on or after this date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter to only show applications starting on or before this date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated json names of the fields of the applications to return (e.g. uuid,kind,progress,start_time), unknown ones are ignored; whole applications are returned if it is omitted",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether deleted applications are listed as well (administrative permissions only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Index of the first application on the page",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Maximum amount of applications on the page (at most 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ApplicationPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
@@ -438,6 +784,12 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
                     }
                 }
             }
@@ -504,6 +856,64 @@ var doc = `{
                 }
             }
         },
+        "/getApplications": {
+            "post": {
+                "description": "Returns the Applications matching the given UUIDs keyed by their UUID, at most 50 at once\nUUIDs without an Application or of Applications the logged in teacher may not see are listed in not_found instead of failing the whole request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns several Applications",
+                "operationId": "get-applications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "UUIDs of the Applications",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.ApplicationsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ApplicationBatch"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
         "/getBusinessTripApplicationExcel": {
             "get": {
                 "description": "Generates a business trip application excel for a teacher and returns it",
@@ -656,17 +1066,17 @@ var doc = `{
                 }
             }
         },
-        "/getCompensationForEducationalSupportForm": {
+        "/getClassTeachers": {
             "get": {
-                "description": "Generates a compensation for educational support form for all teachers and returns it",
+                "description": "Returns the class teacher (Klassenvorstand) and the deputy class teacher of the class, the list is empty if none are assigned in untis",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Generates a compensation for educational support form for all teachers",
-                "operationId": "get-compensation-for-educational-support-form",
+                "summary": "Returns the class teachers of a class",
+                "operationId": "get-class-teachers",
                 "parameters": [
                     {
                         "type": "string",
@@ -678,8 +1088,8 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "Identifier of the application to generate the pdf from",
-                        "name": "uuid",
+                        "description": "Name of the class",
+                        "name": "class",
                         "in": "query",
                         "required": true
                     }
@@ -688,7 +1098,10 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.PDF"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/untis.Teacher"
+                            }
                         }
                     },
                     "401": {
@@ -714,21 +1127,27 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
                     }
                 }
             }
         },
-        "/getNews": {
+        "/getClasses": {
             "get": {
-                "description": "Returns the 10 last changed applications",
+                "description": "Returns all classes known to untis sorted by their name, e.g. to fill selections",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Returns the news",
-                "operationId": "get-news",
+                "summary": "Returns all classes",
+                "operationId": "get-classes",
                 "parameters": [
                     {
                         "type": "string",
@@ -745,7 +1164,7 @@ var doc = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/rest.News"
+                                "$ref": "#/definitions/untis.Class"
                             }
                         }
                     },
@@ -760,21 +1179,27 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
                     }
                 }
             }
         },
-        "/getTeacher": {
+        "/getCompensationForEducationalSupportForm": {
             "get": {
-                "description": "Searches for the Teacher with the specified uuid and returns the data",
+                "description": "Generates a compensation for educational support form for all teachers and returns it",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Returns a teacher with the specified UUID",
-                "operationId": "get-teacher",
+                "summary": "Generates a compensation for educational support form for all teachers",
+                "operationId": "get-compensation-for-educational-support-form",
                 "parameters": [
                     {
                         "type": "string",
@@ -786,7 +1211,7 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "UUID of Teacher",
+                        "description": "Identifier of the application to generate the pdf from",
                         "name": "uuid",
                         "in": "query",
                         "required": true
@@ -796,7 +1221,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/db.Teacher"
+                            "$ref": "#/definitions/rest.PDF"
                         }
                     },
                     "401": {
@@ -826,17 +1251,17 @@ var doc = `{
                 }
             }
         },
-        "/getTeacherByShort": {
+        "/getCurrentLesson": {
             "get": {
-                "description": "Searches for the Teacher with the specified name and returns the data",
+                "description": "Returns the lesson the logged in teacher is teaching at the moment and the next one of today, cancelled lessons are skipped and double periods are returned as one lesson\nEither is null if there is none, e.g. in a break or after the last lesson",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Returns a teacher with the specified short name",
-                "operationId": "get-teacher-by-short",
+                "summary": "Returns the current and the next lesson of the logged in teacher",
+                "operationId": "get-current-lesson",
                 "parameters": [
                     {
                         "type": "string",
@@ -845,20 +1270,13 @@ var doc = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Short Name of Teacher",
-                        "name": "name",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/db.Teacher"
+                            "$ref": "#/definitions/rest.CurrentLesson"
                         }
                     },
                     "401": {
@@ -867,14 +1285,14 @@ var doc = `{
                             "$ref": "#/definitions/rest.Error"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "502": {
+                        "description": "Bad Gateway",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
@@ -882,17 +1300,17 @@ var doc = `{
                 }
             }
         },
-        "/getTeacherByUntis": {
+        "/getExams": {
             "get": {
-                "description": "Searches for the Teacher with the specified untis abbrevation and returns the data",
+                "description": "Returns the exams of the logged in teacher in between from and to (at most 60 days) ordered by their start, the current week is used if they are omitted",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Returns a teacher with the specified untis abbrevation",
-                "operationId": "get-teacher-by-untis",
+                "summary": "Returns the exams",
+                "operationId": "get-exams",
                 "parameters": [
                     {
                         "type": "string",
@@ -904,39 +1322,47 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "Untis abbrevation of Teacher",
-                        "name": "untis",
-                        "in": "query",
-                        "required": true
+                        "description": "Start date of the exams (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date of the exams (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/db.Teacher"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/untis.Exam"
+                            }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "502": {
+                        "description": "Bad Gateway",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
@@ -944,17 +1370,17 @@ var doc = `{
                 }
             }
         },
-        "/getTravelInvoiceExcel": {
+        "/getHolidays": {
             "get": {
-                "description": "Generates a travel invoice excel for a teacher and returns it",
+                "description": "Returns all holidays (days without school) known to untis",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Generates a travel invoice excel for a teacher",
-                "operationId": "get-travel-invoice-excel",
+                "summary": "Returns all holidays",
+                "operationId": "get-holidays",
                 "parameters": [
                     {
                         "type": "string",
@@ -963,34 +1389,16 @@ var doc = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Identifier of the application to generate the excel from",
-                        "name": "uuid",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Short name of the teacher this should be generated for",
-                        "name": "short",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "ID of the Travel Invoice data",
-                        "name": "ti_id",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.Excel"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/untis.Holiday"
+                            }
                         }
                     },
                     "401": {
@@ -999,20 +1407,14 @@ var doc = `{
                             "$ref": "#/definitions/rest.Error"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/rest.Error"
-                        }
-                    },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "502": {
+                        "description": "Bad Gateway",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
@@ -1020,17 +1422,17 @@ var doc = `{
                 }
             }
         },
-        "/getTravelInvoiceForm": {
+        "/getMe": {
             "get": {
-                "description": "Generates a travel invoice form for a teacher and returns it",
+                "description": "Returns the profile of the untis account of the logged in teacher, e.g. the displayed name, the classes and the rights in untis",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Generates a travel invoice for a teacher",
-                "operationId": "get-travel-invoice-form",
+                "summary": "Returns the untis profile of the logged in teacher",
+                "operationId": "get-me",
                 "parameters": [
                     {
                         "type": "string",
@@ -1039,40 +1441,13 @@ var doc = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Identifier of the application to generate the pdf from",
-                        "name": "uuid",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Short name of the teacher this should be generated for",
-                        "name": "short",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "ID of the Travel Invoice data",
-                        "name": "ti_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "If provided the pdf will include all receipt",
-                        "name": "receipts",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.PDF"
+                            "$ref": "#/definitions/untis.UserData"
                         }
                     },
                     "401": {
@@ -1081,20 +1456,14 @@ var doc = `{
                             "$ref": "#/definitions/rest.Error"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
-                        "schema": {
-                            "$ref": "#/definitions/rest.Error"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "502": {
+                        "description": "Bad Gateway",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
@@ -1102,78 +1471,54 @@ var doc = `{
                 }
             }
         },
-        "/login": {
-            "post": {
-                "description": "Login a user using username and password",
+        "/getMyTimetable": {
+            "get": {
+                "description": "Returns all lessons of the logged in teacher in between from and to (at most 60 days), the current week is used if they are omitted",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Login a user",
-                "operationId": "login",
+                "summary": "Returns the timetable of the logged in teacher",
+                "operationId": "get-my-timetable",
                 "parameters": [
                     {
-                        "description": "Account Information",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/rest.User"
-                        }
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date of the timetable (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date of the timetable (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.TokenPair"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/rest.Error"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/untis.Lesson"
+                            }
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
-                    }
-                }
-            }
-        },
-        "/login/refresh": {
-            "post": {
-                "description": "Creates a new token pair when a valid refresh token is provided",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "summary": "Refreshes the token pair of a session",
-                "operationId": "refresh",
-                "parameters": [
-                    {
-                        "description": "Refresh Token",
-                        "name": "token",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/rest.RefreshToken"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/rest.TokenPair"
-                        }
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -1181,14 +1526,14 @@ var doc = `{
                             "$ref": "#/definitions/rest.Error"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "502": {
+                        "description": "Bad Gateway",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
@@ -1196,17 +1541,17 @@ var doc = `{
                 }
             }
         },
-        "/logout": {
-            "post": {
-                "description": "Destroys the session of a user",
+        "/getNews": {
+            "get": {
+                "description": "Returns a page of the applications the logged in teacher participates in, ordered by their last change (newest first by default)\nThe response has a Last-Modified header, if it isn't after the If-Modified-Since header of the request 304 is returned without a body",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Logs out a user",
-                "operationId": "logout",
+                "summary": "Returns the news",
+                "operationId": "get-news",
                 "parameters": [
                     {
                         "type": "string",
@@ -1215,13 +1560,53 @@ var doc = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Index of the first news on the page",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Maximum amount of news on the page (at most 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "date_desc",
+                            "date_asc"
+                        ],
+                        "type": "string",
+                        "default": "date_desc",
+                        "description": "Order of the news by their last change",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified header of a previous response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.Information"
+                            "$ref": "#/definitions/rest.NewsPage"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
                         }
                     },
                     "401": {
@@ -1229,21 +1614,33 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
                     }
                 }
             }
         },
-        "/saveBillingReceipt": {
-            "post": {
-                "description": "Saves a billing receipt in the context of an application",
+        "/getRoomTimetable": {
+            "get": {
+                "description": "Returns all lessons taking place in the room (its short or full name) in between from and to (at most 60 days), the current week is used if they are omitted",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Saves a billing receipt",
-                "operationId": "save-billing-receipt",
+                "summary": "Returns the timetable of a room",
+                "operationId": "get-room-timetable",
                 "parameters": [
                     {
                         "type": "string",
@@ -1255,33 +1652,38 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "Identifier of the application to generate the excel from",
-                        "name": "uuid",
+                        "description": "Name of the room",
+                        "name": "room",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Short name of the teacher this should be generated for",
-                        "name": "short",
-                        "in": "query",
-                        "required": true
+                        "description": "Start date of the timetable (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
                     },
                     {
-                        "description": "The files to save as an array of the base64 decoded file contents",
-                        "name": "files",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/rest.PDFs"
-                        }
+                        "type": "string",
+                        "description": "End date of the timetable (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.Information"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/untis.Lesson"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
                         }
                     },
                     "401": {
@@ -1307,21 +1709,27 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
                     }
                 }
             }
         },
-        "/setTeacherPermissions": {
-            "post": {
-                "description": "Sets the permissions of a Teacher to update their access rights",
+        "/getRooms": {
+            "get": {
+                "description": "Returns all rooms known to untis sorted by their name, e.g. to fill selections or the room of a form",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Sets the permissions of a Teacher",
-                "operationId": "set-teacher-permissions",
+                "summary": "Returns all rooms",
+                "operationId": "get-rooms",
                 "parameters": [
                     {
                         "type": "string",
@@ -1330,29 +1738,16 @@ var doc = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "description": "Permission data of the teacher",
-                        "name": "perm",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/rest.Permissions"
-                        }
-                    },
-                    {
-                        "type": "string",
-                        "description": "UUID of the teacher whos permissions will be changed",
-                        "name": "uuid",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/db.Teacher"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/untis.Room"
+                            }
                         }
                     },
                     "401": {
@@ -1361,14 +1756,14 @@ var doc = `{
                             "$ref": "#/definitions/rest.Error"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "502": {
+                        "description": "Bad Gateway",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
@@ -1376,17 +1771,17 @@ var doc = `{
                 }
             }
         },
-        "/updateApplication": {
-            "put": {
-                "description": "Updates an application identified by a uuid with the data in the body in the system",
+        "/getSubstitutions": {
+            "get": {
+                "description": "Returns all substitutions (cancellations, substitutions, room changes, ...) of the school in between from and to (at most 60 days), the current week is used if they are omitted",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Updates an existing application",
-                "operationId": "update-application",
+                "summary": "Returns the substitution plan",
+                "operationId": "get-substitutions",
                 "parameters": [
                     {
                         "type": "string",
@@ -1397,49 +1792,48 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "description": "The application data to update",
-                        "name": "application",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/db.Application"
-                        }
+                        "type": "string",
+                        "description": "Start date of the substitution plan (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Identifier of the application to update",
-                        "name": "uuid",
-                        "in": "query",
-                        "required": true
+                        "description": "End date of the substitution plan (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.Information"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/untis.Substitution"
+                            }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "502": {
+                        "description": "Bad Gateway",
                         "schema": {
                             "$ref": "#/definitions/rest.Error"
                         }
@@ -1447,17 +1841,17 @@ var doc = `{
                 }
             }
         },
-        "/updateTeacherInformation": {
-            "put": {
-                "description": "Updates a teacher identified by a uuid with the data in the body in the system",
+        "/getTeacher": {
+            "get": {
+                "description": "Searches for the Teacher with the specified uuid and returns the data",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Updates the information of an existing teacher",
-                "operationId": "update-teacher-information",
+                "summary": "Returns a teacher with the specified UUID",
+                "operationId": "get-teacher",
                 "parameters": [
                     {
                         "type": "string",
@@ -1467,18 +1861,9 @@ var doc = `{
                         "in": "header",
                         "required": true
                     },
-                    {
-                        "description": "The teacher information data to set",
-                        "name": "teacher_information",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/rest.TeacherInformation"
-                        }
-                    },
                     {
                         "type": "string",
-                        "description": "Identifier of the teacher to update",
+                        "description": "UUID of Teacher",
                         "name": "uuid",
                         "in": "query",
                         "required": true
@@ -1488,7 +1873,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.Information"
+                            "$ref": "#/definitions/db.Teacher"
                         }
                     },
                     "401": {
@@ -1497,6 +1882,12 @@ var doc = `{
                             "$ref": "#/definitions/rest.Error"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
                     "422": {
                         "description": "Unprocessable Entity",
                         "schema": {
@@ -1511,483 +1902,2884 @@ var doc = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "db.Application": {
-            "type": "object",
-            "properties": {
-                "business_trip_applications": {
+        },
+        "/getTeacherByShort": {
+            "get": {
+                "description": "Searches for the Teacher with the specified name and returns the data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns a teacher with the specified short name",
+                "operationId": "get-teacher-by-short",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short Name of Teacher",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.Teacher"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/getTeacherByUntis": {
+            "get": {
+                "description": "Searches for the Teacher with the specified untis abbrevation and returns the data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns a teacher with the specified untis abbrevation",
+                "operationId": "get-teacher-by-untis",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Untis abbrevation of Teacher",
+                        "name": "untis",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.Teacher"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/getTeachers": {
+            "get": {
+                "description": "Returns all teachers known to untis sorted by their surname, e.g. to fill selections",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns all teachers",
+                "operationId": "get-teachers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/untis.Teacher"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/getTimetableCSV": {
+            "get": {
+                "description": "Returns all lessons of the logged in teacher in between from and to (at most 60 days) as csv file, the current week is used if they are omitted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/csv"
+                ],
+                "summary": "Returns the timetable of the logged in teacher as csv file",
+                "operationId": "get-timetable-csv",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date of the timetable (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date of the timetable (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "csv file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/getTimetableICal": {
+            "get": {
+                "description": "Returns all lessons of the logged in teacher in between start and end as an iCalendar (.ics) feed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/calendar"
+                ],
+                "summary": "Returns the timetable of the logged in teacher as iCalendar feed",
+                "operationId": "get-timetable-ical",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date of the timetable (YYYY-MM-DD)",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date of the timetable (YYYY-MM-DD)",
+                        "name": "end",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/getTimetablesForTeachers": {
+            "post": {
+                "description": "Returns the lessons of every requested teacher in between from and to (at most 60 days), the current week is used if they are omitted. Teachers whose timetable couldn't be fetched contain an error instead of failing the whole request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns the timetables of multiple teachers",
+                "operationId": "get-timetables-for-teachers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Teachers and range of the timetables",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.TimetablesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/rest.TeacherTimetable"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/getTravelInvoice": {
+            "get": {
+                "description": "Returns a travel invoice of a teacher as json, as excel or as pdf file\nThe format is chosen by the format query parameter, or by the Accept header if it is omitted (json if neither is provided), 406 is returned for other formats",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/pdf",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "summary": "Returns a travel invoice of a teacher",
+                "operationId": "get-travel-invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Identifier of the application the travel invoice belongs to",
+                        "name": "uuid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short name of the teacher this should be generated for",
+                        "name": "short",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the Travel Invoice data",
+                        "name": "ti_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "xlsx",
+                            "pdf"
+                        ],
+                        "type": "string",
+                        "description": "Format of the travel invoice",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "If provided the pdf will include all receipts",
+                        "name": "receipts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TravelInvoice"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/getTravelInvoiceExcel": {
+            "get": {
+                "description": "Generates a travel invoice excel for a teacher and returns it (deprecated, use /getTravelInvoice?format=xlsx)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Generates a travel invoice excel for a teacher",
+                "operationId": "get-travel-invoice-excel",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Identifier of the application to generate the excel from",
+                        "name": "uuid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short name of the teacher this should be generated for",
+                        "name": "short",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the Travel Invoice data",
+                        "name": "ti_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Excel"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/getTravelInvoiceForm": {
+            "get": {
+                "description": "Generates a travel invoice form for a teacher and returns it (deprecated, use /getTravelInvoice?format=pdf)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Generates a travel invoice for a teacher",
+                "operationId": "get-travel-invoice-form",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Identifier of the application to generate the pdf from",
+                        "name": "uuid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short name of the teacher this should be generated for",
+                        "name": "short",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the Travel Invoice data",
+                        "name": "ti_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "If provided the pdf will include all receipt",
+                        "name": "receipts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.PDF"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/getTravelInvoicePDF": {
+            "get": {
+                "description": "Generates a travel invoice form for a teacher and returns it as a pdf file, based on the same data as the excel export (deprecated, use /getTravelInvoice?format=pdf)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/pdf"
+                ],
+                "summary": "Generates a travel invoice pdf for a teacher",
+                "operationId": "get-travel-invoice-pdf",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Identifier of the application to generate the pdf from",
+                        "name": "uuid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short name of the teacher this should be generated for",
+                        "name": "short",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the Travel Invoice data",
+                        "name": "ti_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/importApplication": {
+            "post": {
+                "description": "Recreates an application from a document returned by the export application endpoint under a new UUID, which is returned\nThe application has to meet the binding rules of the create application endpoint (e.g. a name and an end after the start), its start may be in the past though\nThe logged in teacher has to participate in the application unless they are an administrator; the application starts with version 1 and isn't deleted, even if the exported one was",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Imports an exported application",
+                "operationId": "import-application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "The exported application",
+                        "name": "export",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.ApplicationExport"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ImportedApplication"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Login a user using username and password\nAfter repeated failed logins of a username or from an ip further logins are refused with 429 for an exponentially growing duration given in the Retry-After header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Login a user",
+                "operationId": "login",
+                "parameters": [
+                    {
+                        "description": "Account Information",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.TokenPair"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/refresh": {
+            "post": {
+                "description": "Creates a new token pair when a valid refresh token is provided, the provided refresh token and its access token are revoked",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Refreshes the token pair of a session",
+                "operationId": "refresh",
+                "parameters": [
+                    {
+                        "description": "Refresh Token",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.RefreshToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/rest.TokenPair"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/logout": {
+            "post": {
+                "description": "Destroys the session of a user, revoking both its access and refresh token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Logs out a user",
+                "operationId": "logout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Information"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/restoreApplication": {
+            "post": {
+                "description": "Restores a deleted application identified by a uuid, so that it is listed again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Restores a deleted application",
+                "operationId": "restore-application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Identifier of the application to restore",
+                        "name": "uuid",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Information"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/saveBillingReceipt": {
+            "post": {
+                "description": "Saves a billing receipt in the context of an application\nThe receipts are stored in a directory per year, named after the application, the teacher, the upload time and the extension of the original file name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Saves a billing receipt",
+                "operationId": "save-billing-receipt",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Identifier of the application to generate the excel from",
+                        "name": "uuid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short name of the teacher this should be generated for",
+                        "name": "short",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "The files to save as an array of the base64 decoded file contents",
+                        "name": "files",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.PDFs"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.SavedReceipts"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/searchTeachers": {
+            "get": {
+                "description": "Returns the teachers known to untis whose forename, surname or short name contains the query case-insensitively, ranked by match quality",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Searches teachers",
+                "operationId": "search-teachers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The text to search for, an empty query matches no teacher",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Maximum amount of teachers returned (at most 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/untis.Teacher"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/setTeacherPermissions": {
+            "post": {
+                "description": "Sets the permissions of a Teacher to update their access rights (deprecated, use /updateTeacherPermissions)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Sets the permissions of a Teacher",
+                "operationId": "set-teacher-permissions",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Permission data of the teacher",
+                        "name": "perm",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.Permissions"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "UUID of the teacher whos permissions will be changed",
+                        "name": "uuid",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.Teacher"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/updateApplication": {
+            "put": {
+                "description": "Updates an application identified by a uuid with the data in the body in the system\nThe body has to contain the version of the application it is based on, if the application was updated in the meantime 409 is returned",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Updates an existing application",
+                "operationId": "update-application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "The application data to update",
+                        "name": "application",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/db.Application"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Identifier of the application to update",
+                        "name": "uuid",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Information"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Updates only the fields of an application identified by a uuid contained in the body (a JSON merge patch, RFC 7396): omitted fields are kept and fields set to null are cleared\nIf the body contains a version, the application is only updated if it still has this version, otherwise 409 is returned",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Partially updates an existing application",
+                "operationId": "patch-application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "The fields of the application to change",
+                        "name": "application",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Identifier of the application to update",
+                        "name": "uuid",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.Application"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/updateTeacherInformation": {
+            "put": {
+                "description": "Updates a teacher identified by a uuid with the data in the body in the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Updates the information of an existing teacher",
+                "operationId": "update-teacher-information",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "The teacher information data to set",
+                        "name": "teacher_information",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.TeacherInformation"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Identifier of the teacher to update",
+                        "name": "uuid",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Information"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/updateTeacherPermissions": {
+            "post": {
+                "description": "Sets the permissions of a Teacher to exactly the listed ones (super_user, administration, av, pek)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Sets the permissions of a Teacher",
+                "operationId": "update-teacher-permissions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "The teacher and their new permissions",
+                        "name": "perm",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.PermissionsUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Information"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/validateApplication": {
+            "post": {
+                "description": "Checks the provided application against the same rules as the create application endpoint (e.g. it has to start in the future, end after it starts and contain the details of its kind) and returns all violations without storing it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Checks a new application without creating it",
+                "operationId": "validate-application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "The Application Data",
+                        "name": "application",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.CreateApplicationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ApplicationValidation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/ws/applications": {
+            "get": {
+                "description": "Upgrades the connection to a websocket and sends an event whenever an application the logged in teacher may see is created, updated, changes its status, is deleted or restored",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Streams changes of applications",
+                "operationId": "applications-websocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Access Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ApplicationEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.Error"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "db.Application": {
+            "type": "object",
+            "properties": {
+                "business_trip_applications": {
                     "description": "The regarding BusinessTripApplication for each teacher",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/db.BusinessTripApplication"
+                        "$ref": "#/definitions/db.BusinessTripApplication"
+                    }
+                },
+                "deleted_at": {
+                    "description": "The time this application was deleted at, if it was deleted; deleted applications are excluded from listings but retained",
+                    "type": "string"
+                },
+                "destination_address": {
+                    "description": "The Destination Address of this Application",
+                    "type": "string",
+                    "example": "Karl Hönck Heim, Kärnten"
+                },
+                "end_time": {
+                    "description": "the time the underlying event of this Application ends",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "The kind of this Application (for more see the Enum for the kinds of Application on this level only Training, SchoolEvent and OtherReason is applicable, the sub kinds should be used in the further detail section of the corresponding site)",
+                    "type": "integer",
+                    "example": 0
+                },
+                "last_changed": {
+                    "description": "The timestamp this application was changed last",
+                    "type": "string"
+                },
+                "miscellaneous_reason": {
+                    "description": "The Reasoning of this Application (there is none if this isn't of the type Miscellaneous)",
+                    "type": "string",
+                    "example": "Guter Grund"
+                },
+                "name": {
+                    "description": "The name on how this Application should be referenced by",
+                    "type": "string",
+                    "example": "Sommersportwoche"
+                },
+                "notes": {
+                    "description": "Other Notes regarding this Application",
+                    "type": "string",
+                    "example": "Wichtig ist, dass wir die Reise bewilligen lassen!"
+                },
+                "other_reason_details": {
+                    "description": "Further Details if this is of the kind of any other, if not this will be empty",
+                    "$ref": "#/definitions/db.OtherReasonDetails"
+                },
+                "progress": {
+                    "description": "The Progress of this Application in filing (for more see the Enum for the Progress)",
+                    "type": "integer",
+                    "example": 3
+                },
+                "school_event_details": {
+                    "description": "Further Details if this is of the kind SchoolEvent, if not this will be empty",
+                    "$ref": "#/definitions/db.SchoolEventDetails"
+                },
+                "start_address": {
+                    "description": "The starting address of this Application",
+                    "type": "string",
+                    "example": "TGM, Wexstraße 19-23, 1220 Wien"
+                },
+                "start_time": {
+                    "description": "the time the underlying event of this Application starts",
+                    "type": "string"
+                },
+                "training_details": {
+                    "description": "Further Details if this is of the kind Training, if not this will be empty",
+                    "$ref": "#/definitions/db.TrainingDetails"
+                },
+                "travel_invoices": {
+                    "description": "The regarding TravelInvoice for each teacher",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.TravelInvoice"
+                    }
+                },
+                "uuid": {
+                    "description": "A generated uuid of this application",
+                    "type": "string",
+                    "example": "693aa616-9895-418b-8904-765f0f6d26a4"
+                },
+                "version": {
+                    "description": "The version of this application, which is incremented on every update; updates have to provide the version they are based on",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "db.AuditChange": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "description": "the json encoded value after the update",
+                    "type": "string",
+                    "example": "2"
+                },
+                "before": {
+                    "description": "the json encoded value before the update",
+                    "type": "string",
+                    "example": "1"
+                }
+            }
+        },
+        "db.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "the kind of operation performed",
+                    "type": "string",
+                    "example": "update_application"
+                },
+                "actor": {
+                    "description": "the short name of the Teacher who performed the operation",
+                    "type": "string",
+                    "example": "szakall"
+                },
+                "changes": {
+                    "description": "the changed fields mapped by their name, only present for updates",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/db.AuditChange"
+                    }
+                },
+                "target_id": {
+                    "description": "the uuid of the Application or Teacher the operation was performed on",
+                    "type": "string",
+                    "example": "3fcf7f67-e0ed-4339-99b4-a6765aaa3dc4"
+                },
+                "time": {
+                    "description": "the time the operation was performed at",
+                    "type": "string"
+                },
+                "uuid": {
+                    "description": "the uuid of this AuditEntry",
+                    "type": "string",
+                    "example": "3fcf7f67-e0ed-4339-99b4-a6765aaa3dc4"
+                }
+            }
+        },
+        "db.BusinessTripApplication": {
+            "type": "object",
+            "properties": {
+                "bonus_mile_confirmation_1": {
+                    "description": "the confirmation of the first bonus mile clause",
+                    "type": "boolean",
+                    "example": true
+                },
+                "bonus_mile_confirmation_2": {
+                    "description": "the confirmation of the second bonus mile clause",
+                    "type": "boolean",
+                    "example": true
+                },
+                "business_card_emitted_outward": {
+                    "description": "whether a business card was emitted outwards",
+                    "type": "boolean",
+                    "example": false
+                },
+                "business_card_emitted_return": {
+                    "description": "whether a business card was emitted on the return",
+                    "type": "boolean",
+                    "example": false
+                },
+                "date_application_approved": {
+                    "description": "the date this application is approved",
+                    "type": "string"
+                },
+                "date_application_filed": {
+                    "description": "the date this application is filed",
+                    "type": "string"
+                },
+                "degree": {
+                    "description": "Degree of the Teacher",
+                    "type": "string",
+                    "example": "DI"
+                },
+                "end_point": {
+                    "description": "The end point (see the regarding Enum: OwnApartment or Office)",
+                    "type": "integer",
+                    "example": 1
+                },
+                "estimated_costs": {
+                    "description": "the total estimated costs",
+                    "type": "number",
+                    "example": 25.32
+                },
+                "id": {
+                    "description": "The id (counting upwards) of this BusinessTripApplication regarding to the uid",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "Name of the Teacher",
+                    "type": "string",
+                    "example": "Stefan"
+                },
+                "other_costs": {
+                    "description": "other costs which appeared",
+                    "type": "number",
+                    "example": 2.42
+                },
+                "other_participants": {
+                    "description": "The name of other participants of this trip",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Markus Schabel",
+                        "Gottfried Koppensteiner"
+                    ]
+                },
+                "paid_by_whom": {
+                    "description": "if some costs are paid by someone else by whom",
+                    "type": "string",
+                    "example": "Technologenverband"
+                },
+                "reasoning": {
+                    "description": "The reasoing behind the trip application",
+                    "type": "string",
+                    "example": "Lehrausgang ins technische Museum"
+                },
+                "referee": {
+                    "description": "the referee checking this application",
+                    "type": "string"
+                },
+                "service_begin_time": {
+                    "description": "The time the service begins",
+                    "type": "string"
+                },
+                "service_end_time": {
+                    "description": "The time the service ends",
+                    "type": "string"
+                },
+                "staffnr": {
+                    "description": "The staffnr of the regarding teacher",
+                    "type": "integer",
+                    "example": 938503154
+                },
+                "starting_point": {
+                    "description": "The starting point (see the regarding Enum: OwnApartment or Office",
+                    "type": "integer",
+                    "example": 1
+                },
+                "staying_costs_paid_by_someone": {
+                    "description": "whether the staying costs are paid by someone else",
+                    "type": "boolean",
+                    "example": true
+                },
+                "surname": {
+                    "description": "Surname of the Teacher",
+                    "type": "string",
+                    "example": "Zakall"
+                },
+                "title": {
+                    "description": "Title of the Teacher",
+                    "type": "string",
+                    "example": "Prof"
+                },
+                "travel_costs_paid_by_someone": {
+                    "description": "whether the travel costs are paid by someone else",
+                    "type": "boolean",
+                    "example": true
+                },
+                "travel_mode": {
+                    "description": "The travel mode (see the regarding Enum for this)",
+                    "type": "integer",
+                    "example": 6
+                },
+                "travel_purpose": {
+                    "description": "The purpose of travelling",
+                    "type": "string",
+                    "example": "Lehrausgang"
+                },
+                "trip_begin_time": {
+                    "description": "The time the trip begins",
+                    "type": "string"
+                },
+                "trip_end_time": {
+                    "description": "The time the trip ends",
+                    "type": "string"
+                },
+                "trip_goal": {
+                    "description": "The trip goal (address)",
+                    "type": "string",
+                    "example": "Technisches Museum Wien"
+                }
+            }
+        },
+        "db.Calculation": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "the id of this calculation",
+                    "type": "integer",
+                    "example": 1
+                },
+                "rows": {
+                    "description": "rows of this calculation",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.Row"
+                    }
+                },
+                "sum_additional_costs": {
+                    "description": "the sum of all additional costs",
+                    "type": "number"
+                },
+                "sum_daily_charges": {
+                    "description": "the sum of all daily charges",
+                    "type": "number"
+                },
+                "sum_nightly_charges": {
+                    "description": "the sum of all nightly charges",
+                    "type": "number"
+                },
+                "sum_of_sums": {
+                    "description": "the sum of all sums",
+                    "type": "number"
+                },
+                "sum_travel_costs": {
+                    "description": "the sum of all travel costs",
+                    "type": "number"
+                }
+            }
+        },
+        "db.OtherReasonDetails": {
+            "type": "object",
+            "properties": {
+                "filer": {
+                    "description": "the teacher this application belongs to",
+                    "type": "string",
+                    "example": "Stefan Zakall"
+                },
+                "kind": {
+                    "description": "The kind of other Reason this Application is filed",
+                    "type": "integer",
+                    "example": 7
+                },
+                "miscellaneous_reason": {
+                    "description": "the reasoning if the other reason is of kind Miscellaneous",
+                    "type": "string",
+                    "example": "Ein guter Grund"
+                },
+                "service_mandate_gz": {
+                    "description": "the gz number if the other reason is a ServiceMandate",
+                    "type": "integer"
+                },
+                "service_mandate_title": {
+                    "description": "The title if the other reason is a ServiceMandate",
+                    "type": "string",
+                    "example": "Dienstverrichtung"
+                }
+            }
+        },
+        "db.Row": {
+            "type": "object",
+            "properties": {
+                "additional_costs": {
+                    "description": "the additionalCosts this Row conducts",
+                    "type": "number",
+                    "example": 4.32
+                },
+                "begin": {
+                    "description": "the begin time this Row refers to",
+                    "type": "string"
+                },
+                "daily_charges": {
+                    "description": "the dailyCharges this Row conducts",
+                    "type": "number",
+                    "example": 4.32
+                },
+                "date": {
+                    "description": "The date this Row refers to",
+                    "type": "string"
+                },
+                "end": {
+                    "description": "the end time this Row refers to",
+                    "type": "string"
+                },
+                "kilometres": {
+                    "description": "the amount of kilometres this row refers to",
+                    "type": "number",
+                    "example": 4.32
+                },
+                "kind_of_cost": {
+                    "description": "the kind of costs this row describes (see costs enum)",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                },
+                "nightly_charges": {
+                    "description": "the nightlyCharges this Row conducts",
+                    "type": "number",
+                    "example": 4.32
+                },
+                "nr": {
+                    "description": "The row nr",
+                    "type": "integer",
+                    "example": 1
+                },
+                "sum": {
+                    "description": "the sum of all costs in this Row",
+                    "type": "number",
+                    "example": 4.32
+                },
+                "travel_costs": {
+                    "description": "the travelCosts this Row conducts",
+                    "type": "number",
+                    "example": 4.32
+                }
+            }
+        },
+        "db.SchoolEventDetails": {
+            "type": "object",
+            "properties": {
+                "amount_female_students": {
+                    "description": "The amount of female students per class (same order as Classes)",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        2,
+                        0,
+                        0,
+                        5
+                    ]
+                },
+                "amount_male_students": {
+                    "description": "The amount of male students per class (same order as Classes)",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        17,
+                        20,
+                        19,
+                        18
+                    ]
+                },
+                "classes": {
+                    "description": "The participating classes",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "5BHIT",
+                        "5AHIT",
+                        "5CHIT",
+                        "5DHIT"
+                    ]
+                },
+                "duration_in_days": {
+                    "description": "The duration of the event in days",
+                    "type": "integer",
+                    "example": 2
+                },
+                "teachers": {
+                    "description": "Details of each teacher participating in the SchoolEvent",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.SchoolEventTeacherDetails"
                     }
+                }
+            }
+        },
+        "db.SchoolEventTeacherDetails": {
+            "type": "object",
+            "properties": {
+                "attendance_from": {
+                    "description": "The teacher will be attending the SchoolEvent from",
+                    "type": "string"
+                },
+                "attendance_till": {
+                    "description": "The teacher will be attend the SchoolEvent till",
+                    "type": "string"
+                },
+                "group": {
+                    "description": "The group number",
+                    "type": "integer",
+                    "example": 1
+                },
+                "meeting_point": {
+                    "description": "Where the teacher will meet with the group to travel together",
+                    "type": "string",
+                    "example": "TGM, Wexstraße 19-23, 1220 Wien"
+                },
+                "name": {
+                    "description": "The full name of a teacher",
+                    "type": "string",
+                    "example": "Stefan Zakall"
+                },
+                "role": {
+                    "description": "The role of each teacher (Leader or Companion)",
+                    "type": "integer",
+                    "example": 0
+                },
+                "shortname": {
+                    "description": "The short name (abbrevation) of a teacher",
+                    "type": "string",
+                    "example": "szakall"
+                },
+                "start_address": {
+                    "description": "Where the teacher starts their travel from",
+                    "type": "string",
+                    "example": "TGM, Wexstraße 19-23, 1220 Wien"
+                }
+            }
+        },
+        "db.Teacher": {
+            "type": "object",
+            "properties": {
+                "administration": {
+                    "description": "whether this Teacher as administration rights",
+                    "type": "boolean",
+                    "example": true
+                },
+                "av": {
+                    "description": "whether this Teacher as av rights",
+                    "type": "boolean",
+                    "example": true
+                },
+                "degree": {
+                    "description": "Degree of the Teacher",
+                    "type": "string",
+                    "example": "DI"
+                },
+                "departments": {
+                    "description": "The Departments this teacher belongs to",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "HIT",
+                        "HBG"
+                    ]
+                },
+                "group": {
+                    "description": "The Group number",
+                    "type": "integer",
+                    "example": 1
+                },
+                "longname": {
+                    "description": "the longname (firstname + sirname) of the Teacher",
+                    "type": "string",
+                    "example": "Stefan Zakall"
+                },
+                "pek": {
+                    "description": "whether this Teacher as pek rights",
+                    "type": "boolean",
+                    "example": true
+                },
+                "short": {
+                    "description": "the short name of the Teacher",
+                    "type": "string",
+                    "example": "szakall"
+                },
+                "staffnr": {
+                    "description": "The Staffnr of the regarding teacher",
+                    "type": "integer",
+                    "example": 938503154
+                },
+                "starting_addresses": {
+                    "description": "The StartingAddresses of the teacher",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Zuhause 1",
+                        "Zuhause 2"
+                    ]
+                },
+                "super_user": {
+                    "description": "Superuser (total admin) of this software",
+                    "type": "boolean",
+                    "example": true
+                },
+                "title": {
+                    "description": "Title of the Teacher",
+                    "type": "string",
+                    "example": "Prof"
+                },
+                "trip_goals": {
+                    "description": "The TripGoals the teacher visited before",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Karl Hönck Heim",
+                        "PH Wien",
+                        "Landesgericht St. Pölten"
+                    ]
+                },
+                "untis": {
+                    "description": "The Untis abbrevation of the teacher",
+                    "type": "string",
+                    "example": "ZAKS"
+                },
+                "uuid": {
+                    "description": "the uuid of this Teacher",
+                    "type": "string",
+                    "example": "3fcf7f67-e0ed-4339-99b4-a6765aaa3dc4"
+                }
+            }
+        },
+        "db.TrainingDetails": {
+            "type": "object",
+            "properties": {
+                "filer": {
+                    "description": "the teacher this application belongs to",
+                    "type": "string",
+                    "example": "Stefan Zakall"
+                },
+                "kind": {
+                    "description": "The kind of Training",
+                    "type": "integer",
+                    "example": 2
+                },
+                "miscellaneous_reason": {
+                    "description": "if its miscellaneous a reasoning for the Training",
+                    "type": "string",
+                    "example": "Ein sonstiger Grund"
+                },
+                "organizer": {
+                    "description": "The company who organizes the Training",
+                    "type": "string",
+                    "example": "Accenture"
+                },
+                "ph": {
+                    "description": "the ph number of the teacher",
+                    "type": "integer",
+                    "example": 938503154
+                }
+            }
+        },
+        "db.TravelInvoice": {
+            "type": "object",
+            "properties": {
+                "approval_date": {
+                    "description": "the date this application was approved",
+                    "type": "string"
+                },
+                "breakfasts": {
+                    "description": "the amount of breakfasts",
+                    "type": "integer",
+                    "example": 2
+                },
+                "calculation": {
+                    "description": "the regarding calculation",
+                    "$ref": "#/definitions/db.Calculation"
+                },
+                "clerk": {
+                    "description": "the clerk maintaining and checking this application",
+                    "type": "string"
+                },
+                "daily_charges_mode": {
+                    "description": "the mode how daily charges are handled",
+                    "type": "integer",
+                    "example": 1
                 },
-                "destination_address": {
-                    "description": "The Destination Address of this Application",
+                "degree": {
+                    "description": "Degree of the Teacher",
                     "type": "string",
-                    "example": "Karl Hönck Heim, Kärnten"
+                    "example": "DI"
                 },
-                "end_time": {
-                    "description": "the time the underlying event of this Application ends",
+                "dinners": {
+                    "description": "the amount of dinners",
+                    "type": "integer",
+                    "example": 4
+                },
+                "end_point": {
+                    "description": "the end point of the trip",
+                    "type": "string",
+                    "example": "Hauptbahnhof, 1220 Wien"
+                },
+                "filing_date": {
+                    "description": "the date this application was filed",
                     "type": "string"
                 },
-                "kind": {
-                    "description": "The kind of this Application (for more see the Enum for the kinds of Application on this level only Training, SchoolEvent and OtherReason is applicable, the sub kinds should be used in the further detail section of the corresponding site)",
+                "id": {
+                    "description": "The id (counting upwards) of this TravelInvoice regarding to the uid",
                     "type": "integer",
-                    "example": 0
+                    "example": 1
                 },
-                "last_changed": {
-                    "description": "The timestamp this application was changed last",
-                    "type": "string"
+                "kilometre_allowance": {
+                    "description": "whether the teacher got a kilometre allowance",
+                    "type": "boolean",
+                    "example": true
                 },
-                "miscellaneous_reason": {
-                    "description": "The Reasoning of this Application (there is none if this isn't of the type Miscellaneous)",
-                    "type": "string",
-                    "example": "Guter Grund"
+                "kilometre_amount": {
+                    "description": "the regarding kilometre amount",
+                    "type": "number",
+                    "example": 25.12
+                },
+                "lunches": {
+                    "description": "the amount of lunches",
+                    "type": "integer",
+                    "example": 3
                 },
                 "name": {
-                    "description": "The name on how this Application should be referenced by",
+                    "description": "Name of the Teacher",
                     "type": "string",
-                    "example": "Sommersportwoche"
+                    "example": "Stefan"
                 },
-                "notes": {
-                    "description": "Other Notes regarding this Application",
-                    "type": "string",
-                    "example": "Wichtig ist, dass wir die Reise bewilligen lassen!"
+                "nightly_charges_mode": {
+                    "description": "the mode how nightly charges are handled",
+                    "type": "integer",
+                    "example": 1
                 },
-                "other_reason_details": {
-                    "description": "Further Details if this is of the kind of any other, if not this will be empty",
-                    "$ref": "#/definitions/db.OtherReasonDetails"
+                "no_travel_costs": {
+                    "description": "whether there aren't any travel costs",
+                    "type": "boolean",
+                    "example": true
                 },
-                "progress": {
-                    "description": "The Progress of this Application in filing (for more see the Enum for the Progress)",
-                    "type": "integer",
-                    "example": 3
+                "nr_and_indications_of_participants": {
+                    "description": "whether the participants of the trip are counted and clearly indicated",
+                    "type": "boolean",
+                    "example": true
                 },
-                "school_event_details": {
-                    "description": "Further Details if this is of the kind SchoolEvent, if not this will be empty",
-                    "$ref": "#/definitions/db.SchoolEventDetails"
+                "official_business_card_got": {
+                    "description": "whether the teacher got a official business card",
+                    "type": "boolean",
+                    "example": true
                 },
-                "start_address": {
-                    "description": "The starting address of this Application",
-                    "type": "string",
-                    "example": "TGM, Wexstraße 19-23, 1220 Wien"
+                "replacement_for_advantage_card": {
+                    "description": "whether the teacher got a replacement for an advantage card",
+                    "type": "boolean",
+                    "example": false
                 },
-                "start_time": {
-                    "description": "the time the underlying event of this Application starts",
+                "replacement_for_train_card_class_2": {
+                    "description": "whether the teacher got a replacement for a train card class 2",
+                    "type": "boolean",
+                    "example": false
+                },
+                "reviewer": {
+                    "description": "the reviewer reviewing the approval of this application",
                     "type": "string"
                 },
-                "training_details": {
-                    "description": "Further Details if this is of the kind Training, if not this will be empty",
-                    "$ref": "#/definitions/db.TrainingDetails"
+                "shortened_amount": {
+                    "description": "the amount the daily charges should be shortened",
+                    "type": "number",
+                    "example": 0
                 },
-                "travel_invoices": {
-                    "description": "The regarding TravelInvoice for each teacher",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/db.TravelInvoice"
-                    }
+                "staffnr": {
+                    "description": "The personnel number of the teacher",
+                    "type": "integer",
+                    "example": 938503154
                 },
-                "uuid": {
-                    "description": "A generated uuid of this application",
+                "starting_point": {
+                    "description": "the starting point of the trip",
                     "type": "string",
-                    "example": "693aa616-9895-418b-8904-765f0f6d26a4"
-                }
-            }
-        },
-        "db.BusinessTripApplication": {
-            "type": "object",
-            "properties": {
-                "bonus_mile_confirmation_1": {
-                    "description": "the confirmation of the first bonus mile clause",
-                    "type": "boolean",
-                    "example": true
+                    "example": "TGM, Wexstraße 19-23, 1220 Wien"
                 },
-                "bonus_mile_confirmation_2": {
-                    "description": "the confirmation of the second bonus mile clause",
-                    "type": "boolean",
-                    "example": true
+                "surname": {
+                    "description": "Surname of the Teacher",
+                    "type": "string",
+                    "example": "Zakall"
                 },
-                "business_card_emitted_outward": {
-                    "description": "whether a business card was emitted outwards",
+                "title": {
+                    "description": "Title of the Teacher",
+                    "type": "string"
+                },
+                "travel_costs_cited": {
+                    "description": "whether the travel costs are clearly cited",
                     "type": "boolean",
                     "example": false
                 },
-                "business_card_emitted_return": {
-                    "description": "whether a business card was emitted on the return",
+                "travel_costs_pre_grant": {
+                    "description": "The granted travel costs",
+                    "type": "number",
+                    "example": 0
+                },
+                "travel_grant": {
+                    "description": "whether the teacher got a travel grant",
                     "type": "boolean",
                     "example": false
                 },
-                "date_application_approved": {
-                    "description": "the date this application is approved",
+                "trip_begin_time": {
+                    "description": "The time the trip begins",
                     "type": "string"
                 },
-                "date_application_filed": {
-                    "description": "the date this application is filed",
+                "trip_end_time": {
+                    "description": "The time the trip ends",
                     "type": "string"
                 },
-                "degree": {
-                    "description": "Degree of the Teacher",
+                "zi": {
+                    "description": "the zi number",
+                    "type": "integer"
+                }
+            }
+        },
+        "rest.ApplicationBatch": {
+            "type": "object",
+            "properties": {
+                "applications": {
+                    "description": "Applications are the found applications keyed by their uuid",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/db.Application"
+                    }
+                },
+                "not_found": {
+                    "description": "NotFound are the requested uuids without an application the logged in teacher may see",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "rest.ApplicationEvent": {
+            "type": "object",
+            "properties": {
+                "application": {
+                    "description": "Application is the application after the change",
+                    "$ref": "#/definitions/db.Application"
+                },
+                "type": {
+                    "description": "Type is the kind of change (created, updated, status_changed, deleted or restored)",
                     "type": "string",
-                    "example": "DI"
+                    "example": "status_changed"
+                }
+            }
+        },
+        "rest.ApplicationExport": {
+            "type": "object",
+            "required": [
+                "schema_version"
+            ],
+            "properties": {
+                "application": {
+                    "description": "Application is the exported application",
+                    "$ref": "#/definitions/db.Application"
                 },
-                "end_point": {
-                    "description": "The end point (see the regarding Enum: OwnApartment or Office)",
+                "exported_at": {
+                    "description": "ExportedAt is the time the application was exported at",
+                    "type": "string"
+                },
+                "schema_version": {
+                    "description": "SchemaVersion is the version of the layout of this document, see ApplicationExportVersion",
                     "type": "integer",
                     "example": 1
+                }
+            }
+        },
+        "rest.ApplicationPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "description": "Items are the applications on this page",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.Application"
+                    }
                 },
-                "estimated_costs": {
-                    "description": "the total estimated costs",
-                    "type": "number",
-                    "example": 25.32
+                "next_offset": {
+                    "description": "NextOffset is the offset of the next page or -1 if this is the last page",
+                    "type": "integer",
+                    "example": 20
                 },
-                "id": {
-                    "description": "The id (counting upwards) of this BusinessTripApplication regarding to the uid",
+                "total": {
+                    "description": "Total is the amount of applications on all pages",
                     "type": "integer",
-                    "example": 1
+                    "example": 42
+                }
+            }
+        },
+        "rest.ApplicationValidation": {
+            "type": "object",
+            "properties": {
+                "valid": {
+                    "description": "Valid whether the application meets all rules and would be accepted by the create application endpoint",
+                    "type": "boolean",
+                    "example": false
                 },
-                "name": {
-                    "description": "Name of the Teacher",
-                    "type": "string",
-                    "example": "Stefan"
+                "violations": {
+                    "description": "Violations are the fields violating a rule",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.FieldError"
+                    }
+                }
+            }
+        },
+        "rest.ApplicationsRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "description": "IDs are the uuids of the requested applications",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "7e1c3f4a-9b2d-4c8e-a1f0-3d5b6e7f8a9b"
+                    ]
+                }
+            }
+        },
+        "rest.BulkStatusRequest": {
+            "type": "object",
+            "required": [
+                "ids",
+                "status"
+            ],
+            "properties": {
+                "ids": {
+                    "description": "IDs are the uuids of the applications to update",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "7e1c3f4a-9b2d-4c8e-a1f0-3d5b6e7f8a9b"
+                    ]
                 },
-                "other_costs": {
-                    "description": "other costs which appeared",
-                    "type": "number",
-                    "example": 2.42
+                "status": {
+                    "description": "Status is the progress the applications are set to: rejected (0), confirmed (3) or done (7)",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "rest.BulkStatusResult": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "description": "Results are the outcomes keyed by the uuids of the applications: updated, not_found, unauthorized,\ninvalid_transition, conflict or failed",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
-                "other_participants": {
-                    "description": "The name of other participants of this trip",
+                "updated": {
+                    "description": "Updated is the amount of updated applications",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "rest.CreateApplicationRequest": {
+            "type": "object",
+            "required": [
+                "end_time",
+                "name",
+                "start_time"
+            ],
+            "properties": {
+                "business_trip_applications": {
+                    "description": "The regarding BusinessTripApplication for each teacher",
                     "type": "array",
                     "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "Markus Schabel",
-                        "Gottfried Koppensteiner"
-                    ]
-                },
-                "paid_by_whom": {
-                    "description": "if some costs are paid by someone else by whom",
-                    "type": "string",
-                    "example": "Technologenverband"
+                        "$ref": "#/definitions/db.BusinessTripApplication"
+                    }
                 },
-                "reasoning": {
-                    "description": "The reasoing behind the trip application",
+                "destination_address": {
+                    "description": "The Destination Address of this Application",
                     "type": "string",
-                    "example": "Lehrausgang ins technische Museum"
-                },
-                "referee": {
-                    "description": "the referee checking this application",
-                    "type": "string"
-                },
-                "service_begin_time": {
-                    "description": "The time the service begins",
-                    "type": "string"
+                    "example": "Karl Hönck Heim, Kärnten"
                 },
-                "service_end_time": {
-                    "description": "The time the service ends",
+                "end_time": {
+                    "description": "the time the underlying event of this Application ends, it has to be after the start time",
                     "type": "string"
                 },
-                "staffnr": {
-                    "description": "The staffnr of the regarding teacher",
-                    "type": "integer",
-                    "example": 938503154
-                },
-                "starting_point": {
-                    "description": "The starting point (see the regarding Enum: OwnApartment or Office",
+                "kind": {
+                    "description": "The kind of this Application, only SchoolEvent (0), Training (1) and OtherReason (6) are applicable",
                     "type": "integer",
-                    "example": 1
+                    "example": 0
                 },
-                "staying_costs_paid_by_someone": {
-                    "description": "whether the staying costs are paid by someone else",
-                    "type": "boolean",
-                    "example": true
+                "miscellaneous_reason": {
+                    "description": "The Reasoning of this Application (there is none if this isn't of the type Miscellaneous)",
+                    "type": "string",
+                    "example": "Guter Grund"
                 },
-                "surname": {
-                    "description": "Surname of the Teacher",
+                "name": {
+                    "description": "The name on how this Application should be referenced by",
                     "type": "string",
-                    "example": "Zakall"
+                    "example": "Sommersportwoche"
                 },
-                "title": {
-                    "description": "Title of the Teacher",
+                "notes": {
+                    "description": "Other Notes regarding this Application",
                     "type": "string",
-                    "example": "Prof"
+                    "example": "Wichtig ist, dass wir die Reise bewilligen lassen!"
                 },
-                "travel_costs_paid_by_someone": {
-                    "description": "whether the travel costs are paid by someone else",
-                    "type": "boolean",
-                    "example": true
+                "other_reason_details": {
+                    "description": "Further Details if this is of the kind of any other, if not this will be empty",
+                    "$ref": "#/definitions/db.OtherReasonDetails"
                 },
-                "travel_mode": {
-                    "description": "The travel mode (see the regarding Enum for this)",
+                "progress": {
+                    "description": "The Progress of this Application in filing (for more see the Enum for the Progress)",
                     "type": "integer",
-                    "example": 6
+                    "example": 1
                 },
-                "travel_purpose": {
-                    "description": "The purpose of travelling",
+                "school_event_details": {
+                    "description": "Further Details if this is of the kind SchoolEvent, if not this will be empty",
+                    "$ref": "#/definitions/db.SchoolEventDetails"
+                },
+                "start_address": {
+                    "description": "The starting address of this Application",
                     "type": "string",
-                    "example": "Lehrausgang"
+                    "example": "TGM, Wexstraße 19-23, 1220 Wien"
                 },
-                "trip_begin_time": {
-                    "description": "The time the trip begins",
+                "start_time": {
+                    "description": "the time the underlying event of this Application starts",
                     "type": "string"
                 },
-                "trip_end_time": {
-                    "description": "The time the trip ends",
-                    "type": "string"
+                "training_details": {
+                    "description": "Further Details if this is of the kind Training, if not this will be empty",
+                    "$ref": "#/definitions/db.TrainingDetails"
                 },
-                "trip_goal": {
-                    "description": "The trip goal (address)",
-                    "type": "string",
-                    "example": "Technisches Museum Wien"
+                "travel_invoices": {
+                    "description": "The regarding TravelInvoice for each teacher",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.TravelInvoice"
+                    }
                 }
             }
         },
-        "db.Calculation": {
+        "rest.CurrentLesson": {
             "type": "object",
             "properties": {
-                "id": {
-                    "description": "the id of this calculation",
-                    "type": "integer",
-                    "example": 1
-                },
-                "rows": {
-                    "description": "rows of this calculation",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/db.Row"
-                    }
-                },
-                "sum_additional_costs": {
-                    "description": "the sum of all additional costs",
-                    "type": "number"
-                },
-                "sum_daily_charges": {
-                    "description": "the sum of all daily charges",
-                    "type": "number"
+                "current": {
+                    "description": "Current is the lesson taking place at the moment, null in a break or outside of school hours",
+                    "$ref": "#/definitions/untis.Lesson"
                 },
-                "sum_nightly_charges": {
-                    "description": "the sum of all nightly charges",
-                    "type": "number"
+                "current_period": {
+                    "description": "CurrentPeriod is the number of the period the current lesson starts in, -1 if there is none",
+                    "type": "integer",
+                    "example": 3
                 },
-                "sum_of_sums": {
-                    "description": "the sum of all sums",
-                    "type": "number"
+                "next": {
+                    "description": "Next is the next lesson of the day, null if there is none",
+                    "$ref": "#/definitions/untis.Lesson"
                 },
-                "sum_travel_costs": {
-                    "description": "the sum of all travel costs",
-                    "type": "number"
+                "next_period": {
+                    "description": "NextPeriod is the number of the period the next lesson starts in, -1 if there is none",
+                    "type": "integer",
+                    "example": 5
                 }
             }
         },
-        "db.OtherReasonDetails": {
+        "rest.Error": {
             "type": "object",
             "properties": {
-                "filer": {
-                    "description": "the teacher this application belongs to",
+                "error": {
+                    "description": "the message that should be sent",
                     "type": "string",
-                    "example": "Stefan Zakall"
-                },
-                "kind": {
-                    "description": "The kind of other Reason this Application is filed",
-                    "type": "integer",
-                    "example": 7
-                },
-                "miscellaneous_reason": {
-                    "description": "the reasoning if the other reason is of kind Miscellaneous",
+                    "example": "couldn't convert token"
+                }
+            }
+        },
+        "rest.Excel": {
+            "type": "object",
+            "properties": {
+                "excel": {
+                    "description": "Content is the content of the excel file",
                     "type": "string",
-                    "example": "Ein guter Grund"
+                    "example": "\u003cbase64\u003e"
+                }
+            }
+        },
+        "rest.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Field is the json name of the invalid field",
+                    "type": "string",
+                    "example": "end_time"
                 },
-                "service_mandate_gz": {
-                    "description": "the gz number if the other reason is a ServiceMandate",
-                    "type": "integer"
+                "message": {
+                    "description": "Message describes the rule, it is omitted for rules without a description",
+                    "type": "string",
+                    "example": "this has to be after start_time"
                 },
-                "service_mandate_title": {
-                    "description": "The title if the other reason is a ServiceMandate",
+                "reason": {
+                    "description": "Reason is the validation rule the field violates",
                     "type": "string",
-                    "example": "Dienstverrichtung"
+                    "example": "gtfield"
                 }
             }
         },
-        "db.Row": {
+        "rest.ImportedApplication": {
             "type": "object",
             "properties": {
-                "additional_costs": {
-                    "description": "the additionalCosts this Row conducts",
-                    "type": "number",
-                    "example": 4.32
-                },
-                "begin": {
-                    "description": "the begin time this Row refers to",
-                    "type": "string"
-                },
-                "daily_charges": {
-                    "description": "the dailyCharges this Row conducts",
-                    "type": "number",
-                    "example": 4.32
-                },
-                "date": {
-                    "description": "The date this Row refers to",
-                    "type": "string"
-                },
-                "end": {
-                    "description": "the end time this Row refers to",
-                    "type": "string"
-                },
-                "kilometres": {
-                    "description": "the amount of kilometres this row refers to",
-                    "type": "number",
-                    "example": 4.32
-                },
-                "kind_of_cost": {
-                    "description": "the kind of costs this row describes (see costs enum)",
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        1,
-                        2,
-                        3
-                    ]
+                "info": {
+                    "description": "Message is the message that should be sent",
+                    "type": "string",
+                    "example": "success; application imported"
                 },
-                "nightly_charges": {
-                    "description": "the nightlyCharges this Row conducts",
-                    "type": "number",
-                    "example": 4.32
+                "uuid": {
+                    "description": "UUID is the uuid of the recreated application",
+                    "type": "string",
+                    "example": "693aa616-9895-418b-8904-765f0f6d26a4"
+                }
+            }
+        },
+        "rest.Information": {
+            "type": "object",
+            "properties": {
+                "info": {
+                    "description": "the message that should be sent",
+                    "type": "string",
+                    "example": "updated teacher successfully"
+                }
+            }
+        },
+        "rest.News": {
+            "type": "object",
+            "properties": {
+                "last_changed": {
+                    "description": "LastChanged is the date of last changes of the application",
+                    "type": "string",
+                    "example": "2009-11-10 23:00:00 +0000 UTC m=+0.000000001"
                 },
-                "nr": {
-                    "description": "The row nr",
+                "state": {
+                    "description": "State of the application",
                     "type": "integer",
-                    "example": 1
+                    "example": 3
                 },
-                "sum": {
-                    "description": "the sum of all costs in this Row",
-                    "type": "number",
-                    "example": 4.32
+                "title": {
+                    "description": "Title of the application",
+                    "type": "string",
+                    "example": "Sommersportwoche"
                 },
-                "travel_costs": {
-                    "description": "the travelCosts this Row conducts",
-                    "type": "number",
-                    "example": 4.32
+                "uuid": {
+                    "description": "UUID of the application",
+                    "type": "string",
+                    "example": "3fcf7f67-e0ed-4339-99b4-a6765aaa3dc4"
                 }
             }
         },
-        "db.SchoolEventDetails": {
+        "rest.NewsPage": {
             "type": "object",
             "properties": {
-                "amount_female_students": {
-                    "description": "The amount of female students per class (same order as Classes)",
+                "items": {
+                    "description": "Items are the news on this page",
                     "type": "array",
                     "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        2,
-                        0,
-                        0,
-                        5
-                    ]
+                        "$ref": "#/definitions/rest.News"
+                    }
                 },
-                "amount_male_students": {
-                    "description": "The amount of male students per class (same order as Classes)",
+                "next_offset": {
+                    "description": "NextOffset is the offset of the next page or -1 if this is the last page",
+                    "type": "integer",
+                    "example": 10
+                },
+                "total": {
+                    "description": "Total is the amount of news on all pages",
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "rest.PDF": {
+            "type": "object",
+            "properties": {
+                "pdf": {
+                    "description": "Content is the content of this file",
+                    "type": "string",
+                    "example": "\u003cbase64\u003e"
+                }
+            }
+        },
+        "rest.PDFs": {
+            "type": "object",
+            "properties": {
+                "files": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        17,
-                        20,
-                        19,
-                        18
-                    ]
+                        "$ref": "#/definitions/rest.Receipt"
+                    }
+                }
+            }
+        },
+        "rest.Permissions": {
+            "type": "object",
+            "properties": {
+                "administration": {
+                    "description": "Administration permission",
+                    "type": "boolean",
+                    "example": true
                 },
-                "classes": {
-                    "description": "The participating classes",
+                "av": {
+                    "description": "AV permission",
+                    "type": "boolean",
+                    "example": true
+                },
+                "pek": {
+                    "description": "PEK permission",
+                    "type": "boolean",
+                    "example": true
+                },
+                "super_user": {
+                    "description": "SuperUser permission",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "rest.PermissionsUpdate": {
+            "type": "object",
+            "required": [
+                "teacher_short"
+            ],
+            "properties": {
+                "permissions": {
+                    "description": "Permissions are the names of all permissions the teacher should have (super_user, administration, av or pek), permissions not listed are revoked",
                     "type": "array",
                     "items": {
                         "type": "string"
                     },
                     "example": [
-                        "5BHIT",
-                        "5AHIT",
-                        "5CHIT",
-                        "5DHIT"
+                        "administration",
+                        "av"
                     ]
                 },
-                "duration_in_days": {
-                    "description": "The duration of the event in days",
-                    "type": "integer",
-                    "example": 2
-                },
-                "teachers": {
-                    "description": "Details of each teacher participating in the SchoolEvent",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/db.SchoolEventTeacherDetails"
-                    }
+                "teacher_short": {
+                    "description": "TeacherShort is the short name of the teacher whose permissions are changed",
+                    "type": "string",
+                    "example": "szakall"
                 }
             }
         },
-        "db.SchoolEventTeacherDetails": {
+        "rest.Receipt": {
             "type": "object",
             "properties": {
-                "attendance_from": {
-                    "description": "The teacher will be attending the SchoolEvent from",
-                    "type": "string"
-                },
-                "attendance_till": {
-                    "description": "The teacher will be attend the SchoolEvent till",
-                    "type": "string"
-                },
-                "group": {
-                    "description": "The group number",
-                    "type": "integer",
-                    "example": 1
-                },
-                "meeting_point": {
-                    "description": "Where the teacher will meet with the group to travel together",
-                    "type": "string",
-                    "example": "TGM, Wexstraße 19-23, 1220 Wien"
-                },
                 "name": {
-                    "description": "The full name of a teacher",
+                    "description": "Name is the original file name, only its extension is used",
                     "type": "string",
-                    "example": "Stefan Zakall"
-                },
-                "role": {
-                    "description": "The role of each teacher (Leader or Companion)",
-                    "type": "integer",
-                    "example": 0
+                    "example": "rechnung.pdf"
                 },
-                "shortname": {
-                    "description": "The short name (abbrevation) of a teacher",
+                "pdf": {
+                    "description": "Content is the content of this file",
                     "type": "string",
-                    "example": "szakall"
-                },
-                "start_address": {
-                    "description": "Where the teacher starts their travel from",
+                    "example": "\u003cbase64\u003e"
+                }
+            }
+        },
+        "rest.RefreshToken": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "description": "Token is the refresh token",
                     "type": "string",
-                    "example": "TGM, Wexstraße 19-23, 1220 Wien"
+                    "example": "\u003cjwt-token\u003e"
                 }
             }
         },
-        "db.Teacher": {
+        "rest.SavedReceipts": {
             "type": "object",
             "properties": {
-                "administration": {
-                    "description": "whether this Teacher as administration rights",
-                    "type": "boolean",
-                    "example": true
-                },
-                "av": {
-                    "description": "whether this Teacher as av rights",
-                    "type": "boolean",
-                    "example": true
+                "files": {
+                    "description": "Files are the paths of the stored receipts relative to the receipts directory",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2021/0f8fad5b-d9cb-469f-a165-70867728950e_mbeier_20210504T120000.000000000.pdf"
+                    ]
                 },
+                "info": {
+                    "description": "Message is the message that should be sent",
+                    "type": "string",
+                    "example": "saving successful"
+                }
+            }
+        },
+        "rest.TeacherInformation": {
+            "type": "object",
+            "properties": {
                 "degree": {
                     "description": "Degree of the Teacher",
                     "type": "string",
@@ -2009,21 +4801,6 @@ var doc = `{
                     "type": "integer",
                     "example": 1
                 },
-                "longname": {
-                    "description": "the longname (firstname + sirname) of the Teacher",
-                    "type": "string",
-                    "example": "Stefan Zakall"
-                },
-                "pek": {
-                    "description": "whether this Teacher as pek rights",
-                    "type": "boolean",
-                    "example": true
-                },
-                "short": {
-                    "description": "the short name of the Teacher",
-                    "type": "string",
-                    "example": "szakall"
-                },
                 "staffnr": {
                     "description": "The Staffnr of the regarding teacher",
                     "type": "integer",
@@ -2040,11 +4817,6 @@ var doc = `{
                         "Zuhause 2"
                     ]
                 },
-                "super_user": {
-                    "description": "Superuser (total admin) of this software",
-                    "type": "boolean",
-                    "example": true
-                },
                 "title": {
                     "description": "Title of the Teacher",
                     "type": "string",
@@ -2066,402 +4838,575 @@ var doc = `{
                     "description": "The Untis abbrevation of the teacher",
                     "type": "string",
                     "example": "ZAKS"
+                }
+            }
+        },
+        "rest.TeacherTimetable": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Error describes why the timetable of the teacher couldn't be fetched, it is omitted on success",
+                    "type": "string"
                 },
-                "uuid": {
-                    "description": "the uuid of this Teacher",
-                    "type": "string",
-                    "example": "3fcf7f67-e0ed-4339-99b4-a6765aaa3dc4"
+                "lessons": {
+                    "description": "Lessons are the lessons of the teacher, empty if an error occurred",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/untis.Lesson"
+                    }
                 }
             }
         },
-        "db.TrainingDetails": {
+        "rest.TimetablesRequest": {
             "type": "object",
+            "required": [
+                "teachers"
+            ],
             "properties": {
-                "filer": {
-                    "description": "the teacher this application belongs to",
+                "from": {
+                    "description": "From is the first day of the timetables (YYYY-MM-DD), the monday of the current week if it is empty",
                     "type": "string",
-                    "example": "Stefan Zakall"
+                    "example": "2021-05-03"
                 },
-                "kind": {
-                    "description": "The kind of Training",
-                    "type": "integer",
-                    "example": 2
+                "teachers": {
+                    "description": "Teachers are the names of the teachers, either the full name or the short name",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Max Mustermann"
+                    ]
                 },
-                "miscellaneous_reason": {
-                    "description": "if its miscellaneous a reasoning for the Training",
+                "to": {
+                    "description": "To is the last day of the timetables (YYYY-MM-DD), the sunday of the week of from if it is empty",
+                    "type": "string",
+                    "example": "2021-05-09"
+                }
+            }
+        },
+        "rest.TokenPair": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "description": "the access token",
+                    "type": "string",
+                    "example": "\u003cjwt-token\u003e"
+                },
+                "refresh_token": {
+                    "description": "the refresh token",
+                    "type": "string",
+                    "example": "\u003cjwt-token\u003e"
+                }
+            }
+        },
+        "rest.User": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "description": "Password of the user",
                     "type": "string",
-                    "example": "Ein sonstiger Grund"
+                    "example": "password1234"
                 },
-                "organizer": {
-                    "description": "The company who organizes the Training",
+                "username": {
+                    "description": "Username of the user",
                     "type": "string",
-                    "example": "Accenture"
+                    "example": "lehrer1234"
+                }
+            }
+        },
+        "rest.ValidationError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "the message that should be sent",
+                    "type": "string",
+                    "example": "invalid request structure provided"
                 },
-                "ph": {
-                    "description": "the ph number of the teacher",
-                    "type": "integer",
-                    "example": 938503154
+                "fields": {
+                    "description": "Fields are the invalid fields",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.FieldError"
+                    }
                 }
             }
         },
-        "db.TravelInvoice": {
+        "untis.Class": {
             "type": "object",
             "properties": {
-                "approval_date": {
-                    "description": "the date this application was approved",
+                "backColor": {
+                    "description": "BackColor is the background color used for the class in untis",
                     "type": "string"
                 },
-                "breakfasts": {
-                    "description": "the amount of breakfasts",
-                    "type": "integer",
-                    "example": 2
+                "foreColor": {
+                    "description": "ForeColor is the text color used for the class in untis",
+                    "type": "string"
                 },
-                "calculation": {
-                    "description": "the regarding calculation",
-                    "$ref": "#/definitions/db.Calculation"
+                "id": {
+                    "description": "ID is the untis id of the class",
+                    "type": "integer"
                 },
-                "clerk": {
-                    "description": "the clerk maintaining and checking this application",
+                "longName": {
+                    "description": "LongName is the full name of the class",
                     "type": "string"
                 },
-                "daily_charges_mode": {
-                    "description": "the mode how daily charges are handled",
-                    "type": "integer",
-                    "example": 1
+                "name": {
+                    "description": "Name is the short name of the class",
+                    "type": "string"
                 },
-                "degree": {
-                    "description": "Degree of the Teacher",
-                    "type": "string",
-                    "example": "DI"
+                "teacher1": {
+                    "description": "Teacher1 is the id of the class teacher",
+                    "type": "integer"
                 },
-                "dinners": {
-                    "description": "the amount of dinners",
-                    "type": "integer",
-                    "example": 4
+                "teacher2": {
+                    "description": "Teacher2 is the id of the deputy class teacher",
+                    "type": "integer"
+                }
+            }
+        },
+        "untis.Exam": {
+            "type": "object",
+            "properties": {
+                "classIDs": {
+                    "description": "ClassIDs are the ids of the classes taking the exam",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "end_point": {
-                    "description": "the end point of the trip",
-                    "type": "string",
-                    "example": "Hauptbahnhof, 1220 Wien"
+                "classes": {
+                    "description": "Classes are the names of the classes taking the exam",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "filing_date": {
-                    "description": "the date this application was filed",
+                "end": {
+                    "description": "End is the end time of the exam",
                     "type": "string"
                 },
                 "id": {
-                    "description": "The id (counting upwards) of this TravelInvoice regarding to the uid",
-                    "type": "integer",
-                    "example": 1
-                },
-                "kilometre_allowance": {
-                    "description": "whether the teacher got a kilometre allowance",
-                    "type": "boolean",
-                    "example": true
-                },
-                "kilometre_amount": {
-                    "description": "the regarding kilometre amount",
-                    "type": "number",
-                    "example": 25.12
-                },
-                "lunches": {
-                    "description": "the amount of lunches",
-                    "type": "integer",
-                    "example": 3
+                    "description": "ID is the id of the exam in untis",
+                    "type": "integer"
                 },
                 "name": {
-                    "description": "Name of the Teacher",
-                    "type": "string",
-                    "example": "Stefan"
-                },
-                "nightly_charges_mode": {
-                    "description": "the mode how nightly charges are handled",
-                    "type": "integer",
-                    "example": 1
-                },
-                "no_travel_costs": {
-                    "description": "whether there aren't any travel costs",
-                    "type": "boolean",
-                    "example": true
-                },
-                "nr_and_indications_of_participants": {
-                    "description": "whether the participants of the trip are counted and clearly indicated",
-                    "type": "boolean",
-                    "example": true
-                },
-                "official_business_card_got": {
-                    "description": "whether the teacher got a official business card",
-                    "type": "boolean",
-                    "example": true
-                },
-                "replacement_for_advantage_card": {
-                    "description": "whether the teacher got a replacement for an advantage card",
-                    "type": "boolean",
-                    "example": false
-                },
-                "replacement_for_train_card_class_2": {
-                    "description": "whether the teacher got a replacement for a train card class 2",
-                    "type": "boolean",
-                    "example": false
-                },
-                "reviewer": {
-                    "description": "the reviewer reviewing the approval of this application",
+                    "description": "Name is the name of the exam",
                     "type": "string"
                 },
-                "shortened_amount": {
-                    "description": "the amount the daily charges should be shortened",
-                    "type": "number",
-                    "example": 0
+                "period": {
+                    "description": "Period is the number of the period of DefaultSchedule the exam starts in, -1 if it starts in none",
+                    "type": "integer"
                 },
-                "staffnr": {
-                    "description": "The personnel number of the teacher",
-                    "type": "integer",
-                    "example": 938503154
+                "roomIDs": {
+                    "description": "RoomIDs are the ids of the rooms the exam takes place in",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "starting_point": {
-                    "description": "the starting point of the trip",
-                    "type": "string",
-                    "example": "TGM, Wexstraße 19-23, 1220 Wien"
+                "rooms": {
+                    "description": "Rooms are the names of the rooms the exam takes place in",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "surname": {
-                    "description": "Surname of the Teacher",
-                    "type": "string",
-                    "example": "Zakall"
+                "start": {
+                    "description": "Start is the start time of the exam",
+                    "type": "string"
                 },
-                "title": {
-                    "description": "Title of the Teacher",
+                "subject": {
+                    "description": "Subject is the name of the subject of the exam, empty if it has none",
                     "type": "string"
                 },
-                "travel_costs_cited": {
-                    "description": "whether the travel costs are clearly cited",
-                    "type": "boolean",
-                    "example": false
+                "subjectID": {
+                    "description": "SubjectID is the id of the subject of the exam, 0 if it has none",
+                    "type": "integer"
                 },
-                "travel_costs_pre_grant": {
-                    "description": "The granted travel costs",
-                    "type": "number",
-                    "example": 0
+                "teacherIDs": {
+                    "description": "TeacherIDs are the ids of the teachers supervising the exam",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "travel_grant": {
-                    "description": "whether the teacher got a travel grant",
-                    "type": "boolean",
-                    "example": false
+                "teachers": {
+                    "description": "Teachers are the names of the teachers supervising the exam",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "trip_begin_time": {
-                    "description": "The time the trip begins",
+                "text": {
+                    "description": "Text is the text untis provides regarding the exam",
                     "type": "string"
                 },
-                "trip_end_time": {
-                    "description": "The time the trip ends",
+                "type": {
+                    "description": "Type is the kind of the exam as configured in untis (e.g. SA for a school exam)",
                     "type": "string"
-                },
-                "zi": {
-                    "description": "the zi number",
-                    "type": "integer"
                 }
             }
         },
-        "rest.Error": {
+        "untis.Holiday": {
             "type": "object",
             "properties": {
-                "error": {
-                    "description": "the message that should be sent",
+                "end": {
+                    "description": "End is the last day of the holidays",
+                    "type": "string"
+                },
+                "long_name": {
+                    "description": "LongName is the full name of the holidays",
                     "type": "string",
-                    "example": "couldn't convert token"
-                }
-            }
-        },
-        "rest.Excel": {
-            "type": "object",
-            "properties": {
-                "excel": {
-                    "description": "Content is the content of the excel file",
+                    "example": "Osterferien"
+                },
+                "name": {
+                    "description": "Name is the short name of the holidays",
                     "type": "string",
-                    "example": "\u003cbase64\u003e"
+                    "example": "Ostern"
+                },
+                "start": {
+                    "description": "Start is the first day of the holidays",
+                    "type": "string"
                 }
             }
         },
-        "rest.Information": {
+        "untis.Lesson": {
             "type": "object",
             "properties": {
+                "cancelled": {
+                    "description": "Cancelled whether this lesson is cancelled",
+                    "type": "boolean"
+                },
+                "classIDs": {
+                    "description": "ClassIDs are the ids of the classes participating",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "classes": {
+                    "description": "Classes are the names of all classes participating",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "end": {
+                    "description": "End is the end time of the lesson",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is the id of the lesson in untis",
+                    "type": "integer"
+                },
                 "info": {
-                    "description": "the message that should be sent",
-                    "type": "string",
-                    "example": "updated teacher successfully"
-                }
-            }
-        },
-        "rest.News": {
-            "type": "object",
-            "properties": {
-                "last_changed": {
-                    "description": "LastChanged is the date of last changes of the application",
-                    "type": "string",
-                    "example": "2009-11-10 23:00:00 +0000 UTC m=+0.000000001"
+                    "description": "Info is the additional information untis provides for this lesson",
+                    "type": "string"
+                },
+                "irregular": {
+                    "description": "Irregular whether this lesson is irregular (e.g. a substitution)",
+                    "type": "boolean"
+                },
+                "lessonText": {
+                    "description": "LessonText is the text untis provides for this lesson (e.g. an exam announcement)",
+                    "type": "string"
+                },
+                "originalRoomIDs": {
+                    "description": "OriginalRoomIDs are the ids of the rooms this lesson was moved from",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "originalRooms": {
+                    "description": "OriginalRooms are the names of the rooms this lesson was moved from",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "state": {
-                    "description": "State of the application",
-                    "type": "integer",
-                    "example": 3
+                "originalTeacherIDs": {
+                    "description": "OriginalTeacherIDs are the ids of the teachers substituted by Teachers",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "title": {
-                    "description": "Title of the application",
-                    "type": "string",
-                    "example": "Sommersportwoche"
+                "originalTeachers": {
+                    "description": "OriginalTeachers are the names of the teachers substituted by Teachers",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "uuid": {
-                    "description": "UUID of the application",
-                    "type": "string",
-                    "example": "3fcf7f67-e0ed-4339-99b4-a6765aaa3dc4"
-                }
-            }
-        },
-        "rest.PDF": {
-            "type": "object",
-            "properties": {
-                "pdf": {
-                    "description": "Content is the content of this file",
-                    "type": "string",
-                    "example": "\u003cbase64\u003e"
-                }
-            }
-        },
-        "rest.PDFs": {
-            "type": "object",
-            "properties": {
-                "files": {
+                "roomIDs": {
+                    "description": "RoomIDs are the room ids this lesson takes place in",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/rest.PDF"
+                        "type": "integer"
                     }
-                }
-            }
-        },
-        "rest.Permissions": {
-            "type": "object",
-            "properties": {
-                "administration": {
-                    "description": "Administration permission",
-                    "type": "boolean",
-                    "example": true
                 },
-                "av": {
-                    "description": "AV permission",
-                    "type": "boolean",
-                    "example": true
+                "rooms": {
+                    "description": "Rooms are the room names this lesson takes place in",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "pek": {
-                    "description": "PEK permission",
-                    "type": "boolean",
-                    "example": true
+                "start": {
+                    "description": "Start is the start time of the lesson",
+                    "type": "string"
                 },
-                "super_user": {
-                    "description": "SuperUser permission",
-                    "type": "boolean",
-                    "example": true
+                "subjectIDs": {
+                    "description": "SubjectIDs are the ids of the subjects taught in this lesson",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "subjects": {
+                    "description": "Subjects are the names of the subjects taught in this lesson",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "substitutionText": {
+                    "description": "SubstitutionText is the text untis provides regarding a substitution of this lesson",
+                    "type": "string"
+                },
+                "teacherIDs": {
+                    "description": "TeacherIDs are the ids of the teachers teaching",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "teachers": {
+                    "description": "Teachers are the names of all teachers teaching",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "rest.RefreshToken": {
+        "untis.Room": {
             "type": "object",
             "properties": {
-                "refresh_token": {
-                    "description": "Token is the refresh token",
-                    "type": "string",
-                    "example": "\u003cjwt-token\u003e"
+                "backColor": {
+                    "description": "BackColor is the background color used for the room in untis",
+                    "type": "string"
+                },
+                "foreColor": {
+                    "description": "ForeColor is the text color used for the room in untis",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is the untis id of the room",
+                    "type": "integer"
+                },
+                "longName": {
+                    "description": "LongName is the full name of the room",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is the short name of the room",
+                    "type": "string"
                 }
             }
         },
-        "rest.TeacherInformation": {
+        "untis.Substitution": {
             "type": "object",
             "properties": {
-                "degree": {
-                    "description": "Degree of the Teacher",
-                    "type": "string",
-                    "example": "DI"
+                "classIDs": {
+                    "description": "ClassIDs are the ids of the affected classes",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "departments": {
-                    "description": "The Departments this teacher belongs to",
+                "classes": {
+                    "description": "Classes are the names of the affected classes",
                     "type": "array",
                     "items": {
                         "type": "string"
-                    },
-                    "example": [
-                        "HIT",
-                        "HBG"
-                    ]
+                    }
                 },
-                "group": {
-                    "description": "The Group number",
-                    "type": "integer",
-                    "example": 1
+                "end": {
+                    "description": "End is the end time of the affected lesson",
+                    "type": "string"
                 },
-                "staffnr": {
-                    "description": "The Staffnr of the regarding teacher",
-                    "type": "integer",
-                    "example": 938503154
+                "lessonID": {
+                    "description": "LessonID is the id of the affected lesson in untis",
+                    "type": "integer"
                 },
-                "starting_addresses": {
-                    "description": "The StartingAddresses of the teacher",
+                "originalRoomIDs": {
+                    "description": "OriginalRoomIDs are the ids of the rooms the lesson was moved from",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "originalRooms": {
+                    "description": "OriginalRooms are the names of the rooms the lesson was moved from",
                     "type": "array",
                     "items": {
                         "type": "string"
-                    },
-                    "example": [
-                        "Zuhause 1",
-                        "Zuhause 2"
-                    ]
+                    }
                 },
-                "title": {
-                    "description": "Title of the Teacher",
-                    "type": "string",
-                    "example": "Prof"
+                "originalTeacherIDs": {
+                    "description": "OriginalTeacherIDs are the ids of the teachers substituted by Teachers",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "trip_goals": {
-                    "description": "The TripGoals the teacher visited before",
+                "originalTeachers": {
+                    "description": "OriginalTeachers are the names of the teachers substituted by Teachers",
                     "type": "array",
                     "items": {
                         "type": "string"
-                    },
-                    "example": [
-                        "Karl Hönck Heim",
-                        "PH Wien",
-                        "Landesgericht St. Pölten"
-                    ]
+                    }
                 },
-                "untis": {
-                    "description": "The Untis abbrevation of the teacher",
-                    "type": "string",
-                    "example": "ZAKS"
+                "period": {
+                    "description": "Period is the number of the period of DefaultSchedule the lesson starts in, -1 if it starts in none",
+                    "type": "integer"
+                },
+                "roomIDs": {
+                    "description": "RoomIDs are the ids of the rooms the lesson takes place in",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "rooms": {
+                    "description": "Rooms are the names of the rooms the lesson takes place in",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "start": {
+                    "description": "Start is the start time of the affected lesson",
+                    "type": "string"
+                },
+                "subjectIDs": {
+                    "description": "SubjectIDs are the ids of the subjects of the lesson",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "subjects": {
+                    "description": "Subjects are the names of the subjects of the lesson",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "teacherIDs": {
+                    "description": "TeacherIDs are the ids of the teachers teaching the lesson",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "teachers": {
+                    "description": "Teachers are the names of the teachers teaching the lesson",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "text": {
+                    "description": "Text is the text untis provides regarding the substitution",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type is the kind of the change",
+                    "type": "string"
                 }
             }
         },
-        "rest.TokenPair": {
+        "untis.Teacher": {
             "type": "object",
             "properties": {
-                "access_token": {
-                    "description": "the access token",
-                    "type": "string",
-                    "example": "\u003cjwt-token\u003e"
+                "backColor": {
+                    "description": "BackColor is the background color used for the teacher in untis",
+                    "type": "string"
                 },
-                "refresh_token": {
-                    "description": "the refresh token",
-                    "type": "string",
-                    "example": "\u003cjwt-token\u003e"
+                "foreColor": {
+                    "description": "ForeColor is the text color used for the teacher in untis",
+                    "type": "string"
+                },
+                "foreName": {
+                    "description": "ForeName is the forename of the teacher",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is the untis id of the teacher",
+                    "type": "integer"
+                },
+                "longName": {
+                    "description": "LongName is the surname of the teacher",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is the short name of the teacher",
+                    "type": "string"
                 }
             }
         },
-        "rest.User": {
+        "untis.UserData": {
             "type": "object",
             "properties": {
-                "password": {
-                    "description": "Password of the user",
+                "class_ids": {
+                    "description": "ClassIDs are the ids of the classes the account belongs to",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "classes": {
+                    "description": "Classes are the names of the classes the account belongs to",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "department_id": {
+                    "description": "DepartmentID is the id of the department of the account, 0 if it belongs to none",
+                    "type": "integer",
+                    "example": 0
+                },
+                "display_name": {
+                    "description": "DisplayName is the name untis displays for the account",
                     "type": "string",
-                    "example": "password1234"
+                    "example": "Max Mustermann"
                 },
-                "username": {
-                    "description": "Username of the user",
+                "element_id": {
+                    "description": "ElementID is the id of the element of the account, e.g. the id of the teacher",
+                    "type": "integer",
+                    "example": 42
+                },
+                "element_type": {
+                    "description": "ElementType is the type of the element of the account (e.g. TEACHER)",
                     "type": "string",
-                    "example": "lehrer1234"
+                    "example": "TEACHER"
+                },
+                "rights": {
+                    "description": "Rights are the permissions of the account in untis (e.g. R_MY_OWN_TIMETABLE)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "school_name": {
+                    "description": "SchoolName is the name of the school of the account",
+                    "type": "string",
+                    "example": "TGM"
                 }
             }
         }
@@ -241,9 +241,13 @@ func GenerateFileEnvironment(app db.Application) (string, error) {
 // GenerateAbsenceFormForClass generates the class absence forms for all classes in the given db.Application.
 // It will be saved under path, and the given username is used to log into the untis service
 // It will return a string array of paths to all generated pdfs or an error if the operation wasn't successful
-func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]string, error) {
+// The labels of the form are written in lang (German or English), German is used for any other language
+func GenerateAbsenceFormForClass(path, username string, app db.Application, lang string) ([]string, error) {
 	paths := make([]string, 0)
-	client := untis.GetClient(username)
+	client, ok := untis.GetClient(username)
+	if !ok {
+		return nil, fmt.Errorf("no untis session of %v", username)
+	}
 	if app.Kind != db.SchoolEvent {
 		return nil, fmt.Errorf("this pdf can only be generated for school events")
 	}
@@ -251,6 +255,13 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 	if err != nil {
 		return nil, fmt.Errorf("couldn't load timezone")
 	}
+	err = client.Acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = client.Release()
+	}()
 	for i, class := range app.SchoolEventDetails.Classes {
 		m := pdf.NewMaroto(consts.Portrait, consts.A4)
 		m.SetPageMargins(10, 15, 10)
@@ -269,7 +280,7 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 				m.ColSpace(2)
 
 				m.Col(2, func() {
-					m.Text("Abwesenheitsmeldung eines Jahrgangs", props.Text{
+					m.Text(label(lang, "Abwesenheitsmeldung eines Jahrgangs"), props.Text{
 						Align:  consts.Center,
 						Family: consts.Helvetica,
 						Size:   12,
@@ -294,7 +305,7 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 		m.Row(3, func() {})
 		m.Row(10, func() {
 			m.Col(12, func() {
-				m.Text("Allgemeine Informationen:", props.Text{
+				m.Text(label(lang, "Allgemeine Informationen:"), props.Text{
 					Top:   3,
 					Align: consts.Left,
 					Style: consts.Bold,
@@ -319,7 +330,7 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 		m.Row(10, func() {
 			m.Col(6, func() {
 				m.Col(3, func() {
-					m.Text("Jahrgang:", props.Text{
+					m.Text(label(lang, "Jahrgang:"), props.Text{
 						Top:   2.5,
 						Align: consts.Left,
 					})
@@ -332,7 +343,7 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 			})
 			m.Col(6, func() {
 				m.Col(3, func() {
-					m.Text("Lehrkraft:", props.Text{
+					m.Text(label(lang, "Lehrkraft:"), props.Text{
 						Top:   2.5,
 						Align: consts.Left,
 					})
@@ -349,7 +360,7 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 		m.Row(10+2.75*compcount, func() {
 			m.Col(6, func() {
 				m.Col(3, func() {
-					m.Text("Anzahl m/w Schüler/innen:", props.Text{
+					m.Text(label(lang, "Anzahl m/w Schüler/innen:"), props.Text{
 						Top:   2.5,
 						Align: consts.Left,
 					})
@@ -361,7 +372,7 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 			})
 			m.Col(6, func() {
 				m.Col(3, func() {
-					m.Text("Begleitpersonen:", props.Text{
+					m.Text(label(lang, "Begleitpersonen:"), props.Text{
 						Top:   2.5,
 						Align: consts.Left,
 					})
@@ -377,12 +388,12 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 		m.Row(10, func() {
 			m.Col(6, func() {
 				m.Col(3, func() {
-					m.Text("Von:", props.Text{
+					m.Text(label(lang, "Von:"), props.Text{
 						Top:   2.5,
 						Align: consts.Left,
 					})
 				})
-				weekday := getWeekday(int(app.StartTime.In(loc).Weekday()))
+				weekday := getWeekday(lang, int(app.StartTime.In(loc).Weekday()))
 				m.Text(fmt.Sprintf("%v, %v", weekday, app.StartTime.In(loc).Format("02. 01. 2006 15:04")),
 					props.Text{
 						Top:   2.5,
@@ -392,12 +403,12 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 			})
 			m.Col(6, func() {
 				m.Col(3, func() {
-					m.Text("Bis:", props.Text{
+					m.Text(label(lang, "Bis:"), props.Text{
 						Top:   2.5,
 						Align: consts.Left,
 					})
 				})
-				weekday := getWeekday(int(app.EndTime.In(loc).Weekday()))
+				weekday := getWeekday(lang, int(app.EndTime.In(loc).Weekday()))
 				m.Text(fmt.Sprintf("%v, %v", weekday, app.EndTime.In(loc).Format("02. 01. 2006 15:04")),
 					props.Text{
 						Top:   2.5,
@@ -410,7 +421,7 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 		m.Row(10, func() {
 			m.Col(6, func() {
 				m.Col(3, func() {
-					m.Text("Anmerkungen:", props.Text{
+					m.Text(label(lang, "Anmerkungen:"), props.Text{
 						Top:   2.5,
 						Align: consts.Left,
 					})
@@ -430,7 +441,7 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 		m.Row(3, func() {})
 		m.Row(10, func() {
 			m.Col(12, func() {
-				m.Text("Schulveranstaltung:", props.Text{
+				m.Text(label(lang, "Schulveranstaltung:"), props.Text{
 					Top:   3,
 					Align: consts.Left,
 					Style: consts.Bold,
@@ -441,7 +452,7 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 		m.Row(10, func() {
 			m.Col(12, func() {
 				m.Col(6, func() {
-					m.Text("Veranstaltung:", props.Text{
+					m.Text(label(lang, "Veranstaltung:"), props.Text{
 						Top:   2.5,
 						Align: consts.Left,
 					})
@@ -457,7 +468,7 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 		m.Row(10, func() {
 			m.Col(6, func() {
 				m.Col(3, func() {
-					m.Text("Treffpunkt:", props.Text{
+					m.Text(label(lang, "Treffpunkt:"), props.Text{
 						Top:   2.5,
 						Align: consts.Left,
 					})
@@ -470,7 +481,7 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 			})
 			m.Col(6, func() {
 				m.Col(3, func() {
-					m.Text("Uhrzeit:", props.Text{
+					m.Text(label(lang, "Uhrzeit:"), props.Text{
 						Top:   2.5,
 						Align: consts.Left,
 					})
@@ -487,18 +498,18 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 		m.Row(10, func() {
 			m.Col(12, func() {
 				m.Col(6, func() {
-					m.Text("Dauer der Veranstaltung:", props.Text{
+					m.Text(label(lang, "Dauer der Veranstaltung:"), props.Text{
 						Top:   2.5,
 						Align: consts.Left,
 					})
 				})
 				dayString := ""
 				if app.SchoolEventDetails.DurationInDays == 1 {
-					dayString = "1-tägig (002)"
+					dayString = label(lang, "1-tägig (002)")
 				} else if app.SchoolEventDetails.DurationInDays > 3 {
-					dayString = "mehr als 3-tägig (004)"
+					dayString = label(lang, "mehr als 3-tägig (004)")
 				} else {
-					dayString = "2-3-tägig (003)"
+					dayString = label(lang, "2-3-tägig (003)")
 				}
 				m.Text(dayString, props.Text{
 					Top:   2.5,
@@ -512,7 +523,7 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 		m.Row(3, func() {})
 		m.Row(10, func() {
 			m.Col(12, func() {
-				m.Text("Supplierungen:", props.Text{
+				m.Text(label(lang, "Supplierungen:"), props.Text{
 					Top:   3,
 					Align: consts.Left,
 					Style: consts.Bold,
@@ -520,10 +531,6 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 			})
 		})
 		tableStrings := make([][]string, 0)
-		err := client.Authenticate()
-		if err != nil {
-			return nil, err
-		}
 		lessons, err := client.GetTimetableOfClass(app.StartTime.In(loc), app.EndTime.In(loc), class)
 		if err != nil {
 			return nil, err
@@ -546,10 +553,6 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 		if err != nil {
 			return nil, err
 		}
-		err = client.Close()
-		if err != nil {
-			return nil, err
-		}
 		untisnames := ""
 		for _, t := range untiscomps {
 			untisnames = untisnames + t + ", "
@@ -595,7 +598,7 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 			tableStrings = append(tableStrings, row)
 		}
 		sortTableByDate(tableStrings)
-		m.TableList([]string{"H/R/E", "Jahrgang", "Datum", "Stunde", "Saal", "LK Supp.", "LK Entf.", "Paraphe"},
+		m.TableList([]string{label(lang, "H/R/E"), label(lang, "Jahrgang"), label(lang, "Datum"), label(lang, "Stunde"), label(lang, "Saal"), label(lang, "LK Supp."), label(lang, "LK Entf."), label(lang, "Paraphe")},
 			tableStrings, props.TableList{
 				Align: consts.Center,
 				HeaderProp: props.TableListContent{
@@ -610,7 +613,7 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 		m.Row(3, func() {})
 		m.Row(10, func() {
 			m.Col(12, func() {
-				m.Text("Kenntnisnahme:", props.Text{
+				m.Text(label(lang, "Kenntnisnahme:"), props.Text{
 					Top:   3,
 					Align: consts.Left,
 					Style: consts.Bold,
@@ -625,10 +628,10 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 		ackStrings[0] = []string{"AV", spacer, spacer}
 		ackStrings[1] = []string{"AV", spacer, spacer}
 		ackStrings[2] = []string{"WL", spacer, spacer}
-		ackStrings[3] = []string{"Begleitperson", spacer, spacer}
-		ackStrings[4] = []string{"Ersteller/in", spacer, spacer}
-		ackStrings[5] = []string{"UNTIS Eintragung", spacer, spacer}
-		m.TableList([]string{"Stelle", "Datum", "Paraphe"}, ackStrings, props.TableList{
+		ackStrings[3] = []string{label(lang, "Begleitperson"), spacer, spacer}
+		ackStrings[4] = []string{label(lang, "Ersteller/in"), spacer, spacer}
+		ackStrings[5] = []string{label(lang, "UNTIS Eintragung"), spacer, spacer}
+		m.TableList([]string{label(lang, "Stelle"), label(lang, "Datum"), label(lang, "Paraphe")}, ackStrings, props.TableList{
 			Align: consts.Center,
 			HeaderProp: props.TableListContent{
 				GridSizes: []uint{2, 5, 5},
@@ -652,7 +655,8 @@ func GenerateAbsenceFormForClass(path, username string, app db.Application) ([]s
 // GenerateCompensationForEducationalSupport generates the compensation for educational support file on the basis of the given db.Application
 // This is only allowed for db.Application with Application.Kind db.SchoolEvent
 // It will be saved in the directory under the given path. It will return the complete path on success, otherwise an error will be returned
-func GenerateCompensationForEducationalSupport(path string, app db.Application) (string, error) {
+// The labels of the form are written in lang (German or English), German is used for any other language
+func GenerateCompensationForEducationalSupport(path string, app db.Application, lang string) (string, error) {
 	if app.Kind != db.SchoolEvent {
 		return "", fmt.Errorf("this pdf can only be generated for school events")
 	}
@@ -687,7 +691,7 @@ func GenerateCompensationForEducationalSupport(path string, app db.Application)
 			m.ColSpace(2)
 
 			m.Col(2, func() {
-				m.Text("Abgeltung für pädagogische Betreuung gemäß §63a", props.Text{
+				m.Text(label(lang, "Abgeltung für pädagogische Betreuung gemäß §63a"), props.Text{
 					Align:  consts.Center,
 					Family: consts.Helvetica,
 					Size:   12,
@@ -712,7 +716,7 @@ func GenerateCompensationForEducationalSupport(path string, app db.Application)
 	m.Row(3, func() {})
 	m.Row(10, func() {
 		m.Col(12, func() {
-			m.Text("Allgemeine Informationen:", props.Text{
+			m.Text(label(lang, "Allgemeine Informationen:"), props.Text{
 				Top:   3,
 				Align: consts.Left,
 				Style: consts.Bold,
@@ -722,14 +726,14 @@ func GenerateCompensationForEducationalSupport(path string, app db.Application)
 
 	m.Row(8, func() {
 		m.Col(12, func() {
-			m.Text("Formular ist vom Leiter bzw. der Leiterin der Schulveranstaltung mit der Reiserechnung in der PEK abzugeben", props.Text{Style: consts.Bold})
+			m.Text(label(lang, "Formular ist vom Leiter bzw. der Leiterin der Schulveranstaltung mit der Reiserechnung in der PEK abzugeben"), props.Text{Style: consts.Bold})
 		})
 	})
 
 	m.Row(10, func() {
 		m.Col(12, func() {
 			m.Col(6, func() {
-				m.Text("Veranstaltung:", props.Text{
+				m.Text(label(lang, "Veranstaltung:"), props.Text{
 					Top:   2.5,
 					Align: consts.Left,
 				})
@@ -745,13 +749,13 @@ func GenerateCompensationForEducationalSupport(path string, app db.Application)
 	m.Row(10, func() {
 		m.Col(12, func() {
 			m.Col(6, func() {
-				m.Text("Datum:", props.Text{
+				m.Text(label(lang, "Datum:"), props.Text{
 					Top:   2.5,
 					Align: consts.Left,
 				})
 			})
-			sweekday := getWeekday(int(app.StartTime.In(loc).Weekday()))
-			eweekday := getWeekday(int(app.EndTime.In(loc).Weekday()))
+			sweekday := getWeekday(lang, int(app.StartTime.In(loc).Weekday()))
+			eweekday := getWeekday(lang, int(app.EndTime.In(loc).Weekday()))
 
 			m.Text(fmt.Sprintf("%v, %v - %v, %v",
 				sweekday, app.StartTime.In(loc).Format("02. 01. 2006 15:04"),
@@ -768,7 +772,7 @@ func GenerateCompensationForEducationalSupport(path string, app db.Application)
 	m.Row(10, func() {
 		m.Col(6, func() {
 			m.Col(3, func() {
-				m.Text("Leitung (2.):", props.Text{
+				m.Text(label(lang, "Leitung (2.):"), props.Text{
 					Top:   2.5,
 					Align: consts.Left,
 				})
@@ -781,7 +785,7 @@ func GenerateCompensationForEducationalSupport(path string, app db.Application)
 		})
 		m.Col(6, func() {
 			m.Col(3, func() {
-				m.Text("Verwendungsgruppe:", props.Text{
+				m.Text(label(lang, "Verwendungsgruppe:"), props.Text{
 					Top:   2.5,
 					Align: consts.Left,
 				})
@@ -797,12 +801,12 @@ func GenerateCompensationForEducationalSupport(path string, app db.Application)
 	m.Row(10, func() {
 		m.Col(6, func() {
 			m.Col(3, func() {
-				m.Text("Beginn:", props.Text{
+				m.Text(label(lang, "Beginn:"), props.Text{
 					Top:   2.5,
 					Align: consts.Left,
 				})
 			})
-			weekday := getWeekday(int(leader.AttendanceFrom.In(loc).Weekday()))
+			weekday := getWeekday(lang, int(leader.AttendanceFrom.In(loc).Weekday()))
 			m.Text(fmt.Sprintf("%v, %v", weekday, leader.AttendanceFrom.In(loc).Format("02.01.2006 15:04")),
 				props.Text{
 					Top:   2.5,
@@ -812,12 +816,12 @@ func GenerateCompensationForEducationalSupport(path string, app db.Application)
 		})
 		m.Col(6, func() {
 			m.Col(3, func() {
-				m.Text("Ende:", props.Text{
+				m.Text(label(lang, "Ende:"), props.Text{
 					Top:   2.5,
 					Align: consts.Left,
 				})
 			})
-			weekday := getWeekday(int(leader.AttendanceTill.In(loc).Weekday()))
+			weekday := getWeekday(lang, int(leader.AttendanceTill.In(loc).Weekday()))
 			m.Text(fmt.Sprintf("%v, %v", weekday, leader.AttendanceTill.In(loc).Format("02.01.2006 15:04")),
 				props.Text{
 					Top:   2.5,
@@ -830,7 +834,7 @@ func GenerateCompensationForEducationalSupport(path string, app db.Application)
 	m.Row(3, func() {})
 	m.Row(10, func() {
 		m.Col(12, func() {
-			m.Text("Pädagogisch-inhaltliche Betreuung: (1.)", props.Text{
+			m.Text(label(lang, "Pädagogisch-inhaltliche Betreuung: (1.)"), props.Text{
 				Top:   3,
 				Align: consts.Left,
 				Style: consts.Bold,
@@ -840,8 +844,8 @@ func GenerateCompensationForEducationalSupport(path string, app db.Application)
 
 	tableString := make([][]string, 0)
 	for _, teacher := range teachers {
-		sweekday := getWeekday(int(app.StartTime.In(loc).Weekday()))
-		eweekday := getWeekday(int(app.EndTime.In(loc).Weekday()))
+		sweekday := getWeekday(lang, int(app.StartTime.In(loc).Weekday()))
+		eweekday := getWeekday(lang, int(app.EndTime.In(loc).Weekday()))
 		row := []string{
 			teacher.Name,
 			fmt.Sprintf("L%d", teacher.Group),
@@ -850,7 +854,7 @@ func GenerateCompensationForEducationalSupport(path string, app db.Application)
 		}
 		tableString = append(tableString, row)
 	}
-	m.TableList([]string{"Name", "Verwendungsgruppe", "Beginn", "Ende"}, tableString, props.TableList{
+	m.TableList([]string{"Name", label(lang, "Verwendungsgruppe"), label(lang, "Beginn"), label(lang, "Ende")}, tableString, props.TableList{
 		Align: consts.Center,
 		HeaderProp: props.TableListContent{
 			GridSizes: []uint{3, 3, 3, 3},
@@ -865,24 +869,20 @@ func GenerateCompensationForEducationalSupport(path string, app db.Application)
 	m.Line(1.0)
 	m.Row(8, func() {
 		m.Col(12, func() {
-			m.Text("Datum und Unterschrift des Leiters der Schulveranstaltung")
+			m.Text(label(lang, "Datum und Unterschrift des Leiters der Schulveranstaltung"))
 		})
 	})
 	m.Row(5, func() {})
 	m.Line(1.0)
 	m.Row(10, func() {
 		m.Col(12, func() {
-			m.Text("1. Dem Lehrer gebührt für die Teilnahme an mindestens zweitägigen Schulveranstaltungen"+
-				" mit Nächtigung, sofern er die pädagogisch-inhaltliche Betreuung"+
-				" einer Schülergruppe innehat, eine Abgeltung.", props.Text{Size: 8})
+			m.Text(label(lang, "1. Dem Lehrer gebührt für die Teilnahme an mindestens zweitägigen Schulveranstaltungen mit Nächtigung, sofern er die pädagogisch-inhaltliche Betreuung einer Schülergruppe innehat, eine Abgeltung."), props.Text{Size: 8})
 		})
 	})
 	m.Row(5, func() {})
 	m.Row(10, func() {
 		m.Col(12, func() {
-			m.Text("2. Weiters gebührt dem Leiter einer mindestens viertägigen Schulveranstaltung als"+
-				"Abgeltung die Einrechnung in die Lehrverpflichtung von 4.55 WE in jener Woche in der die"+
-				"Schulveranstaltung endet.", props.Text{Size: 8})
+			m.Text(label(lang, "2. Weiters gebührt dem Leiter einer mindestens viertägigen Schulveranstaltung als Abgeltung die Einrechnung in die Lehrverpflichtung von 4.55 WE in jener Woche in der die Schulveranstaltung endet."), props.Text{Size: 8})
 		})
 	})
 	savePath := filepath.Join(path, CompensationForEducationalSupportFileName)
@@ -897,13 +897,23 @@ func GenerateCompensationForEducationalSupport(path string, app db.Application)
 // It will be saved under path, and the given username is used to log into the untis service.
 // The teacher string is the teachers abbrevation for the untis service
 // It will return a string array of paths to all generated pdfs or an error if the operation wasn't successful
-func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Application) (string, error) {
-	client := untis.GetClient(username)
-	defer client.Close()
+// The labels of the form are written in lang (German or English), German is used for any other language
+func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Application, lang string) (string, error) {
+	client, ok := untis.GetClient(username)
+	if !ok {
+		return "", fmt.Errorf("no untis session of %v", username)
+	}
 	loc, err := time.LoadLocation("Europe/Vienna")
 	if err != nil {
 		return "", fmt.Errorf("couldn't load timezone")
 	}
+	err = client.Acquire()
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = client.Release()
+	}()
 	m := pdf.NewMaroto(consts.Portrait, consts.A4)
 	m.SetPageMargins(10, 15, 10)
 
@@ -921,7 +931,7 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 			m.ColSpace(2)
 
 			m.Col(2, func() {
-				m.Text("Abwesenheitsmeldung eines Lehrers", props.Text{
+				m.Text(label(lang, "Abwesenheitsmeldung eines Lehrers"), props.Text{
 					Align:  consts.Center,
 					Family: consts.Helvetica,
 					Size:   12,
@@ -946,7 +956,7 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 	m.Row(3, func() {})
 	m.Row(10, func() {
 		m.Col(12, func() {
-			m.Text("Allgemeine Informationen:", props.Text{
+			m.Text(label(lang, "Allgemeine Informationen:"), props.Text{
 				Top:   3,
 				Align: consts.Left,
 				Style: consts.Bold,
@@ -996,12 +1006,12 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 	m.Row(10, func() {
 		m.Col(6, func() {
 			m.Col(3, func() {
-				m.Text("Von:", props.Text{
+				m.Text(label(lang, "Von:"), props.Text{
 					Top:   2.5,
 					Align: consts.Left,
 				})
 			})
-			weekday := getWeekday(int(app.StartTime.In(loc).Weekday()))
+			weekday := getWeekday(lang, int(app.StartTime.In(loc).Weekday()))
 			m.Text(fmt.Sprintf("%v, %v", weekday, app.StartTime.In(loc).Format("02.01.2006 15:04")),
 				props.Text{
 					Top:   2.5,
@@ -1011,12 +1021,12 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 		})
 		m.Col(6, func() {
 			m.Col(3, func() {
-				m.Text("Bis:", props.Text{
+				m.Text(label(lang, "Bis:"), props.Text{
 					Top:   2.5,
 					Align: consts.Left,
 				})
 			})
-			weekday := getWeekday(int(app.EndTime.In(loc).Weekday()))
+			weekday := getWeekday(lang, int(app.EndTime.In(loc).Weekday()))
 			m.Text(fmt.Sprintf("%v, %v", weekday, app.EndTime.In(loc).Format("02.01.2006 15:04")), props.Text{
 				Top:   2.5,
 				Align: consts.Center,
@@ -1028,7 +1038,7 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 	m.Row(10, func() {
 		m.Col(6, func() {
 			m.Col(3, func() {
-				m.Text("Anmerkungen:", props.Text{
+				m.Text(label(lang, "Anmerkungen:"), props.Text{
 					Top:   2.5,
 					Align: consts.Left,
 				})
@@ -1048,7 +1058,7 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 	m.Row(3, func() {})
 	m.Row(10, func() {
 		m.Col(12, func() {
-			m.Text("Abwesenheitsgrund:", props.Text{
+			m.Text(label(lang, "Abwesenheitsgrund:"), props.Text{
 				Top:   3,
 				Align: consts.Left,
 				Style: consts.Bold,
@@ -1061,7 +1071,7 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 			m.Row(10, func() {
 				m.Col(12, func() {
 					m.Col(6, func() {
-						m.Text("Schulveranstaltung:", props.Text{
+						m.Text(label(lang, "Schulveranstaltung:"), props.Text{
 							Top:   2.5,
 							Align: consts.Left,
 						})
@@ -1077,7 +1087,7 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 	} else if app.Kind == db.Training {
 		m.Row(10, func() {
 			m.Col(12, func() {
-				m.Text("Fortbildung:", props.Text{
+				m.Text(label(lang, "Fortbildung:"), props.Text{
 					Top:   3,
 					Align: consts.Left,
 					Style: consts.Italic,
@@ -1087,7 +1097,7 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 		m.Row(10, func() {
 			m.Col(6, func() {
 				m.Col(3, func() {
-					m.Text("Titel der Fortbildung:", props.Text{
+					m.Text(label(lang, "Titel der Fortbildung:"), props.Text{
 						Top:   2.5,
 						Align: consts.Left,
 					})
@@ -1100,7 +1110,7 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 			})
 			m.Col(6, func() {
 				m.Col(3, func() {
-					m.Text("PH-Zahl:", props.Text{
+					m.Text(label(lang, "PH-Zahl:"), props.Text{
 						Top:   2.5,
 						Align: consts.Left,
 					})
@@ -1116,7 +1126,7 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 		m.Row(10, func() {
 			m.Col(6, func() {
 				m.Col(3, func() {
-					m.Text("Art der Veranstaltung:", props.Text{
+					m.Text(label(lang, "Art der Veranstaltung:"), props.Text{
 						Top:   2.5,
 						Align: consts.Left,
 					})
@@ -1127,12 +1137,12 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 					st = "Seminar"
 					break
 				case db.Conference:
-					st = "Tagung"
+					st = label(lang, "Tagung")
 					break
 				case db.Course:
-					st = "Lehrgang"
+					st = label(lang, "Lehrgang")
 				case db.Miscellaneous:
-					st = "Sonstiger Grund: " + app.TrainingDetails.MiscellaneousReason
+					st = label(lang, "Sonstiger Grund: ") + app.TrainingDetails.MiscellaneousReason
 				}
 				m.Text(st, props.Text{
 					Top:   2.5,
@@ -1142,7 +1152,7 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 			})
 			m.Col(6, func() {
 				m.Col(3, func() {
-					m.Text("Veranstalter:", props.Text{
+					m.Text(label(lang, "Veranstalter:"), props.Text{
 						Top:   2.5,
 						Align: consts.Left,
 					})
@@ -1158,7 +1168,7 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 		m.Row(10, func() {
 			m.Col(12, func() {
 				m.Col(6, func() {
-					m.Text("Anderer Grund:", props.Text{
+					m.Text(label(lang, "Anderer Grund:"), props.Text{
 						Top:   2.5,
 						Align: consts.Left,
 					})
@@ -1166,15 +1176,15 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 				st := ""
 				switch app.OtherReasonDetails.Kind {
 				case db.Careleave:
-					st = "Pflegefreistellung"
+					st = label(lang, "Pflegefreistellung")
 					break
 				case db.ServiceMandate:
-					st = "Dienstauftrag"
+					st = label(lang, "Dienstauftrag")
 					break
 				case db.MedicalAppointment:
-					st = "Arzttermin"
+					st = label(lang, "Arzttermin")
 				case db.Miscellaneous:
-					st = "Sonstige Gründe"
+					st = label(lang, "Sonstige Gründe")
 				}
 				m.Text(st, props.Text{
 					Top:   2.5,
@@ -1201,7 +1211,7 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 				})
 				m.Col(6, func() {
 					m.Col(3, func() {
-						m.Text("Titel:", props.Text{
+						m.Text(label(lang, "Titel:"), props.Text{
 							Top:   2.5,
 							Align: consts.Left,
 						})
@@ -1218,7 +1228,7 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 			m.Row(10, func() {
 				m.Col(12, func() {
 					m.Col(6, func() {
-						m.Text("Grund:", props.Text{
+						m.Text(label(lang, "Grund:"), props.Text{
 							Top:   2.5,
 							Align: consts.Left,
 						})
@@ -1237,7 +1247,7 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 	m.Row(3, func() {})
 	m.Row(10, func() {
 		m.Col(12, func() {
-			m.Text("Supplierungen:", props.Text{
+			m.Text(label(lang, "Supplierungen:"), props.Text{
 				Top:   3,
 				Align: consts.Left,
 				Style: consts.Bold,
@@ -1249,25 +1259,18 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 	var untisname string
 	if teacher == "self" {
 		var err error
-		err = client.Authenticate()
-		if err != nil {
-			return "", err
-		}
 		lessons, err = client.GetTimetableOfTeacher(app.StartTime.In(loc), app.EndTime.In(loc))
 		if err != nil {
 			return "", err
 		}
-		untisnameArr, err := client.ResolveTeachers([]int{client.PersonID})
+		personID, _ := client.Person()
+		untisnameArr, err := client.ResolveTeachers([]int{personID})
 		if err != nil {
 			return "", err
 		}
 		untisname = untisnameArr[0]
 	} else {
 		var err error
-		err = client.Authenticate()
-		if err != nil {
-			return "", err
-		}
 		lessons, err = client.GetTimetableOfSpecificTeacher(app.StartTime.In(loc), app.EndTime.In(loc), teacher)
 		if err != nil {
 			return "", err
@@ -1318,7 +1321,7 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 		tableStrings = append(tableStrings, row)
 	}
 	sortTableByDate(tableStrings)
-	m.TableList([]string{"H/R/E", "Jahrgang", "Datum", "Stunde", "Saal", "LK Supp.", "LK Entf.", "Paraphe"},
+	m.TableList([]string{label(lang, "H/R/E"), label(lang, "Jahrgang"), label(lang, "Datum"), label(lang, "Stunde"), label(lang, "Saal"), label(lang, "LK Supp."), label(lang, "LK Entf."), label(lang, "Paraphe")},
 		tableStrings, props.TableList{
 			Align: consts.Center,
 			HeaderProp: props.TableListContent{
@@ -1333,7 +1336,7 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 	m.Row(3, func() {})
 	m.Row(10, func() {
 		m.Col(12, func() {
-			m.Text("Kenntnisnahme:", props.Text{
+			m.Text(label(lang, "Kenntnisnahme:"), props.Text{
 				Top:   3,
 				Align: consts.Left,
 				Style: consts.Bold,
@@ -1347,9 +1350,9 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 	ackStrings := make([][]string, 4)
 	ackStrings[0] = []string{"AV", spacer, spacer}
 	ackStrings[1] = []string{"WL", spacer, spacer}
-	ackStrings[2] = []string{"Ersteller/in", spacer, spacer}
-	ackStrings[3] = []string{"UNTIS Eintragung", spacer, spacer}
-	m.TableList([]string{"Stelle", "Datum", "Paraphe"}, ackStrings, props.TableList{
+	ackStrings[2] = []string{label(lang, "Ersteller/in"), spacer, spacer}
+	ackStrings[3] = []string{label(lang, "UNTIS Eintragung"), spacer, spacer}
+	m.TableList([]string{label(lang, "Stelle"), label(lang, "Datum"), label(lang, "Paraphe")}, ackStrings, props.TableList{
 		Align: consts.Center,
 		HeaderProp: props.TableListContent{
 			GridSizes: []uint{2, 5, 5},
@@ -1371,7 +1374,8 @@ func GenerateAbsenceFormForTeacher(path, username, teacher string, app db.Applic
 // GenerateTravelInvoice generates the travel invoice form for a teacher based on the given db.TravelInvoice
 // It will be saved under path, and the given short name will be used in the form. The uuid is the uuid
 // of the parent db.Application, so a QR Code can be generated
-func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string) (string, error) {
+// The labels of the form are written in lang (German or English), German is used for any other language
+func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid, lang string) (string, error) {
 	loc, err := time.LoadLocation("Europe/Vienna")
 	if err != nil {
 		return "", fmt.Errorf("couldn't load timezone")
@@ -1392,7 +1396,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 		m.ColSpace(2)
 
 		m.Col(2, func() {
-			m.Text("Reiserechnung Inland", props.Text{
+			m.Text(label(lang, "Reiserechnung Inland"), props.Text{
 				Align:  consts.Center,
 				Family: consts.Helvetica,
 				Size:   12,
@@ -1413,7 +1417,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 	m.Line(4.0)
 	m.Row(10, func() {
 		m.Col(2, func() {
-			m.Text("Familienname:", props.Text{
+			m.Text(label(lang, "Familienname:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1426,7 +1430,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 			})
 		})
 		m.Col(2, func() {
-			m.Text("Vorname:", props.Text{
+			m.Text(label(lang, "Vorname:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1439,7 +1443,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 			})
 		})
 		m.Col(2, func() {
-			m.Text("Akademischer Grad:", props.Text{
+			m.Text(label(lang, "Akademischer Grad:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1452,7 +1456,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 			})
 		})
 		m.Col(2, func() {
-			m.Text("Amtstitel:", props.Text{
+			m.Text(label(lang, "Amtstitel:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1467,7 +1471,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 	})
 	m.Row(10, func() {
 		m.Col(1, func() {
-			m.Text("Beginn:", props.Text{
+			m.Text(label(lang, "Beginn:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1480,7 +1484,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 			})
 		})
 		m.Col(1, func() {
-			m.Text("Ende:", props.Text{
+			m.Text(label(lang, "Ende:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1493,7 +1497,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 			})
 		})
 		m.Col(2, func() {
-			m.Text("Reisekostenvorschuss:", props.Text{
+			m.Text(label(lang, "Reisekostenvorschuss:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1506,12 +1510,12 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 			})
 		})
 		m.Col(2, func() {
-			m.Text("Anzahl der Beilagen:", props.Text{
+			m.Text(label(lang, "Anzahl der Beilagen:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
 		})
-		extras, _ := ioutil.ReadDir(filepath.Join(path, UploadFolderName))
+		extras, _ := Receipts(uuid, "")
 		m.Col(1, func() {
 			m.Text(strconv.Itoa(len(extras)), props.Text{
 				Top:   2.5,
@@ -1522,7 +1526,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 	})
 	m.Row(10, func() {
 		m.Col(1, func() {
-			m.Text("Personalnr:", props.Text{
+			m.Text(label(lang, "Personalnr:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1535,7 +1539,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 			})
 		})
 		m.Col(1, func() {
-			m.Text("Bearbeiter:", props.Text{
+			m.Text(label(lang, "Bearbeiter:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1548,7 +1552,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 			})
 		})
 		m.Col(1, func() {
-			m.Text("Prüfer:", props.Text{
+			m.Text(label(lang, "Prüfer:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1561,7 +1565,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 			})
 		})
 		m.Col(1, func() {
-			m.Text("Eingelangt:", props.Text{
+			m.Text(label(lang, "Eingelangt:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1576,7 +1580,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 	})
 	m.Row(10, func() {
 		m.Col(2, func() {
-			m.Text("Ausgangsort:", props.Text{
+			m.Text(label(lang, "Ausgangsort:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1589,7 +1593,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 			})
 		})
 		m.Col(2, func() {
-			m.Text("Zielort:", props.Text{
+			m.Text(label(lang, "Zielort:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1605,7 +1609,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 	m.Line(2.0)
 	m.Row(10, func() {
 		m.Col(12, func() {
-			m.Text("Zusätzliche Daten:", props.Text{
+			m.Text(label(lang, "Zusätzliche Daten:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 				Style: consts.Bold,
@@ -1614,29 +1618,29 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 	})
 	data := ""
 	if app.OfficialBusinessCardGot {
-		data = data + "Amtl. Businesskarte erhalten;   "
+		data = data + label(lang, "Amtl. Businesskarte erhalten;   ")
 	}
 	if app.TravelGrant {
-		data = data + "Beförderungszuschuss;   "
+		data = data + label(lang, "Beförderungszuschuss;   ")
 	}
 	if app.ReplacementForAdvantageCard {
-		data = data + "Ersatz für Vorteilscard (Beleg erford.);   "
+		data = data + label(lang, "Ersatz für Vorteilscard (Beleg erford.);   ")
 	}
 	if app.ReplacementForTrainCardClass2 {
-		data = data + "Ersatz für Bahnfahrt 2. Kl (Beleg erford.);   "
+		data = data + label(lang, "Ersatz für Bahnfahrt 2. Kl (Beleg erford.);   ")
 	}
 	if app.KilometreAllowance {
-		data = data + "Amtl. Kilometergeld für eigenen PKW (" +
-			strconv.FormatFloat(float64(app.KilometreAmount), 'f', 2, 32) + " km);   "
+		data = data + fmt.Sprintf(label(lang, "Amtl. Kilometergeld für eigenen PKW (%v km);   "),
+			strconv.FormatFloat(float64(app.KilometreAmount), 'f', 2, 32))
 	}
 	if app.NRAndIndicationsOfParticipants {
-		data = data + "Anzahl und namentliche Angabe der Mitfahrer;   "
+		data = data + label(lang, "Anzahl und namentliche Angabe der Mitfahrer;   ")
 	}
 	if app.TravelCostsCited {
-		data = data + "Angeführte andere Reisekosten (nur gegen Beleg);   "
+		data = data + label(lang, "Angeführte andere Reisekosten (nur gegen Beleg);   ")
 	}
 	if app.NoTravelCosts {
-		data = data + "Keine Reisekosten;   "
+		data = data + label(lang, "Keine Reisekosten;   ")
 	}
 	data = data[0 : len(data)-4]
 	m.Row(10, func() {
@@ -1650,7 +1654,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 	})
 	m.Row(10, func() {
 		m.Col(1, func() {
-			m.Text("Tagesgebühr:", props.Text{
+			m.Text(label(lang, "Tagesgebühr:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1658,13 +1662,13 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 		day := ""
 		switch app.DailyChargesMode {
 		case db.DailyChargesType1:
-			day = "Tarif I"
+			day = label(lang, "Tarif I")
 			break
 		case db.DailyChargesType2:
-			day = "Tarif II"
+			day = label(lang, "Tarif II")
 			break
 		case db.ToBeShortened:
-			day = "zu kürzen um " + strconv.FormatFloat(float64(app.ShortenedAmount), 'f', 2, 32)
+			day = label(lang, "zu kürzen um ") + strconv.FormatFloat(float64(app.ShortenedAmount), 'f', 2, 32)
 		}
 		m.Col(2, func() {
 			m.Text(day, props.Text{
@@ -1674,9 +1678,8 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 			})
 		})
 		m.Col(5, func() {
-			m.Text(strconv.Itoa(app.Breakfasts)+" Frühstück; "+
-				strconv.Itoa(app.Lunches)+" Mittagessen; "+
-				strconv.Itoa(app.Dinners)+" Abendessen",
+			m.Text(fmt.Sprintf(label(lang, "%d Frühstück; %d Mittagessen; %d Abendessen"),
+				app.Breakfasts, app.Lunches, app.Dinners),
 				props.Text{
 					Top:   2.5,
 					Align: consts.Middle,
@@ -1684,7 +1687,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 				})
 		})
 		m.Col(2, func() {
-			m.Text("Nächtigungsgeb.:", props.Text{
+			m.Text(label(lang, "Nächtigungsgeb.:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1692,13 +1695,13 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 		night := ""
 		switch app.NightlyChargesMode {
 		case db.ProofNeededForCharges:
-			night = "mit Nachweis"
+			night = label(lang, "mit Nachweis")
 			break
 		case db.NoProofNeeded:
-			night = "ohne Nachweis"
+			night = label(lang, "ohne Nachweis")
 			break
 		case db.NoClaimForNightlyCharges:
-			night = "kein Anspruch"
+			night = label(lang, "kein Anspruch")
 		}
 		m.Col(2, func() {
 			m.Text(night, props.Text{
@@ -1711,15 +1714,15 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 	m.Line(2.0)
 	m.Row(10, func() {
 		m.Col(12, func() {
-			m.Text("Berechnungsblatt", props.Text{
+			m.Text(label(lang, "Berechnungsblatt"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 				Style: consts.Bold,
 			})
 		})
 	})
-	header := []string{"Nr.", "Tag", "Beginn", "Ende", "Art Gebühren",
-		"Kilometer", "Reisek.", "Tagk.", "Nachtk.", "Nebenk.", "Summe"}
+	header := []string{label(lang, "Nr."), label(lang, "Tag"), label(lang, "Beginn"), label(lang, "Ende"), label(lang, "Art Gebühren"),
+		label(lang, "Kilometer"), label(lang, "Reisek."), label(lang, "Tagk."), label(lang, "Nachtk."), label(lang, "Nebenk."), label(lang, "Summe")}
 	content := make([][]string, 0)
 	for _, r := range app.Calculation.Rows {
 		row := make([]string, len(header))
@@ -1731,20 +1734,20 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 		for _, kind := range r.KindsOfCost {
 			switch kind {
 			case db.TravelCosts:
-				geb = geb + "Reisekosten, "
+				geb = geb + label(lang, "Reisekosten, ")
 				row[5] = strconv.FormatFloat(float64(r.Kilometres), 'f', 2, 32)
 				row[6] = strconv.FormatFloat(float64(r.TravelCosts), 'f', 2, 32)
 				break
 			case db.DailyCharges:
-				geb = geb + "Tagesgebühr, "
+				geb = geb + label(lang, "Tagesgebühr, ")
 				row[7] = strconv.FormatFloat(float64(r.DailyCharges), 'f', 2, 32)
 				break
 			case db.NightlyCharges:
-				geb = geb + "Nächtigungsgebühr, "
+				geb = geb + label(lang, "Nächtigungsgebühr, ")
 				row[8] = strconv.FormatFloat(float64(r.NightlyCharges), 'f', 2, 32)
 				break
 			case db.AdditionalCosts:
-				geb = geb + "Nebenkosten, "
+				geb = geb + label(lang, "Nebenkosten, ")
 				row[9] = strconv.FormatFloat(float64(r.AdditionalCosts), 'f', 2, 32)
 				break
 			}
@@ -1756,7 +1759,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 		row[10] = strconv.FormatFloat(float64(r.Sum), 'f', 2, 32)
 		content = append(content, row)
 	}
-	content = append(content, []string{"", "", "", "", "Summe:", "",
+	content = append(content, []string{"", "", "", "", label(lang, "Summe:"), "",
 		strconv.FormatFloat(float64(app.Calculation.SumTravelCosts), 'f', 2, 32),
 		strconv.FormatFloat(float64(app.Calculation.SumDailyCharges), 'f', 2, 32),
 		strconv.FormatFloat(float64(app.Calculation.SumNightlyCharges), 'f', 2, 32),
@@ -1775,7 +1778,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 	})
 	m.Row(5, func() {
 		m.Col(4, func() {
-			m.Text("Die sachliche Richtigkeit wird besätigt:", props.Text{
+			m.Text(label(lang, "Die sachliche Richtigkeit wird besätigt:"), props.Text{
 				Size:  7,
 				Top:   2.5,
 				Align: consts.Center,
@@ -1783,7 +1786,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 		})
 		m.ColSpace(4)
 		m.Col(4, func() {
-			m.Text("gem. § 37 RGV 55: für die Richtigkeit der Angaben:", props.Text{
+			m.Text(label(lang, "gem. § 37 RGV 55: für die Richtigkeit der Angaben:"), props.Text{
 				Size:  7,
 				Top:   2.5,
 				Align: consts.Center,
@@ -1794,7 +1797,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 	m.Line(1.0)
 	m.Row(5, func() {
 		m.Col(4, func() {
-			m.Text("(Datum, Unterschrift der/s Anweisungsberechtigten)", props.Text{
+			m.Text(label(lang, "(Datum, Unterschrift der/s Anweisungsberechtigten)"), props.Text{
 				Size:  7,
 				Top:   2.5,
 				Align: consts.Center,
@@ -1802,7 +1805,7 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 		})
 		m.ColSpace(4)
 		m.Col(4, func() {
-			m.Text("(Datum, Unterschrift der/s Rechnungslegers/in)", props.Text{
+			m.Text(label(lang, "(Datum, Unterschrift der/s Rechnungslegers/in)"), props.Text{
 				Size:  7,
 				Top:   2.5,
 				Align: consts.Center,
@@ -1821,7 +1824,8 @@ func GenerateTravelInvoice(path, short string, app db.TravelInvoice, uuid string
 // GenerateBusinessTripApplication generates a business trip application form for a teacher based on the given db.BusinessTripApplication
 // It will be saved under path, and the given short name will be used in the form. The uuid is the uuid
 // of the parent db.Application, so a QR Code can be generated
-func GenerateBusinessTripApplication(path, short string, app db.BusinessTripApplication, uuid string) (string, error) {
+// The labels of the form are written in lang (German or English), German is used for any other language
+func GenerateBusinessTripApplication(path, short string, app db.BusinessTripApplication, uuid, lang string) (string, error) {
 	loc, err := time.LoadLocation("Europe/Vienna")
 	if err != nil {
 		return "", fmt.Errorf("couldn't load timezone")
@@ -1842,7 +1846,7 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 			m.ColSpace(2)
 
 			m.Col(2, func() {
-				m.Text("Dienstreiseantrag Inland", props.Text{
+				m.Text(label(lang, "Dienstreiseantrag Inland"), props.Text{
 					Align:  consts.Center,
 					Family: consts.Helvetica,
 					Size:   12,
@@ -1878,7 +1882,7 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 			})
 		})
 		m.Col(2, func() {
-			m.Text("Akad. Grad:", props.Text{
+			m.Text(label(lang, "Akad. Grad:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1891,7 +1895,7 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 			})
 		})
 		m.Col(2, func() {
-			m.Text("Amtstitel:", props.Text{
+			m.Text(label(lang, "Amtstitel:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1907,7 +1911,7 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 	m.Line(1.0)
 	m.Row(10, func() {
 		m.Col(2, func() {
-			m.Text("Personalnr.:", props.Text{
+			m.Text(label(lang, "Personalnr.:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1920,7 +1924,7 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 			})
 		})
 		m.Col(2, func() {
-			m.Text("Reiseziel:", props.Text{
+			m.Text(label(lang, "Reiseziel:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -1936,32 +1940,32 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 	m.Line(1.0)
 	m.Row(10, func() {
 		m.Col(2, func() {
-			m.Text("Dienstreise", props.Text{
+			m.Text(label(lang, "Dienstreise"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
 		})
 		m.Col(2, func() {
-			m.Text("Beginn:", props.Text{
+			m.Text(label(lang, "Beginn:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
 		})
 		m.Col(3, func() {
-			m.Text(app.TripBeginTime.In(loc).Format("02. 01. 2006 15:04 Uhr"), props.Text{
+			m.Text(app.TripBeginTime.In(loc).Format(label(lang, "02. 01. 2006 15:04 Uhr")), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 				Style: consts.Italic,
 			})
 		})
 		m.Col(2, func() {
-			m.Text("Ende:", props.Text{
+			m.Text(label(lang, "Ende:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
 		})
 		m.Col(3, func() {
-			m.Text(app.TripEndTime.In(loc).Format("02. 01. 2006 15:04 Uhr"), props.Text{
+			m.Text(app.TripEndTime.In(loc).Format(label(lang, "02. 01. 2006 15:04 Uhr")), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 				Style: consts.Italic,
@@ -1971,32 +1975,32 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 	m.Line(1.0)
 	m.Row(10, func() {
 		m.Col(2, func() {
-			m.Text("Dienstverrichtung", props.Text{
+			m.Text(label(lang, "Dienstverrichtung"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
 		})
 		m.Col(2, func() {
-			m.Text("Beginn:", props.Text{
+			m.Text(label(lang, "Beginn:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
 		})
 		m.Col(3, func() {
-			m.Text(app.ServiceBeginTime.In(loc).Format("02. 01. 2006 15:04 Uhr"), props.Text{
+			m.Text(app.ServiceBeginTime.In(loc).Format(label(lang, "02. 01. 2006 15:04 Uhr")), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 				Style: consts.Italic,
 			})
 		})
 		m.Col(2, func() {
-			m.Text("Ende:", props.Text{
+			m.Text(label(lang, "Ende:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
 		})
 		m.Col(3, func() {
-			m.Text(app.ServiceEndTime.In(loc).Format("02. 01. 2006 15:04 Uhr"), props.Text{
+			m.Text(app.ServiceEndTime.In(loc).Format(label(lang, "02. 01. 2006 15:04 Uhr")), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 				Style: consts.Italic,
@@ -2006,7 +2010,7 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 	m.Line(1.0)
 	m.Row(10, func() {
 		m.Col(2, func() {
-			m.Text("Reisezweck:", props.Text{
+			m.Text(label(lang, "Reisezweck:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -2022,7 +2026,7 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 	m.Line(1.0)
 	m.Row(10, func() {
 		m.Col(2, func() {
-			m.Text("Reiseart:", props.Text{
+			m.Text(label(lang, "Reiseart:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -2031,34 +2035,34 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 			travel := ""
 			switch app.TravelMode {
 			case db.OfficialBusinessCardClass2:
-				travel = "Amtl BUSINESSKARTE 2. Kl"
+				travel = label(lang, "Amtl BUSINESSKARTE 2. Kl")
 				break
 			case db.Passenger:
-				travel = "MITFAHRER/INNEN"
+				travel = label(lang, "MITFAHRER/INNEN")
 				break
 			case db.OfficialBusinessCardClass1:
-				travel = "Amtl. BUSINESSKARTE / BAHNVERRECHNUNG 1. Kl - (Begründung erford.)"
+				travel = label(lang, "Amtl. BUSINESSKARTE / BAHNVERRECHNUNG 1. Kl - (Begründung erford.)")
 				break
 			case db.TravelGrant:
-				travel = "BEFÖRDERUNGSZUSCHUSS"
+				travel = label(lang, "BEFÖRDERUNGSZUSCHUSS")
 				break
 			case db.Flight:
-				travel = "FLUG"
+				travel = label(lang, "FLUG")
 				break
 			case db.TrainClass2:
-				travel = "BAHN 2. Kl. - (Beleg erford.)"
+				travel = label(lang, "BAHN 2. Kl. - (Beleg erford.)")
 				break
 			case db.CheapFlight:
-				travel = "BILLIGFLUG"
+				travel = label(lang, "BILLIGFLUG")
 				break
 			case db.OwnCar:
-				travel = "EIGENER PKW - (Begründung erford.)"
+				travel = label(lang, "EIGENER PKW - (Begründung erford.)")
 				break
 			case db.SleepTrain:
-				travel = "SCHLAFWAGEN"
+				travel = label(lang, "SCHLAFWAGEN")
 				break
 			case db.Bus:
-				travel = "BUS - (Beleg erford.)"
+				travel = label(lang, "BUS - (Beleg erford.)")
 			}
 			m.Text(travel, props.Text{
 				Top:   2.5,
@@ -2070,7 +2074,7 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 	m.Line(1.0)
 	m.Row(10, func() {
 		m.Col(3, func() {
-			m.Text("Ausgangspunkt:", props.Text{
+			m.Text(label(lang, "Ausgangspunkt:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -2078,9 +2082,9 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 		m.Col(3, func() {
 			starting := ""
 			if app.StartingPoint == db.Office {
-				starting = "Dienststelle"
+				starting = label(lang, "Dienststelle")
 			} else if app.StartingPoint == db.OwnApartment {
-				starting = "Wohnung"
+				starting = label(lang, "Wohnung")
 			}
 			m.Text(starting, props.Text{
 				Top:   2.5,
@@ -2089,7 +2093,7 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 			})
 		})
 		m.Col(3, func() {
-			m.Text("Endpunkt:", props.Text{
+			m.Text(label(lang, "Endpunkt:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -2097,9 +2101,9 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 		m.Col(3, func() {
 			ending := ""
 			if app.EndPoint == db.Office {
-				ending = "Dienststelle"
+				ending = label(lang, "Dienststelle")
 			} else if app.EndPoint == db.OwnApartment {
-				ending = "Wohnung"
+				ending = label(lang, "Wohnung")
 			}
 			m.Text(ending, props.Text{
 				Top:   2.5,
@@ -2111,7 +2115,7 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 	m.Line(1.0)
 	m.Row(10, func() {
 		m.Col(2, func() {
-			m.Text("Begründung:", props.Text{
+			m.Text(label(lang, "Begründung:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -2127,7 +2131,7 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 	m.Line(1.0)
 	m.Row(10, func() {
 		m.Col(3, func() {
-			m.Text("Sonstige Teilnehmer/innen:", props.Text{
+			m.Text(label(lang, "Sonstige Teilnehmer/innen:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -2152,10 +2156,9 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 		m.Col(6, func() {
 			text := ""
 			if app.BonusMileConfirmation1 {
-				text = "Ich bestätige, dass ich anlässlich von Dienstreisen im Rahmen personenbezogener" +
-					" Bonusprogramme erworbene Prämien nicht privat in Anspruch nehme."
+				text = label(lang, "Ich bestätige, dass ich anlässlich von Dienstreisen im Rahmen personenbezogener Bonusprogramme erworbene Prämien nicht privat in Anspruch nehme.")
 			} else {
-				text = "1. nicht bestätigt"
+				text = label(lang, "1. nicht bestätigt")
 			}
 			m.Text(text, props.Text{
 				Top:   2.5,
@@ -2165,10 +2168,9 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 		m.Col(6, func() {
 			text := ""
 			if app.BonusMileConfirmation1 {
-				text = "Für die Dienstreise verwende ich auf meine Meilenkonto" +
-					" gutgeschriebene, dienstlich erworbene Meilen."
+				text = label(lang, "Für die Dienstreise verwende ich auf meine Meilenkonto gutgeschriebene, dienstlich erworbene Meilen.")
 			} else {
-				text = "2. nicht bestätigt"
+				text = label(lang, "2. nicht bestätigt")
 			}
 			m.Text(text, props.Text{
 				Top:   2.5,
@@ -2179,13 +2181,13 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 	m.Row(10, func() {
 		text := ""
 		if app.TravelCostsPaidBySomeone && app.StayingCostsPaidBySomeone {
-			text = "Es werden Aufenthaltskosten und Reisekosten von " + app.PaidByWhom + " getragen"
+			text = fmt.Sprintf(label(lang, "Es werden Aufenthaltskosten und Reisekosten von %v getragen"), app.PaidByWhom)
 		} else if app.TravelCostsPaidBySomeone && !app.StayingCostsPaidBySomeone {
-			text = "Es werden Reisekosten von " + app.PaidByWhom + " getragen"
+			text = fmt.Sprintf(label(lang, "Es werden Reisekosten von %v getragen"), app.PaidByWhom)
 		} else if !app.TravelCostsPaidBySomeone && app.StayingCostsPaidBySomeone {
-			text = "Es werden Aufenthaltskosten von " + app.PaidByWhom + " getragen"
+			text = fmt.Sprintf(label(lang, "Es werden Aufenthaltskosten von %v getragen"), app.PaidByWhom)
 		} else {
-			text = "Es werden keine Kosten von anderer Stelle getragen"
+			text = label(lang, "Es werden keine Kosten von anderer Stelle getragen")
 		}
 		m.Col(12, func() {
 			m.Text(text, props.Text{
@@ -2196,7 +2198,7 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 	})
 	m.Row(10, func() {
 		m.Col(3, func() {
-			m.Text("Sonstige Kosten:", props.Text{
+			m.Text(label(lang, "Sonstige Kosten:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -2208,7 +2210,7 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 			})
 		})
 		m.Col(3, func() {
-			m.Text("Geschätzte Kosten:", props.Text{
+			m.Text(label(lang, "Geschätzte Kosten:"), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -2224,14 +2226,14 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 	m.Line(1.0)
 	m.Row(5, func() {
 		m.Col(4, func() {
-			m.Text("Antragsteller/in", props.Text{
+			m.Text(label(lang, "Antragsteller/in"), props.Text{
 				Size:  7,
 				Top:   2.5,
 				Align: consts.Center,
 			})
 		})
 		m.Col(8, func() {
-			m.Text("Instituts-/Abteilungsleiter/in", props.Text{
+			m.Text(label(lang, "Instituts-/Abteilungsleiter/in"), props.Text{
 				Size:  7,
 				Top:   2.5,
 				Align: consts.Center,
@@ -2242,13 +2244,13 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 	m.Row(10, func() {
 		t := time.Time{}
 		if app.DateApplicationApproved == t {
-			m.Text("Die vorstehend beantragte Dienstreise wurde noch nicht genehmigt.", props.Text{
+			m.Text(label(lang, "Die vorstehend beantragte Dienstreise wurde noch nicht genehmigt."), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
 		} else {
-			m.Text("Die vorstehend beantragte Dienstreise wird mit "+
-				app.DateApplicationApproved.In(loc).Format("02. 01. 2006")+" genehmigt.", props.Text{
+			m.Text(fmt.Sprintf(label(lang, "Die vorstehend beantragte Dienstreise wird mit %v genehmigt."),
+				app.DateApplicationApproved.In(loc).Format("02. 01. 2006")), props.Text{
 				Top:   2.5,
 				Align: consts.Left,
 			})
@@ -2258,7 +2260,7 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 	m.Line(1.0)
 	m.Row(5, func() {
 		m.Col(4, func() {
-			m.Text("Ort, Datum", props.Text{
+			m.Text(label(lang, "Ort, Datum"), props.Text{
 				Size:  7,
 				Top:   2.5,
 				Align: consts.Center,
@@ -2266,7 +2268,7 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 		})
 		m.ColSpace(4)
 		m.Col(4, func() {
-			m.Text("Unterschrift", props.Text{
+			m.Text(label(lang, "Unterschrift"), props.Text{
 				Size:  7,
 				Top:   2.5,
 				Align: consts.Center,
@@ -2277,7 +2279,7 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 	m.Row(10, func() {
 		m.ColSpace(8)
 		m.Col(4, func() {
-			m.Text("Eingabedatum: "+app.DateApplicationFiled.In(loc).Format("02. 01. 2006"), props.Text{
+			m.Text(label(lang, "Eingabedatum: ")+app.DateApplicationFiled.In(loc).Format("02. 01. 2006"), props.Text{
 				Top:   2.5,
 				Align: consts.Right,
 			})
@@ -2286,7 +2288,7 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 	m.Row(10, func() {
 		m.ColSpace(8)
 		m.Col(4, func() {
-			m.Text("Referent/in: "+app.Referee, props.Text{
+			m.Text(label(lang, "Referent/in: ")+app.Referee, props.Text{
 				Top:   2.5,
 				Align: consts.Right,
 			})
@@ -2297,14 +2299,14 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 			m.ColSpace(6)
 			text := ""
 			if app.BusinessCardEmittedReturn && app.BusinessCardEmittedOutward {
-				text = "Hin- und Rückfahrt"
+				text = label(lang, "Hin- und Rückfahrt")
 			} else if app.BusinessCardEmittedOutward {
-				text = "Hinfahrt"
+				text = label(lang, "Hinfahrt")
 			} else if app.BusinessCardEmittedReturn {
-				text = "Rückfahrt"
+				text = label(lang, "Rückfahrt")
 			}
 			m.Col(6, func() {
-				m.Text("Businesskarte bei "+text+" ausgefolgt.", props.Text{
+				m.Text(fmt.Sprintf(label(lang, "Businesskarte bei %v ausgefolgt."), text), props.Text{
 					Top:   2.5,
 					Align: consts.Right,
 				})
@@ -2321,6 +2323,7 @@ func GenerateBusinessTripApplication(path, short string, app db.BusinessTripAppl
 
 // GenerateTravelInvoiceExcel copies the corresponding excel template and fills it with the information
 // in db.TravelInvoice, it will return the path its saved under on success and an error if it wasn't successful
+// The excel forms are filled into the official german templates, so they are always written in German
 func GenerateTravelInvoiceExcel(path, short string, app db.TravelInvoice) (string, error) {
 	sourceF, err := os.Stat(filepath.Join(TemplatePath, ExcelTemplateTravelInvoicePath))
 	if err != nil {
@@ -2466,7 +2469,7 @@ func GenerateTravelInvoiceExcel(path, short string, app db.TravelInvoice) (strin
 	if err != nil {
 		return "", err
 	}
-	extras, _ := ioutil.ReadDir(filepath.Join(path, UploadFolderName))
+	extras, _ := Receipts(filepath.Base(path), "")
 	err = excel.SetCellValue(Sheet, TIExtraAmount, strconv.Itoa(len(extras)))
 	if err != nil {
 		return "", err
@@ -2751,6 +2754,7 @@ func GenerateTravelInvoiceExcel(path, short string, app db.TravelInvoice) (strin
 
 // GenerateBusinessTripApplicationExcel copies the corresponding excel template and fills it with the information
 // in db.BusinessTripApplication, it will return the path its saved under on success and an error if it wasn't successful
+// The excel forms are filled into the official german templates, so they are always written in German
 func GenerateBusinessTripApplicationExcel(path, short string, app db.BusinessTripApplication) (string, error) {
 	sourceF, err := os.Stat(filepath.Join(TemplatePath, ExcelTemplateBusinessTripApplicationPath))
 	if err != nil {
@@ -3051,23 +3055,23 @@ func GenerateBusinessTripApplicationExcel(path, short string, app db.BusinessTri
 	return newPath, err
 }
 
-// getWeekday resolves an index of weekdays to the german name of this weekday and returns it
-func getWeekday(weekday int) string {
+// getWeekday resolves an index of weekdays to the abbreviated name of this weekday in the language and returns it
+func getWeekday(lang string, weekday int) string {
 	switch weekday {
 	case int(time.Monday):
-		return "Mo"
+		return label(lang, "Mo")
 	case int(time.Tuesday):
-		return "Di"
+		return label(lang, "Di")
 	case int(time.Wednesday):
-		return "Mi"
+		return label(lang, "Mi")
 	case int(time.Thursday):
-		return "Do"
+		return label(lang, "Do")
 	case int(time.Friday):
-		return "Fr"
+		return label(lang, "Fr")
 	case int(time.Saturday):
-		return "Sa"
+		return label(lang, "Sa")
 	case int(time.Sunday):
-		return "So"
+		return label(lang, "So")
 	}
 	return ""
 }
@@ -3126,6 +3130,8 @@ func mergeLessons(lessons []untis.Lesson) []untis.Lesson {
 				n.TeacherIDs = distinctInt(append(n.TeacherIDs, lesson.TeacherIDs...))
 				n.RoomIDs = distinctInt(append(n.RoomIDs, lesson.RoomIDs...))
 				n.ClassIDs = distinctInt(append(n.ClassIDs, lesson.ClassIDs...))
+				n.Subjects = distinctString(append(n.Subjects, lesson.Subjects...))
+				n.SubjectIDs = distinctInt(append(n.SubjectIDs, lesson.SubjectIDs...))
 			}
 		}
 		if !contains {
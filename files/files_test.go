@@ -0,0 +1,115 @@
+package files
+
+import (
+	"bytes"
+	"github.com/refundable-tgm/huginn/db"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestMain runs the tests in the root of the repository like the service is started, so LogoPath can be found
+func TestMain(m *testing.M) {
+	if err := os.Chdir(".."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// travelInvoice returns a travel invoice of a day trip with the given amount of calculation rows
+func travelInvoice(rows int) db.TravelInvoice {
+	begin := time.Date(2021, time.March, 1, 8, 0, 0, 0, time.UTC)
+	invoice := db.TravelInvoice{
+		ID:                 1,
+		Surname:            "Zakall",
+		Name:               "Stefan",
+		Degree:             "DI",
+		TripBeginTime:      begin,
+		TripEndTime:        begin.Add(9 * time.Hour),
+		Staffnr:            938503154,
+		StartingPoint:      "TGM, Wexstraße 19-23, 1200 Wien",
+		EndPoint:           "Hauptbahnhof, 1100 Wien",
+		FilingDate:         begin,
+		DailyChargesMode:   1,
+		NightlyChargesMode: 1,
+		KilometreAllowance: true,
+		KilometreAmount:    25.12,
+	}
+	for i := 0; i < rows; i++ {
+		invoice.Calculation.Rows = append(invoice.Calculation.Rows, db.Row{
+			NR:          i + 1,
+			Date:        begin,
+			Begin:       begin,
+			End:         begin.Add(time.Hour),
+			KindsOfCost: []int{1, 2},
+			Kilometres:  4.32,
+			TravelCosts: 2.4,
+			Sum:         2.4,
+		})
+		invoice.Calculation.SumTravelCosts += 2.4
+	}
+	invoice.Calculation.SumOfSums = invoice.Calculation.SumTravelCosts
+	return invoice
+}
+
+func TestGenerateTravelInvoiceWritesAPDF(t *testing.T) {
+	tests := []struct {
+		name string
+		rows int
+	}{
+		{"without rows", 0},
+		{"a single row", 1},
+		{"rows spanning several pages", 60},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path, err := GenerateTravelInvoice(dir, "szakall", travelInvoice(test.rows), "3fcf7f67-e0ed-4339-99b4-a6765aaa3dc4", "de")
+			if err != nil {
+				t.Fatalf("GenerateTravelInvoice returned %v", err)
+			}
+			if want := filepath.Join(dir, "travel_invoice_szakall.pdf"); path != want {
+				t.Errorf("the pdf was saved at %v, want %v", path, want)
+			}
+			content, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatalf("couldn't read the pdf: %v", err)
+			}
+			if len(content) < 5 {
+				t.Fatal("the pdf is empty")
+			}
+			if !bytes.HasPrefix(content, []byte("%PDF-")) {
+				t.Errorf("the file starts with %q, want a pdf header", content[:5])
+			}
+			if !bytes.Contains(content, []byte("%%EOF")) {
+				t.Error("the pdf isn't terminated by an end of file marker")
+			}
+		})
+	}
+}
+
+func TestGenerateTravelInvoiceIsPaginated(t *testing.T) {
+	short, err := GenerateTravelInvoice(t.TempDir(), "szakall", travelInvoice(1), "", "en")
+	if err != nil {
+		t.Fatalf("GenerateTravelInvoice returned %v", err)
+	}
+	long, err := GenerateTravelInvoice(t.TempDir(), "szakall", travelInvoice(60), "", "en")
+	if err != nil {
+		t.Fatalf("GenerateTravelInvoice returned %v", err)
+	}
+	if shortPages, longPages := pages(t, short), pages(t, long); longPages <= shortPages {
+		t.Errorf("an invoice with 60 rows has %d pages and one with a single row %d, want more pages for more rows", longPages, shortPages)
+	}
+}
+
+// pages returns the amount of pages of the pdf at path
+func pages(t *testing.T, path string) int {
+	t.Helper()
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("couldn't read the pdf: %v", err)
+	}
+	return bytes.Count(content, []byte("/Type /Page\n"))
+}
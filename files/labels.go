@@ -0,0 +1,188 @@
+package files
+
+// German is the language the labels of the generated forms are written in and fall back to if no translation exists
+const German = "de"
+
+// English is the language the labels of the generated forms can be translated into
+const English = "en"
+
+// englishLabels maps the german labels of the generated pdf forms to their english translation
+var englishLabels = map[string]string{
+	"Abwesenheitsmeldung eines Jahrgangs": "Absence report of a class",
+	"Allgemeine Informationen:":           "General information:",
+	"Jahrgang:":                           "Class:",
+	"Lehrkraft:":                          "Teacher:",
+	"Anzahl m/w Schüler/innen:":           "Number of m/f students:",
+	"Begleitpersonen:":                    "Companions:",
+	"Von:":                                "From:",
+	"Bis:":                                "Until:",
+	"Anmerkungen:":                        "Notes:",
+	"Schulveranstaltung:":                 "School event:",
+	"Veranstaltung:":                      "Event:",
+	"Treffpunkt:":                         "Meeting point:",
+	"Uhrzeit:":                            "Time:",
+	"Dauer der Veranstaltung:":            "Duration of the event:",
+	"1-tägig (002)":                       "1 day (002)",
+	"mehr als 3-tägig (004)":              "more than 3 days (004)",
+	"2-3-tägig (003)":                     "2-3 days (003)",
+	"Supplierungen:":                      "Substitutions:",
+	"H/R/E":                               "H/R/C",
+	"Jahrgang":                            "Class",
+	"Datum":                               "Date",
+	"Stunde":                              "Period",
+	"Saal":                                "Room",
+	"LK Supp.":                            "Subst. teacher",
+	"LK Entf.":                            "Absent teacher",
+	"Paraphe":                             "Initials",
+	"Kenntnisnahme:":                      "Acknowledgement:",
+	"Begleitperson":                       "Companion",
+	"Ersteller/in":                        "Author",
+	"UNTIS Eintragung":                    "UNTIS entry",
+	"Stelle":                              "Position",
+	"Abgeltung für pädagogische Betreuung gemäß §63a":                                                             "Compensation for educational support according to §63a",
+	"Formular ist vom Leiter bzw. der Leiterin der Schulveranstaltung mit der Reiserechnung in der PEK abzugeben": "The form has to be handed in at the PEK by the leader of the school event together with the travel invoice",
+	"Datum:":             "Date:",
+	"Leitung (2.):":      "Leader (2.):",
+	"Verwendungsgruppe:": "Pay group:",
+	"Beginn:":            "Start:",
+	"Ende:":              "End:",
+	"Pädagogisch-inhaltliche Betreuung: (1.)": "Educational support: (1.)",
+	"Verwendungsgruppe":                       "Pay group",
+	"Beginn":                                  "Start",
+	"Ende":                                    "End",
+	"Datum und Unterschrift des Leiters der Schulveranstaltung": "Date and signature of the leader of the school event",
+	"1. Dem Lehrer gebührt für die Teilnahme an mindestens zweitägigen Schulveranstaltungen mit Nächtigung, sofern er die pädagogisch-inhaltliche Betreuung einer Schülergruppe innehat, eine Abgeltung.":  "1. Teachers taking part in school events of at least two days with an overnight stay are entitled to a compensation if they are responsible for the educational support of a group of students.",
+	"2. Weiters gebührt dem Leiter einer mindestens viertägigen Schulveranstaltung als Abgeltung die Einrechnung in die Lehrverpflichtung von 4.55 WE in jener Woche in der die Schulveranstaltung endet.": "2. Furthermore, the leader of a school event of at least four days is compensated by crediting 4.55 WE towards the teaching obligation of the week the school event ends in.",
+	"Abwesenheitsmeldung eines Lehrers": "Absence report of a teacher",
+	"Abwesenheitsgrund:":                "Reason of absence:",
+	"Fortbildung:":                      "Training:",
+	"Titel der Fortbildung:":            "Title of the training:",
+	"PH-Zahl:":                          "PH number:",
+	"Art der Veranstaltung:":            "Kind of event:",
+	"Tagung":                            "Conference",
+	"Lehrgang":                          "Course",
+	"Sonstiger Grund: ":                 "Other reason: ",
+	"Veranstalter:":                     "Organizer:",
+	"Anderer Grund:":                    "Other reason:",
+	"Pflegefreistellung":                "Care leave",
+	"Dienstauftrag":                     "Service mandate",
+	"Arzttermin":                        "Medical appointment",
+	"Sonstige Gründe":                   "Other reasons",
+	"Titel:":                            "Title:",
+	"Grund:":                            "Reason:",
+	"Reiserechnung Inland":              "Domestic travel invoice",
+	"Familienname:":                     "Surname:",
+	"Vorname:":                          "First name:",
+	"Akademischer Grad:":                "Academic degree:",
+	"Amtstitel:":                        "Official title:",
+	"Reisekostenvorschuss:":             "Travel cost advance:",
+	"Anzahl der Beilagen:":              "Number of attachments:",
+	"Personalnr:":                       "Staff number:",
+	"Bearbeiter:":                       "Clerk:",
+	"Prüfer:":                           "Reviewer:",
+	"Eingelangt:":                       "Received:",
+	"Ausgangsort:":                      "Place of departure:",
+	"Zielort:":                          "Destination:",
+	"Zusätzliche Daten:":                "Additional data:",
+	"Amtl. Businesskarte erhalten;   ":  "Official business card received;   ",
+	"Beförderungszuschuss;   ":          "Travel grant;   ",
+	"Ersatz für Vorteilscard (Beleg erford.);   ":         "Replacement for advantage card (receipt required);   ",
+	"Ersatz für Bahnfahrt 2. Kl (Beleg erford.);   ":      "Replacement for train journey 2nd class (receipt required);   ",
+	"Amtl. Kilometergeld für eigenen PKW (%v km);   ":     "Official kilometre allowance for own car (%v km);   ",
+	"Anzahl und namentliche Angabe der Mitfahrer;   ":     "Number and names of the passengers;   ",
+	"Angeführte andere Reisekosten (nur gegen Beleg);   ": "Other travel costs listed (only with receipt);   ",
+	"Keine Reisekosten;   ":                               "No travel costs;   ",
+	"Tagesgebühr:":                                        "Daily charges:",
+	"Tarif I":                                             "Tariff I",
+	"Tarif II":                                            "Tariff II",
+	"zu kürzen um ":                                       "to be shortened by ",
+	"%d Frühstück; %d Mittagessen; %d Abendessen":         "%d breakfasts; %d lunches; %d dinners",
+	"Nächtigungsgeb.:":                                    "Nightly charges:",
+	"mit Nachweis":                                        "with proof",
+	"ohne Nachweis":                                       "without proof",
+	"kein Anspruch":                                       "no claim",
+	"Berechnungsblatt":                                    "Calculation sheet",
+	"Nr.":                                                 "No.",
+	"Tag":                                                 "Day",
+	"Art Gebühren":                                        "Kind of charges",
+	"Kilometer":                                           "Kilometres",
+	"Reisek.":                                             "Travel c.",
+	"Tagk.":                                               "Daily c.",
+	"Nachtk.":                                             "Nightly c.",
+	"Nebenk.":                                             "Additional c.",
+	"Summe":                                               "Sum",
+	"Reisekosten, ":                                       "travel costs, ",
+	"Tagesgebühr, ":                                       "daily charges, ",
+	"Nächtigungsgebühr, ":                                 "nightly charges, ",
+	"Nebenkosten, ":                                       "additional costs, ",
+	"Summe:":                                              "Sum:",
+	"Die sachliche Richtigkeit wird besätigt:":           "The factual correctness is confirmed:",
+	"gem. § 37 RGV 55: für die Richtigkeit der Angaben:": "according to § 37 RGV 55: for the correctness of the information:",
+	"(Datum, Unterschrift der/s Anweisungsberechtigten)": "(date, signature of the authorizing officer)",
+	"(Datum, Unterschrift der/s Rechnungslegers/in)":     "(date, signature of the invoicing party)",
+	"Dienstreiseantrag Inland":                           "Domestic business trip application",
+	"Akad. Grad:":                                        "Acad. degree:",
+	"Personalnr.:":                                       "Staff number:",
+	"Reiseziel:":                                         "Destination:",
+	"Dienstreise":                                        "Business trip",
+	"Dienstverrichtung":                                  "Service",
+	"Reisezweck:":                                        "Purpose of the trip:",
+	"Reiseart:":                                          "Mode of travel:",
+	"Amtl BUSINESSKARTE 2. Kl":                           "Official BUSINESS CARD 2nd class",
+	"MITFAHRER/INNEN":                                    "PASSENGER",
+	"Amtl. BUSINESSKARTE / BAHNVERRECHNUNG 1. Kl - (Begründung erford.)": "Official BUSINESS CARD / TRAIN BILLING 1st class - (reason required)",
+	"BEFÖRDERUNGSZUSCHUSS":               "TRAVEL GRANT",
+	"FLUG":                               "FLIGHT",
+	"BAHN 2. Kl. - (Beleg erford.)":      "TRAIN 2nd class - (receipt required)",
+	"BILLIGFLUG":                         "CHEAP FLIGHT",
+	"EIGENER PKW - (Begründung erford.)": "OWN CAR - (reason required)",
+	"SCHLAFWAGEN":                        "SLEEPER TRAIN",
+	"BUS - (Beleg erford.)":              "BUS - (receipt required)",
+	"Ausgangspunkt:":                     "Starting point:",
+	"Dienststelle":                       "Office",
+	"Wohnung":                            "Apartment",
+	"Endpunkt:":                          "End point:",
+	"Begründung:":                        "Reasoning:",
+	"Sonstige Teilnehmer/innen:":         "Other participants:",
+	"Ich bestätige, dass ich anlässlich von Dienstreisen im Rahmen personenbezogener Bonusprogramme erworbene Prämien nicht privat in Anspruch nehme.": "I confirm that I don't use bonuses earned in personal bonus programs on business trips privately.",
+	"1. nicht bestätigt": "1. not confirmed",
+	"Für die Dienstreise verwende ich auf meine Meilenkonto gutgeschriebene, dienstlich erworbene Meilen.": "For the business trip I use miles earned on business and credited to my miles account.",
+	"2. nicht bestätigt": "2. not confirmed",
+	"Es werden Aufenthaltskosten und Reisekosten von %v getragen": "Staying costs and travel costs are paid by %v",
+	"Es werden Reisekosten von %v getragen":                       "Travel costs are paid by %v",
+	"Es werden Aufenthaltskosten von %v getragen":                 "Staying costs are paid by %v",
+	"Es werden keine Kosten von anderer Stelle getragen":          "No costs are paid by someone else",
+	"Sonstige Kosten:":               "Other costs:",
+	"Geschätzte Kosten:":             "Estimated costs:",
+	"Antragsteller/in":               "Applicant",
+	"Instituts-/Abteilungsleiter/in": "Head of institute/department",
+	"Die vorstehend beantragte Dienstreise wurde noch nicht genehmigt.": "The business trip applied for above wasn't approved yet.",
+	"Die vorstehend beantragte Dienstreise wird mit %v genehmigt.":      "The business trip applied for above is approved as of %v.",
+	"Ort, Datum":                       "Place, date",
+	"Unterschrift":                     "Signature",
+	"Eingabedatum: ":                   "Date of entry: ",
+	"Referent/in: ":                    "Referee: ",
+	"Hin- und Rückfahrt":               "the outward and return journey",
+	"Hinfahrt":                         "the outward journey",
+	"Rückfahrt":                        "the return journey",
+	"Businesskarte bei %v ausgefolgt.": "Business card issued for %v.",
+	"02. 01. 2006 15:04 Uhr":           "02. 01. 2006 15:04",
+	"Mo":                               "Mon",
+	"Di":                               "Tue",
+	"Mi":                               "Wed",
+	"Do":                               "Thu",
+	"Fr":                               "Fri",
+	"Sa":                               "Sat",
+	"So":                               "Sun",
+}
+
+// label returns the german label text in the language, it is returned unchanged if the language is German,
+// unknown or no translation exists
+func label(lang, text string) string {
+	if lang == English {
+		if translation, ok := englishLabels[text]; ok {
+			return translation
+		}
+	}
+	return text
+}
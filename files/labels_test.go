@@ -0,0 +1,28 @@
+package files
+
+import "testing"
+
+func TestLabel(t *testing.T) {
+	tests := []struct {
+		lang, text, want string
+	}{
+		{German, "Lehrkraft:", "Lehrkraft:"},
+		{English, "Lehrkraft:", "Teacher:"},
+		{"fr", "Lehrkraft:", "Lehrkraft:"},
+		{English, "ohne Übersetzung", "ohne Übersetzung"},
+	}
+	for _, test := range tests {
+		if got := label(test.lang, test.text); got != test.want {
+			t.Errorf("label(%q, %q) = %q, want %q", test.lang, test.text, got, test.want)
+		}
+	}
+}
+
+func TestGetWeekday(t *testing.T) {
+	if got := getWeekday(German, 1); got != "Mo" {
+		t.Errorf("getWeekday(German, 1) = %q, want Mo", got)
+	}
+	if got := getWeekday(English, 1); got != "Mon" {
+		t.Errorf("getWeekday(English, 1) = %q, want Mon", got)
+	}
+}
@@ -0,0 +1,126 @@
+package files
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultReceiptsPath is the directory uploaded receipts are stored in if ReceiptsPath isn't set
+const DefaultReceiptsPath = BasePath + "receipts/"
+
+// ReceiptsPath is the directory uploaded receipts are stored in, DefaultReceiptsPath is used if it is empty
+var ReceiptsPath string
+
+// ReceiptTimeFormat is the format of the upload time in the name of a stored receipt
+const ReceiptTimeFormat = "20060102T150405.000000000"
+
+// DefaultReceiptExtension is the extension of receipts uploaded without a (valid) file name
+const DefaultReceiptExtension = ".pdf"
+
+// receiptComponent matches the parts of a receipt name which are supplied by clients (the teacher and the extension)
+var receiptComponent = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+
+// receiptsPath returns ReceiptsPath or DefaultReceiptsPath if it isn't set
+func receiptsPath() string {
+	if ReceiptsPath == "" {
+		return DefaultReceiptsPath
+	}
+	return ReceiptsPath
+}
+
+// ValidReceiptOwner checks whether the short name of a teacher can be part of the name of a receipt
+// it can't if it could leave the receipt directory or contains the separator of the name parts
+func ValidReceiptOwner(short string) bool {
+	return receiptComponent.MatchString(short)
+}
+
+// receiptExtension returns the lower case extension of the file name supplied by a client
+// only the base name is considered, so directories in it are ignored; DefaultReceiptExtension is returned
+// if the name has no extension or it contains anything but letters, digits and dashes
+func receiptExtension(name string) string {
+	ext := strings.ToLower(filepath.Ext(filepath.Base(filepath.Clean("/" + name))))
+	if !receiptComponent.MatchString(strings.TrimPrefix(ext, ".")) {
+		return DefaultReceiptExtension
+	}
+	return ext
+}
+
+// SaveReceipt stores the receipt uploaded by the teacher short for the application with the given uuid
+// it is stored in a directory of the current year inside of the receipts path and named after the application,
+// the teacher, the upload time and the extension of name (e.g. 2021/<uuid>_mbeier_20210504T120000.000000000.pdf),
+// a counter is appended if a receipt with this name exists already
+// the path of the stored receipt relative to the receipts path is returned
+func SaveReceipt(uuid, short, name string, content []byte, now time.Time) (string, error) {
+	if !ValidReceiptOwner(short) || !receiptComponent.MatchString(uuid) {
+		return "", fmt.Errorf("invalid receipt owner")
+	}
+	year := now.Format("2006")
+	dir := filepath.Join(receiptsPath(), year)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+	base := fmt.Sprintf("%v_%v_%v", uuid, short, now.Format(ReceiptTimeFormat))
+	ext := receiptExtension(name)
+	for i := 1; ; i++ {
+		file := base + ext
+		if i > 1 {
+			file = fmt.Sprintf("%v_%d%v", base, i, ext)
+		}
+		f, err := os.OpenFile(filepath.Join(dir, file), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err := f.Write(content); err != nil {
+			_ = f.Close()
+			return "", err
+		}
+		if err := f.Sync(); err != nil {
+			_ = f.Close()
+			return "", err
+		}
+		if err := f.Close(); err != nil {
+			return "", err
+		}
+		return filepath.Join(year, file), nil
+	}
+}
+
+// Receipts returns the paths of all receipts uploaded for the application with the given uuid (by the teacher short
+// or by everyone if short is empty) in the order they were uploaded in
+// receipts stored in the upload folder of the application before the receipts path existed are included first
+func Receipts(uuid, short string) ([]string, error) {
+	if !receiptComponent.MatchString(uuid) || (short != "" && !ValidReceiptOwner(short)) {
+		return nil, fmt.Errorf("invalid receipt owner")
+	}
+	owner := "*"
+	if short != "" {
+		owner = short
+	}
+	stored, err := filepath.Glob(filepath.Join(receiptsPath(), "*", uuid+"_"+owner+"_*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(stored, func(i, j int) bool {
+		return receiptTime(stored[i]) < receiptTime(stored[j])
+	})
+	legacy, err := filepath.Glob(filepath.Join(BasePath, uuid, UploadFolderName, "*_"+owner+"_receipt.pdf"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(legacy)
+	return append(legacy, stored...), nil
+}
+
+// receiptTime returns the part of the name of a stored receipt starting with its upload time, which sorts by it
+func receiptTime(path string) string {
+	parts := strings.SplitN(filepath.Base(path), "_", 3)
+	return parts[len(parts)-1]
+}
@@ -0,0 +1,144 @@
+package files
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// receiptUUID is the identifier of the application the receipts of the tests are uploaded for
+const receiptUUID = "3fcf7f67-e0ed-4339-99b4-a6765aaa3dc4"
+
+// receiptsIn stores the receipts in a temporary directory until the test finishes and returns it
+func receiptsIn(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	previous := ReceiptsPath
+	ReceiptsPath = dir
+	t.Cleanup(func() { ReceiptsPath = previous })
+	return dir
+}
+
+func TestSaveReceiptStoresItPerYear(t *testing.T) {
+	dir := receiptsIn(t)
+	now := time.Date(2021, time.May, 4, 12, 0, 0, 0, time.UTC)
+	path, err := SaveReceipt(receiptUUID, "mbeier", "Zugticket.PDF", []byte("%PDF-1.4"), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join("2021", receiptUUID+"_mbeier_20210504T120000.000000000.pdf"); path != want {
+		t.Errorf("the receipt is stored as %v, want %v", path, want)
+	}
+	content, err := ioutil.ReadFile(filepath.Join(dir, path))
+	if err != nil || string(content) != "%PDF-1.4" {
+		t.Errorf("the stored receipt contains %q, %v, want the uploaded content", content, err)
+	}
+}
+
+func TestSaveReceiptDoesNotOverwriteReceipts(t *testing.T) {
+	dir := receiptsIn(t)
+	now := time.Date(2021, time.May, 4, 12, 0, 0, 0, time.UTC)
+	paths := make(map[string]bool)
+	for i, content := range []string{"first", "second", "third"} {
+		path, err := SaveReceipt(receiptUUID, "mbeier", "receipt.pdf", []byte(content), now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if paths[path] {
+			t.Fatalf("the receipt %d is stored as %v like an earlier one", i+1, path)
+		}
+		paths[path] = true
+		stored, err := ioutil.ReadFile(filepath.Join(dir, path))
+		if err != nil || string(stored) != content {
+			t.Errorf("%v contains %q, %v, want %q", path, stored, err, content)
+		}
+	}
+	if !paths[filepath.Join("2021", receiptUUID+"_mbeier_20210504T120000.000000000_2.pdf")] {
+		t.Errorf("the receipts are stored as %v, want a counter appended to colliding names", paths)
+	}
+}
+
+func TestSaveReceiptSanitizesTheFileName(t *testing.T) {
+	dir := receiptsIn(t)
+	now := time.Date(2021, time.May, 4, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name, ext string
+	}{
+		{"../../../etc/cron.d/receipt.sh", ".sh"},
+		{"..", DefaultReceiptExtension},
+		{"receipt.pdf/../../x", DefaultReceiptExtension},
+		{"receipt.p_df", DefaultReceiptExtension},
+		{"receipt", DefaultReceiptExtension},
+		{"", DefaultReceiptExtension},
+	}
+	for _, test := range tests {
+		path, err := SaveReceipt(receiptUUID, "mbeier", test.name, []byte("%PDF-1.4"), now)
+		if err != nil {
+			t.Fatalf("saving %q returned %v", test.name, err)
+		}
+		if filepath.Dir(path) != "2021" || strings.Contains(filepath.Base(path), "..") {
+			t.Errorf("saving %q stored it as %v, want it in the directory of the year", test.name, path)
+		}
+		if ext := filepath.Ext(path); ext != test.ext {
+			t.Errorf("saving %q stored it with the extension %q, want %q", test.name, ext, test.ext)
+		}
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "2021" {
+		t.Errorf("the receipts path contains %d entries, want only the directory of the year", len(entries))
+	}
+}
+
+func TestSaveReceiptRejectsInvalidOwners(t *testing.T) {
+	dir := receiptsIn(t)
+	now := time.Date(2021, time.May, 4, 12, 0, 0, 0, time.UTC)
+	for _, owner := range [][2]string{{receiptUUID, "../mbeier"}, {receiptUUID, "m_beier"}, {receiptUUID, ""}, {"../" + receiptUUID, "mbeier"}} {
+		if _, err := SaveReceipt(owner[0], owner[1], "receipt.pdf", []byte("%PDF-1.4"), now); err == nil {
+			t.Errorf("saving a receipt of %q for %q returned no error", owner[1], owner[0])
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2021")); !os.IsNotExist(err) {
+		t.Error("a receipt of an invalid owner was stored")
+	}
+}
+
+func TestReceiptsAreReturnedInUploadOrder(t *testing.T) {
+	receiptsIn(t)
+	first := time.Date(2020, time.December, 31, 23, 0, 0, 0, time.UTC)
+	uploads := []struct {
+		short string
+		at    time.Time
+	}{
+		{"mbeier", first.AddDate(0, 0, 2)},
+		{"hud", first.AddDate(0, 0, 1)},
+		{"mbeier", first},
+	}
+	want := make([]string, len(uploads))
+	for i, upload := range uploads {
+		path, err := SaveReceipt(receiptUUID, upload.short, "receipt.pdf", []byte("%PDF-1.4"), upload.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want[len(uploads)-1-i] = filepath.Join(receiptsPath(), path)
+	}
+	all, err := Receipts(receiptUUID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(all, ",") != strings.Join(want, ",") {
+		t.Errorf("got %v, want %v", all, want)
+	}
+	own, err := Receipts(receiptUUID, "mbeier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(own) != 2 || own[0] != want[0] || own[1] != want[2] {
+		t.Errorf("got %v, want the receipts of mbeier %v and %v", own, want[0], want[2])
+	}
+}
@@ -1,6 +1,7 @@
 package ldap
 
 import (
+	"errors"
 	"fmt"
 	"github.com/go-ldap/ldap/v3"
 	"github.com/google/uuid"
@@ -15,43 +16,61 @@ const URL = "10.2.24.151"
 // Port of the tgm ldap server. In this case it is the default port
 const Port = 389
 
+// ErrInvalidCredentials is returned by Authenticate if the ldap server rejects the username or password
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
 // AuthenticateUserCredentials authenicates a user given by username and password through the tgm ldap server.
 // Furthermore if it is the first login of a user it will create a new Teacher instance and save it to the local database.
 // It will return true if the credentials are valid and able to produce a successful login operation on the ldap server
 // Otherwise if any connection error occurs or the credentials aren't valid this method will return false
 func AuthenticateUserCredentials(username, password string) bool {
+	return Authenticate(username, password) == nil
+}
+
+// Authenticate is like AuthenticateUserCredentials but returns why the login failed.
+// If the ldap server rejects the credentials ErrInvalidCredentials is returned, errors of the untis api
+// (e.g. rejected credentials or too many sessions) are returned as *untis.UntisError
+func Authenticate(username, password string) error {
 	cred := username + "@tgm.ac.at"
 	l, err := ldap.Dial("tcp", fmt.Sprintf("%s:%d", URL, Port))
 	if err != nil {
-		return false
+		return fmt.Errorf("couldn't connect to ldap server: %w", err)
 	}
+	defer l.Close()
 	err = l.Bind(cred, password)
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		return ErrInvalidCredentials
+	}
 	if err != nil {
-		return false
+		return fmt.Errorf("couldn't bind to ldap server: %w", err)
 	}
 	mongo := db.MongoDatabaseConnector{}
 	if !mongo.Connect() {
-		return false
+		return fmt.Errorf("database didn't respond")
 	}
 	defer mongo.Close()
 	longname, err := GetLongName(username, password, username)
 	if err != nil {
-		return false
+		return err
 	}
 	client := untis.CreateClient(username, password)
+	// every request authenticates the client again, so a failed logout mustn't leave it authenticated
+	client.ForceCloseOnError = true
 	if !mongo.DoesTeacherExistByShort(username) {
 		err = client.Authenticate()
 		if err != nil {
 			_ = client.Close()
-			return false
+			return err
 		}
 		id, err := client.ResolveTeacherID(longname)
 		if err != nil {
-			return false
+			_ = client.Close()
+			return err
 		}
 		untisAb, err := client.ResolveTeachers([]int{id})
 		if err != nil {
-			return false
+			_ = client.Close()
+			return err
 		}
 		if !mongo.CreateTeacher(db.Teacher{
 			UUID:           uuid.NewString(),
@@ -64,14 +83,14 @@ func AuthenticateUserCredentials(username, password string) bool {
 			Untis:          untisAb[0],
 		}) {
 			_ = client.Close()
-			return false
+			return fmt.Errorf("couldn't create teacher")
 		}
 		err = client.Close()
 		if err != nil {
-			return false
+			return err
 		}
 	}
-	return true
+	return nil
 }
 
 // GetLongName will find out the full name (name + surname) of a teacher identified by key through their saved file on the active directory
@@ -2,9 +2,17 @@ package main
 
 import (
 	"github.com/refundable-tgm/huginn/rest"
+	"log"
+	"os"
 )
 
 // main function starting the rest service
 func main() {
-	rest.StartService()
+	cfg, err := rest.LoadConfig(os.Getenv(rest.ConfigFileEnv))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := rest.StartService(cfg); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,181 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	uuidG "github.com/google/uuid"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	// AuditCreateApplication is the action recorded if an application was created
+	AuditCreateApplication = "create_application"
+	// AuditImportApplication is the action recorded if an application was recreated from an export
+	AuditImportApplication = "import_application"
+	// AuditUpdateApplication is the action recorded if an application was updated
+	AuditUpdateApplication = "update_application"
+	// AuditUpdateApplicationStatus is the action recorded if the progress of an application was set by a bulk update
+	AuditUpdateApplicationStatus = "update_application_status"
+	// AuditDeleteApplication is the action recorded if an application was deleted
+	AuditDeleteApplication = "delete_application"
+	// AuditRestoreApplication is the action recorded if a deleted application was restored
+	AuditRestoreApplication = "restore_application"
+	// AuditSetPermissions is the action recorded if the permissions of a teacher were changed
+	AuditSetPermissions = "set_teacher_permissions"
+)
+
+// AuditLog is an append-only record of all mutating operations
+type AuditLog interface {
+	// Append adds the entry to the log
+	Append(entry mongo.AuditEntry) error
+	// Entries returns the entries of actor (of all actors if it is empty) in between from (inclusive) and to (exclusive),
+	// a zero time leaves that side of the range open; the newest entries come first
+	Entries(actor string, from, to time.Time) ([]mongo.AuditEntry, error)
+}
+
+// Audit is the audit log all mutating endpoints record their operations in
+var Audit AuditLog = databaseAuditLog{}
+
+// databaseAuditLog is an AuditLog stored in the AuditCollection of the database
+type databaseAuditLog struct{}
+
+// Append stores the entry in the database
+func (databaseAuditLog) Append(entry mongo.AuditEntry) error {
+	db := mongo.MongoDatabaseConnector{}
+	if !db.Connect() {
+		return fmt.Errorf("database didn't respond")
+	}
+	defer db.Close()
+	if !db.CreateAuditEntry(entry) {
+		return fmt.Errorf("couldn't store audit entry")
+	}
+	return nil
+}
+
+// Entries reads the entries from the database
+func (databaseAuditLog) Entries(actor string, from, to time.Time) ([]mongo.AuditEntry, error) {
+	db := mongo.MongoDatabaseConnector{}
+	if !db.Connect() {
+		return nil, fmt.Errorf("database didn't respond")
+	}
+	defer db.Close()
+	entries := db.GetAuditEntries(actor, from, to)
+	if entries == nil {
+		entries = make([]mongo.AuditEntry, 0)
+	}
+	return entries, nil
+}
+
+// recordAudit appends an entry for the action of actor on target to Audit, if before and after are given the changes
+// in between them are recorded as well; a failure is logged but doesn't fail the request
+func recordAudit(con *gin.Context, actor, action, target string, before, after interface{}) {
+	entry := mongo.AuditEntry{
+		UUID:     uuidG.NewString(),
+		Actor:    actor,
+		Action:   action,
+		TargetID: target,
+		Time:     time.Now().UTC(),
+	}
+	if before != nil && after != nil {
+		changes, err := auditChanges(before, after)
+		if err != nil {
+			log.Printf("level=error request_id=%v msg=%q", GetRequestID(con), "couldn't compute audit changes: "+err.Error())
+		}
+		entry.Changes = changes
+	}
+	if err := Audit.Append(entry); err != nil {
+		log.Printf("level=error request_id=%v msg=%q", GetRequestID(con), fmt.Sprintf("couldn't record %v of %v by %v: %v", action, target, actor, err))
+	}
+}
+
+// auditChanges compares the json encodings of before and after and returns all top level fields that differ
+func auditChanges(before, after interface{}) (map[string]mongo.AuditChange, error) {
+	var old, updated map[string]json.RawMessage
+	if err := remarshal(before, &old); err != nil {
+		return nil, err
+	}
+	if err := remarshal(after, &updated); err != nil {
+		return nil, err
+	}
+	changes := make(map[string]mongo.AuditChange)
+	for field, value := range updated {
+		if previous, ok := old[field]; !ok || string(previous) != string(value) {
+			changes[field] = mongo.AuditChange{Before: string(old[field]), After: string(value)}
+		}
+	}
+	for field, previous := range old {
+		if _, ok := updated[field]; !ok {
+			changes[field] = mongo.AuditChange{Before: string(previous)}
+		}
+	}
+	return changes, nil
+}
+
+// remarshal encodes value as json and decodes it into target
+func remarshal(value interface{}, target interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, target)
+}
+
+// GetAuditLog represents the get audit log endpoint
+// @Summary Returns the audit log
+// @Description Returns all recorded mutating operations, newest first, optionally restricted to an actor and a date range, only available for administrators
+// @ID get-audit-log
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param actor query string false "Short name of the teacher who performed the operations"
+// @Param from query string false "First day of the operations (YYYY-MM-DD)"
+// @Param to query string false "Last day of the operations (YYYY-MM-DD)"
+// @Success 200 {array} db.AuditEntry
+// @Failure 400 {object} Error
+// @Failure 401 {object} Error
+// @Failure 500 {object} Error
+// @Router /auditLog [get]
+func GetAuditLog(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	var from, to time.Time
+	if raw := con.Query("from"); raw != "" {
+		from, err = time.Parse(DateFormat, raw)
+		if err != nil {
+			con.JSON(http.StatusBadRequest, Error{localize(con, "invalid from date provided")})
+			return
+		}
+	}
+	if raw := con.Query("to"); raw != "" {
+		to, err = time.Parse(DateFormat, raw)
+		if err != nil {
+			con.JSON(http.StatusBadRequest, Error{localize(con, "invalid to date provided")})
+			return
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	db := mongo.MongoDatabaseConnector{}
+	if !db.Connect() {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
+		return
+	}
+	requester := db.GetTeacherByShort(auth.Username)
+	db.Close()
+	if !(requester.Administration || requester.SuperUser) {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "unauthorized")})
+		return
+	}
+	entries, err := Audit.Entries(con.Query("actor"), from, to)
+	if err != nil {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't read audit log")})
+		return
+	}
+	con.JSON(http.StatusOK, entries)
+}
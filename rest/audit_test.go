@@ -0,0 +1,212 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryAuditLog is an AuditLog keeping its entries in memory
+type memoryAuditLog struct {
+	mutex   sync.Mutex
+	entries []mongo.AuditEntry
+	// err is returned by Append if it is set
+	err error
+}
+
+// Append adds the entry unless err is set
+func (l *memoryAuditLog) Append(entry mongo.AuditEntry) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.err != nil {
+		return l.err
+	}
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+// Entries returns all entries of the actor, the range is ignored
+func (l *memoryAuditLog) Entries(actor string, from, to time.Time) ([]mongo.AuditEntry, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	entries := make([]mongo.AuditEntry, 0)
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		if actor == "" || l.entries[i].Actor == actor {
+			entries = append(entries, l.entries[i])
+		}
+	}
+	return entries, nil
+}
+
+// actions returns the actions of all entries in the order they were appended
+func (l *memoryAuditLog) actions() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	actions := make([]string, 0, len(l.entries))
+	for _, entry := range l.entries {
+		actions = append(actions, entry.Action)
+	}
+	return actions
+}
+
+// auditingInMemory replaces Audit by a memoryAuditLog until the test finishes
+func auditingInMemory(t *testing.T) *memoryAuditLog {
+	t.Helper()
+	previous := Audit
+	audit := &memoryAuditLog{}
+	Audit = audit
+	t.Cleanup(func() { Audit = previous })
+	return audit
+}
+
+func TestRecordAudit(t *testing.T) {
+	audit := auditingInMemory(t)
+	con, _ := testContext(http.MethodDelete, "/deleteApplication?uuid=1", "")
+	recordAudit(con, "szakall", AuditDeleteApplication, "1", nil, nil)
+	if len(audit.entries) != 1 {
+		t.Fatalf("recording wrote %d entries, want 1", len(audit.entries))
+	}
+	entry := audit.entries[0]
+	if entry.UUID == "" {
+		t.Error("the entry has no uuid")
+	}
+	if entry.Actor != "szakall" || entry.Action != AuditDeleteApplication || entry.TargetID != "1" {
+		t.Errorf("recorded %v of %v by %v, want %v of 1 by szakall", entry.Action, entry.TargetID, entry.Actor, AuditDeleteApplication)
+	}
+	if time.Since(entry.Time) > time.Minute || entry.Time.Location() != time.UTC {
+		t.Errorf("the entry was recorded at %v, want now in UTC", entry.Time)
+	}
+	if entry.Changes != nil {
+		t.Errorf("an entry without before and after recorded the changes %v", entry.Changes)
+	}
+
+	before := mongo.Teacher{Short: "szakall", AV: true}
+	after := mongo.Teacher{Short: "szakall", Administration: true}
+	recordAudit(con, "borko", AuditSetPermissions, "2", before, after)
+	if len(audit.entries) != 2 {
+		t.Fatalf("recording wrote %d entries, want 2", len(audit.entries))
+	}
+	if changes := audit.entries[1].Changes; len(changes) != 2 {
+		t.Errorf("recorded the changes %v, want the two changed permissions", changes)
+	}
+}
+
+func TestRecordAuditDoesNotFailOnAFailingLog(t *testing.T) {
+	audit := auditingInMemory(t)
+	audit.err = errors.New("unavailable")
+	con, recorder := testContext(http.MethodDelete, "/deleteApplication?uuid=1", "")
+	recordAudit(con, "szakall", AuditDeleteApplication, "1", nil, nil)
+	if con.IsAborted() || recorder.Body.Len() > 0 {
+		t.Error("a failing audit log affected the response")
+	}
+}
+
+func TestAuditChanges(t *testing.T) {
+	before := map[string]interface{}{"name": "Dienstreise", "progress": 1, "notes": "Graz", "version": 1}
+	after := map[string]interface{}{"name": "Dienstreise", "progress": 2, "rooms": []string{"H1102"}, "version": 1}
+	changes, err := auditChanges(before, after)
+	if err != nil {
+		t.Fatalf("auditChanges returned %v", err)
+	}
+	want := map[string]mongo.AuditChange{
+		"progress": {Before: "1", After: "2"},
+		"rooms":    {Before: "", After: `["H1102"]`},
+		"notes":    {Before: `"Graz"`, After: ""},
+	}
+	if len(changes) != len(want) {
+		t.Errorf("returned the changes %v, want %v", changes, want)
+	}
+	for field, change := range want {
+		if changes[field] != change {
+			t.Errorf("the change of %v is %+v, want %+v", field, changes[field], change)
+		}
+	}
+}
+
+func TestAuditChangesRejectsValuesWhichArentObjects(t *testing.T) {
+	if _, err := auditChanges([]int{1}, map[string]int{"a": 1}); err == nil {
+		t.Error("comparing a list returned no error")
+	}
+}
+
+func TestGetAuditLogRejectsInvalidDates(t *testing.T) {
+	for _, query := range []string{"from=01.03.2021", "to=2021-13-01"} {
+		t.Run(query, func(t *testing.T) {
+			con, recorder := authorizedContext(t, "auditor", http.MethodGet, "/auditLog?"+query, "")
+			GetAuditLog(con)
+			if recorder.Code != http.StatusBadRequest {
+				t.Errorf("reading the audit log responded with %d, want %d", recorder.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestGetAuditLogRequiresALogin(t *testing.T) {
+	con, recorder := testContext(http.MethodGet, "/auditLog", "")
+	GetAuditLog(con)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("reading the audit log without a login responded with %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestMutationsAreAudited(t *testing.T) {
+	db := requireDatabase(t)
+	audit := auditingInMemory(t)
+	admin := storeTeacher(t, db, "auditor", Permissions{Administration: true})
+	other := storeTeacher(t, db, "audited", Permissions{})
+
+	con, recorder := authorizedContext(t, admin.Short, http.MethodPost, "/createApplication", applicationBody(t, nil))
+	CreateApplication(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("creating responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	if len(audit.entries) == 1 {
+		t.Cleanup(func() { db.DeleteApplication(audit.entries[0].TargetID) })
+	}
+
+	stored := storeApplication(t, db, otherReason(admin.Longname))
+	stored.Name = "Dienstreise nach Graz"
+	if status := updateApplication(t, admin.Short, stored); status != http.StatusOK {
+		t.Fatalf("updating responded with %d, want %d", status, http.StatusOK)
+	}
+	con, recorder = authorizedContext(t, admin.Short, http.MethodDelete, "/deleteApplication?uuid="+stored.UUID, "")
+	DeleteApplication(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("deleting responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	con, recorder = authorizedContext(t, admin.Short, http.MethodPost, "/restoreApplication?uuid="+stored.UUID, "")
+	RestoreApplication(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("restoring responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	body, _ := json.Marshal(PermissionsUpdate{TeacherShort: other.Short, Permissions: []string{"av"}})
+	con, recorder = authorizedContext(t, admin.Short, http.MethodPost, "/updateTeacherPermissions", string(body))
+	UpdateTeacherPermissions(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("setting the permissions responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+
+	want := []string{AuditCreateApplication, AuditUpdateApplication, AuditDeleteApplication, AuditRestoreApplication, AuditSetPermissions}
+	actions := audit.actions()
+	if len(actions) != len(want) {
+		t.Fatalf("recorded the actions %v, want %v", actions, want)
+	}
+	for i, action := range want {
+		if actions[i] != action {
+			t.Errorf("action %d is %v, want %v", i, actions[i], action)
+		}
+		if entry := audit.entries[i]; entry.Actor != admin.Short {
+			t.Errorf("%v was recorded for %v, want %v", action, entry.Actor, admin.Short)
+		}
+	}
+	if update := audit.entries[1]; update.TargetID != stored.UUID || update.Changes["name"].After != `"Dienstreise nach Graz"` {
+		t.Errorf("the update of %v recorded the changes %v, want the changed name of %v", update.TargetID, update.Changes, stored.UUID)
+	}
+	if permissions := audit.entries[4]; permissions.TargetID != other.UUID || permissions.Changes["av"].After != "true" {
+		t.Errorf("setting the permissions of %v recorded the changes %v, want av of %v", permissions.TargetID, permissions.Changes, other.UUID)
+	}
+}
@@ -0,0 +1,72 @@
+package rest
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+)
+
+// DefaultMaxBodySize is the maximum size in bytes of a request body if MaxBodySizeEnv isn't set
+const DefaultMaxBodySize = 1 << 20
+
+// MaxReceiptBodySize is the maximum size in bytes of the body of a receipt upload, which contains whole base64 encoded files
+const MaxReceiptBodySize = 32 << 20
+
+// MaxBodySizeEnv is the environment variable the maximum size in bytes of request bodies can be specified with
+// (e.g. 2097152), it doesn't apply to the routes of bodySizeOverrides
+const MaxBodySizeEnv = "HUGINN_MAX_BODY_SIZE"
+
+// MaxBodySize is the maximum size in bytes of request bodies of routes without an override
+var MaxBodySize int64 = DefaultMaxBodySize
+
+// bodySizeOverrides are the maximum body sizes of routes accepting larger bodies than MaxBodySize
+var bodySizeOverrides = map[string]int64{
+	"/api/saveBillingReceipt": MaxReceiptBodySize,
+}
+
+// maxBodySize parses the maximum size of request bodies, DefaultMaxBodySize if size is empty
+func maxBodySize(size string) (int64, error) {
+	if size == "" {
+		return DefaultMaxBodySize, nil
+	}
+	limit, err := strconv.ParseInt(size, 10, 64)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid %v provided: %q", MaxBodySizeEnv, size)
+	}
+	return limit, nil
+}
+
+// BodyLimit is a middleware rejecting requests whose body is larger than MaxBodySize (or the override of their route)
+// with 413; the body is read up front, so handlers never see a truncated body
+func BodyLimit() gin.HandlerFunc {
+	return func(con *gin.Context) {
+		if con.Request.Body == nil || con.Request.Body == http.NoBody {
+			con.Next()
+			return
+		}
+		limit := MaxBodySize
+		if override, ok := bodySizeOverrides[con.FullPath()]; ok {
+			limit = override
+		}
+		if con.Request.ContentLength > limit {
+			con.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, Error{localize(con, "request body too large")})
+			return
+		}
+		body, err := ioutil.ReadAll(http.MaxBytesReader(con.Writer, con.Request.Body, limit))
+		_ = con.Request.Body.Close()
+		// the reader stops at the limit, other errors (e.g. a connection closed by the client) stop before it
+		if err != nil && int64(len(body)) >= limit {
+			con.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, Error{localize(con, "request body too large")})
+			return
+		}
+		if err != nil {
+			con.AbortWithStatusJSON(http.StatusBadRequest, Error{localize(con, "invalid request structure provided")})
+			return
+		}
+		con.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+		con.Next()
+	}
+}
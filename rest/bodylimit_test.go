@@ -0,0 +1,124 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// serveBodyLimit sends a POST request with the body through the BodyLimit middleware to a handler on path responding
+// with the body it read
+func serveBodyLimit(path string, body *strings.Reader, contentLength int64) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.POST(path, BodyLimit(), func(con *gin.Context) {
+		read, err := ioutil.ReadAll(con.Request.Body)
+		if err != nil {
+			con.String(http.StatusInternalServerError, err.Error())
+			return
+		}
+		con.Data(http.StatusOK, "text/plain", read)
+	})
+	req := httptest.NewRequest(http.MethodPost, path, body)
+	req.ContentLength = contentLength
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestBodyLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		size          int64
+		contentLength bool
+		status        int
+	}{
+		{"a body of the maximum size", "/api/createApplication", MaxBodySize, true, http.StatusOK},
+		{"a too large body", "/api/createApplication", MaxBodySize + 1, true, http.StatusRequestEntityTooLarge},
+		{"a too large body without a length", "/api/createApplication", MaxBodySize + 1, false, http.StatusRequestEntityTooLarge},
+		{"a receipt larger than the default", "/api/saveBillingReceipt", 2 * MaxBodySize, true, http.StatusOK},
+		{"a too large receipt", "/api/saveBillingReceipt", MaxReceiptBodySize + 1, false, http.StatusRequestEntityTooLarge},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body := strings.Repeat("a", int(test.size))
+			contentLength := int64(-1)
+			if test.contentLength {
+				contentLength = test.size
+			}
+			recorder := serveBodyLimit(test.path, strings.NewReader(body), contentLength)
+			if recorder.Code != test.status {
+				t.Fatalf("got %d, want %d", recorder.Code, test.status)
+			}
+			if test.status == http.StatusOK && recorder.Body.Len() != len(body) {
+				t.Errorf("the handler read %d bytes, want the whole body of %d bytes", recorder.Body.Len(), len(body))
+			}
+		})
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	if limit, err := maxBodySize(""); err != nil || limit != DefaultMaxBodySize {
+		t.Errorf("got %d, %v without a size, want DefaultMaxBodySize", limit, err)
+	}
+	if limit, err := maxBodySize("2097152"); err != nil || limit != 2097152 {
+		t.Errorf("got %d, %v, want 2097152", limit, err)
+	}
+	for _, size := range []string{"0", "-1", "2MB"} {
+		if _, err := maxBodySize(size); err == nil {
+			t.Errorf("the size %q was accepted", size)
+		}
+	}
+}
+
+func TestCreateApplicationRejectsOversizedBodies(t *testing.T) {
+	body := applicationBody(t, map[string]interface{}{"notes": strings.Repeat("a", int(MaxBodySize))})
+	req := httptest.NewRequest(http.MethodPost, "/api/createApplication", strings.NewReader(body))
+	req.Header.Set("Authorization", loginAs(t, "oversized"))
+	req.Header.Set("Accept-Language", "en")
+	recorder := serveRouter(t, req)
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("creating an application with a body of %d bytes responded with %d, want %d", len(body), recorder.Code, http.StatusRequestEntityTooLarge)
+	}
+	res := Error{}
+	decodeJSON(t, recorder, &res)
+	if res.Message != "request body too large" {
+		t.Errorf("got the error %q, want request body too large", res.Message)
+	}
+}
+
+func TestCreateApplicationAcceptsBodiesWithinTheLimit(t *testing.T) {
+	db := requireDatabase(t)
+	filer := storeTeacher(t, db, "limited", Permissions{})
+	notes := "within the body limit " + filer.UUID
+	body := applicationBody(t, map[string]interface{}{
+		"notes":                notes,
+		"other_reason_details": map[string]interface{}{"filer": filer.Longname},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/createApplication", strings.NewReader(body))
+	req.Header.Set("Authorization", loginAs(t, filer.Short))
+	recorder := serveRouter(t, req)
+	t.Cleanup(func() {
+		for _, app := range db.GetAllApplications() {
+			if app.Notes == notes {
+				db.DeleteApplication(app.UUID)
+			}
+		}
+	})
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("creating an application responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	created := make([]mongo.Application, 0)
+	for _, app := range db.GetAllApplications() {
+		if app.Notes == notes {
+			created = append(created, app)
+		}
+	}
+	if len(created) != 1 {
+		t.Errorf("%d applications were created, want 1", len(created))
+	}
+}
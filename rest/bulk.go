@@ -0,0 +1,137 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"net/http"
+	"time"
+)
+
+const (
+	// BulkStatusUpdated is the outcome of applications whose progress was set
+	BulkStatusUpdated = "updated"
+	// BulkStatusNotFound is the outcome of uuids without an application that isn't deleted
+	BulkStatusNotFound = "not_found"
+	// BulkStatusUnauthorized is the outcome of applications the logged in teacher may not review
+	BulkStatusUnauthorized = "unauthorized"
+	// BulkStatusInvalidTransition is the outcome of applications whose progress can't be changed to the status
+	BulkStatusInvalidTransition = "invalid_transition"
+	// BulkStatusConflict is the outcome of applications which were changed while being updated
+	BulkStatusConflict = "conflict"
+	// BulkStatusFailed is the outcome of applications which couldn't be saved
+	BulkStatusFailed = "failed"
+)
+
+// reviewTransitions are the progresses an application may be in for a review to set it to the key
+var reviewTransitions = map[int][]int{
+	mongo.Rejected:  {mongo.InProcess, mongo.CostsInProcess},
+	mongo.Confirmed: {mongo.InProcess},
+	mongo.Done:      {mongo.CostsInProcess},
+}
+
+// mayReview checks whether the teacher may review applications in the given progress,
+// like get admin applications only administration and AV review applications in process, PEK reviews costs as well
+func mayReview(teacher mongo.Teacher, progress int) bool {
+	if progress == mongo.InProcess {
+		return teacher.Administration || teacher.AV || teacher.SuperUser
+	}
+	return teacher.PEK || teacher.Administration || teacher.AV || teacher.SuperUser
+}
+
+// reviewOutcome returns the outcome of setting the progress of the application to status by a review of the teacher
+// if it is allowed, BulkStatusUpdated is returned
+func reviewOutcome(application mongo.Application, teacher mongo.Teacher, status int) string {
+	for _, from := range reviewTransitions[status] {
+		if application.Progress == from {
+			if !mayReview(teacher, from) {
+				return BulkStatusUnauthorized
+			}
+			return BulkStatusUpdated
+		}
+	}
+	return BulkStatusInvalidTransition
+}
+
+// BulkUpdateApplicationStatus represents the bulk update application status endpoint
+// @Summary Sets the status of several applications
+// @Description Sets the progress of the applications matching the given UUIDs to the status, at most 100 at once
+// @Description The status has to be rejected (0), confirmed (3) or done (7); applications in process may be confirmed or rejected by administration and AV, applications with costs in process may be set to done or rejected by PEK as well
+// @Description Every application is updated on its own, the outcome for each UUID is returned: updated, not_found, unauthorized, invalid_transition, conflict or failed
+// @ID bulk-update-application-status
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param request body BulkStatusRequest true "UUIDs of the Applications and their new status"
+// @Success 200 {object} BulkStatusResult
+// @Failure 400 {object} ValidationError
+// @Failure 401 {object} Error
+// @Failure 500 {object} Error
+// @Router /bulkUpdateApplicationStatus [post]
+func BulkUpdateApplicationStatus(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	var req BulkStatusRequest
+	if err := con.ShouldBindJSON(&req); err != nil {
+		con.JSON(http.StatusBadRequest, validationError(con, err, req))
+		return
+	}
+	if _, ok := reviewTransitions[*req.Status]; !ok {
+		con.JSON(http.StatusBadRequest, Error{localize(con, "invalid status provided")})
+		return
+	}
+	if len(req.IDs) > MaxBulkStatusApplications {
+		con.JSON(http.StatusBadRequest, Error{localizef(con, "at most %d applications may be updated at once", MaxBulkStatusApplications)})
+		return
+	}
+	db := mongo.MongoDatabaseConnector{}
+	if !db.Connect() {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
+		return
+	}
+	defer db.Close()
+	teacher := db.GetTeacherByShort(auth.Username)
+	if !(teacher.PEK || teacher.Administration || teacher.AV || teacher.SuperUser) {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "unauthorized")})
+		return
+	}
+	found := make(map[string]mongo.Application)
+	for _, application := range db.GetApplications(req.IDs) {
+		found[application.UUID] = application
+	}
+	res := BulkStatusResult{Results: make(map[string]string)}
+	for _, uuid := range req.IDs {
+		if _, ok := res.Results[uuid]; ok {
+			continue
+		}
+		application, ok := found[uuid]
+		if !ok || application.DeletedAt != nil {
+			res.Results[uuid] = BulkStatusNotFound
+			continue
+		}
+		outcome := reviewOutcome(application, teacher, *req.Status)
+		if outcome != BulkStatusUpdated {
+			res.Results[uuid] = outcome
+			continue
+		}
+		update := application
+		update.Progress = *req.Status
+		update.LastChanged = time.Now()
+		updated, conflict := db.UpdateApplicationIfVersion(uuid, update, application.Version)
+		switch {
+		case conflict:
+			res.Results[uuid] = BulkStatusConflict
+		case !updated:
+			res.Results[uuid] = BulkStatusFailed
+		default:
+			res.Results[uuid] = BulkStatusUpdated
+			res.Updated++
+			stored := db.GetApplication(uuid)
+			applicationEvents.publish(ApplicationStatusChanged, stored)
+			recordAudit(con, auth.Username, AuditUpdateApplicationStatus, uuid, application, stored)
+		}
+	}
+	con.JSON(http.StatusOK, res)
+}
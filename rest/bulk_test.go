@@ -0,0 +1,163 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// bulkUpdateStatus calls BulkUpdateApplicationStatus as user with the request as body
+func bulkUpdateStatus(t *testing.T, username string, req interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	con, recorder := authorizedContext(t, username, http.MethodPost, "/bulkUpdateApplicationStatus", string(body))
+	con.Request.Header.Set("Accept-Language", "en")
+	BulkUpdateApplicationStatus(con)
+	return recorder
+}
+
+func TestReviewOutcome(t *testing.T) {
+	administration := mongo.Teacher{Administration: true}
+	pek := mongo.Teacher{PEK: true}
+	tests := []struct {
+		name     string
+		progress int
+		teacher  mongo.Teacher
+		status   int
+		want     string
+	}{
+		{"administration confirming an application in process", mongo.InProcess, administration, mongo.Confirmed, BulkStatusUpdated},
+		{"administration rejecting an application in process", mongo.InProcess, administration, mongo.Rejected, BulkStatusUpdated},
+		{"pek confirming an application in process", mongo.InProcess, pek, mongo.Confirmed, BulkStatusUnauthorized},
+		{"pek finishing costs in process", mongo.CostsInProcess, pek, mongo.Done, BulkStatusUpdated},
+		{"pek rejecting costs in process", mongo.CostsInProcess, pek, mongo.Rejected, BulkStatusUpdated},
+		{"confirming a confirmed application", mongo.Confirmed, administration, mongo.Confirmed, BulkStatusInvalidTransition},
+		{"finishing an application in process", mongo.InProcess, administration, mongo.Done, BulkStatusInvalidTransition},
+		{"rejecting an application in submission", mongo.InSubmission, administration, mongo.Rejected, BulkStatusInvalidTransition},
+	}
+	for _, test := range tests {
+		if got := reviewOutcome(mongo.Application{Progress: test.progress}, test.teacher, test.status); got != test.want {
+			t.Errorf("%v: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestBulkUpdateApplicationStatusRejectsInvalidRequests(t *testing.T) {
+	confirmed := mongo.Confirmed
+	running := mongo.Running
+	tooMany := make([]string, MaxBulkStatusApplications+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.NewString()
+	}
+	tests := []struct {
+		name    string
+		req     interface{}
+		message string
+	}{
+		{"an invalid target status", BulkStatusRequest{IDs: []string{uuid.NewString()}, Status: &running}, "invalid status provided"},
+		{"an unknown target status", map[string]interface{}{"ids": []string{uuid.NewString()}, "status": 42}, "invalid status provided"},
+		{"too many applications", BulkStatusRequest{IDs: tooMany, Status: &confirmed}, "at most 100 applications may be updated at once"},
+		{"no applications", BulkStatusRequest{IDs: []string{}, Status: &confirmed}, ""},
+		{"no status", map[string]interface{}{"ids": []string{uuid.NewString()}}, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := bulkUpdateStatus(t, "reviewer", test.req)
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("got %d, want %d", recorder.Code, http.StatusBadRequest)
+			}
+			if test.message != "" && !strings.Contains(recorder.Body.String(), test.message) {
+				t.Errorf("the response %s doesn't contain %q", recorder.Body, test.message)
+			}
+		})
+	}
+	con, recorder := testContext(http.MethodPost, "/bulkUpdateApplicationStatus", `{"ids":["1"],"status":3}`)
+	BulkUpdateApplicationStatus(con)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("updating without a login responded with %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
+
+// storeApplicationIn stores an application of the filer in the given progress
+func storeApplicationIn(t *testing.T, db mongo.MongoDatabaseConnector, filer string, progress int) mongo.Application {
+	t.Helper()
+	app := otherReason(filer)
+	app.Progress = progress
+	return storeApplication(t, db, app)
+}
+
+func TestBulkUpdateApplicationStatusApprovesTheApplications(t *testing.T) {
+	db := requireDatabase(t)
+	audit := auditingInMemory(t)
+	reviewer := storeTeacher(t, db, "approving", Permissions{Administration: true})
+	first := storeApplicationIn(t, db, reviewer.Longname, mongo.InProcess)
+	second := storeApplicationIn(t, db, reviewer.Longname, mongo.InProcess)
+	sub := applicationEvents.subscribe(func(mongo.Application) bool { return true })
+	defer applicationEvents.unsubscribe(sub)
+
+	confirmed := mongo.Confirmed
+	recorder := bulkUpdateStatus(t, reviewer.Short, BulkStatusRequest{IDs: []string{first.UUID, second.UUID}, Status: &confirmed})
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d", recorder.Code, http.StatusOK)
+	}
+	res := BulkStatusResult{}
+	decodeJSON(t, recorder, &res)
+	if res.Updated != 2 || res.Results[first.UUID] != BulkStatusUpdated || res.Results[second.UUID] != BulkStatusUpdated {
+		t.Errorf("got %+v, want both applications updated", res)
+	}
+	for _, app := range []mongo.Application{first, second} {
+		if stored := db.GetApplication(app.UUID); stored.Progress != mongo.Confirmed || stored.Version != app.Version+1 {
+			t.Errorf("%v is in progress %d in version %d, want it confirmed in a new version", app.UUID, stored.Progress, stored.Version)
+		}
+		if event := receive(t, sub); event.Type != ApplicationStatusChanged || event.Application.Progress != mongo.Confirmed {
+			t.Errorf("got the event %v of progress %d, want the status change", event.Type, event.Application.Progress)
+		}
+	}
+	if len(audit.entries) != 2 || audit.entries[0].Action != AuditUpdateApplicationStatus || audit.entries[0].Actor != reviewer.Short {
+		t.Errorf("recorded %+v, want a status update by the reviewer per application", audit.entries)
+	}
+}
+
+func TestBulkUpdateApplicationStatusReportsEveryApplication(t *testing.T) {
+	db := requireDatabase(t)
+	audit := auditingInMemory(t)
+	reviewer := storeTeacher(t, db, "mixing", Permissions{Administration: true})
+	pending := storeApplicationIn(t, db, reviewer.Longname, mongo.InProcess)
+	finished := storeApplicationIn(t, db, reviewer.Longname, mongo.Done)
+	missing := uuid.NewString()
+
+	rejected := mongo.Rejected
+	recorder := bulkUpdateStatus(t, reviewer.Short, BulkStatusRequest{IDs: []string{pending.UUID, missing, finished.UUID, pending.UUID}, Status: &rejected})
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d", recorder.Code, http.StatusOK)
+	}
+	res := BulkStatusResult{}
+	decodeJSON(t, recorder, &res)
+	want := map[string]string{pending.UUID: BulkStatusUpdated, missing: BulkStatusNotFound, finished.UUID: BulkStatusInvalidTransition}
+	if res.Updated != 1 || len(res.Results) != len(want) {
+		t.Fatalf("got %+v, want %v", res, want)
+	}
+	for id, outcome := range want {
+		if res.Results[id] != outcome {
+			t.Errorf("%v has the outcome %v, want %v", id, res.Results[id], outcome)
+		}
+	}
+	if stored := db.GetApplication(finished.UUID); stored.Progress != mongo.Done || stored.Version != finished.Version {
+		t.Errorf("the finished application changed to progress %d in version %d", stored.Progress, stored.Version)
+	}
+	if len(audit.entries) != 1 || audit.entries[0].TargetID != pending.UUID {
+		t.Errorf("recorded %+v, want only the rejection of the pending application", audit.entries)
+	}
+
+	teacher := storeTeacher(t, db, "unprivileged", Permissions{})
+	if recorder := bulkUpdateStatus(t, teacher.Short, BulkStatusRequest{IDs: []string{pending.UUID}, Status: &rejected}); recorder.Code != http.StatusUnauthorized {
+		t.Errorf("updating without permissions responded with %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
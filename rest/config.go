@@ -0,0 +1,197 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/refundable-tgm/huginn/untis"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ConfigFileEnv is the environment variable the path of a json configuration file can be specified with
+// (e.g. /vol/files/config.json), the configuration is only read from the environment if it is empty
+const ConfigFileEnv = "HUGINN_CONFIG"
+
+// UntisServerEnv is the environment variable the untis server can be specified with (e.g. https://neilo.webuntis.com)
+const UntisServerEnv = "HUGINN_UNTIS_SERVER"
+
+// UntisSchoolEnv is the environment variable the untis school can be specified with (e.g. tgm)
+const UntisSchoolEnv = "HUGINN_UNTIS_SCHOOL"
+
+// UntisProxyEnv is the environment variable the url of a proxy requests to untis are sent through can be specified
+// with (e.g. http://proxy.tgm.ac.at:3128), the proxy of the environment (HTTPS_PROXY) is used if it is empty
+const UntisProxyEnv = "HUGINN_UNTIS_PROXY"
+
+// UntisCAFileEnv is the environment variable the path of a pem file with root CAs trusted for requests to untis
+// in addition to the system ones can be specified with (e.g. /vol/files/ca.pem)
+const UntisCAFileEnv = "HUGINN_UNTIS_CA_FILE"
+
+// ModeEnv is the environment variable the gin mode can be specified with (debug, release or test),
+// it is derived from DebugFilePath if it is empty
+const ModeEnv = "HUGINN_MODE"
+
+// ginModes are the modes gin may be run in
+var ginModes = map[string]bool{
+	gin.DebugMode:   true,
+	gin.ReleaseMode: true,
+	gin.TestMode:    true,
+}
+
+// Config is the configuration the service is started with
+type Config struct {
+	// Address is the address the api listens on (e.g. 127.0.0.1:8080)
+	Address string `json:"address"`
+	// UntisServer is the untis server the clients of the users connect to
+	UntisServer string `json:"untis_server"`
+	// UntisSchool is the untis school the clients of the users log into
+	UntisSchool string `json:"untis_school"`
+	// UntisProxy is the url of the proxy requests to untis are sent through, the proxy of the environment is used if it is empty
+	UntisProxy string `json:"untis_proxy"`
+	// UntisCAFile is the path of a pem file with root CAs trusted for requests to untis in addition to the system ones
+	UntisCAFile string `json:"untis_ca_file"`
+	// Mode is the gin mode (debug, release or test), it is derived from DebugFilePath if it is empty
+	Mode string `json:"mode"`
+	// CORSOrigins is a comma separated list of the origins allowed to access this api, all are allowed if it is empty
+	CORSOrigins string `json:"cors_origins"`
+	// SessionTTL is the time an untis client may stay unused before it is evicted (e.g. 12h),
+	// the lifetime of refresh tokens is used if it is empty
+	SessionTTL string `json:"session_ttl"`
+	// ReceiptsDir is the directory uploaded receipts are stored in, files.DefaultReceiptsPath is used if it is empty
+	ReceiptsDir string `json:"receipts_dir"`
+	// MaxBodySize is the maximum size in bytes of request bodies, DefaultMaxBodySize is used if it is 0
+	MaxBodySize int64 `json:"max_body_size"`
+}
+
+// DefaultConfig returns the configuration used for all settings neither the configuration file nor the environment specify
+func DefaultConfig() Config {
+	return Config{
+		Address:     ":" + strconv.Itoa(Port),
+		UntisServer: untis.DefaultServer,
+		UntisSchool: untis.DefaultSchool,
+		MaxBodySize: DefaultMaxBodySize,
+	}
+}
+
+// LoadConfig loads the configuration of the service: the defaults are overridden by the json file at path (if path
+// isn't empty), which are overridden by the environment variables; an error is returned if the file can't be read,
+// contains unknown fields or the resulting configuration is invalid
+func LoadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("couldn't read config: %w", err)
+		}
+		defer f.Close()
+		decoder := json.NewDecoder(f)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&cfg); err != nil {
+			return Config{}, fmt.Errorf("couldn't parse config %v: %w", path, err)
+		}
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return Config{}, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// applyEnv overrides the settings of the configuration with the environment variables which are set
+func (cfg *Config) applyEnv() error {
+	overrides := map[string]*string{
+		AddressEnv:     &cfg.Address,
+		UntisServerEnv: &cfg.UntisServer,
+		UntisSchoolEnv: &cfg.UntisSchool,
+		UntisProxyEnv:  &cfg.UntisProxy,
+		UntisCAFileEnv: &cfg.UntisCAFile,
+		ModeEnv:        &cfg.Mode,
+		CORSOriginsEnv: &cfg.CORSOrigins,
+		SessionTTLEnv:  &cfg.SessionTTL,
+		ReceiptsDirEnv: &cfg.ReceiptsDir,
+	}
+	for env, setting := range overrides {
+		if value, ok := os.LookupEnv(env); ok && value != "" {
+			*setting = value
+		}
+	}
+	if size := os.Getenv(MaxBodySizeEnv); size != "" {
+		limit, err := maxBodySize(size)
+		if err != nil {
+			return err
+		}
+		cfg.MaxBodySize = limit
+	}
+	return nil
+}
+
+// Validate checks whether the configuration can be started with and returns a descriptive error if it can't
+func (cfg Config) Validate() error {
+	host, port, err := net.SplitHostPort(cfg.Address)
+	if err != nil {
+		return fmt.Errorf("invalid config: address %q must be of the form host:port", cfg.Address)
+	}
+	if strings.ContainsAny(host, " /") {
+		return fmt.Errorf("invalid config: address %q has an invalid host", cfg.Address)
+	}
+	number, err := strconv.Atoi(port)
+	if err != nil || number < 1 || number > 65535 {
+		return fmt.Errorf("invalid config: port %q of address %q must be between 1 and 65535", port, cfg.Address)
+	}
+	if strings.TrimSpace(cfg.UntisServer) == "" {
+		return fmt.Errorf("invalid config: untis_server must be set")
+	}
+	server, err := url.Parse(cfg.UntisServer)
+	if err != nil || (server.Scheme != "http" && server.Scheme != "https") || server.Host == "" {
+		return fmt.Errorf("invalid config: untis_server %q must be an http(s) url", cfg.UntisServer)
+	}
+	if strings.TrimSpace(cfg.UntisSchool) == "" {
+		return fmt.Errorf("invalid config: untis_school must be set")
+	}
+	if cfg.UntisProxy != "" {
+		proxy, err := url.Parse(cfg.UntisProxy)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			return fmt.Errorf("invalid config: untis_proxy %q must be an absolute url", cfg.UntisProxy)
+		}
+	}
+	if cfg.Mode != "" && !ginModes[cfg.Mode] {
+		return fmt.Errorf("invalid config: mode %q must be one of debug, release or test", cfg.Mode)
+	}
+	if _, err := sessionTTL(cfg.SessionTTL); err != nil {
+		return fmt.Errorf("invalid config: session_ttl %q must be a positive duration", cfg.SessionTTL)
+	}
+	if cfg.MaxBodySize < 0 {
+		return fmt.Errorf("invalid config: max_body_size %d mustn't be negative", cfg.MaxBodySize)
+	}
+	return nil
+}
+
+// untisTransport configures the transport of all untis clients to use the proxy and root CAs of the configuration
+func (cfg Config) untisTransport() error {
+	var caPEM []byte
+	if cfg.UntisCAFile != "" {
+		var err error
+		caPEM, err = ioutil.ReadFile(cfg.UntisCAFile)
+		if err != nil {
+			return fmt.Errorf("couldn't read untis_ca_file: %w", err)
+		}
+	}
+	return untis.ConfigureDefaultTransport(cfg.UntisProxy, caPEM)
+}
+
+// mode returns the gin mode of the configuration, the debug mode if it is empty and a .debug file is present
+func (cfg Config) mode() string {
+	if cfg.Mode != "" {
+		return cfg.Mode
+	}
+	if debugMode() {
+		return gin.DebugMode
+	}
+	return gin.ReleaseMode
+}
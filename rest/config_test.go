@@ -0,0 +1,104 @@
+package rest
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// configFile writes the json configuration to a temporary file and clears all environment variables overriding it
+// until the test finishes, it returns the path of the file
+func configFile(t *testing.T, content string) string {
+	t.Helper()
+	for _, env := range []string{AddressEnv, UntisServerEnv, UntisSchoolEnv, UntisProxyEnv, UntisCAFileEnv, ModeEnv,
+		CORSOriginsEnv, SessionTTLEnv, ReceiptsDirEnv, MaxBodySizeEnv} {
+		setenv(t, env, "")
+	}
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := configFile(t, `{
+		"address": "127.0.0.1:9090",
+		"untis_server": "https://neilo.webuntis.com",
+		"untis_school": "tgm",
+		"mode": "release",
+		"session_ttl": "12h"
+	}`)
+	setenv(t, UntisSchoolEnv, "htl-donaustadt")
+	setenv(t, MaxBodySizeEnv, "2097152")
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Config{
+		Address:     "127.0.0.1:9090",
+		UntisServer: "https://neilo.webuntis.com",
+		UntisSchool: "htl-donaustadt",
+		Mode:        "release",
+		SessionTTL:  "12h",
+		MaxBodySize: 2097152,
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("got %+v, want %+v", cfg, want)
+	}
+
+	configFile(t, "")
+	cfg, err = LoadConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg, DefaultConfig()) {
+		t.Errorf("got %+v without a file, want the defaults %+v", cfg, DefaultConfig())
+	}
+}
+
+func TestLoadConfigRejectsInvalidConfigs(t *testing.T) {
+	tests := []struct {
+		name, config, want string
+	}{
+		{"a missing school", `{"untis_school": ""}`, "untis_school must be set"},
+		{"a missing server", `{"untis_server": " "}`, "untis_server must be set"},
+		{"a server without a scheme", `{"untis_server": "neilo.webuntis.com"}`, "must be an http(s) url"},
+		{"a port out of range", `{"address": ":65536"}`, "must be between 1 and 65535"},
+		{"the port 0", `{"address": "127.0.0.1:0"}`, "must be between 1 and 65535"},
+		{"a port which is no number", `{"address": ":http"}`, "must be between 1 and 65535"},
+		{"an address without a port", `{"address": "127.0.0.1"}`, "must be of the form host:port"},
+		{"an unknown mode", `{"mode": "production"}`, "mode \"production\" must be one of"},
+		{"a relative proxy", `{"untis_proxy": "proxy.tgm.ac.at"}`, "untis_proxy"},
+		{"an invalid session ttl", `{"session_ttl": "-1h"}`, "session_ttl"},
+		{"a negative body size", `{"max_body_size": -1}`, "max_body_size"},
+		{"an unknown field", `{"port": 8080}`, "unknown field"},
+		{"invalid json", `{"address": `, "couldn't parse config"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := LoadConfig(configFile(t, test.config))
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("got %v, want an error containing %q", err, test.want)
+			}
+		})
+	}
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil || !strings.Contains(err.Error(), "couldn't read config") {
+		t.Errorf("got %v for a missing file, want it to be reported", err)
+	}
+	configFile(t, "")
+	setenv(t, AddressEnv, ":99999")
+	if _, err := LoadConfig(""); err == nil {
+		t.Error("an out of range port of the environment was accepted")
+	}
+}
+
+func TestStartServiceFailsFastOnInvalidConfigs(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.UntisSchool = ""
+	if err := StartService(cfg); err == nil || !strings.Contains(err.Error(), "untis_school") {
+		t.Errorf("got %v, want the missing school to be reported", err)
+	}
+}
@@ -2,6 +2,7 @@ package rest
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
@@ -13,41 +14,37 @@ import (
 	"github.com/refundable-tgm/huginn/ldap"
 	"github.com/refundable-tgm/huginn/untis"
 	"io/ioutil"
+	"log"
+	"math"
 	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
-// AuthWall drops every token which doesnt provide a valid token
+// AuthWall drops every request which doesnt provide a valid token
+// rejected requests are answered with 401, an AuthError containing the reason and a WWW-Authenticate header
 func AuthWall() gin.HandlerFunc {
 	return func(con *gin.Context) {
-		ok, err := TokenValid(con.Request)
-		if !ok && err != nil {
-			con.JSON(http.StatusUnauthorized, Error{"present a valid token"})
-			con.Abort()
-			return
-		}
-		auth, err := ExtractTokenMeta(con.Request)
-		if err != nil {
-			con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
-			con.Abort()
-			return
-		}
-		if _, ok = activeTokens[auth.AccessUUID]; !ok {
-			con.JSON(http.StatusUnauthorized, Error{"token presented is invalid"})
-			con.Abort()
+		if reason := RejectionReason(con.Request); reason != "" {
+			con.Header("WWW-Authenticate", "Bearer")
+			con.AbortWithStatusJSON(http.StatusUnauthorized, AuthError{"unauthorized", reason})
 			return
 		}
 		con.Next()
 	}
 }
 
+// authenticateLogin checks the credentials of a login at the ldap server and untis, see ldap.Authenticate
+var authenticateLogin = ldap.Authenticate
+
 // Login represents the login endpoint
 // @Summary Login a user
 // @Description Login a user using username and password
+// @Description After repeated failed logins of a username or from an ip further logins are refused with 429 for an exponentially growing duration given in the Retry-After header
 // @ID login
 // @Accept json
 // @Produce json
@@ -55,20 +52,43 @@ func AuthWall() gin.HandlerFunc {
 // @Success 200 {object} TokenPair
 // @Failure 401 {object} Error
 // @Failure 422 {object} Error
+// @Failure 429 {object} Error
+// @Failure 500 {object} Error
+// @Failure 502 {object} Error
 // @Router /login [post]
 func Login(con *gin.Context) {
 	u := User{}
 	if err := con.ShouldBindJSON(&u); err != nil {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
+		return
+	}
+	if wait := logins.retryAfter(u.Username, con.ClientIP()); wait > 0 {
+		con.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		con.JSON(http.StatusTooManyRequests, Error{localize(con, "too many failed logins, try again later")})
 		return
 	}
-	if !ldap.AuthenticateUserCredentials(u.Username, u.Password) {
-		con.JSON(http.StatusUnauthorized, Error{"this credentials do not resolve into an authorized login"})
+	if err := authenticateLogin(u.Username, u.Password); err != nil {
+		switch {
+		case errors.Is(err, ldap.ErrInvalidCredentials):
+			logins.fail(u.Username, con.ClientIP())
+			con.JSON(http.StatusUnauthorized, Error{localize(con, "this credentials do not resolve into an authorized login")})
+		case untis.HasErrorCode(err, untis.BadCredentialsErrorCode):
+			logins.fail(u.Username, con.ClientIP())
+			con.JSON(http.StatusUnauthorized, Error{localize(con, "untis rejected this credentials")})
+		case untis.HasErrorCode(err, untis.TooManySessionsErrorCode):
+			con.JSON(http.StatusTooManyRequests, Error{localize(con, "untis is refusing further sessions, try again later")})
+		case untisStatus(err) == http.StatusBadGateway:
+			con.JSON(http.StatusBadGateway, Error{localize(con, "untis is unavailable, try again later")})
+		default:
+			log.Printf("level=error request_id=%v msg=%q", GetRequestID(con), "login failed: "+err.Error())
+			con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't log in")})
+		}
 		return
 	}
+	logins.succeed(u.Username, con.ClientIP())
 	token, err := CreateToken(u.Username)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't sign token"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't sign token")})
 		return
 	}
 	SaveToken(u.Username, token)
@@ -81,7 +101,7 @@ func Login(con *gin.Context) {
 
 // Logout represents the logout endpoint
 // @Summary Logs out a user
-// @Description Destroys the session of a user
+// @Description Destroys the session of a user, revoking both its access and refresh token
 // @ID logout
 // @Accept json
 // @Produce json
@@ -92,17 +112,19 @@ func Login(con *gin.Context) {
 func Logout(con *gin.Context) {
 	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
 		return
 	}
-	DeleteToken(auth.AccessUUID)
-	untis.GetClient(auth.Username).DeleteClient()
+	RevokeToken(auth.AccessUUID)
+	for _, err := range untis.CloseAllForUser(auth.Username) {
+		log.Printf("level=warn request_id=%v msg=%q", GetRequestID(con), "couldn't close untis session: "+err.Error())
+	}
 	con.JSON(http.StatusOK, Information{"logged out"})
 }
 
 // Refresh represents the refresh endpoint
 // @Summary Refreshes the token pair of a session
-// @Description Creates a new token pair when a valid refresh token is provided
+// @Description Creates a new token pair when a valid refresh token is provided, the provided refresh token and its access token are revoked
 // @ID refresh
 // @Accept json
 // @Produce json
@@ -115,7 +137,7 @@ func Logout(con *gin.Context) {
 func Refresh(con *gin.Context) {
 	body := RefreshToken{}
 	if err := con.ShouldBindJSON(&body); err != nil {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	refresh := body.Token
@@ -127,34 +149,37 @@ func Refresh(con *gin.Context) {
 	})
 
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"token expired"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "token expired")})
 		return
 	}
 
 	if _, ok := token.Claims.(jwt.Claims); !ok && !token.Valid {
-		con.JSON(http.StatusUnauthorized, Error{"token unvalid"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "token unvalid")})
 		return
 	}
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if ok && token.Valid {
 		uuid, ok := claims["refresh_uuid"].(string)
 		if !ok {
-			con.JSON(http.StatusUnprocessableEntity, Error{"couldn't extract uuid"})
+			con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "couldn't extract uuid")})
 			return
 		}
 		username, ok := claims["username"].(string)
 		if !ok {
-			con.JSON(http.StatusUnprocessableEntity, Error{"couldn't extract username"})
+			con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "couldn't extract username")})
 			return
 		}
-		if _, ok := activeTokens[uuid]; !ok {
-			con.JSON(http.StatusUnauthorized, Error{"this token isn't valid"})
+		if !RevokeToken(uuid) {
+			if IsTokenRevoked(uuid) {
+				con.JSON(http.StatusUnauthorized, Error{localize(con, "this token was already used or revoked")})
+				return
+			}
+			con.JSON(http.StatusUnauthorized, Error{localize(con, "this token isn't valid")})
 			return
 		}
-		DeleteToken(uuid)
 		tok, err := CreateToken(username)
 		if err != nil {
-			con.JSON(http.StatusForbidden, Error{"invalid request structure provided"})
+			con.JSON(http.StatusForbidden, Error{localize(con, "invalid request structure provided")})
 			return
 		}
 		SaveToken(username, tok)
@@ -164,7 +189,7 @@ func Refresh(con *gin.Context) {
 		}
 		con.JSON(http.StatusCreated, tokens)
 	} else {
-		con.JSON(http.StatusUnauthorized, Error{"refresh token expired"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "refresh token expired")})
 	}
 }
 
@@ -180,22 +205,23 @@ func Refresh(con *gin.Context) {
 // @Failure 401 {object} Error
 // @Failure 422 {object} Error
 // @Failure 500 {object} Error
+// @Failure 502 {object} Error
 // @Router /getTeacherByShort [get]
 func GetTeacherByShort(con *gin.Context) {
 	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
 		return
 	}
 	query := con.Request.URL.Query()
 	if query.Get("name") == "" {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	name := query.Get("name")
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
@@ -204,31 +230,35 @@ func GetTeacherByShort(con *gin.Context) {
 		con.JSON(http.StatusOK, teacher)
 		return
 	}
-	client := untis.GetClient(auth.Username)
+	client, ok := untis.GetClient(auth.Username)
+	if !ok {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
 	longname, err := ldap.GetLongName(client.Username, client.Password, name)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't read longname of new teacher"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't read longname of new teacher")})
+		return
+	}
+	err = client.Acquire()
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't authenticate with untis API")})
 		return
 	}
-	err = client.Authenticate()
 	defer func() {
-		err = client.Close()
+		err = client.Release()
 		if err != nil {
-			con.JSON(http.StatusInternalServerError, Error{"couldn't logout out off untis API"})
+			con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't logout out off untis API")})
 		}
 	}()
-	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't authenticate with untis API"})
-		return
-	}
 	id, err := client.ResolveTeacherID(longname)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't resolve untis id of new teacher"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't resolve untis id of new teacher")})
 		return
 	}
 	untisAb, err := client.ResolveTeachers([]int{id})
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't resolve untis abbrevation of new teacher"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't resolve untis abbrevation of new teacher")})
 		return
 	}
 	teacher := mongo.Teacher{
@@ -242,7 +272,7 @@ func GetTeacherByShort(con *gin.Context) {
 		Untis:          untisAb[0],
 	}
 	if !db.CreateTeacher(teacher) {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't create new teacher based on this"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create new teacher based on this")})
 		return
 	}
 	con.JSON(http.StatusOK, teacher)
@@ -265,23 +295,23 @@ func GetTeacherByShort(con *gin.Context) {
 func GetTeacher(con *gin.Context) {
 	_, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
 		return
 	}
 	query := con.Request.URL.Query()
 	if query.Get("uuid") == "" {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	uuid := query.Get("uuid")
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
 	if !db.DoesTeacherExistByUUID(uuid) {
-		con.JSON(http.StatusNotFound, Error{"teacher not found"})
+		con.JSON(http.StatusNotFound, Error{localize(con, "teacher not found")})
 		return
 	}
 	teacher := db.GetTeacherByUUID(uuid)
@@ -305,23 +335,23 @@ func GetTeacher(con *gin.Context) {
 func GetTeacherByUntis(con *gin.Context) {
 	_, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
 		return
 	}
 	query := con.Request.URL.Query()
 	if query.Get("untis") == "" {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	untisAb := query.Get("untis")
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
 	if !db.DoesTeacherExistByUntis(untisAb) {
-		con.JSON(http.StatusNotFound, Error{"teacher not found"})
+		con.JSON(http.StatusNotFound, Error{localize(con, "teacher not found")})
 		return
 	}
 	teacher := db.GetTeacherByUntis(untisAb)
@@ -329,9 +359,11 @@ func GetTeacherByUntis(con *gin.Context) {
 }
 
 // SetTeacherPermissions represents the set teacher permissions endpoint
+// Deprecated: use UpdateTeacherPermissions, which identifies the teacher by the request body and validates the permissions
 // @Summary Sets the permissions of a Teacher
-// @Description Sets the permissions of a Teacher to update their access rights
+// @Description Sets the permissions of a Teacher to update their access rights (deprecated, use /updateTeacherPermissions)
 // @ID set-teacher-permissions
+// @Deprecated
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
@@ -345,41 +377,128 @@ func GetTeacherByUntis(con *gin.Context) {
 func SetTeacherPermissions(con *gin.Context) {
 	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
 		return
 	}
 	perm := Permissions{}
 	if err := con.ShouldBindJSON(&perm); err != nil {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	query := con.Request.URL.Query()
 	if query.Get("uuid") == "" {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	uuid := query.Get("uuid")
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
 	requester := db.GetTeacherByShort(auth.Username)
 	if !(requester.PEK || requester.Administration || requester.AV || requester.SuperUser) {
-		con.JSON(http.StatusUnauthorized, Error{"unauthorized"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "unauthorized")})
 		return
 	}
 	teacher := db.GetTeacherByUUID(uuid)
+	before := teacher
 	teacher.SuperUser = perm.SuperUser
 	teacher.Administration = perm.Administration
 	teacher.PEK = perm.PEK
-	teacher.Administration = perm.Administration
+	teacher.AV = perm.AV
 	if db.UpdateTeacher(uuid, teacher) {
+		recordAudit(con, auth.Username, AuditSetPermissions, uuid, before, teacher)
 		con.JSON(http.StatusOK, Information{"permissions updated"})
 	} else {
-		con.JSON(http.StatusInternalServerError, Error{"permissions couldn't be updated"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "permissions couldn't be updated")})
+	}
+}
+
+// UpdateTeacherPermissions represents the update teacher permissions endpoint
+// @Summary Sets the permissions of a Teacher
+// @Description Sets the permissions of a Teacher to exactly the listed ones (super_user, administration, av, pek)
+// @ID update-teacher-permissions
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param perm body PermissionsUpdate true "The teacher and their new permissions"
+// @Success 200 {object} Information
+// @Failure 400 {object} ValidationError
+// @Failure 401 {object} Error
+// @Failure 404 {object} Error
+// @Failure 500 {object} Error
+// @Router /updateTeacherPermissions [post]
+func UpdateTeacherPermissions(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	update := PermissionsUpdate{}
+	if err := con.ShouldBindJSON(&update); err != nil {
+		con.JSON(http.StatusBadRequest, validationError(con, err, update))
+		return
+	}
+	perm, unknown := parsePermissions(update.Permissions)
+	if len(unknown) > 0 {
+		res := ValidationError{localize(con, "unknown permissions provided"), make([]FieldError, 0)}
+		for _, name := range unknown {
+			res.Fields = append(res.Fields, FieldError{Field: "permissions", Reason: name})
+		}
+		con.JSON(http.StatusBadRequest, res)
+		return
+	}
+	db := mongo.MongoDatabaseConnector{}
+	if !db.Connect() {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
+		return
+	}
+	defer db.Close()
+	requester := db.GetTeacherByShort(auth.Username)
+	if !(requester.PEK || requester.Administration || requester.AV || requester.SuperUser) {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "unauthorized")})
+		return
+	}
+	if !db.DoesTeacherExistByShort(update.TeacherShort) {
+		con.JSON(http.StatusNotFound, Error{localize(con, "teacher not found")})
+		return
+	}
+	teacher := db.GetTeacherByShort(update.TeacherShort)
+	before := teacher
+	teacher.SuperUser = perm.SuperUser
+	teacher.Administration = perm.Administration
+	teacher.AV = perm.AV
+	teacher.PEK = perm.PEK
+	if !db.UpdateTeacher(teacher.UUID, teacher) {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "permissions couldn't be updated")})
+		return
 	}
+	recordAudit(con, auth.Username, AuditSetPermissions, teacher.UUID, before, teacher)
+	log.Printf("level=info request_id=%v msg=%q", GetRequestID(con),
+		fmt.Sprintf("permissions of %v set to %v by %v", teacher.Short, update.Permissions, auth.Username))
+	con.JSON(http.StatusOK, Information{"permissions updated"})
+}
+
+// parsePermissions converts a list of permission names into Permissions
+// names not matching any permission are returned as unknown
+func parsePermissions(names []string) (perm Permissions, unknown []string) {
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "super_user":
+			perm.SuperUser = true
+		case "administration":
+			perm.Administration = true
+		case "av":
+			perm.AV = true
+		case "pek":
+			perm.PEK = true
+		default:
+			unknown = append(unknown, name)
+		}
+	}
+	return perm, unknown
 }
 
 // UpdateTeacherInformation represents the update teacher information endpoint
@@ -399,30 +518,30 @@ func SetTeacherPermissions(con *gin.Context) {
 func UpdateTeacherInformation(con *gin.Context) {
 	ti := TeacherInformation{}
 	if err := con.ShouldBindJSON(&ti); err != nil {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
 		return
 	}
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
 	query := con.Request.URL.Query()
 	uuid := query.Get("uuid")
 	if query.Get("uuid") == "" {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	requestTeacher := db.GetTeacherByShort(auth.Username)
 	teacherToUpdate := db.GetTeacherByUUID(uuid)
 	if !(requestTeacher.UUID == teacherToUpdate.UUID) {
-		con.JSON(http.StatusUnauthorized, Error{"teacher are only allowed to update themselves"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "teacher are only allowed to update themselves")})
 		return
 	}
 	teacherToUpdate.Degree = ti.Degree
@@ -436,7 +555,7 @@ func UpdateTeacherInformation(con *gin.Context) {
 	if db.UpdateTeacher(uuid, teacherToUpdate) {
 		con.JSON(http.StatusOK, Information{"success; teacher updated"})
 	} else {
-		con.JSON(http.StatusInternalServerError, Error{"error; teacher not updated"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "error; teacher not updated")})
 	}
 }
 
@@ -448,19 +567,31 @@ func UpdateTeacherInformation(con *gin.Context) {
 // @Produce json
 // @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
 // @Param username query string false "Filter to only show applications of this teacher"
+// @Param status query int false "Filter to only show applications with this progress"
+// @Param from query string false "Filter to only show applications ending on or after this date (YYYY-MM-DD)"
+// @Param to query string false "Filter to only show applications starting on or before this date (YYYY-MM-DD)"
+// @Param fields query string false "Comma separated json names of the fields of the applications to return (e.g. uuid,kind,progress,start_time), unknown ones are ignored; whole applications are returned if it is omitted"
 // @Success 200 {array} db.Application
+// @Failure 400 {object} Error
 // @Failure 401 {object} Error
 // @Failure 500 {object} Error
+// @Failure 502 {object} Error
 // @Router /getActiveApplications [get]
 func GetActiveApplications(con *gin.Context) {
 	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	appFilter, err := parseApplicationFilter(con)
+	if err != nil {
+		con.JSON(http.StatusBadRequest, Error{localize(con, err.Error())})
 		return
 	}
+	fields := parseFields(con)
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
@@ -473,36 +604,40 @@ func GetActiveApplications(con *gin.Context) {
 		con.JSON(http.StatusUnauthorized, "unauthorized")
 		return
 	}
-	applications := db.GetActiveApplications()
+	applications := appFilter.apply(db.GetActiveApplications())
 	var teacher mongo.Teacher
 	if db.DoesTeacherExistByShort(filter) {
 		teacher = db.GetTeacherByShort(filter)
 	} else {
-		client := untis.GetClient(auth.Username)
+		client, ok := untis.GetClient(auth.Username)
+		if !ok {
+			con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+			return
+		}
 		longname, err := ldap.GetLongName(client.Username, client.Password, filter)
 		if err != nil {
-			con.JSON(http.StatusInternalServerError, Error{"couldn't read longname of new teacher"})
+			con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't read longname of new teacher")})
+			return
+		}
+		err = client.Acquire()
+		if err != nil {
+			con.JSON(untisStatus(err), Error{localize(con, "couldn't authenticate with untis API")})
 			return
 		}
-		err = client.Authenticate()
 		defer func() {
-			err = client.Close()
+			err = client.Release()
 			if err != nil {
-				con.JSON(http.StatusInternalServerError, Error{"couldn't logout out off untis API"})
+				con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't logout out off untis API")})
 			}
 		}()
-		if err != nil {
-			con.JSON(http.StatusInternalServerError, Error{"couldn't authenticate with untis API"})
-			return
-		}
 		id, err := client.ResolveTeacherID(longname)
 		if err != nil {
-			con.JSON(http.StatusInternalServerError, Error{"couldn't resolve untis id of new teacher"})
+			con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't resolve untis id of new teacher")})
 			return
 		}
 		untisAb, err := client.ResolveTeachers([]int{id})
 		if err != nil {
-			con.JSON(http.StatusInternalServerError, Error{"couldn't resolve untis abbrevation of new teacher"})
+			con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't resolve untis abbrevation of new teacher")})
 			return
 		}
 		teacher = mongo.Teacher{
@@ -516,7 +651,7 @@ func GetActiveApplications(con *gin.Context) {
 			Untis:          untisAb[0],
 		}
 		if !db.CreateTeacher(teacher) {
-			con.JSON(http.StatusInternalServerError, Error{"couldn't create new teacher based on this"})
+			con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create new teacher based on this")})
 			return
 		}
 	}
@@ -542,33 +677,54 @@ func GetActiveApplications(con *gin.Context) {
 				}
 			}
 		}
-		con.JSON(http.StatusOK, res)
+		respondApplications(con, res, fields)
 		return
 	}
-	con.JSON(http.StatusOK, applications)
+	respondApplications(con, applications, fields)
 }
 
 // GetAllApplications represents the get all applications endpoint
 // @Summary Returns all applications
-// @Description Returns all applications as a list of applications
+// @Description Returns all applications as a paginated list of applications
 // @ID get-all-applications
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
 // @Param username query string false "Filter to only show applications of this teacher"
-// @Success 200 {array} db.Application
+// @Param status query int false "Filter to only show applications with this progress"
+// @Param from query string false "Filter to only show applications ending on or after this date (YYYY-MM-DD)"
+// @Param to query string false "Filter to only show applications starting on or before this date (YYYY-MM-DD)"
+// @Param fields query string false "Comma separated json names of the fields of the applications to return (e.g. uuid,kind,progress,start_time), unknown ones are ignored; whole applications are returned if it is omitted"
+// @Param include_deleted query bool false "Whether deleted applications are listed as well (administrative permissions only)"
+// @Param offset query int false "Index of the first application on the page" default(0)
+// @Param limit query int false "Maximum amount of applications on the page (at most 100)" default(20)
+// @Success 200 {object} ApplicationPage
+// @Failure 400 {object} Error
 // @Failure 401 {object} Error
+// @Failure 422 {object} Error
 // @Failure 500 {object} Error
+// @Failure 502 {object} Error
 // @Router /getAllApplications [get]
 func GetAllApplications(con *gin.Context) {
 	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	offset, limit, ok := parsePagination(con)
+	if !ok {
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
+		return
+	}
+	appFilter, err := parseApplicationFilter(con)
+	if err != nil {
+		con.JSON(http.StatusBadRequest, Error{localize(con, err.Error())})
 		return
 	}
+	fields := parseFields(con)
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
@@ -578,39 +734,52 @@ func GetAllApplications(con *gin.Context) {
 	filter := query.Get("username")
 	requestTeacher := db.GetTeacherByShort(auth.Username)
 	if !(requestTeacher.Administration || requestTeacher.AV || requestTeacher.SuperUser || requestTeacher.PEK || (applyFilter && requestTeacher.Short == filter)) {
-		con.JSON(http.StatusUnauthorized, Error{"unauthorized"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "unauthorized")})
 		return
 	}
-	applications := db.GetAllApplications()
+	var applications []mongo.Application
+	if includeDeleted, _ := strconv.ParseBool(con.Query("include_deleted")); includeDeleted {
+		if !(requestTeacher.Administration || requestTeacher.AV || requestTeacher.SuperUser || requestTeacher.PEK) {
+			con.JSON(http.StatusUnauthorized, Error{localize(con, "unauthorized")})
+			return
+		}
+		applications = appFilter.apply(db.GetAllApplicationsIncludingDeleted())
+	} else {
+		applications = appFilter.apply(db.GetAllApplications())
+	}
 	var teacher mongo.Teacher
 	if db.DoesTeacherExistByShort(filter) {
 		teacher = db.GetTeacherByShort(filter)
 	} else {
-		client := untis.GetClient(auth.Username)
+		client, ok := untis.GetClient(auth.Username)
+		if !ok {
+			con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+			return
+		}
 		longname, err := ldap.GetLongName(client.Username, client.Password, filter)
 		if err != nil {
-			con.JSON(http.StatusInternalServerError, Error{"couldn't read longname of new teacher"})
+			con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't read longname of new teacher")})
+			return
+		}
+		err = client.Acquire()
+		if err != nil {
+			con.JSON(untisStatus(err), Error{localize(con, "couldn't authenticate with untis API")})
 			return
 		}
-		err = client.Authenticate()
 		defer func() {
-			err = client.Close()
+			err = client.Release()
 			if err != nil {
-				con.JSON(http.StatusInternalServerError, Error{"couldn't logout out off untis API"})
+				con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't logout out off untis API")})
 			}
 		}()
-		if err != nil {
-			con.JSON(http.StatusInternalServerError, Error{"couldn't authenticate with untis API"})
-			return
-		}
 		id, err := client.ResolveTeacherID(longname)
 		if err != nil {
-			con.JSON(http.StatusInternalServerError, Error{"couldn't resolve untis id of new teacher"})
+			con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't resolve untis id of new teacher")})
 			return
 		}
 		untisAb, err := client.ResolveTeachers([]int{id})
 		if err != nil {
-			con.JSON(http.StatusInternalServerError, Error{"couldn't resolve untis abbrevation of new teacher"})
+			con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't resolve untis abbrevation of new teacher")})
 			return
 		}
 		teacher = mongo.Teacher{
@@ -624,7 +793,7 @@ func GetAllApplications(con *gin.Context) {
 			Untis:          untisAb[0],
 		}
 		if !db.CreateTeacher(teacher) {
-			con.JSON(http.StatusInternalServerError, Error{"couldn't create new teacher based on this"})
+			con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create new teacher based on this")})
 			return
 		}
 	}
@@ -649,32 +818,50 @@ func GetAllApplications(con *gin.Context) {
 				}
 			}
 		}
-		con.JSON(http.StatusOK, res)
+		respondApplicationPage(con, paginateApplications(res, offset, limit), fields)
 		return
 	}
-	con.JSON(http.StatusOK, applications)
+	respondApplicationPage(con, paginateApplications(applications, offset, limit), fields)
 }
 
 // GetNews represents the get news endpoint
 // @Summary Returns the news
-// @Description Returns the 10 last changed applications
+// @Description Returns a page of the applications the logged in teacher participates in, ordered by their last change (newest first by default)
+// @Description The response has a Last-Modified header, if it isn't after the If-Modified-Since header of the request 304 is returned without a body
 // @ID get-news
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
-// @Success 200 {array} News
+// @Param offset query int false "Index of the first news on the page" default(0)
+// @Param limit query int false "Maximum amount of news on the page (at most 100)" default(10)
+// @Param sort query string false "Order of the news by their last change" Enums(date_desc, date_asc) default(date_desc)
+// @Param If-Modified-Since header string false "Last-Modified header of a previous response"
+// @Success 200 {object} NewsPage
+// @Success 304 "Not Modified"
+// @Failure 400 {object} Error
 // @Failure 401 {object} Error
+// @Failure 422 {object} Error
 // @Failure 500 {object} Error
 // @Router /getNews [get]
 func GetNews(con *gin.Context) {
 	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"unauthorized"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "unauthorized")})
+		return
+	}
+	offset, limit, ok := parsePaginationOf(con, NewsPageSize)
+	if !ok {
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
+		return
+	}
+	newestFirst, err := parseNewsSort(con)
+	if err != nil {
+		con.JSON(http.StatusBadRequest, Error{localize(con, err.Error())})
 		return
 	}
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
@@ -682,35 +869,27 @@ func GetNews(con *gin.Context) {
 	teacher := db.GetTeacherByShort(auth.Username)
 	res := make([]mongo.Application, 0)
 	for _, app := range applications {
-		if app.Kind == mongo.SchoolEvent {
-			teachers := app.SchoolEventDetails.Teachers
-			for _, t := range teachers {
-				if t.Shortname == teacher.Short {
-					res = append(res, app)
-					break
-				}
-			}
-		} else if app.Kind == mongo.Training {
-			if app.TrainingDetails.Filer == teacher.Longname {
-				res = append(res, app)
-			}
-		} else if app.Kind == mongo.OtherReason {
-			if app.OtherReasonDetails.Filer == teacher.Longname {
-				res = append(res, app)
-			}
+		if isParticipant(app, teacher) {
+			res = append(res, app)
 		}
 	}
+	// the uuid breaks ties, so pages don't overlap when applications were changed at the same time
 	sort.Slice(res, func(i, j int) bool {
-		return res[i].LastChanged.After(res[j].LastChanged)
+		if !res[i].LastChanged.Equal(res[j].LastChanged) {
+			return res[i].LastChanged.After(res[j].LastChanged) == newestFirst
+		}
+		return res[i].UUID < res[j].UUID
 	})
-	if len(res) > 10 {
-		res = res[0:10]
+	con.Header("Cache-Control", NewsCacheControl)
+	if notModifiedSince(con, newsLastModified(db.ApplicationsModified(), res), time.Now()) {
+		con.Status(http.StatusNotModified)
+		return
 	}
-	news := make([]News, 0)
+	news := make([]News, 0, len(res))
 	for _, app := range res {
 		news = append(news, News{app.UUID, app.Name, app.Progress, app.LastChanged.String()})
 	}
-	con.JSON(http.StatusOK, news)
+	con.JSON(http.StatusOK, paginateNews(news, offset, limit))
 }
 
 // GetApplication represents the get application endpoint
@@ -730,24 +909,24 @@ func GetNews(con *gin.Context) {
 func GetApplication(con *gin.Context) {
 	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
 		return
 	}
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
 	query := con.Request.URL.Query()
 	uuid := query.Get("uuid")
 	if query.Get("uuid") == "" {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	requestTeacher := db.GetTeacherByShort(auth.Username)
 	if !db.DoesApplicationExist(uuid) {
-		con.JSON(http.StatusNotFound, Error{"application not found"})
+		con.JSON(http.StatusNotFound, Error{localize(con, "application not found")})
 		return
 	}
 	application := db.GetApplication(uuid)
@@ -770,12 +949,69 @@ func GetApplication(con *gin.Context) {
 		}
 	}
 	if !(in || requestTeacher.Administration || requestTeacher.AV || requestTeacher.PEK || requestTeacher.SuperUser) {
-		con.JSON(http.StatusUnauthorized, Error{"unauthorized"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "unauthorized")})
 		return
 	}
 	con.JSON(http.StatusOK, application)
 }
 
+// GetApplications represents the get applications endpoint
+// @Summary Returns several Applications
+// @Description Returns the Applications matching the given UUIDs keyed by their UUID, at most 50 at once
+// @Description UUIDs without an Application or of Applications the logged in teacher may not see are listed in not_found instead of failing the whole request
+// @ID get-applications
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param request body ApplicationsRequest true "UUIDs of the Applications"
+// @Success 200 {object} ApplicationBatch
+// @Failure 400 {object} ValidationError
+// @Failure 401 {object} Error
+// @Failure 500 {object} Error
+// @Router /getApplications [post]
+func GetApplications(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	var req ApplicationsRequest
+	if err := con.ShouldBindJSON(&req); err != nil {
+		con.JSON(http.StatusBadRequest, validationError(con, err, req))
+		return
+	}
+	if len(req.IDs) > MaxBatchApplications {
+		con.JSON(http.StatusBadRequest, Error{localizef(con, "at most %d applications may be requested at once", MaxBatchApplications)})
+		return
+	}
+	db := mongo.MongoDatabaseConnector{}
+	if !db.Connect() {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
+		return
+	}
+	defer db.Close()
+	requestTeacher := db.GetTeacherByShort(auth.Username)
+	privileged := requestTeacher.Administration || requestTeacher.AV || requestTeacher.PEK || requestTeacher.SuperUser
+	res := ApplicationBatch{
+		Applications: make(map[string]mongo.Application),
+		NotFound:     make([]string, 0),
+	}
+	for _, application := range db.GetApplications(req.IDs) {
+		// applications the teacher may not see are reported like missing ones, so their existence isn't revealed
+		if privileged || isParticipant(application, requestTeacher) {
+			res.Applications[application.UUID] = application
+		}
+	}
+	reported := make(map[string]bool)
+	for _, uuid := range req.IDs {
+		if _, ok := res.Applications[uuid]; !ok && !reported[uuid] {
+			reported[uuid] = true
+			res.NotFound = append(res.NotFound, uuid)
+		}
+	}
+	con.JSON(http.StatusOK, res)
+}
+
 // GetAdminApplications represents the get admin applications endpoint
 // @Summary Returns all admin applications
 // @Description Returns all applications currently needing a review by an admin
@@ -790,18 +1026,18 @@ func GetApplication(con *gin.Context) {
 func GetAdminApplications(con *gin.Context) {
 	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
 		return
 	}
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
 	teacher := db.GetTeacherByShort(auth.Username)
 	if !(teacher.PEK || teacher.Administration || teacher.AV || teacher.SuperUser) {
-		con.JSON(http.StatusUnauthorized, Error{"unauthorized"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "unauthorized")})
 		return
 	}
 	applications := db.GetAllApplications()
@@ -820,44 +1056,74 @@ func GetAdminApplications(con *gin.Context) {
 // CreateApplication represents the create applications endpoint
 // @Summary Creates a new application
 // @Description Creates the provided application in the system
+// @Description It has to meet the same rules as checked by the validate application endpoint, otherwise 400 is returned with all violations
 // @ID create-application
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
-// @Param application body db.Application true "The Application Data"
+// @Param application body CreateApplicationRequest true "The Application Data"
 // @Success 200 {object} Information
+// @Failure 400 {object} ValidationError
 // @Failure 401 {object} Error
-// @Failure 422 {object} Error
 // @Failure 500 {object} Error
 // @Router /createApplication [post]
 func CreateApplication(con *gin.Context) {
-	app := mongo.Application{}
-	if err := con.ShouldBindJSON(&app); err != nil {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+	req, res, malformed := validateApplicationRequest(con)
+	if malformed || len(res.Fields) > 0 {
+		con.JSON(http.StatusBadRequest, res)
 		return
 	}
+	app := req.toApplication()
 	app.UUID = uuidG.NewString()
-	_, err := ExtractTokenMeta(con.Request)
+	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
 		return
 	}
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
 	if db.CreateApplication(app) {
+		applicationEvents.publish(ApplicationCreated, db.GetApplication(app.UUID))
+		recordAudit(con, auth.Username, AuditCreateApplication, app.UUID, nil, nil)
 		con.JSON(http.StatusOK, Information{"success; application created"})
 	} else {
-		con.JSON(http.StatusInternalServerError, Error{"error; application not created"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "error; application not created")})
+	}
+}
+
+// ValidateApplication represents the validate application endpoint
+// @Summary Checks a new application without creating it
+// @Description Checks the provided application against the same rules as the create application endpoint (e.g. it has to start in the future, end after it starts and contain the details of its kind) and returns all violations without storing it
+// @ID validate-application
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param application body CreateApplicationRequest true "The Application Data"
+// @Success 200 {object} ApplicationValidation
+// @Failure 400 {object} ValidationError
+// @Failure 401 {object} Error
+// @Router /validateApplication [post]
+func ValidateApplication(con *gin.Context) {
+	if _, err := ExtractTokenMeta(con.Request); err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	_, res, malformed := validateApplicationRequest(con)
+	if malformed {
+		con.JSON(http.StatusBadRequest, res)
+		return
 	}
+	con.JSON(http.StatusOK, ApplicationValidation{Valid: len(res.Fields) == 0, Violations: res.Fields})
 }
 
 // UpdateApplication represents the update applications endpoint
 // @Summary Updates an existing application
 // @Description Updates an application identified by a uuid with the data in the body in the system
+// @Description The body has to contain the version of the application it is based on, if the application was updated in the meantime 409 is returned
 // @ID update-application
 // @Accept json
 // @Produce json
@@ -867,38 +1133,44 @@ func CreateApplication(con *gin.Context) {
 // @Success 200 {object} Information
 // @Failure 401 {object} Error
 // @Failure 404 {object} Error
+// @Failure 409 {object} Error
 // @Failure 422 {object} Error
 // @Failure 500 {object} Error
 // @Router /updateApplication [put]
 func UpdateApplication(con *gin.Context) {
 	app := mongo.Application{}
 	if err := con.ShouldBindJSON(&app); err != nil {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
 		return
 	}
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
 	query := con.Request.URL.Query()
 	uuid := query.Get("uuid")
 	if query.Get("uuid") == "" {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	requestTeacher := db.GetTeacherByShort(auth.Username)
 	if !db.DoesApplicationExist(uuid) {
-		con.JSON(http.StatusNotFound, Error{"application not found"})
+		con.JSON(http.StatusNotFound, Error{localize(con, "application not found")})
 		return
 	}
 	application := db.GetApplication(uuid)
+	if application.DeletedAt != nil {
+		con.JSON(http.StatusNotFound, Error{localize(con, "application not found")})
+		return
+	}
+	app.DeletedAt = nil
 	var in bool
 	if application.Kind == mongo.SchoolEvent {
 		teachers := application.SchoolEventDetails.Teachers
@@ -918,19 +1190,29 @@ func UpdateApplication(con *gin.Context) {
 		}
 	}
 	if !(in || requestTeacher.Administration || requestTeacher.AV || requestTeacher.PEK || requestTeacher.SuperUser) {
-		con.JSON(http.StatusUnauthorized, Error{"unauthorized"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "unauthorized")})
 		return
 	}
-	if db.UpdateApplication(uuid, app) {
+	updated, conflict := db.UpdateApplicationIfVersion(uuid, app, app.Version)
+	if conflict {
+		con.JSON(http.StatusConflict, Error{localize(con, "application was changed in the meantime; reload it and apply the changes again")})
+	} else if updated {
+		stored := db.GetApplication(uuid)
+		if stored.Progress != application.Progress {
+			applicationEvents.publish(ApplicationStatusChanged, stored)
+		} else {
+			applicationEvents.publish(ApplicationUpdated, stored)
+		}
+		recordAudit(con, auth.Username, AuditUpdateApplication, uuid, application, stored)
 		con.JSON(http.StatusOK, Information{"success; application updated"})
 	} else {
-		con.JSON(http.StatusInternalServerError, Error{"error; application not updated"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "error; application not updated")})
 	}
 }
 
 // DeleteApplication represents the delete applications endpoint
 // @Summary Deletes an existing application
-// @Description Deletes an application identified by a uuid
+// @Description Deletes an application identified by a uuid, it is excluded from all listings afterwards but can be restored
 // @ID delete-application
 // @Accept json
 // @Produce json
@@ -938,34 +1220,38 @@ func UpdateApplication(con *gin.Context) {
 // @Param uuid query string true "Identifier of the application to delete"
 // @Success 200 {object} Information
 // @Failure 401 {object} Error
-// @Failure 404 {object Error
+// @Failure 404 {object} Error
 // @Failure 422 {object} Error
 // @Failure 500 {object} Error
 // @Router /deleteApplication [delete]
 func DeleteApplication(con *gin.Context) {
 	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
 		return
 	}
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
 	query := con.Request.URL.Query()
 	uuid := query.Get("uuid")
 	if query.Get("uuid") == "" {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	requestTeacher := db.GetTeacherByShort(auth.Username)
 	if !db.DoesApplicationExist(uuid) {
-		con.JSON(http.StatusNotFound, Error{"application not found"})
+		con.JSON(http.StatusNotFound, Error{localize(con, "application not found")})
 		return
 	}
 	application := db.GetApplication(uuid)
+	if application.DeletedAt != nil {
+		con.JSON(http.StatusNotFound, Error{localize(con, "application not found")})
+		return
+	}
 	var in bool
 	if application.Kind == mongo.SchoolEvent {
 		teachers := application.SchoolEventDetails.Teachers
@@ -985,13 +1271,69 @@ func DeleteApplication(con *gin.Context) {
 		}
 	}
 	if !(in || requestTeacher.Administration || requestTeacher.AV || requestTeacher.PEK || requestTeacher.SuperUser) {
-		con.JSON(http.StatusUnauthorized, Error{"unauthorized"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "unauthorized")})
 		return
 	}
-	if db.DeleteApplication(uuid) {
+	if db.SoftDeleteApplication(uuid) {
+		applicationEvents.publish(ApplicationDeleted, db.GetApplication(uuid))
+		recordAudit(con, auth.Username, AuditDeleteApplication, uuid, nil, nil)
 		con.JSON(http.StatusOK, Information{"success; application deleted"})
 	} else {
-		con.JSON(http.StatusInternalServerError, Error{"error; application not deleted"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "error; application not deleted")})
+	}
+}
+
+// RestoreApplication represents the restore applications endpoint
+// @Summary Restores a deleted application
+// @Description Restores a deleted application identified by a uuid, so that it is listed again
+// @ID restore-application
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param uuid query string true "Identifier of the application to restore"
+// @Success 200 {object} Information
+// @Failure 401 {object} Error
+// @Failure 404 {object} Error
+// @Failure 422 {object} Error
+// @Failure 500 {object} Error
+// @Router /restoreApplication [post]
+func RestoreApplication(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	uuid := con.Query("uuid")
+	if uuid == "" {
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
+		return
+	}
+	db := mongo.MongoDatabaseConnector{}
+	if !db.Connect() {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
+		return
+	}
+	defer db.Close()
+	requestTeacher := db.GetTeacherByShort(auth.Username)
+	if !db.DoesApplicationExist(uuid) {
+		con.JSON(http.StatusNotFound, Error{localize(con, "application not found")})
+		return
+	}
+	application := db.GetApplication(uuid)
+	if !(isParticipant(application, requestTeacher) || requestTeacher.Administration || requestTeacher.AV || requestTeacher.PEK || requestTeacher.SuperUser) {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "unauthorized")})
+		return
+	}
+	if application.DeletedAt == nil {
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "application isn't deleted")})
+		return
+	}
+	if db.RestoreApplication(uuid) {
+		applicationEvents.publish(ApplicationRestored, db.GetApplication(uuid))
+		recordAudit(con, auth.Username, AuditRestoreApplication, uuid, nil, nil)
+		con.JSON(http.StatusOK, Information{"success; application restored"})
+	} else {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "error; application not restored")})
 	}
 }
 
@@ -1013,18 +1355,18 @@ func DeleteApplication(con *gin.Context) {
 func GetAbsenceFormForClasses(con *gin.Context) {
 	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
 	}
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
 	_ = con.Request.ParseForm()
 	query := con.Request.URL.Query()
 	if _, hasUUID := con.Request.Form["uuid"]; !hasUUID {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	uuid := query.Get("uuid")
@@ -1034,7 +1376,7 @@ func GetAbsenceFormForClasses(con *gin.Context) {
 		classes = query["classes"]
 	}
 	if !db.DoesApplicationExist(uuid) {
-		con.JSON(http.StatusNotFound, Error{"application not found"})
+		con.JSON(http.StatusNotFound, Error{localize(con, "application not found")})
 		return
 	}
 	application := db.GetApplication(uuid)
@@ -1058,17 +1400,17 @@ func GetAbsenceFormForClasses(con *gin.Context) {
 		}
 	}
 	if !(in || requestTeacher.Administration || requestTeacher.AV || requestTeacher.PEK || requestTeacher.SuperUser) {
-		con.JSON(http.StatusUnauthorized, Error{"you have no permission to do this"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you have no permission to do this")})
 		return
 	}
 	path, err := files.GenerateFileEnvironment(application)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't create directories"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create directories")})
 		return
 	}
-	paths, err := files.GenerateAbsenceFormForClass(path, auth.Username, application)
+	paths, err := files.GenerateAbsenceFormForClass(path, auth.Username, application, language(con))
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't create pdfs"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create pdfs")})
 		return
 	}
 
@@ -1100,24 +1442,24 @@ func GetAbsenceFormForClasses(con *gin.Context) {
 	created := filepath.Join(filepath.Dir(pp[0]), fmt.Sprintf(files.ClassAbsenceFormFileName, "merge"))
 	err = api.MergeCreateFile(pp, created, pdfcpu.NewDefaultConfiguration())
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't save merged pdf"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't save merged pdf")})
 		return
 	}
 	err = api.OptimizeFile(created, "", nil)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't optimize pdf"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't optimize pdf")})
 		return
 	}
 	file, err := ioutil.ReadFile(created)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't read merged pdf"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't read merged pdf")})
 		return
 	}
 	enc := base64.StdEncoding.EncodeToString(file)
 	res := PDF{enc}
 	err = os.Remove(created)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't delete merged pdf"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't delete merged pdf")})
 		return
 	}
 	con.JSON(http.StatusOK, res)
@@ -1141,18 +1483,18 @@ func GetAbsenceFormForClasses(con *gin.Context) {
 func GetAbsenceFormForTeacher(con *gin.Context) {
 	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
 	}
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
 	_ = con.Request.ParseForm()
 	query := con.Request.URL.Query()
 	if _, hasUUID := con.Request.Form["uuid"]; !hasUUID {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	uuid := query.Get("uuid")
@@ -1162,7 +1504,7 @@ func GetAbsenceFormForTeacher(con *gin.Context) {
 		teacher = query.Get("teacher")
 	}
 	if !db.DoesApplicationExist(uuid) {
-		con.JSON(http.StatusNotFound, Error{"application not found"})
+		con.JSON(http.StatusNotFound, Error{localize(con, "application not found")})
 		return
 	}
 	application := db.GetApplication(uuid)
@@ -1186,32 +1528,32 @@ func GetAbsenceFormForTeacher(con *gin.Context) {
 		}
 	}
 	if !((!applyTeacher && in) || (applyTeacher && (requestTeacher.Administration || requestTeacher.AV || requestTeacher.PEK || requestTeacher.SuperUser))) {
-		con.JSON(http.StatusUnauthorized, Error{"you have no permission to do this"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you have no permission to do this")})
 		return
 	}
 	path, err := files.GenerateFileEnvironment(application)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't create directories"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create directories")})
 		return
 	}
 	if applyTeacher {
 		reqTeacher := db.GetTeacherByShort(teacher)
-		path, err = files.GenerateAbsenceFormForTeacher(path, auth.Username, reqTeacher.Longname, application)
+		path, err = files.GenerateAbsenceFormForTeacher(path, auth.Username, reqTeacher.Longname, application, language(con))
 	} else {
-		path, err = files.GenerateAbsenceFormForTeacher(path, auth.Username, "self", application)
+		path, err = files.GenerateAbsenceFormForTeacher(path, auth.Username, "self", application, language(con))
 	}
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't create pdf"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create pdf")})
 		return
 	}
 	err = api.OptimizeFile(path, "", nil)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't optimize pdf"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't optimize pdf")})
 		return
 	}
 	file, err := ioutil.ReadFile(path)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't read generated pdf"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't read generated pdf")})
 		return
 	}
 	enc := base64.StdEncoding.EncodeToString(file)
@@ -1236,23 +1578,23 @@ func GetAbsenceFormForTeacher(con *gin.Context) {
 func GetCompensationForEducationalSupportForm(con *gin.Context) {
 	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
 	}
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
 	_ = con.Request.ParseForm()
 	query := con.Request.URL.Query()
 	if _, hasUUID := con.Request.Form["uuid"]; !hasUUID {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	uuid := query.Get("uuid")
 	if !db.DoesApplicationExist(uuid) {
-		con.JSON(http.StatusNotFound, Error{"application not found"})
+		con.JSON(http.StatusNotFound, Error{localize(con, "application not found")})
 		return
 	}
 	application := db.GetApplication(uuid)
@@ -1276,27 +1618,27 @@ func GetCompensationForEducationalSupportForm(con *gin.Context) {
 		}
 	}
 	if !(in || requestTeacher.Administration || requestTeacher.AV || requestTeacher.PEK || requestTeacher.SuperUser) {
-		con.JSON(http.StatusUnauthorized, Error{"you have no permission to do this"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you have no permission to do this")})
 		return
 	}
 	path, err := files.GenerateFileEnvironment(application)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't create directories"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create directories")})
 		return
 	}
-	path, err = files.GenerateCompensationForEducationalSupport(path, application)
+	path, err = files.GenerateCompensationForEducationalSupport(path, application, language(con))
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't create pdfs"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create pdfs")})
 		return
 	}
 	err = api.OptimizeFile(path, "", nil)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't optimize pdf"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't optimize pdf")})
 		return
 	}
 	file, err := ioutil.ReadFile(path)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't read generated pdf"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't read generated pdf")})
 		return
 	}
 	enc := base64.StdEncoding.EncodeToString(file)
@@ -1304,155 +1646,263 @@ func GetCompensationForEducationalSupportForm(con *gin.Context) {
 	con.JSON(http.StatusOK, res)
 }
 
-// GetTravelInvoiceForm represents get travel invoice form endpoint
-// @Summary Generates a travel invoice for a teacher
-// @Description Generates a travel invoice form for a teacher and returns it
-// @ID get-travel-invoice-form
+// GetTravelInvoice represents the get travel invoice endpoint
+// @Summary Returns a travel invoice of a teacher
+// @Description Returns a travel invoice of a teacher as json, as excel or as pdf file
+// @Description The format is chosen by the format query parameter, or by the Accept header if it is omitted (json if neither is provided), 406 is returned for other formats
+// @ID get-travel-invoice
 // @Accept json
-// @Produce json
+// @Produce json,application/pdf,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
-// @Param uuid query string true "Identifier of the application to generate the pdf from"
+// @Param uuid query string true "Identifier of the application the travel invoice belongs to"
 // @Param short query string true "Short name of the teacher this should be generated for"
 // @Param ti_id query int true "ID of the Travel Invoice data"
-// @Param receipts query bool false "If provided the pdf will include all receipt"
-// @Success 200 {object} PDF
+// @Param format query string false "Format of the travel invoice" Enums(json, xlsx, pdf)
+// @Param receipts query bool false "If provided the pdf will include all receipts"
+// @Success 200 {object} db.TravelInvoice
 // @Failure 401 {object} Error
 // @Failure 404 {object} Error
+// @Failure 406 {object} Error
 // @Failure 422 {object} Error
 // @Failure 500 {object} Error
-// @Router /getTravelInvoiceForm [get]
-func GetTravelInvoiceForm(con *gin.Context) {
+// @Router /getTravelInvoice [get]
+func GetTravelInvoice(con *gin.Context) {
+	format, ok := negotiateFormat(con, FormatJSON, FormatXLSX, FormatPDF)
+	if !ok {
+		con.JSON(http.StatusNotAcceptable, Error{localize(con, "unsupported format requested")})
+		return
+	}
+	serveTravelInvoice(con, format, false)
+}
+
+// serveTravelInvoice responds with the travel invoice requested by the query parameters in the format
+// if encoded is set, excel and pdf files are returned base64 encoded inside of json like the deprecated endpoints did
+func serveTravelInvoice(con *gin.Context, format string, encoded bool) {
 	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
-	}
-	db := mongo.MongoDatabaseConnector{}
-	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
 		return
 	}
-	defer db.Close()
-	_ = con.Request.ParseForm()
 	query := con.Request.URL.Query()
-	if _, hasUUID := con.Request.Form["uuid"]; !hasUUID {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
-		return
-	}
 	uuid := query.Get("uuid")
-	if _, hasShort := con.Request.Form["short"]; !hasShort {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
-		return
-	}
 	short := query.Get("short")
-	if _, hasTIID := con.Request.Form["ti_id"]; !hasTIID {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+	if uuid == "" || short == "" || query.Get("ti_id") == "" {
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	tiID, err := strconv.Atoi(query.Get("ti_id"))
 	if err != nil {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid ti_id provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid ti_id provided")})
+		return
+	}
+	_, withReceipts := query["receipts"]
+	db := mongo.MongoDatabaseConnector{}
+	if !db.Connect() {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
-	_, applyMergeReceipts := con.Request.Form["receipts"]
+	defer db.Close()
 	if !db.DoesApplicationExist(uuid) {
-		con.JSON(http.StatusNotFound, Error{"application not found"})
+		con.JSON(http.StatusNotFound, Error{localize(con, "application not found")})
 		return
 	}
 	application := db.GetApplication(uuid)
 	requestTeacher := db.GetTeacherByShort(auth.Username)
-	var in bool
-	if application.Kind == mongo.SchoolEvent {
-		teachers := application.SchoolEventDetails.Teachers
-		for _, t := range teachers {
-			if t.Shortname == requestTeacher.Short {
-				in = true
-				break
-			}
-		}
-	} else if application.Kind == mongo.Training {
-		if application.TrainingDetails.Filer == requestTeacher.Longname {
-			in = true
-		}
-	} else if application.Kind == mongo.OtherReason {
-		if application.OtherReasonDetails.Filer == requestTeacher.Longname {
-			in = true
-		}
-	}
-	if !(in || requestTeacher.Administration || requestTeacher.AV || requestTeacher.PEK || requestTeacher.SuperUser) {
-		con.JSON(http.StatusUnauthorized, Error{"you have no permission to do this"})
-		return
-	}
-	path, err := files.GenerateFileEnvironment(application)
-	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't create directories"})
+	if !(isParticipant(application, requestTeacher) || requestTeacher.Administration || requestTeacher.AV || requestTeacher.PEK || requestTeacher.SuperUser) {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you have no permission to do this")})
 		return
 	}
 	var ti mongo.TravelInvoice
+	found := false
 	for _, tis := range application.TravelInvoices {
 		if tis.ID == tiID {
 			ti = tis
+			found = true
 			break
 		}
 	}
-	path, err = files.GenerateTravelInvoice(path, short, ti, application.UUID)
-	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't create pdfs"})
+	if !found {
+		con.JSON(http.StatusNotFound, Error{localize(con, "travel invoice not found")})
+		return
+	}
+	if format == FormatJSON {
+		con.JSON(http.StatusOK, ti)
+		return
+	}
+	var file []byte
+	var name string
+	var ok bool
+	if format == FormatXLSX {
+		file, name, ok = travelInvoiceExcel(con, application, short, ti)
+	} else {
+		file, name, ok = travelInvoicePDF(con, application, short, ti, withReceipts)
+	}
+	if !ok {
 		return
 	}
+	switch {
+	case encoded && format == FormatXLSX:
+		con.JSON(http.StatusOK, Excel{base64.StdEncoding.EncodeToString(file)})
+	case encoded:
+		con.JSON(http.StatusOK, PDF{base64.StdEncoding.EncodeToString(file)})
+	default:
+		con.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+		con.Data(http.StatusOK, formatContentTypes[format], file)
+	}
+}
 
-	if applyMergeReceipts {
-		pp := append(make([]string, 0), path)
-		uploadFolder := filepath.Join(filepath.Dir(path), files.UploadFolderName)
-		ff, err := ioutil.ReadDir(uploadFolder)
+// travelInvoicePDF generates the travel invoice of the teacher short as pdf and returns its content and file name,
+// the uploaded pdf receipts of the teacher are appended if withReceipts is set
+// if it fails, the request is answered with an error and false is returned
+func travelInvoicePDF(con *gin.Context, application mongo.Application, short string, ti mongo.TravelInvoice, withReceipts bool) ([]byte, string, bool) {
+	path, err := files.GenerateFileEnvironment(application)
+	if err != nil {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create directories")})
+		return nil, "", false
+	}
+	path, err = files.GenerateTravelInvoice(path, short, ti, application.UUID, language(con))
+	if err != nil {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create pdf")})
+		return nil, "", false
+	}
+	if !withReceipts {
+		err = api.OptimizeFile(path, "", nil)
 		if err != nil {
-			con.JSON(http.StatusInternalServerError, Error{"couldn't read upload directory"})
-			return
-		}
-		for _, file := range ff {
-			data := strings.Split(file.Name(), "_")
-			if data[1] == short {
-				pp = append(pp, filepath.Join(uploadFolder, file.Name()))
-			}
+			con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't optimize pdf")})
+			return nil, "", false
 		}
-		created := filepath.Join(filepath.Dir(path), fmt.Sprintf(files.TravelInvoicePDFFileName, short+"_merge"))
-		err = api.MergeCreateFile(pp, created, pdfcpu.NewDefaultConfiguration())
-		if err != nil {
-			con.JSON(http.StatusInternalServerError, Error{"couldn't save merged pdf; the uploaded files might be corrupted"})
-			return
-		}
-		err = api.OptimizeFile(created, "", nil)
-		if err != nil {
-			con.JSON(http.StatusInternalServerError, Error{"couldn't optimize merged pdf"})
-			return
-		}
-		file, err := ioutil.ReadFile(created)
-		if err != nil {
-			con.JSON(http.StatusInternalServerError, Error{"couldn't read generated pdf"})
-			return
-		}
-		enc := base64.StdEncoding.EncodeToString(file)
-		res := PDF{enc}
-		err = os.Remove(created)
+		file, err := ioutil.ReadFile(path)
 		if err != nil {
-			con.JSON(http.StatusInternalServerError, Error{"couldn't delete merged pdf"})
-			return
+			con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't read generated pdf")})
+			return nil, "", false
 		}
-		con.JSON(http.StatusOK, res)
-	} else {
-		err = api.OptimizeFile(path, "", nil)
-		if err != nil {
-			con.JSON(http.StatusInternalServerError, Error{"couldn't optimize pdf"})
-			return
+		return file, filepath.Base(path), true
+	}
+	receipts, err := files.Receipts(application.UUID, short)
+	if err != nil {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't read upload directory")})
+		return nil, "", false
+	}
+	pp := append(make([]string, 0), path)
+	for _, receipt := range receipts {
+		if strings.EqualFold(filepath.Ext(receipt), ".pdf") {
+			pp = append(pp, receipt)
 		}
-		file, err := ioutil.ReadFile(path)
-		if err != nil {
-			con.JSON(http.StatusInternalServerError, Error{"couldn't read generated pdf"})
-			return
+	}
+	created := filepath.Join(filepath.Dir(path), fmt.Sprintf(files.TravelInvoicePDFFileName, short+"_merge"))
+	err = api.MergeCreateFile(pp, created, pdfcpu.NewDefaultConfiguration())
+	if err != nil {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't save merged pdf; the uploaded files might be corrupted")})
+		return nil, "", false
+	}
+	err = api.OptimizeFile(created, "", nil)
+	if err != nil {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't optimize merged pdf")})
+		return nil, "", false
+	}
+	file, err := ioutil.ReadFile(created)
+	if err != nil {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't read generated pdf")})
+		return nil, "", false
+	}
+	err = os.Remove(created)
+	if err != nil {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't delete merged pdf")})
+		return nil, "", false
+	}
+	return file, filepath.Base(created), true
+}
+
+// travelInvoiceExcel generates the travel invoice of the teacher short as excel and returns its content and file name
+// if it fails, the request is answered with an error and false is returned
+func travelInvoiceExcel(con *gin.Context, application mongo.Application, short string, ti mongo.TravelInvoice) ([]byte, string, bool) {
+	path, err := files.GenerateFileEnvironment(application)
+	if err != nil {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create directories")})
+		return nil, "", false
+	}
+	path, err = files.GenerateTravelInvoiceExcel(path, short, ti)
+	if err != nil {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create excel")})
+		return nil, "", false
+	}
+	file, err := ioutil.ReadFile(path)
+	if err != nil {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't read generated excel")})
+		return nil, "", false
+	}
+	return file, filepath.Base(path), true
+}
+
+// GetTravelInvoiceForm represents get travel invoice form endpoint
+// Deprecated: use GetTravelInvoice with the pdf format, which returns the pdf file itself
+// @Summary Generates a travel invoice for a teacher
+// @Description Generates a travel invoice form for a teacher and returns it (deprecated, use /getTravelInvoice?format=pdf)
+// @ID get-travel-invoice-form
+// @Deprecated
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param uuid query string true "Identifier of the application to generate the pdf from"
+// @Param short query string true "Short name of the teacher this should be generated for"
+// @Param ti_id query int true "ID of the Travel Invoice data"
+// @Param receipts query bool false "If provided the pdf will include all receipt"
+// @Success 200 {object} PDF
+// @Failure 401 {object} Error
+// @Failure 404 {object} Error
+// @Failure 422 {object} Error
+// @Failure 500 {object} Error
+// @Router /getTravelInvoiceForm [get]
+func GetTravelInvoiceForm(con *gin.Context) {
+	serveTravelInvoice(con, FormatPDF, true)
+}
+
+// GetTravelInvoicePDF represents get travel invoice pdf endpoint
+// Deprecated: use GetTravelInvoice with the pdf format
+// @Summary Generates a travel invoice pdf for a teacher
+// @Description Generates a travel invoice form for a teacher and returns it as a pdf file, based on the same data as the excel export (deprecated, use /getTravelInvoice?format=pdf)
+// @ID get-travel-invoice-pdf
+// @Deprecated
+// @Accept json
+// @Produce application/pdf
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param uuid query string true "Identifier of the application to generate the pdf from"
+// @Param short query string true "Short name of the teacher this should be generated for"
+// @Param ti_id query int true "ID of the Travel Invoice data"
+// @Success 200 {file} file
+// @Failure 401 {object} Error
+// @Failure 404 {object} Error
+// @Failure 422 {object} Error
+// @Failure 500 {object} Error
+// @Router /getTravelInvoicePDF [get]
+func GetTravelInvoicePDF(con *gin.Context) {
+	serveTravelInvoice(con, FormatPDF, false)
+}
+
+// untisStatus returns the status a request failing because of the untis error err is answered with, which is
+// 502 Bad Gateway if untis is unavailable and 500 otherwise
+func untisStatus(err error) int {
+	var unavailable *untis.UpstreamUnavailableError
+	if errors.As(err, &unavailable) {
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
+}
+
+// isParticipant checks whether the teacher takes part in or filed the application
+func isParticipant(application mongo.Application, teacher mongo.Teacher) bool {
+	if application.Kind == mongo.SchoolEvent {
+		for _, t := range application.SchoolEventDetails.Teachers {
+			if t.Shortname == teacher.Short {
+				return true
+			}
 		}
-		enc := base64.StdEncoding.EncodeToString(file)
-		res := PDF{enc}
-		con.JSON(http.StatusOK, res)
+	} else if application.Kind == mongo.Training {
+		return application.TrainingDetails.Filer == teacher.Longname
+	} else if application.Kind == mongo.OtherReason {
+		return application.OtherReasonDetails.Filer == teacher.Longname
 	}
+	return false
 }
 
 // GetBusinessTripApplicationForm represents get business application form endpoint
@@ -1474,37 +1924,37 @@ func GetTravelInvoiceForm(con *gin.Context) {
 func GetBusinessTripApplicationForm(con *gin.Context) {
 	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
 	}
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
 	_ = con.Request.ParseForm()
 	query := con.Request.URL.Query()
 	if _, hasUUID := con.Request.Form["uuid"]; !hasUUID {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	uuid := query.Get("uuid")
 	if _, hasShort := con.Request.Form["short"]; !hasShort {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	short := query.Get("short")
 	if _, hasBTAID := con.Request.Form["bta_id"]; !hasBTAID {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	btaID, err := strconv.Atoi(query.Get("bta_id"))
 	if err != nil {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid bta_id provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid bta_id provided")})
 		return
 	}
 	if !db.DoesApplicationExist(uuid) {
-		con.JSON(http.StatusNotFound, Error{"application not found"})
+		con.JSON(http.StatusNotFound, Error{localize(con, "application not found")})
 		return
 	}
 	application := db.GetApplication(uuid)
@@ -1528,12 +1978,12 @@ func GetBusinessTripApplicationForm(con *gin.Context) {
 		}
 	}
 	if !(in || requestTeacher.Administration || requestTeacher.AV || requestTeacher.PEK || requestTeacher.SuperUser) {
-		con.JSON(http.StatusUnauthorized, Error{"you have no permission to do this"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you have no permission to do this")})
 		return
 	}
 	path, err := files.GenerateFileEnvironment(application)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't create directories"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create directories")})
 		return
 	}
 	var bta mongo.BusinessTripApplication
@@ -1543,19 +1993,19 @@ func GetBusinessTripApplicationForm(con *gin.Context) {
 			break
 		}
 	}
-	path, err = files.GenerateBusinessTripApplication(path, short, bta, application.UUID)
+	path, err = files.GenerateBusinessTripApplication(path, short, bta, application.UUID, language(con))
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't create pdf"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create pdf")})
 		return
 	}
 	err = api.OptimizeFile(path, "", nil)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't optimize pdf"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't optimize pdf")})
 		return
 	}
 	file, err := ioutil.ReadFile(path)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't read generated pdf"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't read generated pdf")})
 		return
 	}
 	enc := base64.StdEncoding.EncodeToString(file)
@@ -1564,9 +2014,11 @@ func GetBusinessTripApplicationForm(con *gin.Context) {
 }
 
 // GetTravelInvoiceExcel represents get travel invoice excel endpoint
+// Deprecated: use GetTravelInvoice with the xlsx format, which returns the excel file itself
 // @Summary Generates a travel invoice excel for a teacher
-// @Description Generates a travel invoice excel for a teacher and returns it
+// @Description Generates a travel invoice excel for a teacher and returns it (deprecated, use /getTravelInvoice?format=xlsx)
 // @ID get-travel-invoice-excel
+// @Deprecated
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
@@ -1580,90 +2032,7 @@ func GetBusinessTripApplicationForm(con *gin.Context) {
 // @Failure 500 {object} Error
 // @Router /getTravelInvoiceExcel [get]
 func GetTravelInvoiceExcel(con *gin.Context) {
-	auth, err := ExtractTokenMeta(con.Request)
-	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
-	}
-	db := mongo.MongoDatabaseConnector{}
-	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
-		return
-	}
-	defer db.Close()
-	_ = con.Request.ParseForm()
-	query := con.Request.URL.Query()
-	if _, hasUUID := con.Request.Form["uuid"]; !hasUUID {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
-		return
-	}
-	uuid := query.Get("uuid")
-	if _, hasShort := con.Request.Form["short"]; !hasShort {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
-		return
-	}
-	short := query.Get("short")
-	if _, hasTIID := con.Request.Form["ti_id"]; !hasTIID {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
-		return
-	}
-	tiID, err := strconv.Atoi(query.Get("ti_id"))
-	if err != nil {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid ti_id provided"})
-		return
-	}
-	if !db.DoesApplicationExist(uuid) {
-		con.JSON(http.StatusNotFound, Error{"application not found"})
-		return
-	}
-	application := db.GetApplication(uuid)
-	requestTeacher := db.GetTeacherByShort(auth.Username)
-	var in bool
-	if application.Kind == mongo.SchoolEvent {
-		teachers := application.SchoolEventDetails.Teachers
-		for _, t := range teachers {
-			if t.Shortname == requestTeacher.Short {
-				in = true
-				break
-			}
-		}
-	} else if application.Kind == mongo.Training {
-		if application.TrainingDetails.Filer == requestTeacher.Longname {
-			in = true
-		}
-	} else if application.Kind == mongo.OtherReason {
-		if application.OtherReasonDetails.Filer == requestTeacher.Longname {
-			in = true
-		}
-	}
-	if !(in || requestTeacher.Administration || requestTeacher.AV || requestTeacher.PEK || requestTeacher.SuperUser) {
-		con.JSON(http.StatusUnauthorized, Error{"you have no permission to do this"})
-		return
-	}
-	path, err := files.GenerateFileEnvironment(application)
-	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't create directories"})
-		return
-	}
-	var ti mongo.TravelInvoice
-	for _, tis := range application.TravelInvoices {
-		if tis.ID == tiID {
-			ti = tis
-			break
-		}
-	}
-	path, err = files.GenerateTravelInvoiceExcel(path, short, ti)
-	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't create excel"})
-		return
-	}
-	file, err := ioutil.ReadFile(path)
-	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't read generated excel"})
-		return
-	}
-	enc := base64.StdEncoding.EncodeToString(file)
-	res := Excel{enc}
-	con.JSON(http.StatusOK, res)
+	serveTravelInvoice(con, FormatXLSX, true)
 }
 
 // GetBusinessTripApplicationExcel represents get business application excel endpoint
@@ -1685,37 +2054,37 @@ func GetTravelInvoiceExcel(con *gin.Context) {
 func GetBusinessTripApplicationExcel(con *gin.Context) {
 	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
 	}
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
 	_ = con.Request.ParseForm()
 	query := con.Request.URL.Query()
 	if _, hasUUID := con.Request.Form["uuid"]; !hasUUID {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	uuid := query.Get("uuid")
 	if _, hasShort := con.Request.Form["short"]; !hasShort {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	short := query.Get("short")
 	if _, hasBTAID := con.Request.Form["bta_id"]; !hasBTAID {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	btaID, err := strconv.Atoi(query.Get("bta_id"))
 	if err != nil {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid bta_id provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid bta_id provided")})
 		return
 	}
 	if !db.DoesApplicationExist(uuid) {
-		con.JSON(http.StatusNotFound, Error{"application not found"})
+		con.JSON(http.StatusNotFound, Error{localize(con, "application not found")})
 		return
 	}
 	application := db.GetApplication(uuid)
@@ -1739,12 +2108,12 @@ func GetBusinessTripApplicationExcel(con *gin.Context) {
 		}
 	}
 	if !(in || requestTeacher.Administration || requestTeacher.AV || requestTeacher.PEK || requestTeacher.SuperUser) {
-		con.JSON(http.StatusUnauthorized, Error{"you have no permission to do this"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you have no permission to do this")})
 		return
 	}
 	path, err := files.GenerateFileEnvironment(application)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't create directories"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create directories")})
 		return
 	}
 	var bta mongo.BusinessTripApplication
@@ -1756,12 +2125,12 @@ func GetBusinessTripApplicationExcel(con *gin.Context) {
 	}
 	path, err = files.GenerateBusinessTripApplicationExcel(path, short, bta)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't create excel"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create excel")})
 		return
 	}
 	file, err := ioutil.ReadFile(path)
 	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't read generated excel"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't read generated excel")})
 		return
 	}
 	enc := base64.StdEncoding.EncodeToString(file)
@@ -1772,6 +2141,7 @@ func GetBusinessTripApplicationExcel(con *gin.Context) {
 // SaveBillingReceipt represents get save billing receipt endpoint
 // @Summary Saves a billing receipt
 // @Description Saves a billing receipt in the context of an application
+// @Description The receipts are stored in a directory per year, named after the application, the teacher, the upload time and the extension of the original file name
 // @ID save-billing-receipt
 // @Accept json
 // @Produce json
@@ -1779,7 +2149,7 @@ func GetBusinessTripApplicationExcel(con *gin.Context) {
 // @Param uuid query string true "Identifier of the application to generate the excel from"
 // @Param short query string true "Short name of the teacher this should be generated for"
 // @Param files body PDFs true "The files to save as an array of the base64 decoded file contents"
-// @Success 200 {object} Information
+// @Success 200 {object} SavedReceipts
 // @Failure 401 {object} Error
 // @Failure 404 {object} Error
 // @Failure 422 {object} Error
@@ -1788,33 +2158,33 @@ func GetBusinessTripApplicationExcel(con *gin.Context) {
 func SaveBillingReceipt(con *gin.Context) {
 	auth, err := ExtractTokenMeta(con.Request)
 	if err != nil {
-		con.JSON(http.StatusUnauthorized, Error{"you are not logged in"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
 	}
 	r := PDFs{}
 	if err := con.ShouldBindJSON(&r); err != nil {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	db := mongo.MongoDatabaseConnector{}
 	if !db.Connect() {
-		con.JSON(http.StatusInternalServerError, Error{"database didn't respond"})
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
 		return
 	}
 	defer db.Close()
 	_ = con.Request.ParseForm()
 	query := con.Request.URL.Query()
 	if _, hasUUID := con.Request.Form["uuid"]; !hasUUID {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	uuid := query.Get("uuid")
 	if _, hasShort := con.Request.Form["short"]; !hasShort {
-		con.JSON(http.StatusUnprocessableEntity, Error{"invalid request structure provided"})
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
 	short := query.Get("short")
 	if !db.DoesApplicationExist(uuid) {
-		con.JSON(http.StatusNotFound, Error{"application not found"})
+		con.JSON(http.StatusNotFound, Error{localize(con, "application not found")})
 		return
 	}
 	application := db.GetApplication(uuid)
@@ -1838,47 +2208,742 @@ func SaveBillingReceipt(con *gin.Context) {
 		}
 	}
 	if !in {
-		con.JSON(http.StatusUnauthorized, Error{"you have no permission to do this"})
-		return
-	}
-	path, err := files.GenerateFileEnvironment(application)
-	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't create directories"})
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you have no permission to do this")})
 		return
 	}
-	ff, err := ioutil.ReadDir(filepath.Join(path, files.UploadFolderName))
-	if err != nil {
-		con.JSON(http.StatusInternalServerError, Error{"couldn't read upload directory"})
+	if !files.ValidReceiptOwner(short) {
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
 		return
 	}
-	counter := 1
-	for _, file := range ff {
-		data := strings.Split(file.Name(), "_")
-		if data[1] == short {
-			counter++
-		}
-	}
+	stored := make([]string, 0, len(r.Files))
 	for i, pdf := range r.Files {
-		name := fmt.Sprintf(files.ReceiptFileName, i+counter, short)
 		dec, err := base64.StdEncoding.DecodeString(pdf.Content)
 		if err != nil {
-			con.JSON(http.StatusInternalServerError, Error{fmt.Sprintf("couldn't decode the pdf file: %v", name)})
+			con.JSON(http.StatusInternalServerError, Error{localizef(con, "couldn't decode the pdf file: %v", i+1)})
 			return
 		}
-		file, err := os.Create(filepath.Join(path, files.UploadFolderName, name))
+		path, err := files.SaveReceipt(application.UUID, short, pdf.Name, dec, time.Now())
 		if err != nil {
-			con.JSON(http.StatusInternalServerError, Error{fmt.Sprintf("couldn't create the pdf file: %v", name)})
+			log.Printf("level=error request_id=%v msg=%q", GetRequestID(con), "couldn't save receipt: "+err.Error())
+			con.JSON(http.StatusInternalServerError, Error{localizef(con, "couldn't create the pdf file: %v", i+1)})
 			return
 		}
-		if _, err := file.Write(dec); err != nil {
-			con.JSON(http.StatusInternalServerError, Error{fmt.Sprintf("couldn't write the pdf file: %v", name)})
+		stored = append(stored, path)
+	}
+	con.JSON(http.StatusOK, SavedReceipts{"saving successful", stored})
+}
+
+// GetTimetableICal represents the get timetable ical endpoint
+// @Summary Returns the timetable of the logged in teacher as iCalendar feed
+// @Description Returns all lessons of the logged in teacher in between start and end as an iCalendar (.ics) feed
+// @ID get-timetable-ical
+// @Accept json
+// @Produce text/calendar
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param start query string false "Start date of the timetable (YYYY-MM-DD)"
+// @Param end query string false "End date of the timetable (YYYY-MM-DD)"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 400 {object} Error
+// @Failure 401 {object} Error
+// @Failure 500 {object} Error
+// @Failure 502 {object} Error
+// @Router /getTimetableICal [get]
+func GetTimetableICal(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	start, end, err := timetableRange(con.Query("start"), con.Query("end"))
+	if err != nil {
+		con.JSON(http.StatusBadRequest, Error{localize(con, err.Error())})
+		return
+	}
+	client, ok := untis.GetClient(auth.Username)
+	if !ok {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	err = client.AcquireContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't authenticate with untis API")})
+		return
+	}
+	defer func() {
+		_ = client.Release()
+	}()
+	lessons, err := client.GetTimetableOfTeacherContext(con.Request.Context(), start, end)
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't read timetable from untis API")})
+		return
+	}
+	cal, err := untis.LessonsToICal(lessons)
+	if err != nil {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create iCalendar feed")})
+		return
+	}
+	con.Data(http.StatusOK, "text/calendar; charset=utf-8", cal)
+}
+
+// GetHolidays represents the get holidays endpoint
+// @Summary Returns all holidays
+// @Description Returns all holidays (days without school) known to untis
+// @ID get-holidays
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Success 200 {array} untis.Holiday
+// @Failure 401 {object} Error
+// @Failure 500 {object} Error
+// @Failure 502 {object} Error
+// @Router /getHolidays [get]
+func GetHolidays(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	client, ok := untis.GetClient(auth.Username)
+	if !ok {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	err = client.AcquireContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't authenticate with untis API")})
+		return
+	}
+	defer func() {
+		_ = client.Release()
+	}()
+	holidays, err := client.GetHolidaysContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't read holidays from untis API")})
+		return
+	}
+	con.JSON(http.StatusOK, holidays)
+}
+
+// SearchTeachers represents the search teachers endpoint
+// @Summary Searches teachers
+// @Description Returns the teachers known to untis whose forename, surname or short name contains the query case-insensitively, ranked by match quality
+// @ID search-teachers
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param q query string true "The text to search for, an empty query matches no teacher"
+// @Param limit query int false "Maximum amount of teachers returned (at most 50)" default(10)
+// @Success 200 {array} untis.Teacher
+// @Failure 401 {object} Error
+// @Failure 422 {object} Error
+// @Failure 500 {object} Error
+// @Failure 502 {object} Error
+// @Router /searchTeachers [get]
+func SearchTeachers(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	limit := DefaultSearchLimit
+	if raw, present := con.GetQuery("limit"); present {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid limit provided")})
 			return
 		}
-		if err := file.Sync(); err != nil {
-			con.JSON(http.StatusInternalServerError, Error{fmt.Sprintf("couldn't sync the pdf file: %v", name)})
-			return
+		if limit > MaxSearchLimit {
+			limit = MaxSearchLimit
 		}
-		_ = file.Close()
 	}
-	con.JSON(http.StatusOK, Information{"saving successful"})
+	query := con.Query("q")
+	if strings.TrimSpace(query) == "" {
+		con.JSON(http.StatusOK, make([]untis.Teacher, 0))
+		return
+	}
+	client, ok := untis.GetClient(auth.Username)
+	if !ok {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	err = client.AcquireContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't authenticate with untis API")})
+		return
+	}
+	defer func() {
+		_ = client.Release()
+	}()
+	teachers, err := client.ListTeachersContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't read teachers from untis API")})
+		return
+	}
+	con.JSON(http.StatusOK, untis.SearchTeachers(teachers, query, limit))
+}
+
+// GetMyTimetable represents the get my timetable endpoint
+// @Summary Returns the timetable of the logged in teacher
+// @Description Returns all lessons of the logged in teacher in between from and to (at most 60 days), the current week is used if they are omitted
+// @ID get-my-timetable
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param from query string false "Start date of the timetable (YYYY-MM-DD)"
+// @Param to query string false "End date of the timetable (YYYY-MM-DD)"
+// @Success 200 {array} untis.Lesson
+// @Failure 400 {object} Error
+// @Failure 401 {object} Error
+// @Failure 500 {object} Error
+// @Failure 502 {object} Error
+// @Router /getMyTimetable [get]
+func GetMyTimetable(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	from, to, err := parseTimetableRange(con)
+	if err != nil {
+		con.JSON(http.StatusBadRequest, Error{localize(con, err.Error())})
+		return
+	}
+	client, ok := untis.GetClient(auth.Username)
+	if !ok {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	err = client.AcquireContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't authenticate with untis API")})
+		return
+	}
+	defer func() {
+		_ = client.Release()
+	}()
+	lessons, err := client.GetTimetableOfTeacherContext(con.Request.Context(), from, to)
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't read timetable from untis API")})
+		return
+	}
+	con.JSON(http.StatusOK, lessons)
+}
+
+// GetRoomTimetable represents the get room timetable endpoint
+// @Summary Returns the timetable of a room
+// @Description Returns all lessons taking place in the room (its short or full name) in between from and to (at most 60 days), the current week is used if they are omitted
+// @ID get-room-timetable
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param room query string true "Name of the room"
+// @Param from query string false "Start date of the timetable (YYYY-MM-DD)"
+// @Param to query string false "End date of the timetable (YYYY-MM-DD)"
+// @Success 200 {array} untis.Lesson
+// @Failure 400 {object} Error
+// @Failure 401 {object} Error
+// @Failure 404 {object} Error
+// @Failure 422 {object} Error
+// @Failure 500 {object} Error
+// @Failure 502 {object} Error
+// @Router /getRoomTimetable [get]
+func GetRoomTimetable(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	room := con.Query("room")
+	if room == "" {
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
+		return
+	}
+	from, to, err := parseTimetableRange(con)
+	if err != nil {
+		con.JSON(http.StatusBadRequest, Error{localize(con, err.Error())})
+		return
+	}
+	client, ok := untis.GetClient(auth.Username)
+	if !ok {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	err = client.AcquireContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't authenticate with untis API")})
+		return
+	}
+	defer func() {
+		_ = client.Release()
+	}()
+	lessons, err := client.GetTimetableOfRoomContext(con.Request.Context(), from, to, room)
+	if errors.Is(err, untis.ErrRoomNotFound) {
+		con.JSON(http.StatusNotFound, Error{localize(con, "room not found")})
+		return
+	}
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't read timetable from untis API")})
+		return
+	}
+	con.JSON(http.StatusOK, lessons)
+}
+
+// GetCurrentLesson represents the get current lesson endpoint
+// @Summary Returns the current and the next lesson of the logged in teacher
+// @Description Returns the lesson the logged in teacher is teaching at the moment and the next one of today, cancelled lessons are skipped and double periods are returned as one lesson
+// @Description Either is null if there is none, e.g. in a break or after the last lesson
+// @ID get-current-lesson
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Success 200 {object} CurrentLesson
+// @Failure 401 {object} Error
+// @Failure 500 {object} Error
+// @Failure 502 {object} Error
+// @Router /getCurrentLesson [get]
+func GetCurrentLesson(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	client, ok := untis.GetClient(auth.Username)
+	if !ok {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	err = client.AcquireContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't authenticate with untis API")})
+		return
+	}
+	defer func() {
+		_ = client.Release()
+	}()
+	current, next, err := client.GetCurrentLessonContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't read timetable from untis API")})
+		return
+	}
+	res := CurrentLesson{Current: current, CurrentPeriod: -1, Next: next, NextPeriod: -1}
+	if current != nil {
+		res.CurrentPeriod = untis.DefaultSchedule.LessonNrByStart(current.Start)
+	}
+	if next != nil {
+		res.NextPeriod = untis.DefaultSchedule.LessonNrByStart(next.Start)
+	}
+	con.JSON(http.StatusOK, res)
+}
+
+// GetSubstitutions represents the get substitutions endpoint
+// @Summary Returns the substitution plan
+// @Description Returns all substitutions (cancellations, substitutions, room changes, ...) of the school in between from and to (at most 60 days), the current week is used if they are omitted
+// @ID get-substitutions
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param from query string false "Start date of the substitution plan (YYYY-MM-DD)"
+// @Param to query string false "End date of the substitution plan (YYYY-MM-DD)"
+// @Success 200 {array} untis.Substitution
+// @Failure 400 {object} Error
+// @Failure 401 {object} Error
+// @Failure 500 {object} Error
+// @Failure 502 {object} Error
+// @Router /getSubstitutions [get]
+func GetSubstitutions(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	from, to, err := parseTimetableRange(con)
+	if err != nil {
+		con.JSON(http.StatusBadRequest, Error{localize(con, err.Error())})
+		return
+	}
+	client, ok := untis.GetClient(auth.Username)
+	if !ok {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	err = client.AcquireContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't authenticate with untis API")})
+		return
+	}
+	defer func() {
+		_ = client.Release()
+	}()
+	substitutions, err := client.GetSubstitutionsContext(con.Request.Context(), from, to)
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't read substitutions from untis API")})
+		return
+	}
+	con.JSON(http.StatusOK, substitutions)
+}
+
+// GetExams represents the get exams endpoint
+// @Summary Returns the exams
+// @Description Returns the exams of the logged in teacher in between from and to (at most 60 days) ordered by their start, the current week is used if they are omitted
+// @ID get-exams
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param from query string false "Start date of the exams (YYYY-MM-DD)"
+// @Param to query string false "End date of the exams (YYYY-MM-DD)"
+// @Success 200 {array} untis.Exam
+// @Failure 400 {object} Error
+// @Failure 401 {object} Error
+// @Failure 500 {object} Error
+// @Failure 502 {object} Error
+// @Router /getExams [get]
+func GetExams(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	from, to, err := parseTimetableRange(con)
+	if err != nil {
+		con.JSON(http.StatusBadRequest, Error{localize(con, err.Error())})
+		return
+	}
+	client, ok := untis.GetClient(auth.Username)
+	if !ok {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	err = client.AcquireContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't authenticate with untis API")})
+		return
+	}
+	defer func() {
+		_ = client.Release()
+	}()
+	exams, err := client.GetExamsContext(con.Request.Context(), from, to)
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't read exams from untis API")})
+		return
+	}
+	con.JSON(http.StatusOK, exams)
+}
+
+// GetClassTeachers represents the get class teachers endpoint
+// @Summary Returns the class teachers of a class
+// @Description Returns the class teacher (Klassenvorstand) and the deputy class teacher of the class, the list is empty if none are assigned in untis
+// @ID get-class-teachers
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param class query string true "Name of the class"
+// @Success 200 {array} untis.Teacher
+// @Failure 401 {object} Error
+// @Failure 404 {object} Error
+// @Failure 422 {object} Error
+// @Failure 500 {object} Error
+// @Failure 502 {object} Error
+// @Router /getClassTeachers [get]
+func GetClassTeachers(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	class := con.Query("class")
+	if class == "" {
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
+		return
+	}
+	client, ok := untis.GetClient(auth.Username)
+	if !ok {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	err = client.AcquireContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't authenticate with untis API")})
+		return
+	}
+	defer func() {
+		_ = client.Release()
+	}()
+	teachers, err := client.GetClassTeachersContext(con.Request.Context(), class)
+	if errors.Is(err, untis.ErrClassNotFound) {
+		con.JSON(http.StatusNotFound, Error{localize(con, "class not found")})
+		return
+	}
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't read teachers from untis API")})
+		return
+	}
+	con.JSON(http.StatusOK, teachers)
+}
+
+// GetTimetableCSV represents the get timetable csv endpoint
+// @Summary Returns the timetable of the logged in teacher as csv file
+// @Description Returns all lessons of the logged in teacher in between from and to (at most 60 days) as csv file, the current week is used if they are omitted
+// @ID get-timetable-csv
+// @Accept json
+// @Produce text/csv
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param from query string false "Start date of the timetable (YYYY-MM-DD)"
+// @Param to query string false "End date of the timetable (YYYY-MM-DD)"
+// @Success 200 {string} string "csv file"
+// @Failure 400 {object} Error
+// @Failure 401 {object} Error
+// @Failure 500 {object} Error
+// @Failure 502 {object} Error
+// @Router /getTimetableCSV [get]
+func GetTimetableCSV(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	from, to, err := parseTimetableRange(con)
+	if err != nil {
+		con.JSON(http.StatusBadRequest, Error{localize(con, err.Error())})
+		return
+	}
+	client, ok := untis.GetClient(auth.Username)
+	if !ok {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	err = client.AcquireContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't authenticate with untis API")})
+		return
+	}
+	defer func() {
+		_ = client.Release()
+	}()
+	lessons, err := client.GetTimetableOfTeacherContext(con.Request.Context(), from, to)
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't read timetable from untis API")})
+		return
+	}
+	file, err := untis.LessonsToCSV(lessons)
+	if err != nil {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create csv file")})
+		return
+	}
+	name := fmt.Sprintf("stundenplan_%v_%v_%v.csv", auth.Username, from.Format(DateFormat), to.Format(DateFormat))
+	con.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	con.Data(http.StatusOK, "text/csv; charset=utf-8", file)
+}
+
+// GetTimetablesForTeachers represents the get timetables for teachers endpoint
+// @Summary Returns the timetables of multiple teachers
+// @Description Returns the lessons of every requested teacher in between from and to (at most 60 days), the current week is used if they are omitted. Teachers whose timetable couldn't be fetched contain an error instead of failing the whole request
+// @ID get-timetables-for-teachers
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param request body TimetablesRequest true "Teachers and range of the timetables"
+// @Success 200 {object} map[string]TeacherTimetable
+// @Failure 400 {object} ValidationError
+// @Failure 401 {object} Error
+// @Failure 500 {object} Error
+// @Failure 502 {object} Error
+// @Router /getTimetablesForTeachers [post]
+func GetTimetablesForTeachers(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	var req TimetablesRequest
+	if err := con.ShouldBindJSON(&req); err != nil {
+		con.JSON(http.StatusBadRequest, validationError(con, err, req))
+		return
+	}
+	if len(req.Teachers) > MaxBulkTeachers {
+		con.JSON(http.StatusBadRequest, Error{localizef(con, "at most %d teachers may be requested at once", MaxBulkTeachers)})
+		return
+	}
+	from, to, err := timetableRange(req.From, req.To)
+	if err != nil {
+		con.JSON(http.StatusBadRequest, Error{localize(con, err.Error())})
+		return
+	}
+	client, ok := untis.GetClient(auth.Username)
+	if !ok {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	err = client.AcquireContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't authenticate with untis API")})
+		return
+	}
+	defer func() {
+		_ = client.Release()
+	}()
+	timetables, errs := client.GetTimetablesOfTeachersContext(con.Request.Context(), from, to, req.Teachers)
+	res := make(map[string]TeacherTimetable, len(req.Teachers))
+	for teacher, lessons := range timetables {
+		res[teacher] = TeacherTimetable{Lessons: lessons}
+	}
+	for teacher, err := range errs {
+		res[teacher] = TeacherTimetable{Lessons: make([]untis.Lesson, 0), Error: err.Error()}
+	}
+	con.JSON(http.StatusOK, res)
+}
+
+// GetTeachers represents the get teachers endpoint
+// @Summary Returns all teachers
+// @Description Returns all teachers known to untis sorted by their surname, e.g. to fill selections
+// @ID get-teachers
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Success 200 {array} untis.Teacher
+// @Failure 401 {object} Error
+// @Failure 500 {object} Error
+// @Failure 502 {object} Error
+// @Router /getTeachers [get]
+func GetTeachers(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	client, ok := untis.GetClient(auth.Username)
+	if !ok {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	err = client.AcquireContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't authenticate with untis API")})
+		return
+	}
+	defer func() {
+		_ = client.Release()
+	}()
+	teachers, err := client.ListTeachersContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't read teachers from untis API")})
+		return
+	}
+	con.JSON(http.StatusOK, teachers)
+}
+
+// GetClasses represents the get classes endpoint
+// @Summary Returns all classes
+// @Description Returns all classes known to untis sorted by their name, e.g. to fill selections
+// @ID get-classes
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Success 200 {array} untis.Class
+// @Failure 401 {object} Error
+// @Failure 500 {object} Error
+// @Failure 502 {object} Error
+// @Router /getClasses [get]
+func GetClasses(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	client, ok := untis.GetClient(auth.Username)
+	if !ok {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	err = client.AcquireContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't authenticate with untis API")})
+		return
+	}
+	defer func() {
+		_ = client.Release()
+	}()
+	classes, err := client.ListClassesContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't read classes from untis API")})
+		return
+	}
+	con.JSON(http.StatusOK, classes)
+}
+
+// GetRooms represents the get rooms endpoint
+// @Summary Returns all rooms
+// @Description Returns all rooms known to untis sorted by their name, e.g. to fill selections or the room of a form
+// @ID get-rooms
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Success 200 {array} untis.Room
+// @Failure 401 {object} Error
+// @Failure 500 {object} Error
+// @Failure 502 {object} Error
+// @Router /getRooms [get]
+func GetRooms(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	client, ok := untis.GetClient(auth.Username)
+	if !ok {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	err = client.AcquireContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't authenticate with untis API")})
+		return
+	}
+	defer func() {
+		_ = client.Release()
+	}()
+	rooms, err := client.ListRoomsContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't read rooms from untis API")})
+		return
+	}
+	con.JSON(http.StatusOK, rooms)
+}
+
+// GetMe represents the get me endpoint
+// @Summary Returns the untis profile of the logged in teacher
+// @Description Returns the profile of the untis account of the logged in teacher, e.g. the displayed name, the classes and the rights in untis
+// @ID get-me
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Success 200 {object} untis.UserData
+// @Failure 401 {object} Error
+// @Failure 500 {object} Error
+// @Failure 502 {object} Error
+// @Router /getMe [get]
+func GetMe(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	client, ok := untis.GetClient(auth.Username)
+	if !ok {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	err = client.AcquireContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't authenticate with untis API")})
+		return
+	}
+	defer func() {
+		_ = client.Release()
+	}()
+	profile, err := client.GetUserDataContext(con.Request.Context())
+	if err != nil {
+		con.JSON(untisStatus(err), Error{localize(con, "couldn't read user data from untis API")})
+		return
+	}
+	con.JSON(http.StatusOK, profile)
 }
@@ -0,0 +1,1201 @@
+package rest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"github.com/refundable-tgm/huginn/ldap"
+	"github.com/refundable-tgm/huginn/untis"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// searchableTeachers are the teachers served by the fake untis in the tests of SearchTeachers
+var searchableTeachers = []untis.Teacher{
+	{ID: 1, Name: "BOR", ForeName: "Michael", LongName: "Borko"},
+	{ID: 2, Name: "HUD", ForeName: "Anna", LongName: "van der Hude"},
+	{ID: 3, Name: "MAY", ForeName: "Eva", LongName: "Mayer"},
+	{ID: 4, Name: "MIC", ForeName: "Lukas", LongName: "Michalek"},
+}
+
+// searchTeachers calls SearchTeachers with the query string as user searching at a fake untis serving searchableTeachers
+func searchTeachers(t *testing.T, query string) (int, []untis.Teacher) {
+	t.Helper()
+	untisServing(t, "searching", map[string]interface{}{"getTeachers": searchableTeachers})
+	con, recorder := authorizedContext(t, "searching", http.MethodGet, "/searchTeachers?"+query, "")
+	SearchTeachers(con)
+	teachers := make([]untis.Teacher, 0)
+	if recorder.Code == http.StatusOK {
+		decodeJSON(t, recorder, &teachers)
+	}
+	return recorder.Code, teachers
+}
+
+// teacherIDs returns the ids of the teachers in order
+func teacherIDs(teachers []untis.Teacher) []int {
+	ids := make([]int, 0, len(teachers))
+	for _, teacher := range teachers {
+		ids = append(ids, teacher.ID)
+	}
+	return ids
+}
+
+func TestSearchTeachers(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []int
+	}{
+		{"an exact short name", "q=bor", []int{1}},
+		{"part of a long name", "q=der+hu", []int{2}},
+		{"part of a forename", "q=luk", []int{4}},
+		{"a full name", "q=" + url.QueryEscape("Eva Mayer"), []int{3}},
+		{"different case", "q=MAYER", []int{3}},
+		{"matches ranked by quality", "q=mic", []int{4, 1}},
+		{"a limit", "q=a&limit=2", []int{2, 1}},
+		{"no match", "q=xyz", []int{}},
+		{"an empty query", "q=", []int{}},
+		{"a blank query", "q=+++", []int{}},
+		{"no query", "", []int{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, teachers := searchTeachers(t, test.query)
+			if status != http.StatusOK {
+				t.Fatalf("searching responded with %d, want %d", status, http.StatusOK)
+			}
+			got := teacherIDs(teachers)
+			if len(got) != len(test.want) {
+				t.Fatalf("found the teachers %v, want %v", got, test.want)
+			}
+			for i := range got {
+				if got[i] != test.want[i] {
+					t.Fatalf("found the teachers %v, want %v", got, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchTeachersRejectsInvalidLimits(t *testing.T) {
+	for _, limit := range []string{"0", "-1", "ten"} {
+		if status, _ := searchTeachers(t, "q=a&limit="+limit); status != http.StatusUnprocessableEntity {
+			t.Errorf("searching with the limit %v responded with %d, want %d", limit, status, http.StatusUnprocessableEntity)
+		}
+	}
+}
+
+func TestSearchTeachersRequiresALogin(t *testing.T) {
+	con, recorder := testContext(http.MethodGet, "/searchTeachers?q=bor", "")
+	SearchTeachers(con)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("searching without a token responded with %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestParsePermissions(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		want    Permissions
+		unknown []string
+	}{
+		{"no permissions", nil, Permissions{}, nil},
+		{"all permissions", []string{"super_user", "administration", "av", "pek"}, Permissions{SuperUser: true, Administration: true, AV: true, PEK: true}, nil},
+		{"different case and spacing", []string{" AV ", "Pek"}, Permissions{AV: true, PEK: true}, nil},
+		{"a repeated permission", []string{"av", "av"}, Permissions{AV: true}, nil},
+		{"unknown permissions", []string{"av", "root", ""}, Permissions{AV: true}, []string{"root", ""}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			perm, unknown := parsePermissions(test.names)
+			if perm != test.want {
+				t.Errorf("got the permissions %+v, want %+v", perm, test.want)
+			}
+			if len(unknown) != len(test.unknown) {
+				t.Fatalf("got the unknown permissions %q, want %q", unknown, test.unknown)
+			}
+			for i := range unknown {
+				if unknown[i] != test.unknown[i] {
+					t.Fatalf("got the unknown permissions %q, want %q", unknown, test.unknown)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateTeacherPermissionsValidatesThePermissions(t *testing.T) {
+	con, recorder := authorizedContext(t, "admin", http.MethodPost, "/updateTeacherPermissions",
+		`{"teacher_short":"szakall","permissions":["administration","av"]}`)
+	UpdateTeacherPermissions(con)
+	// valid permissions pass the validation and are only stored afterwards, which fails without a database
+	if recorder.Code == http.StatusBadRequest {
+		t.Errorf("valid permissions were rejected: %s", recorder.Body.String())
+	}
+
+	tests := []struct {
+		name          string
+		body          string
+		field, reason string
+	}{
+		{"an unknown permission", `{"teacher_short":"szakall","permissions":["av","root"]}`, "permissions", "root"},
+		{"a misspelled permission", `{"teacher_short":"szakall","permissions":["superuser"]}`, "permissions", "superuser"},
+		{"no teacher", `{"permissions":["av"]}`, "teacher_short", "required"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			con, recorder := authorizedContext(t, "admin", http.MethodPost, "/updateTeacherPermissions", test.body)
+			UpdateTeacherPermissions(con)
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("got %d, want %d", recorder.Code, http.StatusBadRequest)
+			}
+			if res := decodeValidationError(t, recorder.Body.Bytes()); !hasFieldError(res, test.field, test.reason) {
+				t.Errorf("got %+v, want %v to violate %v", res, test.field, test.reason)
+			}
+		})
+	}
+}
+
+func TestUpdateTeacherPermissionsRequiresALogin(t *testing.T) {
+	con, recorder := testContext(http.MethodPost, "/updateTeacherPermissions", `{"teacher_short":"szakall","permissions":["av"]}`)
+	UpdateTeacherPermissions(con)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("updating without a token responded with %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
+
+// otherReason returns an application of the kind other reason filed by the teacher with the long name
+func otherReason(filer string) mongo.Application {
+	start := time.Now().AddDate(0, 0, 1).Truncate(time.Second)
+	return mongo.Application{
+		Name:               "Dienstreise",
+		Kind:               mongo.OtherReason,
+		Progress:           1,
+		StartTime:          start,
+		EndTime:            start.Add(8 * time.Hour),
+		OtherReasonDetails: mongo.OtherReasonDetails{Filer: filer},
+	}
+}
+
+// updateApplication calls UpdateApplication as the user with the application as body and returns the status
+func updateApplication(t *testing.T, username string, app mongo.Application) int {
+	t.Helper()
+	body, err := json.Marshal(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	con, recorder := authorizedContext(t, username, http.MethodPut, "/updateApplication?uuid="+app.UUID, string(body))
+	UpdateApplication(con)
+	return recorder.Code
+}
+
+func TestUpdateApplicationRejectsChangesOfAnOutdatedVersion(t *testing.T) {
+	db := requireDatabase(t)
+	filer := storeTeacher(t, db, "updater", Permissions{})
+	stored := storeApplication(t, db, otherReason(filer.Longname))
+	if stored.Version != 1 {
+		t.Fatalf("a new application has the version %d, want 1", stored.Version)
+	}
+	first, second := stored, stored
+	first.Name = "Dienstreise nach Graz"
+	second.Name = "Dienstreise nach Linz"
+	if status := updateApplication(t, filer.Short, first); status != http.StatusOK {
+		t.Fatalf("the first update responded with %d, want %d", status, http.StatusOK)
+	}
+	if status := updateApplication(t, filer.Short, second); status != http.StatusConflict {
+		t.Errorf("the second update of the same version responded with %d, want %d", status, http.StatusConflict)
+	}
+	updated := db.GetApplication(stored.UUID)
+	if updated.Name != first.Name || updated.Version != 2 {
+		t.Errorf("the stored application is %q in version %d, want %q in version 2", updated.Name, updated.Version, first.Name)
+	}
+	second.Version = updated.Version
+	if status := updateApplication(t, filer.Short, second); status != http.StatusOK {
+		t.Errorf("updating the current version responded with %d, want %d", status, http.StatusOK)
+	}
+	if updated := db.GetApplication(stored.UUID); updated.Name != second.Name || updated.Version != 3 {
+		t.Errorf("the stored application is %q in version %d, want %q in version 3", updated.Name, updated.Version, second.Name)
+	}
+}
+
+func TestUpdateApplicationRejectsInvalidBodies(t *testing.T) {
+	con, recorder := authorizedContext(t, "updater", http.MethodPut, "/updateApplication?uuid=1", `{"version":"one"}`)
+	UpdateApplication(con)
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("updating with an invalid body responded with %d, want %d", recorder.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+// listsApplication returns whether GetAllApplications lists the application to the user filing it
+func listsApplication(t *testing.T, filer mongo.Teacher, uuid string, includeDeleted bool) bool {
+	t.Helper()
+	target := "/getAllApplications?limit=100&username=" + filer.Short
+	if includeDeleted {
+		target += "&include_deleted=true"
+	}
+	con, recorder := authorizedContext(t, filer.Short, http.MethodGet, target, "")
+	GetAllApplications(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("listing the applications responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	page := ApplicationPage{}
+	decodeJSON(t, recorder, &page)
+	for _, app := range page.Items {
+		if app.UUID == uuid {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDeletedApplicationsCanBeRestored(t *testing.T) {
+	db := requireDatabase(t)
+	filer := storeTeacher(t, db, "deleter", Permissions{Administration: true})
+	stored := storeApplication(t, db, otherReason(filer.Longname))
+	if !listsApplication(t, filer, stored.UUID, false) {
+		t.Fatal("a new application isn't listed")
+	}
+
+	con, recorder := authorizedContext(t, filer.Short, http.MethodDelete, "/deleteApplication?uuid="+stored.UUID, "")
+	DeleteApplication(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("deleting responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	if deleted := db.GetApplication(stored.UUID); deleted.DeletedAt == nil {
+		t.Error("the deleted application isn't marked as deleted")
+	}
+	if listsApplication(t, filer, stored.UUID, false) {
+		t.Error("the deleted application is still listed")
+	}
+	if !listsApplication(t, filer, stored.UUID, true) {
+		t.Error("the deleted application isn't listed including deleted ones")
+	}
+
+	con, recorder = authorizedContext(t, filer.Short, http.MethodPost, "/restoreApplication?uuid="+stored.UUID, "")
+	RestoreApplication(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("restoring responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	if !listsApplication(t, filer, stored.UUID, false) {
+		t.Error("the restored application isn't listed")
+	}
+	con, recorder = authorizedContext(t, filer.Short, http.MethodPost, "/restoreApplication?uuid="+stored.UUID, "")
+	RestoreApplication(con)
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("restoring an application which isn't deleted responded with %d, want %d", recorder.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestRestoreApplicationRequiresAUUID(t *testing.T) {
+	con, recorder := authorizedContext(t, "restorer", http.MethodPost, "/restoreApplication", "")
+	RestoreApplication(con)
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("restoring without a uuid responded with %d, want %d", recorder.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+// loginFailingWith makes Login's check of the credentials fail with err until the test finishes,
+// the failed logins recorded meanwhile are forgotten afterwards
+func loginFailingWith(t *testing.T, err error) {
+	t.Helper()
+	previous, previousLogins := authenticateLogin, logins
+	authenticateLogin = func(username, password string) error { return err }
+	logins = newLoginLimiter()
+	t.Cleanup(func() { authenticateLogin, logins = previous, previousLogins })
+}
+
+// login calls Login with the credentials and returns the recorder of its response
+func login(username, password string) *httptest.ResponseRecorder {
+	con, recorder := testContext(http.MethodPost, "/login", fmt.Sprintf(`{"username":%q,"password":%q}`, username, password))
+	con.Request.Header.Set("Accept-Language", "en")
+	Login(con)
+	return recorder
+}
+
+// getNews calls GetNews with the query string as user and decodes the page
+func getNews(t *testing.T, username, query string) NewsPage {
+	t.Helper()
+	con, recorder := authorizedContext(t, username, http.MethodGet, "/getNews?"+query, "")
+	GetNews(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("%q: got %d, want %d", query, recorder.Code, http.StatusOK)
+	}
+	page := NewsPage{}
+	decodeJSON(t, recorder, &page)
+	return page
+}
+
+// newsUUIDs returns the uuids of the news on the page
+func newsUUIDs(page NewsPage) []string {
+	uuids := make([]string, 0, len(page.Items))
+	for _, news := range page.Items {
+		uuids = append(uuids, news.UUID)
+	}
+	return uuids
+}
+
+func TestGetNewsIsSortedAndPaged(t *testing.T) {
+	db := requireDatabase(t)
+	reader := storeTeacher(t, db, "newsreader", Permissions{})
+	changed := time.Date(2021, time.March, 1, 8, 0, 0, 0, time.UTC)
+	oldestFirst := make([]string, 0, 3)
+	for i := 0; i < 3; i++ {
+		app := otherReason(reader.Longname)
+		app.LastChanged = changed.Add(time.Duration(i) * time.Hour)
+		oldestFirst = append(oldestFirst, storeApplication(t, db, app).UUID)
+	}
+	newestFirst := []string{oldestFirst[2], oldestFirst[1], oldestFirst[0]}
+
+	tests := []struct {
+		query string
+		want  []string
+		next  int
+	}{
+		{"", newestFirst, -1},
+		{"sort=date_desc", newestFirst, -1},
+		{"sort=date_asc", oldestFirst, -1},
+		{"limit=2", newestFirst[:2], 2},
+		{"offset=2&limit=2", newestFirst[2:], -1},
+		{"offset=1&limit=1&sort=date_asc", oldestFirst[1:2], 2},
+		{"offset=3", []string{}, -1},
+	}
+	for _, test := range tests {
+		page := getNews(t, reader.Short, test.query)
+		if got := newsUUIDs(page); strings.Join(got, ",") != strings.Join(test.want, ",") {
+			t.Errorf("%q: got %v, want %v", test.query, got, test.want)
+		}
+		if page.Total != 3 || page.NextOffset != test.next {
+			t.Errorf("%q: got the total %d and next offset %d, want 3 and %d", test.query, page.Total, page.NextOffset, test.next)
+		}
+	}
+}
+
+func TestGetNewsRejectsInvalidParameters(t *testing.T) {
+	tests := []struct {
+		query  string
+		status int
+	}{
+		{"sort=name", http.StatusBadRequest},
+		{"sort=date", http.StatusBadRequest},
+		{"offset=-1", http.StatusUnprocessableEntity},
+		{"limit=0", http.StatusUnprocessableEntity},
+	}
+	for _, test := range tests {
+		con, recorder := authorizedContext(t, "newsreader", http.MethodGet, "/getNews?"+test.query, "")
+		GetNews(con)
+		if recorder.Code != test.status {
+			t.Errorf("%q: got %d, want %d", test.query, recorder.Code, test.status)
+		}
+	}
+}
+
+// getApplications calls GetApplications with the ids as user
+func getApplications(t *testing.T, username string, ids []string) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(ApplicationsRequest{IDs: ids})
+	if err != nil {
+		t.Fatal(err)
+	}
+	con, recorder := authorizedContext(t, username, http.MethodPost, "/getApplications", string(body))
+	GetApplications(con)
+	return recorder
+}
+
+func TestGetApplicationsReportsMissingIDs(t *testing.T) {
+	db := requireDatabase(t)
+	filer := storeTeacher(t, db, "batched", Permissions{})
+	other := storeTeacher(t, db, "unrelated", Permissions{})
+	own := storeApplication(t, db, otherReason(filer.Longname))
+	foreign := storeApplication(t, db, otherReason(other.Longname))
+	missing := uuid.NewString()
+
+	recorder := getApplications(t, filer.Short, []string{own.UUID, missing, foreign.UUID, missing})
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d", recorder.Code, http.StatusOK)
+	}
+	res := ApplicationBatch{}
+	decodeJSON(t, recorder, &res)
+	if len(res.Applications) != 1 || res.Applications[own.UUID].UUID != own.UUID {
+		t.Errorf("got the applications %v, want only the own one", res.Applications)
+	}
+	if len(res.NotFound) != 2 || res.NotFound[0] != missing || res.NotFound[1] != foreign.UUID {
+		t.Errorf("got %v as not found, want the missing id once and the application of another teacher", res.NotFound)
+	}
+}
+
+func TestGetApplicationsRejectsInvalidBatches(t *testing.T) {
+	ids := make([]string, MaxBatchApplications+1)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	tests := []struct {
+		name string
+		ids  []string
+	}{
+		{"an over-size batch", ids},
+		{"an empty batch", []string{}},
+		{"no batch", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if recorder := getApplications(t, "batched", test.ids); recorder.Code != http.StatusBadRequest {
+				t.Errorf("got %d, want %d", recorder.Code, http.StatusBadRequest)
+			}
+		})
+	}
+	con, recorder := testContext(http.MethodPost, "/getApplications", `{"ids":["1"]}`)
+	GetApplications(con)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("requesting without a login responded with %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestLoginMapsAuthenticationFailuresToStatuses(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		message string
+	}{
+		{"credentials rejected by ldap", ldap.ErrInvalidCredentials, http.StatusUnauthorized, "this credentials do not resolve into an authorized login"},
+		{"credentials rejected by untis", &untis.UntisError{Code: untis.BadCredentialsErrorCode, Message: "bad credentials"}, http.StatusUnauthorized, "untis rejected this credentials"},
+		{"a wrapped untis rejection", fmt.Errorf("couldn't authenticate: %w", &untis.UntisError{Code: untis.BadCredentialsErrorCode}), http.StatusUnauthorized, "untis rejected this credentials"},
+		{"too many untis sessions", &untis.UntisError{Code: untis.TooManySessionsErrorCode, Message: "too many sessions"}, http.StatusTooManyRequests, "untis is refusing further sessions, try again later"},
+		{"an unavailable untis", &untis.UpstreamUnavailableError{Status: http.StatusServiceUnavailable, ContentType: "text/html"}, http.StatusBadGateway, "untis is unavailable, try again later"},
+		{"another untis error", &untis.UntisError{Code: -7004, Message: "no allowed date"}, http.StatusInternalServerError, "couldn't log in"},
+		{"an unreachable ldap server", errors.New("couldn't connect to ldap server: connection refused"), http.StatusInternalServerError, "couldn't log in"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			loginFailingWith(t, test.err)
+			recorder := login("szakall", "secret")
+			if recorder.Code != test.status {
+				t.Errorf("got %d, want %d", recorder.Code, test.status)
+			}
+			var body Error
+			decodeJSON(t, recorder, &body)
+			if body.Message != test.message {
+				t.Errorf("got the message %q, want %q", body.Message, test.message)
+			}
+		})
+	}
+}
+
+func TestLoginIssuesATokenPair(t *testing.T) {
+	loginFailingWith(t, nil)
+	recorder := login("szakall", "secret")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d", recorder.Code, http.StatusOK)
+	}
+	pair := TokenPair{}
+	decodeJSON(t, recorder, &pair)
+	if !accessGranted(pair.AccessToken) {
+		t.Error("the issued access token is rejected")
+	}
+	if status, _ := refresh(t, pair.RefreshToken); status != http.StatusCreated {
+		t.Errorf("refreshing the issued refresh token responded with %d, want %d", status, http.StatusCreated)
+	}
+}
+
+func TestUntisStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"an unavailable untis", &untis.UpstreamUnavailableError{Status: http.StatusBadGateway}, http.StatusBadGateway},
+		{"a wrapped unavailable untis", fmt.Errorf("couldn't fetch: %w", &untis.UpstreamUnavailableError{Status: http.StatusServiceUnavailable}), http.StatusBadGateway},
+		{"an untis error", &untis.UntisError{Code: untis.NotAuthenticatedErrorCode}, http.StatusInternalServerError},
+		{"another error", errors.New("decoding failed"), http.StatusInternalServerError},
+	}
+	for _, test := range tests {
+		if status := untisStatus(test.err); status != test.status {
+			t.Errorf("%v: got %d, want %d", test.name, status, test.status)
+		}
+	}
+}
+
+// timetableWindow returns the first and last day the fake was asked for the timetable of
+func timetableWindow(t *testing.T, fake *fakeUntis) (string, string) {
+	t.Helper()
+	calls := fake.callsOf("getTimetable")
+	if len(calls) == 0 {
+		t.Fatal("untis wasn't asked for the timetable")
+	}
+	first, last := "", ""
+	for _, params := range calls {
+		req := struct {
+			StartDate string `json:"startDate"`
+			EndDate   string `json:"endDate"`
+		}{}
+		if err := json.Unmarshal(params, &req); err != nil {
+			t.Fatalf("couldn't decode the parameters %s: %v", params, err)
+		}
+		if first == "" || req.StartDate < first {
+			first = req.StartDate
+		}
+		if req.EndDate > last {
+			last = req.EndDate
+		}
+	}
+	return first, last
+}
+
+// getMyTimetable calls GetMyTimetable with the query string as user at a fake untis serving the lessons
+func getMyTimetable(t *testing.T, query string, lessons ...map[string]interface{}) (*fakeUntis, *httptest.ResponseRecorder) {
+	t.Helper()
+	fake := untisServing(t, "timetabled", masterData(map[string]interface{}{"getTimetable": lessons}))
+	con, recorder := authorizedContext(t, "timetabled", http.MethodGet, "/getMyTimetable?"+query, "")
+	GetMyTimetable(con)
+	return fake, recorder
+}
+
+func TestGetMyTimetableDefaultsToTheCurrentWeek(t *testing.T) {
+	fake, recorder := getMyTimetable(t, "")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d", recorder.Code, http.StatusOK)
+	}
+	monday := startOfWeek(time.Now().In(untis.Location()))
+	first, last := timetableWindow(t, fake)
+	if want := monday.Format("20060102"); first != want {
+		t.Errorf("the timetable starts at %v, want the monday %v", first, want)
+	}
+	if want := monday.AddDate(0, 0, 6).Format("20060102"); last != want {
+		t.Errorf("the timetable ends at %v, want the sunday %v", last, want)
+	}
+}
+
+func TestGetMyTimetableReturnsTheLessonsOfTheRange(t *testing.T) {
+	fake, recorder := getMyTimetable(t, "from=2021-03-01&to=2021-03-12",
+		untisLesson(1, 20210301, 800, 850, 20, 1, 30, 10),
+		untisLesson(2, 20210310, 1000, 1050, 21, 1, 31, 11))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d", recorder.Code, http.StatusOK)
+	}
+	if first, last := timetableWindow(t, fake); first != "20210301" || last != "20210312" {
+		t.Errorf("untis was asked for %v to %v, want 20210301 to 20210312", first, last)
+	}
+	lessons := make([]untis.Lesson, 0)
+	decodeJSON(t, recorder, &lessons)
+	if len(lessons) != 2 {
+		t.Fatalf("got %d lessons, want 2", len(lessons))
+	}
+	if lessons[0].ID != 1 || lessons[0].Subjects[0] != "SEW" || lessons[0].Rooms[0] != "H1102" || lessons[0].Classes[0] != "5AHIT" {
+		t.Errorf("the first lesson is %+v, want SEW of 5AHIT in H1102", lessons[0])
+	}
+	if lessons[1].ID != 2 || lessons[1].Subjects[0] != "D" || lessons[1].Rooms[0] != "L2201" {
+		t.Errorf("the second lesson is %+v, want D in L2201", lessons[1])
+	}
+}
+
+func TestGetMyTimetableRejectsInvalidRanges(t *testing.T) {
+	for _, query := range []string{
+		"from=2021-03-01&to=2021-05-01",
+		"from=2021-03-02&to=2021-03-01",
+		"from=yesterday",
+	} {
+		fake, recorder := getMyTimetable(t, query)
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("%v: got %d, want %d", query, recorder.Code, http.StatusBadRequest)
+		}
+		if calls := fake.callsOf("getTimetable"); len(calls) != 0 {
+			t.Errorf("%v: untis was asked for the timetable although the range is invalid", query)
+		}
+	}
+}
+
+// getTimetableICal calls GetTimetableICal with the query string as user at a fake untis serving the lessons
+func getTimetableICal(t *testing.T, query string, lessons ...map[string]interface{}) (*fakeUntis, *httptest.ResponseRecorder) {
+	t.Helper()
+	fake := untisServing(t, "subscribed", masterData(map[string]interface{}{"getTimetable": lessons}))
+	con, recorder := authorizedContext(t, "subscribed", http.MethodGet, "/getTimetableICal?"+query, "")
+	GetTimetableICal(con)
+	return fake, recorder
+}
+
+func TestGetTimetableICalExportsTheLessonsOfTheRange(t *testing.T) {
+	fake, recorder := getTimetableICal(t, "start=2021-03-01&end=2021-03-12",
+		untisLesson(1, 20210301, 800, 850, 20, 1, 30, 10))
+	if recorder.Code != http.StatusOK || !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/calendar") {
+		t.Fatalf("got %d as %q, want an iCalendar feed", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+	if first, last := timetableWindow(t, fake); first != "20210301" || last != "20210312" {
+		t.Errorf("untis was asked for %v to %v, want 20210301 to 20210312", first, last)
+	}
+	// the lesson at 8:00 in Vienna starts at 7:00 UTC
+	if !strings.Contains(recorder.Body.String(), "DTSTART:20210301T070000Z") {
+		t.Errorf("the feed %s doesn't contain the lesson at 8:00 in Vienna", recorder.Body)
+	}
+}
+
+func TestGetTimetableICalRejectsInvalidRanges(t *testing.T) {
+	for _, query := range []string{
+		"start=2021-03-01&end=2021-05-01",
+		"start=2021-03-02&end=2021-03-01",
+		"start=yesterday",
+		"start=2021-03-01&end=tomorrow",
+	} {
+		fake, recorder := getTimetableICal(t, query)
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("%v: got %d, want %d", query, recorder.Code, http.StatusBadRequest)
+		}
+		if calls := fake.callsOf("getTimetable"); len(calls) != 0 {
+			t.Errorf("%v: untis was asked for the timetable although the range is invalid", query)
+		}
+	}
+}
+
+// getCurrentLesson calls GetCurrentLesson as user at now at a fake untis serving the lessons
+func getCurrentLesson(t *testing.T, now time.Time, lessons ...map[string]interface{}) CurrentLesson {
+	t.Helper()
+	previous := untis.DefaultClock
+	untis.DefaultClock = untis.ClockFunc(func() time.Time { return now })
+	t.Cleanup(func() { untis.DefaultClock = previous })
+	untisServing(t, "current", masterData(map[string]interface{}{"getTimetable": lessons}))
+	con, recorder := authorizedContext(t, "current", http.MethodGet, "/getCurrentLesson", "")
+	GetCurrentLesson(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d", recorder.Code, http.StatusOK)
+	}
+	res := CurrentLesson{}
+	decodeJSON(t, recorder, &res)
+	return res
+}
+
+func TestGetCurrentLessonReturnsTheCurrentAndNextLesson(t *testing.T) {
+	lessons := []map[string]interface{}{
+		untisLesson(1, 20210301, 800, 850, 20, 1, 30, 10),
+		untisLesson(2, 20210301, 1045, 1135, 21, 1, 31, 11),
+	}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2021, time.March, 1, hour, minute, 0, 0, untis.Location())
+	}
+	tests := []struct {
+		name                      string
+		now                       time.Time
+		current, next             int
+		currentPeriod, nextPeriod int
+	}{
+		{"mid-lesson", at(8, 20), 1, 2, 1, 4},
+		{"in a break", at(9, 30), 0, 2, -1, 4},
+		{"in the last lesson", at(11, 0), 2, 0, 4, -1},
+		{"after school", at(16, 0), 0, 0, -1, -1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res := getCurrentLesson(t, test.now, lessons...)
+			current, next := 0, 0
+			if res.Current != nil {
+				current = res.Current.ID
+			}
+			if res.Next != nil {
+				next = res.Next.ID
+			}
+			if current != test.current || next != test.next {
+				t.Errorf("got the current lesson %d and the next %d, want %d and %d", current, next, test.current, test.next)
+			}
+			if res.CurrentPeriod != test.currentPeriod || res.NextPeriod != test.nextPeriod {
+				t.Errorf("got the periods %d and %d, want %d and %d", res.CurrentPeriod, res.NextPeriod, test.currentPeriod, test.nextPeriod)
+			}
+		})
+	}
+}
+
+// getRoomTimetable calls GetRoomTimetable with the query string as user at a fake untis serving the lessons
+func getRoomTimetable(t *testing.T, query string, lessons ...map[string]interface{}) (*fakeUntis, *httptest.ResponseRecorder) {
+	t.Helper()
+	fake := untisServing(t, "occupancy", masterData(map[string]interface{}{"getTimetable": lessons}))
+	con, recorder := authorizedContext(t, "occupancy", http.MethodGet, "/getRoomTimetable?"+query, "")
+	con.Request.Header.Set("Accept-Language", "en")
+	GetRoomTimetable(con)
+	return fake, recorder
+}
+
+func TestGetRoomTimetableReturnsTheLessonsInTheRoom(t *testing.T) {
+	fake, recorder := getRoomTimetable(t, "room=Labor+2201&from=2021-03-01&to=2021-03-05",
+		untisLesson(1, 20210301, 800, 850, 20, 2, 30, 11))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d", recorder.Code, http.StatusOK)
+	}
+	calls := fake.callsOf("getTimetable")
+	if len(calls) != 1 {
+		t.Fatalf("untis was asked %d times for the timetable, want once", len(calls))
+	}
+	req := struct {
+		ID        int    `json:"id"`
+		Type      int    `json:"type"`
+		StartDate string `json:"startDate"`
+		EndDate   string `json:"endDate"`
+	}{}
+	if err := json.Unmarshal(calls[0], &req); err != nil {
+		t.Fatal(err)
+	}
+	if req.ID != 11 || req.Type != 4 || req.StartDate != "20210301" || req.EndDate != "20210305" {
+		t.Errorf("untis was asked for %s, want the timetable of the room 11 from 20210301 to 20210305", calls[0])
+	}
+	lessons := make([]untis.Lesson, 0)
+	decodeJSON(t, recorder, &lessons)
+	if len(lessons) != 1 || lessons[0].Rooms[0] != "L2201" || lessons[0].Teachers[0] != "HUD" {
+		t.Errorf("got %+v, want the lesson of HUD in L2201", lessons)
+	}
+}
+
+func TestGetRoomTimetableRejectsUnknownRooms(t *testing.T) {
+	fake, recorder := getRoomTimetable(t, "room=Turnsaal")
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("got %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+	res := Error{}
+	decodeJSON(t, recorder, &res)
+	if res.Message != "room not found" {
+		t.Errorf("responded with %q, want the room not to be found", res.Message)
+	}
+	if calls := fake.callsOf("getTimetable"); len(calls) != 0 {
+		t.Error("untis was asked for the timetable of an unknown room")
+	}
+
+	_, recorder = getRoomTimetable(t, "from=2021-03-01")
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("got %d without a room, want %d", recorder.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestGetSubstitutionsReturnsThePlanOfTheRange(t *testing.T) {
+	fake := untisServing(t, "substituting", masterData(map[string]interface{}{"getSubstitutions": []map[string]interface{}{
+		{"type": "subst", "lsid": 102, "date": 20210301, "startTime": 850, "endTime": 940,
+			"kl": []map[string]int{{"id": 20}}, "te": []map[string]int{{"id": 2, "orgid": 1}}, "su": []map[string]int{{"id": 30}}, "ro": []map[string]int{{"id": 10}}},
+		{"type": "cancel", "lsid": 103, "date": 20210302, "startTime": 800, "endTime": 850,
+			"kl": []map[string]int{{"id": 21}}, "te": []map[string]int{{"id": 1}}, "su": []map[string]int{{"id": 31}}, "ro": []map[string]int{{"id": 11}}},
+	}}))
+	con, recorder := authorizedContext(t, "substituting", http.MethodGet, "/getSubstitutions?from=2021-03-01&to=2021-03-02", "")
+	GetSubstitutions(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d", recorder.Code, http.StatusOK)
+	}
+	calls := fake.callsOf("getSubstitutions")
+	if len(calls) != 1 {
+		t.Fatalf("untis was asked %d times for the substitutions, want once", len(calls))
+	}
+	req := struct {
+		StartDate string `json:"startDate"`
+		EndDate   string `json:"endDate"`
+	}{}
+	if err := json.Unmarshal(calls[0], &req); err != nil || req.StartDate != "20210301" || req.EndDate != "20210302" {
+		t.Errorf("untis was asked for %s, want the substitutions from 20210301 to 20210302", calls[0])
+	}
+	substitutions := make([]untis.Substitution, 0)
+	decodeJSON(t, recorder, &substitutions)
+	if len(substitutions) != 2 {
+		t.Fatalf("got %d substitutions, want 2", len(substitutions))
+	}
+	if substitution := substitutions[0]; substitution.Type != untis.SubstitutionTeacher || substitution.Teachers[0] != "HUD" || substitution.OriginalTeachers[0] != "BOR" {
+		t.Errorf("the first substitution is %+v, want HUD substituting BOR", substitution)
+	}
+	if substitution := substitutions[1]; substitution.Type != untis.SubstitutionCancel || substitution.Classes[0] != "4BHIT" {
+		t.Errorf("the second substitution is %+v, want the cancelled lesson of 4BHIT", substitution)
+	}
+}
+
+func TestGetExamsReturnsTheExamsOfTheRange(t *testing.T) {
+	fake := untisServing(t, "examining", masterData(map[string]interface{}{
+		"getAppSharedSecret": "JBSWY3DPEHPK3PXP",
+		"getExams2017": map[string]interface{}{"exams": []map[string]interface{}{
+			{"id": 2, "examType": "SA", "name": "Schularbeit", "date": 20210304, "startTime": 955, "endTime": 1135,
+				"subjectId": 31, "klasseIds": []int{21}, "roomIds": []int{11}, "teacherIds": []int{2}},
+			{"id": 1, "examType": "TEST", "name": "Test SEW", "date": 20210302, "startTime": 800, "endTime": 850,
+				"subjectId": 30, "klasseIds": []int{20}, "roomIds": []int{10}, "teacherIds": []int{1}},
+		}},
+	}))
+	con, recorder := authorizedContext(t, "examining", http.MethodGet, "/getExams?from=2021-03-01&to=2021-03-05", "")
+	GetExams(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d", recorder.Code, http.StatusOK)
+	}
+	calls := fake.callsOf("getExams2017")
+	if len(calls) != 1 {
+		t.Fatalf("untis was asked %d times for the exams, want once", len(calls))
+	}
+	req := make([]struct {
+		StartDate string `json:"startDate"`
+		EndDate   string `json:"endDate"`
+	}, 0)
+	if err := json.Unmarshal(calls[0], &req); err != nil || len(req) != 1 || req[0].StartDate != "20210301" || req[0].EndDate != "20210305" {
+		t.Errorf("untis was asked for %s, want the exams from 20210301 to 20210305", calls[0])
+	}
+	exams := make([]untis.Exam, 0)
+	decodeJSON(t, recorder, &exams)
+	if len(exams) != 2 {
+		t.Fatalf("got %d exams, want 2", len(exams))
+	}
+	if exam := exams[0]; exam.Name != "Test SEW" || exam.Subject != "SEW" || exam.Classes[0] != "5AHIT" || exam.Rooms[0] != "H1102" || exam.Teachers[0] != "BOR" {
+		t.Errorf("the first exam is %+v, want the test of 5AHIT in SEW", exam)
+	}
+	if exam := exams[1]; exam.Name != "Schularbeit" || exam.Subject != "D" || exam.Teachers[0] != "HUD" || exam.Period != 3 {
+		t.Errorf("the second exam is %+v, want the school exam in D in the third period", exam)
+	}
+}
+
+func TestGetExamsRejectsInvalidRequests(t *testing.T) {
+	fake := untisServing(t, "examining", masterData(nil))
+	con, recorder := authorizedContext(t, "examining", http.MethodGet, "/getExams?from=2021-03-02&to=2021-03-01", "")
+	GetExams(con)
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("an invalid range responded with %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+	if calls := fake.callsOf("getExams2017"); len(calls) != 0 {
+		t.Error("untis was asked for the exams of an invalid range")
+	}
+	con, recorder = testContext(http.MethodGet, "/getExams", "")
+	GetExams(con)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("requesting without a login responded with %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestGetSubstitutionsRejectsInvalidRequests(t *testing.T) {
+	fake := untisServing(t, "substituting", masterData(nil))
+	con, recorder := authorizedContext(t, "substituting", http.MethodGet, "/getSubstitutions?from=2021-03-02&to=2021-03-01", "")
+	GetSubstitutions(con)
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("an invalid range responded with %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+	if calls := fake.callsOf("getSubstitutions"); len(calls) != 0 {
+		t.Error("untis was asked for the substitutions of an invalid range")
+	}
+	con, recorder = testContext(http.MethodGet, "/getSubstitutions", "")
+	GetSubstitutions(con)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("requesting without a login responded with %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestEndpointsWithoutAnUntisSessionRespondUnauthorized(t *testing.T) {
+	endpoints := map[string]gin.HandlerFunc{
+		"/getMyTimetable":                  GetMyTimetable,
+		"/getRoomTimetable?room=H1102":     GetRoomTimetable,
+		"/getSubstitutions":                GetSubstitutions,
+		"/getTimetableCSV?from=2021-03-01": GetTimetableCSV,
+		"/getTeachers":                     GetTeachers,
+		"/getClasses":                      GetClasses,
+	}
+	for target, endpoint := range endpoints {
+		t.Run(target, func(t *testing.T) {
+			con, recorder := authorizedContext(t, "sessionless", http.MethodGet, target, "")
+			con.Request.Header.Set("Accept-Language", "en")
+			endpoint(con)
+			if recorder.Code != http.StatusUnauthorized {
+				t.Errorf("got %d, want %d", recorder.Code, http.StatusUnauthorized)
+			}
+			res := Error{}
+			decodeJSON(t, recorder, &res)
+			if res.Message != "you are not logged in" {
+				t.Errorf("responded with %q, want to be told to log in", res.Message)
+			}
+		})
+	}
+}
+
+func TestGetTimetableCSVIsADownload(t *testing.T) {
+	untisServing(t, "exporting", masterData(map[string]interface{}{"getTimetable": []map[string]interface{}{
+		untisLesson(1, 20210301, 800, 850, 20, 1, 30, 10),
+		untisLesson(2, 20210302, 800, 850, 20, 1, 30, 10),
+	}}))
+	con, recorder := authorizedContext(t, "exporting", http.MethodGet, "/getTimetableCSV?from=2021-03-01&to=2021-03-05", "")
+	GetTimetableCSV(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d", recorder.Code, http.StatusOK)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "text/csv; charset=utf-8" {
+		t.Errorf("the content type is %v, want text/csv", contentType)
+	}
+	if disposition := recorder.Header().Get("Content-Disposition"); disposition != `attachment; filename="stundenplan_exporting_2021-03-01_2021-03-05.csv"` {
+		t.Errorf("the content disposition is %v, want a download of the range", disposition)
+	}
+	records, err := csv.NewReader(recorder.Body).ReadAll()
+	if err != nil || len(records) != 3 {
+		t.Errorf("got the rows %q (%v), want a header and 2 lessons", records, err)
+	}
+}
+
+// getTimetablesForTeachers calls GetTimetablesForTeachers with the body as user at a fake untis serving one lesson
+func getTimetablesForTeachers(t *testing.T, body string) (*fakeUntis, *httptest.ResponseRecorder) {
+	t.Helper()
+	fake := untisServing(t, "substituting", masterData(map[string]interface{}{"getTimetable": []map[string]interface{}{
+		untisLesson(1, 20210301, 800, 850, 20, 1, 30, 10),
+	}}))
+	con, recorder := authorizedContext(t, "substituting", http.MethodPost, "/getTimetablesForTeachers", body)
+	GetTimetablesForTeachers(con)
+	return fake, recorder
+}
+
+func TestGetTimetablesForTeachersReportsErrorsPerTeacher(t *testing.T) {
+	fake, recorder := getTimetablesForTeachers(t, `{"teachers":["BOR","Anna van der Hude","Nobody"],"from":"2021-03-01","to":"2021-03-05"}`)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d", recorder.Code, http.StatusOK)
+	}
+	res := make(map[string]TeacherTimetable)
+	decodeJSON(t, recorder, &res)
+	if len(res) != 3 {
+		t.Fatalf("got the timetables of %d teachers, want 3: %v", len(res), res)
+	}
+	for _, teacher := range []string{"BOR", "Anna van der Hude"} {
+		if timetable := res[teacher]; timetable.Error != "" || len(timetable.Lessons) != 1 {
+			t.Errorf("the timetable of %v is %+v, want its lesson", teacher, timetable)
+		}
+	}
+	if unknown := res["Nobody"]; unknown.Error == "" || unknown.Lessons == nil || len(unknown.Lessons) != 0 {
+		t.Errorf("the timetable of an unknown teacher is %+v, want an error and no lessons", unknown)
+	}
+	ids := make(map[int]bool)
+	for _, params := range fake.callsOf("getTimetable") {
+		req := struct {
+			ID int `json:"id"`
+		}{}
+		if err := json.Unmarshal(params, &req); err != nil {
+			t.Fatal(err)
+		}
+		ids[req.ID] = true
+	}
+	if len(ids) != 2 || !ids[1] || !ids[2] {
+		t.Errorf("untis was asked for the timetables of %v, want those of BOR (1) and HUD (2)", ids)
+	}
+}
+
+func TestGetTimetablesForTeachersValidatesTheBatch(t *testing.T) {
+	tooMany := make([]string, MaxBulkTeachers+1)
+	for i := range tooMany {
+		tooMany[i] = "BOR"
+	}
+	batch, err := json.Marshal(TimetablesRequest{Teachers: tooMany})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"no teachers", `{"teachers":[]}`},
+		{"a missing list of teachers", `{}`},
+		{"too many teachers", string(batch)},
+		{"a too long range", `{"teachers":["BOR"],"from":"2021-03-01","to":"2021-06-01"}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake, recorder := getTimetablesForTeachers(t, test.body)
+			if recorder.Code != http.StatusBadRequest {
+				t.Errorf("got %d, want %d", recorder.Code, http.StatusBadRequest)
+			}
+			if calls := fake.callsOf("getTimetable"); len(calls) != 0 {
+				t.Error("untis was asked for timetables of an invalid batch")
+			}
+		})
+	}
+}
+
+func TestGetTeachersAndClassesListThemWithTheirColors(t *testing.T) {
+	untisServing(t, "lister", map[string]interface{}{
+		"getTeachers": []untis.Teacher{
+			{ID: 2, Name: "HUD", ForeName: "Anna", LongName: "van der Hude", ForeColor: "ffffff", BackColor: "0000ff"},
+			{ID: 1, Name: "BOR", ForeName: "Michael", LongName: "Borko", ForeColor: "000000", BackColor: "ff0000"},
+		},
+		"getKlassen": []untis.Class{
+			{ID: 20, Name: "5AHIT", LongName: "Informationstechnologie 5A", ForeColor: "000000", BackColor: "cccccc", Teacher1: 1},
+			{ID: 21, Name: "4BHIT", LongName: "Informationstechnologie 4B", ForeColor: "000000", BackColor: "bbbbbb", Teacher1: 2},
+		},
+	})
+
+	con, recorder := authorizedContext(t, "lister", http.MethodGet, "/getTeachers", "")
+	GetTeachers(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("listing the teachers responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	teachers := make([]untis.Teacher, 0)
+	decodeJSON(t, recorder, &teachers)
+	if len(teachers) != 2 || teachers[0].LongName != "Borko" || teachers[0].BackColor != "ff0000" || teachers[1].ForeColor != "ffffff" {
+		t.Errorf("listed the teachers %+v, want Borko and van der Hude with their colors", teachers)
+	}
+
+	con, recorder = authorizedContext(t, "lister", http.MethodGet, "/getClasses", "")
+	GetClasses(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("listing the classes responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	classes := make([]untis.Class, 0)
+	decodeJSON(t, recorder, &classes)
+	if len(classes) != 2 || classes[0].Name != "4BHIT" || classes[0].BackColor != "bbbbbb" || classes[1].LongName != "Informationstechnologie 5A" {
+		t.Errorf("listed the classes %+v, want 4BHIT and 5AHIT with their names and colors", classes)
+	}
+}
+
+func TestGetRoomsListsThemByName(t *testing.T) {
+	untisServing(t, "lister", masterData(map[string]interface{}{"getRooms": []untis.Room{
+		{ID: 11, Name: "L2201", LongName: "Labor 2201", BackColor: "dddddd"},
+		{ID: 10, Name: "H1102", LongName: "Hörsaal 1102", BackColor: "eeeeee"},
+	}}))
+	con, recorder := authorizedContext(t, "lister", http.MethodGet, "/getRooms", "")
+	GetRooms(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("listing the rooms responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	rooms := make([]untis.Room, 0)
+	decodeJSON(t, recorder, &rooms)
+	if len(rooms) != 2 || rooms[0].Name != "H1102" || rooms[0].LongName != "Hörsaal 1102" || rooms[1].BackColor != "dddddd" {
+		t.Errorf("listed the rooms %+v, want H1102 and L2201 with their names and colors", rooms)
+	}
+}
+
+func TestGetMeReturnsTheUntisProfile(t *testing.T) {
+	untisServing(t, "profiled", masterData(map[string]interface{}{
+		"getAppSharedSecret": "JBSWY3DPEHPK3PXP",
+		"getUserData2017": map[string]interface{}{"userData": map[string]interface{}{
+			"elemType": "TEACHER", "elemId": 1, "displayName": "Michael Borko", "schoolName": "TGM",
+			"klassenIds": []int{20}, "rights": []string{"R_MY_OWN_TIMETABLE"},
+		}},
+	}))
+	con, recorder := authorizedContext(t, "profiled", http.MethodGet, "/getMe", "")
+	GetMe(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("getting the profile responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	profile := untis.UserData{}
+	decodeJSON(t, recorder, &profile)
+	if profile.DisplayName != "Michael Borko" || profile.ElementID != 1 || len(profile.Classes) != 1 || profile.Classes[0] != "5AHIT" ||
+		len(profile.Rights) != 1 || profile.Rights[0] != "R_MY_OWN_TIMETABLE" {
+		t.Errorf("got the profile %+v, want the one of untis with the names of the classes", profile)
+	}
+
+	con, recorder = testContext(http.MethodGet, "/getMe", "")
+	GetMe(con)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("getting the profile without a login responded with %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
+
+// getClassTeachers calls GetClassTeachers with the query string as user at a fake untis with classes of one, two and
+// no class teachers
+func getClassTeachers(t *testing.T, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	untisServing(t, "routing", masterData(map[string]interface{}{"getKlassen": []untis.Class{
+		{ID: 20, Name: "5AHIT", Teacher1: 1},
+		{ID: 21, Name: "4BHIT", Teacher1: 2, Teacher2: 1},
+		{ID: 22, Name: "3CHIT"},
+	}}))
+	con, recorder := authorizedContext(t, "routing", http.MethodGet, "/getClassTeachers?"+query, "")
+	GetClassTeachers(con)
+	return recorder
+}
+
+func TestGetClassTeachersReturnsTheAssignedTeachers(t *testing.T) {
+	tests := map[string][]string{
+		"5AHIT": {"BOR"},
+		"4BHIT": {"HUD", "BOR"},
+		"3CHIT": {},
+	}
+	for class, want := range tests {
+		recorder := getClassTeachers(t, "class="+class)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("%v: got %d, want %d", class, recorder.Code, http.StatusOK)
+		}
+		teachers := make([]untis.Teacher, 0)
+		decodeJSON(t, recorder, &teachers)
+		shorts := make([]string, 0, len(teachers))
+		for _, teacher := range teachers {
+			shorts = append(shorts, teacher.Name)
+		}
+		if strings.Join(shorts, ",") != strings.Join(want, ",") {
+			t.Errorf("%v has the class teachers %v, want %v", class, shorts, want)
+		}
+	}
+}
+
+func TestGetClassTeachersRejectsInvalidClasses(t *testing.T) {
+	if recorder := getClassTeachers(t, "class=1XHIT"); recorder.Code != http.StatusNotFound {
+		t.Errorf("an unknown class responded with %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+	if recorder := getClassTeachers(t, ""); recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("no class responded with %d, want %d", recorder.Code, http.StatusUnprocessableEntity)
+	}
+	con, recorder := testContext(http.MethodGet, "/getClassTeachers?class=5AHIT", "")
+	GetClassTeachers(con)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("no login responded with %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestGetTeachersAndClassesRequireALogin(t *testing.T) {
+	for name, handler := range map[string]gin.HandlerFunc{"/getTeachers": GetTeachers, "/getClasses": GetClasses} {
+		con, recorder := testContext(http.MethodGet, name, "")
+		handler(con)
+		if recorder.Code != http.StatusUnauthorized {
+			t.Errorf("%v without a login responded with %d, want %d", name, recorder.Code, http.StatusUnauthorized)
+		}
+	}
+}
+
+func TestRepeatedFailedLoginsAreLockedOut(t *testing.T) {
+	loginFailingWith(t, ldap.ErrInvalidCredentials)
+	for i := 1; i < MaxUserLoginFailures; i++ {
+		if recorder := login("szakall", "wrong"); recorder.Code != http.StatusUnauthorized {
+			t.Fatalf("failed login %d responded with %d, want %d", i, recorder.Code, http.StatusUnauthorized)
+		}
+	}
+	if recorder := login("szakall", "wrong"); recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("the last failed login before the lockout responded with %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+
+	authenticateLogin = func(username, password string) error { return nil }
+	recorder := login("szakall", "secret")
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("logging in during the lockout responded with %d, want %d", recorder.Code, http.StatusTooManyRequests)
+	}
+	if retry := recorder.Header().Get("Retry-After"); retry != "30" {
+		t.Errorf("the lockout has to be waited for %q seconds, want 30", retry)
+	}
+	if recorder := login("borko", "secret"); recorder.Code != http.StatusOK {
+		t.Errorf("another user logging in responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+
+	logins.mutex.Lock()
+	logins.attempts[userKey("szakall")].lockedUntil = time.Now()
+	logins.mutex.Unlock()
+	if recorder := login("szakall", "secret"); recorder.Code != http.StatusOK {
+		t.Fatalf("logging in after the lockout responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	authenticateLogin = func(username, password string) error { return ldap.ErrInvalidCredentials }
+	if recorder := login("szakall", "wrong"); recorder.Code != http.StatusUnauthorized {
+		t.Errorf("a failed login after a successful one responded with %d, want %d as the failures were reset", recorder.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestSuccessfulLoginsResetTheFailures(t *testing.T) {
+	loginFailingWith(t, ldap.ErrInvalidCredentials)
+	for round := 0; round < 3; round++ {
+		authenticateLogin = func(username, password string) error { return ldap.ErrInvalidCredentials }
+		for i := 1; i < MaxUserLoginFailures; i++ {
+			if recorder := login("szakall", "wrong"); recorder.Code != http.StatusUnauthorized {
+				t.Fatalf("failed login %d of round %d responded with %d, want %d", i, round, recorder.Code, http.StatusUnauthorized)
+			}
+		}
+		authenticateLogin = func(username, password string) error { return nil }
+		if recorder := login("szakall", "secret"); recorder.Code != http.StatusOK {
+			t.Fatalf("logging in in round %d responded with %d, want %d", round, recorder.Code, http.StatusOK)
+		}
+	}
+}
+
+func TestFailuresOfUntisAreNotCountedAsFailedLogins(t *testing.T) {
+	loginFailingWith(t, &untis.UpstreamUnavailableError{Status: http.StatusServiceUnavailable})
+	for i := 0; i < 2*MaxUserLoginFailures; i++ {
+		if recorder := login("szakall", "secret"); recorder.Code != http.StatusBadGateway {
+			t.Fatalf("login %d responded with %d, want %d", i, recorder.Code, http.StatusBadGateway)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+// CacheControl is the Cache-Control header of responses with an ETag, they may only be cached by the client
+// and have to be revalidated before every use
+const CacheControl = "private, no-cache"
+
+// bufferedWriter holds back the response of a handler so it can be hashed before it is sent
+type bufferedWriter struct {
+	gin.ResponseWriter
+	// body is the response body written by the handler
+	body bytes.Buffer
+	// status is the status code set by the handler
+	status int
+}
+
+// WriteHeader records the status code without sending it
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+// WriteHeaderNow does nothing, the header is sent once the response is complete
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+// Write buffers the data
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+// WriteString buffers the string
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// Status returns the status code set by the handler
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+// Size returns the amount of bytes buffered
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+// Written returns whether anything was written yet
+func (w *bufferedWriter) Written() bool {
+	return w.body.Len() > 0
+}
+
+// ETag is a middleware adding an ETag (a hash of the body) and CacheControl to successful GET responses
+// if the request contains the same ETag in If-None-Match 304 Not Modified is returned without a body instead
+func ETag() gin.HandlerFunc {
+	return func(con *gin.Context) {
+		if con.Request.Method != http.MethodGet {
+			con.Next()
+			return
+		}
+		original := con.Writer
+		writer := &bufferedWriter{ResponseWriter: original, status: http.StatusOK}
+		con.Writer = writer
+		con.Next()
+		con.Writer = original
+		if writer.status != http.StatusOK {
+			original.WriteHeader(writer.status)
+			_, _ = original.Write(writer.body.Bytes())
+			return
+		}
+		sum := sha256.Sum256(writer.body.Bytes())
+		tag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		original.Header().Set("ETag", tag)
+		original.Header().Set("Cache-Control", CacheControl)
+		if matchesETag(con.GetHeader("If-None-Match"), tag) {
+			original.Header().Del("Content-Length")
+			original.WriteHeader(http.StatusNotModified)
+			original.WriteHeaderNow()
+			return
+		}
+		original.WriteHeader(http.StatusOK)
+		_, _ = original.Write(writer.body.Bytes())
+	}
+}
+
+// matchesETag checks whether the If-None-Match header contains the tag, weak tags match as well
+func matchesETag(header, tag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == tag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// serveETag sends a request with the method and If-None-Match header (none if it is empty) through the ETag middleware
+// to a handler responding with the status and body
+func serveETag(method, ifNoneMatch string, status int, body string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Handle(method, "/", ETag(), func(con *gin.Context) {
+		con.String(status, body)
+	})
+	req := httptest.NewRequest(method, "/", nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestETagRevalidatesUnchangedResponses(t *testing.T) {
+	first := serveETag(http.MethodGet, "", http.StatusOK, "Dienstreise")
+	if first.Code != http.StatusOK || first.Body.String() != "Dienstreise" {
+		t.Fatalf("the first request responded with %d and %q, want %d and the body", first.Code, first.Body, http.StatusOK)
+	}
+	tag := first.Header().Get("ETag")
+	if tag == "" {
+		t.Fatal("the response carries no ETag")
+	}
+	if cache := first.Header().Get("Cache-Control"); cache != CacheControl {
+		t.Errorf("the response carries the Cache-Control %q, want %q", cache, CacheControl)
+	}
+
+	second := serveETag(http.MethodGet, tag, http.StatusOK, "Dienstreise")
+	if second.Code != http.StatusNotModified {
+		t.Fatalf("revalidating responded with %d, want %d", second.Code, http.StatusNotModified)
+	}
+	if second.Body.Len() > 0 {
+		t.Errorf("the not modified response has the body %q, want none", second.Body)
+	}
+	if second.Header().Get("ETag") != tag {
+		t.Errorf("the not modified response carries the ETag %q, want %q", second.Header().Get("ETag"), tag)
+	}
+
+	changed := serveETag(http.MethodGet, tag, http.StatusOK, "Dienstreise nach Graz")
+	if changed.Code != http.StatusOK || changed.Body.String() != "Dienstreise nach Graz" {
+		t.Errorf("revalidating a changed response responded with %d and %q, want %d and the new body", changed.Code, changed.Body, http.StatusOK)
+	}
+	if changed.Header().Get("ETag") == tag {
+		t.Error("a changed response carries the ETag of the old one")
+	}
+}
+
+func TestETagSkipsFailuresAndOtherMethods(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		status int
+	}{
+		{"a failed request", http.MethodGet, http.StatusNotFound},
+		{"a post", http.MethodPost, http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := serveETag(test.method, "*", test.status, "body")
+			if recorder.Code != test.status || recorder.Body.String() != "body" {
+				t.Errorf("responded with %d and %q, want %d and the body", recorder.Code, recorder.Body, test.status)
+			}
+			if tag := recorder.Header().Get("ETag"); tag != "" {
+				t.Errorf("the response carries the ETag %q, want none", tag)
+			}
+		})
+	}
+}
+
+func TestMatchesETag(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"xyz", "abc"`, true},
+		{`*`, true},
+		{`"xyz"`, false},
+		{`abc`, false},
+		{``, false},
+	}
+	for _, test := range tests {
+		if got := matchesETag(test.header, `"abc"`); got != test.want {
+			t.Errorf("matchesETag(%q) = %v, want %v", test.header, got, test.want)
+		}
+	}
+}
+
+func TestGetApplicationIsRevalidated(t *testing.T) {
+	db := requireDatabase(t)
+	filer := storeTeacher(t, db, "tagger", Permissions{})
+	stored := storeApplication(t, db, otherReason(filer.Longname))
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/getApplication?uuid="+stored.UUID, nil)
+		req.Header.Set("Authorization", loginAs(t, filer.Short))
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		return serveRouter(t, req)
+	}
+	first := get("")
+	if first.Code != http.StatusOK || first.Header().Get("ETag") == "" {
+		t.Fatalf("the first request responded with %d and the ETag %q, want %d and an ETag", first.Code, first.Header().Get("ETag"), http.StatusOK)
+	}
+	second := get(first.Header().Get("ETag"))
+	if second.Code != http.StatusNotModified || second.Body.Len() > 0 {
+		t.Errorf("revalidating responded with %d and %q, want %d without a body", second.Code, second.Body, http.StatusNotModified)
+	}
+}
@@ -0,0 +1,181 @@
+package rest
+
+import (
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ApplicationCreated is the type of events sent if an application was created
+	ApplicationCreated = "created"
+	// ApplicationUpdated is the type of events sent if an application was updated without changing its progress
+	ApplicationUpdated = "updated"
+	// ApplicationStatusChanged is the type of events sent if the progress of an application changed
+	ApplicationStatusChanged = "status_changed"
+	// ApplicationDeleted is the type of events sent if an application was deleted
+	ApplicationDeleted = "deleted"
+	// ApplicationRestored is the type of events sent if a deleted application was restored
+	ApplicationRestored = "restored"
+)
+
+// EventBufferSize is the amount of events buffered per connection, events are dropped for connections lagging behind
+const EventBufferSize = 16
+
+// PingInterval is the interval in which pings are sent to keep websocket connections alive
+const PingInterval = 30 * time.Second
+
+// ApplicationEvent is sent to websocket connections if an application they may see changed
+type ApplicationEvent struct {
+	// Type is the kind of change (created, updated, status_changed, deleted or restored)
+	Type string `json:"type" example:"status_changed"`
+	// Application is the application after the change
+	Application mongo.Application `json:"application"`
+}
+
+// subscriber is a websocket connection listening to application events
+type subscriber struct {
+	// events are the events the connection should send
+	events chan ApplicationEvent
+	// visible checks whether the user of the connection is allowed to see the application
+	visible func(mongo.Application) bool
+}
+
+// eventHub broadcasts application events to all subscribers allowed to see them
+type eventHub struct {
+	// mutex guards subscribers
+	mutex sync.RWMutex
+	// subscribers are all currently connected subscribers
+	subscribers map[*subscriber]bool
+}
+
+// applicationEvents is the hub all application events are published to
+var applicationEvents = &eventHub{subscribers: make(map[*subscriber]bool)}
+
+// subscribe registers a new subscriber receiving all events of applications passing visible
+func (hub *eventHub) subscribe(visible func(mongo.Application) bool) *subscriber {
+	sub := &subscriber{events: make(chan ApplicationEvent, EventBufferSize), visible: visible}
+	hub.mutex.Lock()
+	hub.subscribers[sub] = true
+	hub.mutex.Unlock()
+	return sub
+}
+
+// unsubscribe removes the subscriber from the hub
+func (hub *eventHub) unsubscribe(sub *subscriber) {
+	hub.mutex.Lock()
+	delete(hub.subscribers, sub)
+	hub.mutex.Unlock()
+}
+
+// publish sends the event to all subscribers allowed to see the application without blocking
+// and records the change in applicationsModified
+func (hub *eventHub) publish(kind string, application mongo.Application) {
+	applicationsModified.touch(time.Now())
+	event := ApplicationEvent{Type: kind, Application: application}
+	hub.mutex.RLock()
+	defer hub.mutex.RUnlock()
+	for sub := range hub.subscribers {
+		if !sub.visible(application) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
+
+// upgrader upgrades requests to websocket connections, newRouter makes it accept the same origins as the CORS
+// configuration of the router
+var upgrader = newUpgrader(corsConfig(""))
+
+// newUpgrader creates an upgrader accepting websocket requests of the origins allowed by the CORS configuration
+func newUpgrader(config cors.Config) websocket.Upgrader {
+	return websocket.Upgrader{CheckOrigin: func(r *http.Request) bool {
+		return checkOrigin(config, r)
+	}}
+}
+
+// checkOrigin checks whether the origin of a websocket request is allowed by the CORS configuration
+func checkOrigin(config cors.Config, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || config.AllowAllOrigins {
+		return true
+	}
+	for _, allowed := range config.AllowOrigins {
+		if strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplicationsWebSocket represents the application events websocket endpoint
+// @Summary Streams changes of applications
+// @Description Upgrades the connection to a websocket and sends an event whenever an application the logged in teacher may see is created, updated, changes its status, is deleted or restored
+// @ID applications-websocket
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Success 101 {object} ApplicationEvent
+// @Failure 401 {object} Error
+// @Failure 500 {object} Error
+// @Router /ws/applications [get]
+func ApplicationsWebSocket(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	db := mongo.MongoDatabaseConnector{}
+	if !db.Connect() {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
+		return
+	}
+	teacher := db.GetTeacherByShort(auth.Username)
+	db.Close()
+	conn, err := upgrader.Upgrade(con.Writer, con.Request, nil)
+	if err != nil {
+		// the upgrader already responded with an error
+		return
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	sub := applicationEvents.subscribe(func(application mongo.Application) bool {
+		return teacher.Administration || teacher.AV || teacher.PEK || teacher.SuperUser || isParticipant(application, teacher)
+	})
+	defer applicationEvents.unsubscribe(sub)
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	ticker := time.NewTicker(PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case event := <-sub.events:
+			if err := conn.WriteJSON(event); err != nil {
+				log.Printf("level=warn request_id=%v msg=%q", GetRequestID(con), "couldn't send application event: "+err.Error())
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(PingInterval)); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/gorilla/websocket"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// receive returns the next event of the subscriber or fails the test if none arrives in time
+func receive(t *testing.T, sub *subscriber) ApplicationEvent {
+	t.Helper()
+	select {
+	case event := <-sub.events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event was received")
+		return ApplicationEvent{}
+	}
+}
+
+// receivesNothing fails the test if the subscriber has an event pending
+func receivesNothing(t *testing.T, sub *subscriber) {
+	t.Helper()
+	select {
+	case event := <-sub.events:
+		t.Errorf("received the unexpected event %+v", event)
+	default:
+	}
+}
+
+func TestEventsAreBroadcastToAllSubscribersAllowedToSeeThem(t *testing.T) {
+	hub := &eventHub{subscribers: make(map[*subscriber]bool)}
+	everything := func(mongo.Application) bool { return true }
+	admin, anotherAdmin := hub.subscribe(everything), hub.subscribe(everything)
+	filer := hub.subscribe(func(app mongo.Application) bool { return app.OtherReasonDetails.Filer == "Michael Borko" })
+	own, foreign := otherReason("Michael Borko"), otherReason("Anna van der Hude")
+	own.UUID, foreign.UUID = "own", "foreign"
+
+	hub.publish(ApplicationStatusChanged, own)
+	for _, sub := range []*subscriber{admin, anotherAdmin, filer} {
+		if event := receive(t, sub); event.Type != ApplicationStatusChanged || event.Application.UUID != "own" {
+			t.Errorf("received %v of %v, want the status change of own", event.Type, event.Application.UUID)
+		}
+	}
+	hub.publish(ApplicationCreated, foreign)
+	receive(t, admin)
+	receive(t, anotherAdmin)
+	receivesNothing(t, filer)
+
+	hub.unsubscribe(anotherAdmin)
+	hub.publish(ApplicationDeleted, own)
+	receive(t, admin)
+	receive(t, filer)
+	receivesNothing(t, anotherAdmin)
+}
+
+func TestPublishingDoesNotBlockOnLaggingSubscribers(t *testing.T) {
+	hub := &eventHub{subscribers: make(map[*subscriber]bool)}
+	lagging := hub.subscribe(func(mongo.Application) bool { return true })
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		for i := 0; i < 2*EventBufferSize; i++ {
+			hub.publish(ApplicationUpdated, mongo.Application{})
+		}
+	}()
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("publishing blocked on a subscriber not reading its events")
+	}
+	if pending := len(lagging.events); pending != EventBufferSize {
+		t.Errorf("%d events are pending, want the buffer of %d", pending, EventBufferSize)
+	}
+}
+
+func TestWebSocketOriginsFollowTheCORSConfiguration(t *testing.T) {
+	previous := upgrader
+	t.Cleanup(func() { upgrader = previous })
+	// the origins are only configured in the file, not in the environment
+	cfg, err := LoadConfig(configFile(t, `{"cors_origins": "https://huginn.tgm.ac.at"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newRouter(cfg); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"", true},
+		{"https://huginn.tgm.ac.at", true},
+		{"https://HUGINN.tgm.ac.at", true},
+		{"https://evil.example", false},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/ws/applications", nil)
+		if test.origin != "" {
+			req.Header.Set("Origin", test.origin)
+		}
+		if allowed := upgrader.CheckOrigin(req); allowed != test.allowed {
+			t.Errorf("the origin %q is allowed: %v, want %v", test.origin, allowed, test.allowed)
+		}
+	}
+
+	if _, err := newRouter(DefaultConfig()); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/ws/applications", nil)
+	req.Header.Set("Origin", "https://evil.example")
+	if !upgrader.CheckOrigin(req) {
+		t.Error("an origin was rejected without configured origins")
+	}
+}
+
+func TestWebSocketReceivesUpdatesOfApplications(t *testing.T) {
+	db := requireDatabase(t)
+	filer := storeTeacher(t, db, "listener", Permissions{})
+	stored := storeApplication(t, db, otherReason(filer.Longname))
+	base := startTestServer(t)
+	header := http.Header{}
+	header.Set("Authorization", loginAs(t, filer.Short))
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(base, "http")+"/api/ws/applications", header)
+	if err != nil {
+		t.Fatalf("couldn't connect: %v", err)
+	}
+	defer conn.Close()
+
+	stored.Progress = 2
+	body, err := json.Marshal(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest(http.MethodPut, base+"/api/updateApplication?uuid="+stored.UUID, strings.NewReader(string(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", loginAs(t, filer.Short))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("updating responded with %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var event ApplicationEvent
+	if err := conn.ReadJSON(&event); err != nil {
+		t.Fatalf("no event was received: %v", err)
+	}
+	if event.Type != ApplicationStatusChanged || event.Application.UUID != stored.UUID || event.Application.Progress != 2 {
+		t.Errorf("received %v of %v in progress %d, want the status change of %v to 2",
+			event.Type, event.Application.UUID, event.Application.Progress, stored.UUID)
+	}
+}
@@ -0,0 +1,267 @@
+package rest
+
+import (
+	"archive/zip"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	uuidG "github.com/google/uuid"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"github.com/refundable-tgm/huginn/files"
+	"github.com/refundable-tgm/huginn/untis"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+)
+
+// applicationKindNames are the names of the kinds of applications used in the file names of exported forms
+var applicationKindNames = map[int]string{
+	mongo.SchoolEvent: "schulveranstaltung",
+	mongo.Training:    "fortbildung",
+	mongo.OtherReason: "sonstiges",
+}
+
+// exportFileName returns the name of the exported form of the application, made of its start date and kind
+// a counter is appended if used already contains the name, the returned name is added to used
+func exportFileName(application mongo.Application, used map[string]bool) string {
+	kind, ok := applicationKindNames[application.Kind]
+	if !ok {
+		kind = "antrag"
+	}
+	base := application.StartTime.In(untis.Location()).Format(DateFormat) + "_" + kind
+	name := base + ".pdf"
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%v_%d.pdf", base, i)
+	}
+	used[name] = true
+	return name
+}
+
+// addExportFile copies the file at path into the archive under name
+func addExportFile(archive *zip.Writer, name, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	entry, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, file)
+	return err
+}
+
+// startExport sends the headers of the export of the username and returns the archive streamed as its body
+func startExport(con *gin.Context, username string) *zip.Writer {
+	con.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "antraege_"+username+".zip"))
+	con.Header("Content-Type", "application/zip")
+	con.Status(http.StatusOK)
+	return zip.NewWriter(con.Writer)
+}
+
+// ExportMyApplications represents the export my applications endpoint
+// @Summary Downloads the forms of all applications of the logged in teacher
+// @Description Generates the absence form of every application the logged in teacher participates in and streams them as a zip archive, named by the start date and the kind of the application
+// @Description The archive is streamed while the forms are generated: if generating a form fails after the first one was sent, the archive ends early and is invalid
+// @ID export-my-applications
+// @Produce application/zip
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param status query int false "Filter to only export applications with this progress"
+// @Param from query string false "Filter to only export applications ending on or after this date (YYYY-MM-DD)"
+// @Param to query string false "Filter to only export applications starting on or before this date (YYYY-MM-DD)"
+// @Success 200 {file} file
+// @Failure 400 {object} Error
+// @Failure 401 {object} Error
+// @Failure 500 {object} Error
+// @Router /exportMyApplications [get]
+func ExportMyApplications(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	appFilter, err := parseApplicationFilter(con)
+	if err != nil {
+		con.JSON(http.StatusBadRequest, Error{localize(con, err.Error())})
+		return
+	}
+	db := mongo.MongoDatabaseConnector{}
+	if !db.Connect() {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
+		return
+	}
+	requestTeacher := db.GetTeacherByShort(auth.Username)
+	applications := make([]mongo.Application, 0)
+	for _, application := range appFilter.apply(db.GetAllApplications()) {
+		if isParticipant(application, requestTeacher) {
+			applications = append(applications, application)
+		}
+	}
+	db.Close()
+	sort.SliceStable(applications, func(i, j int) bool {
+		return applications[i].StartTime.Before(applications[j].StartTime)
+	})
+	var archive *zip.Writer
+	used := make(map[string]bool)
+	for _, application := range applications {
+		path, err := files.GenerateFileEnvironment(application)
+		if err == nil {
+			path, err = files.GenerateAbsenceFormForTeacher(path, auth.Username, "self", application, language(con))
+		}
+		if err == nil {
+			err = api.OptimizeFile(path, "", nil)
+		}
+		if err != nil {
+			if archive == nil {
+				con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't create pdfs")})
+				return
+			}
+			// the archive is left without its central directory, so clients notice that it is incomplete
+			log.Printf("level=error request_id=%v msg=%q", GetRequestID(con), fmt.Sprintf("couldn't export application %v: %v", application.UUID, err))
+			return
+		}
+		if archive == nil {
+			archive = startExport(con, auth.Username)
+		}
+		if err := addExportFile(archive, exportFileName(application, used), path); err != nil {
+			log.Printf("level=error request_id=%v msg=%q", GetRequestID(con), fmt.Sprintf("couldn't send export of application %v: %v", application.UUID, err))
+			return
+		}
+		con.Writer.Flush()
+	}
+	if archive == nil {
+		archive = startExport(con, auth.Username)
+	}
+	if err := archive.Close(); err != nil {
+		log.Printf("level=error request_id=%v msg=%q", GetRequestID(con), "couldn't finish export: "+err.Error())
+	}
+}
+
+// ApplicationExportVersion is the current schema version of exported applications, imports of other versions are rejected
+const ApplicationExportVersion = 1
+
+// ExportApplication represents the export application endpoint
+// @Summary Exports an application
+// @Description Returns all data of the application matching the given UUID as a versioned document, which can be recreated by the import application endpoint
+// @Description Uploaded files (e.g. receipts) aren't part of the export
+// @ID export-application
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param id query string true "The UUID of the application to export"
+// @Param uuid query string false "Deprecated alias of id, used if id isn't set"
+// @Success 200 {object} ApplicationExport
+// @Failure 401 {object} Error
+// @Failure 404 {object} Error
+// @Failure 422 {object} Error
+// @Failure 500 {object} Error
+// @Router /exportApplication [get]
+func ExportApplication(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	uuid := con.Query("id")
+	if uuid == "" {
+		uuid = con.Query("uuid")
+	}
+	if uuid == "" {
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
+		return
+	}
+	db := mongo.MongoDatabaseConnector{}
+	if !db.Connect() {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
+		return
+	}
+	defer db.Close()
+	if !db.DoesApplicationExist(uuid) {
+		con.JSON(http.StatusNotFound, Error{localize(con, "application not found")})
+		return
+	}
+	application := db.GetApplication(uuid)
+	requestTeacher := db.GetTeacherByShort(auth.Username)
+	if !(isParticipant(application, requestTeacher) || requestTeacher.Administration || requestTeacher.AV || requestTeacher.PEK || requestTeacher.SuperUser) {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "unauthorized")})
+		return
+	}
+	con.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "antrag_"+uuid+".json"))
+	con.JSON(http.StatusOK, ApplicationExport{
+		SchemaVersion: ApplicationExportVersion,
+		ExportedAt:    time.Now(),
+		Application:   application,
+	})
+}
+
+// ImportApplication represents the import application endpoint
+// @Summary Imports an exported application
+// @Description Recreates an application from a document returned by the export application endpoint under a new UUID, which is returned
+// @Description The application has to meet the binding rules of the create application endpoint (e.g. a name and an end after the start), its start may be in the past though
+// @Description The logged in teacher has to participate in the application unless they are an administrator; the application starts with version 1 and isn't deleted, even if the exported one was
+// @ID import-application
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param export body ApplicationExport true "The exported application"
+// @Success 200 {object} ImportedApplication
+// @Failure 400 {object} ValidationError
+// @Failure 401 {object} Error
+// @Failure 422 {object} Error
+// @Failure 500 {object} Error
+// @Router /importApplication [post]
+func ImportApplication(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	var export ApplicationExport
+	if err := con.ShouldBindJSON(&export); err != nil {
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
+		return
+	}
+	if export.SchemaVersion != ApplicationExportVersion {
+		con.JSON(http.StatusBadRequest, Error{localizef(con, "unsupported schema version %d", export.SchemaVersion)})
+		return
+	}
+	application := export.Application
+	if _, ok := applicationKindNames[application.Kind]; !ok {
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
+		return
+	}
+	var req CreateApplicationRequest
+	if err := remarshal(application, &req); err != nil {
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
+		return
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		con.JSON(http.StatusBadRequest, validationError(con, err, req))
+		return
+	}
+	db := mongo.MongoDatabaseConnector{}
+	if !db.Connect() {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
+		return
+	}
+	defer db.Close()
+	requestTeacher := db.GetTeacherByShort(auth.Username)
+	if !(isParticipant(application, requestTeacher) || requestTeacher.Administration || requestTeacher.AV || requestTeacher.PEK || requestTeacher.SuperUser) {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "unauthorized")})
+		return
+	}
+	application.UUID = uuidG.NewString()
+	application.DeletedAt = nil
+	application.LastChanged = time.Now()
+	if !db.CreateApplication(application) {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "error; application not created")})
+		return
+	}
+	applicationEvents.publish(ApplicationCreated, db.GetApplication(application.UUID))
+	recordAudit(con, auth.Username, AuditImportApplication, application.UUID, nil, nil)
+	con.JSON(http.StatusOK, ImportedApplication{"success; application imported", application.UUID})
+}
@@ -0,0 +1,212 @@
+package rest
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"github.com/refundable-tgm/huginn/untis"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// readZip opens the body of the response as zip archive and returns the names of its entries
+func readZip(t *testing.T, recorder *httptest.ResponseRecorder) []string {
+	t.Helper()
+	body := recorder.Body.Bytes()
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("the response isn't a valid zip archive: %v", err)
+	}
+	names := make([]string, 0, len(archive.File))
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	return names
+}
+
+func TestExportFileName(t *testing.T) {
+	start := time.Date(2021, time.March, 1, 23, 30, 0, 0, time.UTC)
+	used := make(map[string]bool)
+	tests := []struct {
+		kind int
+		want string
+	}{
+		{mongo.SchoolEvent, "2021-03-02_schulveranstaltung.pdf"},
+		{mongo.Training, "2021-03-02_fortbildung.pdf"},
+		{mongo.OtherReason, "2021-03-02_sonstiges.pdf"},
+		{mongo.OtherReason, "2021-03-02_sonstiges_2.pdf"},
+		{mongo.OtherReason, "2021-03-02_sonstiges_3.pdf"},
+		{4, "2021-03-02_antrag.pdf"},
+	}
+	for _, test := range tests {
+		if got := exportFileName(mongo.Application{Kind: test.kind, StartTime: start}, used); got != test.want {
+			t.Errorf("an application of the kind %d is exported as %v, want %v", test.kind, got, test.want)
+		}
+	}
+}
+
+func TestStartedExportsAreValidZipArchives(t *testing.T) {
+	form := filepath.Join(t.TempDir(), "form.pdf")
+	if err := ioutil.WriteFile(form, []byte("%PDF-1.4"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	con, recorder := testContext(http.MethodGet, "/exportMyApplications", "")
+	archive := startExport(con, "szakall")
+	for _, name := range []string{"2021-03-01_sonstiges.pdf", "2021-03-02_fortbildung.pdf"} {
+		if err := addExportFile(archive, name, form); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Type") != "application/zip" {
+		t.Errorf("the export responded with %d as %q, want %d as a zip archive", recorder.Code, recorder.Header().Get("Content-Type"), http.StatusOK)
+	}
+	if disposition := recorder.Header().Get("Content-Disposition"); disposition != `attachment; filename="antraege_szakall.zip"` {
+		t.Errorf("the export is sent with the disposition %q, want an attachment named after the user", disposition)
+	}
+	if names := readZip(t, recorder); len(names) != 2 || names[0] != "2021-03-01_sonstiges.pdf" || names[1] != "2021-03-02_fortbildung.pdf" {
+		t.Errorf("the archive contains %v, want both forms", names)
+	}
+	if err := addExportFile(zip.NewWriter(ioutil.Discard), "missing.pdf", filepath.Join(t.TempDir(), "missing.pdf")); err == nil {
+		t.Error("adding a missing file returned no error")
+	}
+}
+
+func TestExportMyApplicationsRejectsInvalidRequests(t *testing.T) {
+	con, recorder := testContext(http.MethodGet, "/exportMyApplications", "")
+	ExportMyApplications(con)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("exporting without a login responded with %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+	con, recorder = authorizedContext(t, "exporter", http.MethodGet, "/exportMyApplications?from=yesterday", "")
+	ExportMyApplications(con)
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("exporting with an invalid filter responded with %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+}
+
+func TestExportMyApplicationsContainsAFormPerApplication(t *testing.T) {
+	db := requireDatabase(t)
+	filer := storeTeacher(t, db, "exporter", Permissions{})
+	other := storeTeacher(t, db, "bystander", Permissions{})
+	untisServing(t, filer.Short, masterData(nil))
+	first := otherReason(filer.Longname)
+	second := otherReason(filer.Longname)
+	second.StartTime, second.EndTime = first.StartTime.AddDate(0, 0, 1), first.EndTime.AddDate(0, 0, 1)
+	storeApplication(t, db, first)
+	storeApplication(t, db, second)
+	storeApplication(t, db, otherReason(other.Longname))
+
+	con, recorder := authorizedContext(t, filer.Short, http.MethodGet, "/exportMyApplications", "")
+	ExportMyApplications(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("exporting responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	names := readZip(t, recorder)
+	sort.Strings(names)
+	want := []string{
+		first.StartTime.In(untis.Location()).Format(DateFormat) + "_sonstiges.pdf",
+		second.StartTime.In(untis.Location()).Format(DateFormat) + "_sonstiges.pdf",
+	}
+	if len(names) != len(want) || names[0] != want[0] || names[1] != want[1] {
+		t.Errorf("the archive contains %v, want %v", names, want)
+	}
+}
+
+// importApplication calls ImportApplication as user with the export as body
+func importApplication(t *testing.T, username string, export interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(export)
+	if err != nil {
+		t.Fatal(err)
+	}
+	con, recorder := authorizedContext(t, username, http.MethodPost, "/importApplication", string(body))
+	ImportApplication(con)
+	return recorder
+}
+
+func TestImportApplicationRejectsInvalidDocuments(t *testing.T) {
+	valid := otherReason("Michael Borko")
+	unnamed := valid
+	unnamed.Name = ""
+	unknownKind := valid
+	unknownKind.Kind = 42
+	tests := []struct {
+		name   string
+		export interface{}
+		status int
+	}{
+		{"a newer schema version", ApplicationExport{SchemaVersion: ApplicationExportVersion + 1, Application: valid}, http.StatusBadRequest},
+		{"an unknown schema version", ApplicationExport{SchemaVersion: -1, Application: valid}, http.StatusBadRequest},
+		{"no schema version", map[string]interface{}{"application": valid}, http.StatusUnprocessableEntity},
+		{"an unknown kind", ApplicationExport{SchemaVersion: ApplicationExportVersion, Application: unknownKind}, http.StatusUnprocessableEntity},
+		{"an invalid application", ApplicationExport{SchemaVersion: ApplicationExportVersion, Application: unnamed}, http.StatusBadRequest},
+		{"a list", []ApplicationExport{}, http.StatusUnprocessableEntity},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if recorder := importApplication(t, "importer", test.export); recorder.Code != test.status {
+				t.Errorf("got %d, want %d", recorder.Code, test.status)
+			}
+		})
+	}
+	con, recorder := testContext(http.MethodGet, "/exportApplication", "")
+	ExportApplication(con)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("exporting without a login responded with %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+	con, recorder = authorizedContext(t, "importer", http.MethodGet, "/exportApplication", "")
+	ExportApplication(con)
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("exporting without an id responded with %d, want %d", recorder.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestExportedApplicationsCanBeImported(t *testing.T) {
+	db := requireDatabase(t)
+	filer := storeTeacher(t, db, "migrating", Permissions{})
+	app := otherReason(filer.Longname)
+	app.Notes = "Zug um 7:12"
+	app.StartAddress = "TGM, Wexstraße 19-23, 1200 Wien"
+	app.DestinationAddress = "Graz"
+	original := storeApplication(t, db, app)
+
+	con, recorder := authorizedContext(t, filer.Short, http.MethodGet, "/exportApplication?id="+original.UUID, "")
+	ExportApplication(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("exporting responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	export := ApplicationExport{}
+	decodeJSON(t, recorder, &export)
+	if export.SchemaVersion != ApplicationExportVersion {
+		t.Errorf("the export has the schema version %d, want %d", export.SchemaVersion, ApplicationExportVersion)
+	}
+
+	recorder = importApplication(t, filer.Short, export)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("importing responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	imported := ImportedApplication{}
+	decodeJSON(t, recorder, &imported)
+	t.Cleanup(func() { db.DeleteApplication(imported.UUID) })
+	if imported.UUID == "" || imported.UUID == original.UUID {
+		t.Fatalf("the application was imported as %q, want a new uuid", imported.UUID)
+	}
+	recreated := db.GetApplication(imported.UUID)
+	if recreated.Version != 1 || recreated.DeletedAt != nil {
+		t.Errorf("the imported application is in version %d and deleted at %v, want a new application", recreated.Version, recreated.DeletedAt)
+	}
+	recreated.UUID, recreated.Version, recreated.LastChanged = original.UUID, original.Version, original.LastChanged
+	if !reflect.DeepEqual(recreated, original) {
+		t.Errorf("the imported application is %+v, want the fields of %+v", recreated, original)
+	}
+}
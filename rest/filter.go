@@ -0,0 +1,72 @@
+package rest
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"strconv"
+	"time"
+)
+
+// applicationFilter restricts a list of applications to those matching all of its set criteria
+type applicationFilter struct {
+	// hasStatus whether only applications with the progress status should be kept
+	hasStatus bool
+	// status is the progress applications must have
+	status int
+	// from is the day applications must end on or after, if it isn't zero
+	from time.Time
+	// to is the day applications must start on or before, if it isn't zero
+	to time.Time
+}
+
+// parseApplicationFilter reads the status, from and to query parameters of a request
+// from and to are dates in DateFormat and both days are included in the range
+func parseApplicationFilter(con *gin.Context) (applicationFilter, error) {
+	filter := applicationFilter{}
+	if raw, present := con.GetQuery("status"); present {
+		status, err := strconv.Atoi(raw)
+		if err != nil || status < mongo.Rejected || status > mongo.Done {
+			return filter, fmt.Errorf("invalid status provided")
+		}
+		filter.hasStatus = true
+		filter.status = status
+	}
+	if raw, present := con.GetQuery("from"); present {
+		from, err := time.Parse(DateFormat, raw)
+		if err != nil {
+			return filter, fmt.Errorf("invalid from date provided")
+		}
+		filter.from = from
+	}
+	if raw, present := con.GetQuery("to"); present {
+		to, err := time.Parse(DateFormat, raw)
+		if err != nil {
+			return filter, fmt.Errorf("invalid to date provided")
+		}
+		filter.to = to
+	}
+	if !filter.from.IsZero() && !filter.to.IsZero() && filter.to.Before(filter.from) {
+		return filter, fmt.Errorf("to date is before from date")
+	}
+	return filter, nil
+}
+
+// apply returns the applications matching all criteria of the filter
+// an application matches the date range if it takes place at least partly in between from and to
+func (filter applicationFilter) apply(applications []mongo.Application) []mongo.Application {
+	res := make([]mongo.Application, 0)
+	for _, app := range applications {
+		if filter.hasStatus && app.Progress != filter.status {
+			continue
+		}
+		if !filter.from.IsZero() && app.EndTime.Before(filter.from) {
+			continue
+		}
+		if !filter.to.IsZero() && !app.StartTime.Before(filter.to.AddDate(0, 0, 1)) {
+			continue
+		}
+		res = append(res, app)
+	}
+	return res
+}
@@ -0,0 +1,58 @@
+package rest
+
+import (
+	mongo "github.com/refundable-tgm/huginn/db"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestApplicationFilter(t *testing.T) {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2021, month, day, 8, 0, 0, 0, time.UTC)
+	}
+	applications := []mongo.Application{
+		{UUID: "march", Progress: mongo.Rejected, StartTime: date(3, 1), EndTime: date(3, 5)},
+		{UUID: "april", Progress: mongo.Done, StartTime: date(4, 12), EndTime: date(4, 12)},
+		{UUID: "may", Progress: mongo.Done, StartTime: date(5, 3), EndTime: date(5, 7)},
+		{UUID: "april-may", Progress: mongo.Rejected, StartTime: date(4, 28), EndTime: date(5, 2)},
+	}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"march", "april", "may", "april-may"}},
+		{"status=" + strconv.Itoa(mongo.Done), []string{"april", "may"}},
+		{"from=2021-04-12", []string{"april", "may", "april-may"}},
+		{"to=2021-04-12", []string{"march", "april"}},
+		{"from=2021-04-01&to=2021-04-30", []string{"april", "april-may"}},
+		{"status=" + strconv.Itoa(mongo.Rejected) + "&from=2021-04-01&to=2021-04-30", []string{"april-may"}},
+		{"from=2021-06-01", []string{}},
+	}
+	for _, test := range tests {
+		con, _ := testContext(http.MethodGet, "/api/getAllApplications?"+test.query, "")
+		filter, err := parseApplicationFilter(con)
+		if err != nil {
+			t.Fatalf("%v: %v", test.query, err)
+		}
+		got := make([]string, 0)
+		for _, app := range filter.apply(applications) {
+			got = append(got, app.UUID)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%v: got %v, want %v", test.query, got, test.want)
+		}
+	}
+}
+
+func TestGetAllApplicationsRejectsInvalidFilters(t *testing.T) {
+	for _, query := range []string{"from=2021-13-01", "to=01.04.2021", "from=2021-05-01&to=2021-04-01", "status=99", "status=done"} {
+		con, recorder := authorizedContext(t, "filter", http.MethodGet, "/api/getAllApplications?"+query, "")
+		GetAllApplications(con)
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("got %d for %v, want %d", recorder.Code, query, http.StatusBadRequest)
+		}
+	}
+}
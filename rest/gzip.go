@@ -0,0 +1,181 @@
+package rest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// GzipMinLength is the minimum size in bytes a response body has to have to be compressed
+const GzipMinLength = 1024
+
+// compressedContentTypes are the content types of responses, which are compressed already and are sent as they are
+var compressedContentTypes = []string{
+	"application/pdf",
+	"application/zip",
+	"application/gzip",
+	"application/vnd.openxmlformats-officedocument",
+	"image/",
+}
+
+// gzipWriter holds back the response of a handler until it is known whether it is large enough to be compressed
+type gzipWriter struct {
+	gin.ResponseWriter
+	// status is the status code set by the handler
+	status int
+	// buffer is the beginning of the body as long as it is shorter than GzipMinLength
+	buffer bytes.Buffer
+	// committed whether the header was sent, from then on the body is written to compressor or directly
+	committed bool
+	// compressor compresses the body if it is compressed
+	compressor *gzip.Writer
+}
+
+// WriteHeader records the status code without sending it
+func (w *gzipWriter) WriteHeader(code int) {
+	if !w.committed {
+		w.status = code
+	}
+}
+
+// WriteHeaderNow does nothing, the header is sent once it is known whether the body is compressed
+func (w *gzipWriter) WriteHeaderNow() {}
+
+// Write buffers the data until the body reaches GzipMinLength and compresses it from then on
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	if w.committed {
+		if w.compressor != nil {
+			return w.compressor.Write(data)
+		}
+		return w.ResponseWriter.Write(data)
+	}
+	if isCompressedContentType(w.Header().Get("Content-Type")) {
+		_ = w.commit(false)
+		return w.ResponseWriter.Write(data)
+	}
+	w.buffer.Write(data)
+	if w.buffer.Len() >= GzipMinLength {
+		if err := w.commit(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+// WriteString is like Write
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Status returns the status code set by the handler
+func (w *gzipWriter) Status() int {
+	return w.status
+}
+
+// Written returns whether anything was written yet
+func (w *gzipWriter) Written() bool {
+	return w.committed || w.buffer.Len() > 0
+}
+
+// Flush sends everything written so far, a body which isn't compressed yet is sent uncompressed
+func (w *gzipWriter) Flush() {
+	if !w.committed {
+		_ = w.commit(false)
+	}
+	if w.compressor != nil {
+		_ = w.compressor.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// commit sends the header and the buffered body, compressed if compress is set
+func (w *gzipWriter) commit(compress bool) error {
+	w.committed = true
+	if compress {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Del("Content-Length")
+		// the body differs from the one the ETag was computed from, so it only matches weakly
+		if tag := w.Header().Get("ETag"); tag != "" && !strings.HasPrefix(tag, "W/") {
+			w.Header().Set("ETag", "W/"+tag)
+		}
+		w.compressor = gzip.NewWriter(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+	var err error
+	if w.compressor != nil {
+		_, err = w.compressor.Write(w.buffer.Bytes())
+	} else {
+		_, err = w.ResponseWriter.Write(w.buffer.Bytes())
+	}
+	w.buffer.Reset()
+	return err
+}
+
+// close sends what is left of the response and finishes the compression
+func (w *gzipWriter) close() {
+	if !w.committed {
+		_ = w.commit(false)
+		w.ResponseWriter.WriteHeaderNow()
+	}
+	if w.compressor != nil {
+		_ = w.compressor.Close()
+	}
+}
+
+// Gzip is a middleware compressing response bodies of at least GzipMinLength bytes if the client accepts gzip
+// bodies of compressedContentTypes and websocket upgrades are sent unchanged
+func Gzip() gin.HandlerFunc {
+	return func(con *gin.Context) {
+		con.Writer.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(con.GetHeader("Accept-Encoding")) || con.GetHeader("Upgrade") != "" || con.Request.Method == http.MethodHead {
+			con.Next()
+			return
+		}
+		original := con.Writer
+		writer := &gzipWriter{ResponseWriter: original, status: http.StatusOK}
+		con.Writer = writer
+		con.Next()
+		con.Writer = original
+		writer.close()
+	}
+}
+
+// acceptsGzip checks whether the Accept-Encoding header allows gzip (or any encoding) with a quality above 0
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		encoding := strings.ToLower(strings.TrimSpace(fields[0]))
+		if encoding != "gzip" && encoding != "*" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// isCompressedContentType checks whether the content type is one of compressedContentTypes
+func isCompressedContentType(contentType string) bool {
+	for _, compressed := range compressedContentTypes {
+		if strings.HasPrefix(contentType, compressed) {
+			return true
+		}
+	}
+	return false
+}
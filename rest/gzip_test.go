@@ -0,0 +1,112 @@
+package rest
+
+import (
+	"compress/gzip"
+	"github.com/gin-gonic/gin"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// serveGzip sends a request with the Accept-Encoding header (none if it is empty) through the Gzip middleware to
+// handler
+func serveGzip(acceptEncoding string, handler gin.HandlerFunc) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.GET("/", Gzip(), handler)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// jsonApplications returns a handler responding with a json list of n applications
+func jsonApplications(n int) gin.HandlerFunc {
+	return func(con *gin.Context) {
+		list := make([]gin.H, n)
+		for i := range list {
+			list[i] = gin.H{"name": "Dienstreise", "notes": "Zug um 7:12"}
+		}
+		con.JSON(http.StatusOK, list)
+	}
+}
+
+func TestLargeResponsesAreGzipped(t *testing.T) {
+	recorder := serveGzip("gzip, deflate", jsonApplications(100))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	if encoding := recorder.Header().Get("Content-Encoding"); encoding != "gzip" {
+		t.Fatalf("the large response is sent with the encoding %q, want gzip", encoding)
+	}
+	if vary := recorder.Header().Get("Vary"); vary != "Accept-Encoding" {
+		t.Errorf("the response varies by %q, want Accept-Encoding", vary)
+	}
+	reader, err := gzip.NewReader(recorder.Body)
+	if err != nil {
+		t.Fatalf("the body isn't gzipped: %v", err)
+	}
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uncompressed := serveGzip("", jsonApplications(100)); string(body) != uncompressed.Body.String() {
+		t.Errorf("the body decompresses to %q, want %q", body, uncompressed.Body)
+	}
+	if !strings.HasPrefix(recorder.Header().Get("Content-Type"), "application/json") {
+		t.Errorf("the response has the content type %q, want json", recorder.Header().Get("Content-Type"))
+	}
+}
+
+func TestResponsesAreSentUncompressed(t *testing.T) {
+	pdf := func(con *gin.Context) {
+		con.Data(http.StatusOK, "application/pdf", []byte("%PDF-1.4"+strings.Repeat(" ", 2*GzipMinLength)))
+	}
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		handler        gin.HandlerFunc
+	}{
+		{"a small response", "gzip", jsonApplications(1)},
+		{"a pdf", "gzip", pdf},
+		{"a client not accepting gzip", "", jsonApplications(100)},
+		{"a client refusing gzip", "gzip;q=0", jsonApplications(100)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := serveGzip(test.acceptEncoding, test.handler)
+			if encoding := recorder.Header().Get("Content-Encoding"); encoding != "" {
+				t.Errorf("the response is sent with the encoding %q, want none", encoding)
+			}
+			if vary := recorder.Header().Get("Vary"); vary != "Accept-Encoding" {
+				t.Errorf("the response varies by %q, want Accept-Encoding", vary)
+			}
+			if uncompressed := serveGzip("", test.handler); recorder.Body.String() != uncompressed.Body.String() {
+				t.Errorf("the body is %q, want it unchanged", recorder.Body)
+			}
+		})
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"gzip", true},
+		{"deflate, GZIP;q=0.5", true},
+		{"*", true},
+		{"gzip;q=0", false},
+		{"deflate, br", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		if got := acceptsGzip(test.header); got != test.want {
+			t.Errorf("acceptsGzip(%q) = %v, want %v", test.header, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package rest
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/refundable-tgm/huginn/untis"
+	"net/http"
+	"time"
+)
+
+// ReadinessTimeout is the time the readiness check waits for the untis api to respond
+const ReadinessTimeout = 3 * time.Second
+
+// startTime is the time the service was started at
+var startTime = time.Now()
+
+// Healthz represents the health check endpoint
+// it isn't part of the api group to be usable by probes without authentication
+// it responds with 200 if the token manager is initialized and 503 otherwise
+func Healthz(con *gin.Context) {
+	health := currentHealth()
+	if health.Status != "ok" {
+		con.JSON(http.StatusServiceUnavailable, health)
+		return
+	}
+	con.JSON(http.StatusOK, health)
+}
+
+// Readyz represents the readiness check endpoint
+// in addition to the health check it verifies that the untis api is reachable within ReadinessTimeout
+// it responds with 200 if everything is available and 503 otherwise
+func Readyz(con *gin.Context) {
+	health := currentHealth()
+	ctx, cancel := context.WithTimeout(con.Request.Context(), ReadinessTimeout)
+	defer cancel()
+	reachable := untis.CheckReachability(ctx) == nil
+	health.Untis = &reachable
+	if !reachable {
+		health.Status = "degraded"
+	}
+	if health.Status != "ok" {
+		con.JSON(http.StatusServiceUnavailable, health)
+		return
+	}
+	con.JSON(http.StatusOK, health)
+}
+
+// currentHealth collects the health information of this api
+func currentHealth() Health {
+	health := Health{
+		Status:       "ok",
+		Uptime:       time.Since(startTime).Round(time.Second).String(),
+		TokenManager: tokenManagerInitialized(),
+	}
+	if !health.TokenManager {
+		health.Status = "degraded"
+	}
+	return health
+}
@@ -0,0 +1,69 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/refundable-tgm/huginn/untis"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// checkHealth calls the health check handler and decodes its response
+func checkHealth(t *testing.T, handler gin.HandlerFunc) (int, map[string]interface{}) {
+	t.Helper()
+	con, recorder := testContext(http.MethodGet, "/healthz", "")
+	handler(con)
+	body := make(map[string]interface{})
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("the health check didn't respond with json: %v", err)
+	}
+	return recorder.Code, body
+}
+
+// untisAnswering makes untis.Server a server answering every request with the status until the test finishes
+func untisAnswering(t *testing.T, status int) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	previous := untis.Server
+	untis.Server = server.URL
+	t.Cleanup(func() { untis.Server = previous })
+}
+
+func TestHealthz(t *testing.T) {
+	status, body := checkHealth(t, Healthz)
+	if status != http.StatusOK || body["status"] != "ok" || body["token_manager"] != true {
+		t.Errorf("got %d %v, want a healthy service", status, body)
+	}
+	if _, ok := body["uptime"].(string); !ok {
+		t.Errorf("the health check doesn't report the uptime: %v", body)
+	}
+	if _, ok := body["untis"]; ok {
+		t.Errorf("the health check reports the untis api: %v", body)
+	}
+
+	previous := accessSecret
+	accessSecret = ""
+	defer func() { accessSecret = previous }()
+	status, body = checkHealth(t, Healthz)
+	if status != http.StatusServiceUnavailable || body["status"] != "degraded" || body["token_manager"] != false {
+		t.Errorf("got %d %v without a token manager, want a degraded service", status, body)
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	untisAnswering(t, http.StatusOK)
+	status, body := checkHealth(t, Readyz)
+	if status != http.StatusOK || body["status"] != "ok" || body["untis"] != true {
+		t.Errorf("got %d %v, want a ready service", status, body)
+	}
+
+	untisAnswering(t, http.StatusBadGateway)
+	status, body = checkHealth(t, Readyz)
+	if status != http.StatusServiceUnavailable || body["status"] != "degraded" || body["untis"] != false {
+		t.Errorf("got %d %v with an unavailable untis api, want a degraded service", status, body)
+	}
+}
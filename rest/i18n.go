@@ -0,0 +1,169 @@
+package rest
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// German is the language error messages are returned in by default
+const German = "de"
+
+// English is the language error messages are written in and fall back to if no translation exists
+const English = "en"
+
+// germanMessages maps the english error messages of the endpoints to their german translation
+var germanMessages = map[string]string{
+	"application isn't deleted": "Der Antrag ist nicht gelöscht",
+	"application not found":     "Der Antrag wurde nicht gefunden",
+	"application was changed in the meantime; reload it and apply the changes again": "Der Antrag wurde in der Zwischenzeit geändert; bitte neu laden und die Änderungen erneut durchführen",
+	"class not found":                                                         "Die Klasse wurde nicht gefunden",
+	"couldn't authenticate with untis API":                                    "Die Anmeldung bei WebUntis ist fehlgeschlagen",
+	"couldn't create csv file":                                                "Die CSV-Datei konnte nicht erstellt werden",
+	"couldn't create directories":                                             "Die Verzeichnisse konnten nicht erstellt werden",
+	"couldn't create excel":                                                   "Die Excel-Datei konnte nicht erstellt werden",
+	"couldn't create iCalendar feed":                                          "Der iCalendar-Feed konnte nicht erstellt werden",
+	"couldn't create new teacher based on this":                               "Die Lehrkraft konnte nicht angelegt werden",
+	"couldn't create pdf":                                                     "Das PDF konnte nicht erstellt werden",
+	"couldn't create pdfs":                                                    "Die PDFs konnten nicht erstellt werden",
+	"couldn't create the pdf file: %v":                                        "Die PDF-Datei konnte nicht erstellt werden: %v",
+	"couldn't decode the pdf file: %v":                                        "Die PDF-Datei konnte nicht dekodiert werden: %v",
+	"couldn't delete merged pdf":                                              "Das zusammengeführte PDF konnte nicht gelöscht werden",
+	"couldn't extract username":                                               "Der Benutzername konnte nicht ausgelesen werden",
+	"couldn't extract uuid":                                                   "Die UUID konnte nicht ausgelesen werden",
+	"couldn't log in":                                                         "Die Anmeldung ist fehlgeschlagen",
+	"couldn't logout out off untis API":                                       "Die Abmeldung bei WebUntis ist fehlgeschlagen",
+	"couldn't optimize merged pdf":                                            "Das zusammengeführte PDF konnte nicht optimiert werden",
+	"couldn't optimize pdf":                                                   "Das PDF konnte nicht optimiert werden",
+	"couldn't read classes from untis API":                                    "Die Klassen konnten nicht von WebUntis gelesen werden",
+	"couldn't read audit log":                                                 "Das Änderungsprotokoll konnte nicht gelesen werden",
+	"couldn't read generated excel":                                           "Die erstellte Excel-Datei konnte nicht gelesen werden",
+	"couldn't read generated pdf":                                             "Das erstellte PDF konnte nicht gelesen werden",
+	"couldn't read holidays from untis API":                                   "Die Ferien konnten nicht von WebUntis gelesen werden",
+	"couldn't read rooms from untis API":                                      "Die Räume konnten nicht von WebUntis gelesen werden",
+	"couldn't read longname of new teacher":                                   "Der Name der neuen Lehrkraft konnte nicht gelesen werden",
+	"couldn't read merged pdf":                                                "Das zusammengeführte PDF konnte nicht gelesen werden",
+	"couldn't read exams from untis API":                                      "Die Prüfungen konnten nicht von WebUntis gelesen werden",
+	"couldn't read substitutions from untis API":                              "Der Vertretungsplan konnte nicht von WebUntis gelesen werden",
+	"couldn't read teachers from untis API":                                   "Die Lehrkräfte konnten nicht von WebUntis gelesen werden",
+	"couldn't read timetable from untis API":                                  "Der Stundenplan konnte nicht von WebUntis gelesen werden",
+	"couldn't read user data from untis API":                                  "Die Benutzerdaten konnten nicht von WebUntis gelesen werden",
+	"couldn't read upload directory":                                          "Das Upload-Verzeichnis konnte nicht gelesen werden",
+	"couldn't resolve untis abbrevation of new teacher":                       "Das WebUntis-Kürzel der neuen Lehrkraft konnte nicht ermittelt werden",
+	"couldn't resolve untis id of new teacher":                                "Die WebUntis-ID der neuen Lehrkraft konnte nicht ermittelt werden",
+	"couldn't save merged pdf":                                                "Das zusammengeführte PDF konnte nicht gespeichert werden",
+	"couldn't save merged pdf; the uploaded files might be corrupted":         "Das zusammengeführte PDF konnte nicht gespeichert werden; die hochgeladenen Dateien könnten beschädigt sein",
+	"couldn't select the requested fields":                                    "Die angeforderten Felder konnten nicht ausgewählt werden",
+	"couldn't sign token":                                                     "Der Token konnte nicht signiert werden",
+	"couldn't sync the pdf file: %v":                                          "Die PDF-Datei konnte nicht gespeichert werden: %v",
+	"couldn't write the pdf file: %v":                                         "Die PDF-Datei konnte nicht geschrieben werden: %v",
+	"database didn't respond":                                                 "Die Datenbank antwortet nicht",
+	"endpoint not found":                                                      "Der Endpunkt wurde nicht gefunden",
+	"error; application not created":                                          "Fehler; der Antrag wurde nicht erstellt",
+	"error; application not deleted":                                          "Fehler; der Antrag wurde nicht gelöscht",
+	"error; application not restored":                                         "Fehler; der Antrag wurde nicht wiederhergestellt",
+	"error; application not updated":                                          "Fehler; der Antrag wurde nicht aktualisiert",
+	"error; teacher not updated":                                              "Fehler; die Lehrkraft wurde nicht aktualisiert",
+	"invalid bta_id provided":                                                 "Ungültige bta_id angegeben",
+	"invalid from date provided":                                              "Ungültiges Startdatum angegeben",
+	"invalid limit provided":                                                  "Ungültiges Limit angegeben",
+	"invalid request structure provided":                                      "Ungültige Anfrage",
+	"invalid sort provided":                                                   "Ungültige Sortierung angegeben",
+	"invalid status provided":                                                 "Ungültiger Status angegeben",
+	"invalid ti_id provided":                                                  "Ungültige ti_id angegeben",
+	"invalid to date provided":                                                "Ungültiges Enddatum angegeben",
+	"method not allowed":                                                      "Die Methode ist nicht erlaubt",
+	"not authenticated":                                                       "Nicht angemeldet",
+	"permissions couldn't be updated":                                         "Die Berechtigungen konnten nicht aktualisiert werden",
+	"refresh token expired":                                                   "Der Refresh-Token ist abgelaufen",
+	"request body too large":                                                  "Der Inhalt der Anfrage ist zu groß",
+	"room not found":                                                          "Der Raum wurde nicht gefunden",
+	"teacher are only allowed to update themselves":                           "Lehrkräfte dürfen nur ihre eigenen Daten ändern",
+	"teacher not found":                                                       "Die Lehrkraft wurde nicht gefunden",
+	"this credentials do not resolve into an authorized login":                "Die Zugangsdaten sind ungültig",
+	"this token isn't valid":                                                  "Dieser Token ist ungültig",
+	"this token was already used or revoked":                                  "Dieser Token wurde bereits verwendet oder widerrufen",
+	"to date is before from date":                                             "Das Enddatum liegt vor dem Startdatum",
+	"too many failed logins, try again later":                                 "Zu viele fehlgeschlagene Anmeldungen, bitte später erneut versuchen",
+	"token expired":                                                           "Der Token ist abgelaufen",
+	"token unvalid":                                                           "Der Token ist ungültig",
+	"travel invoice not found":                                                "Die Reiserechnung wurde nicht gefunden",
+	"unauthorized":                                                            "Keine Berechtigung",
+	"unknown permissions provided":                                            "Unbekannte Berechtigungen angegeben",
+	"unsupported format requested":                                            "Das angeforderte Format wird nicht unterstützt",
+	"unsupported schema version %d":                                           "Nicht unterstützte Schemaversion %d",
+	"untis is refusing further sessions, try again later":                     "WebUntis lässt keine weiteren Sitzungen zu, bitte später erneut versuchen",
+	"untis is unavailable, try again later":                                   "WebUntis ist nicht erreichbar, bitte später erneut versuchen",
+	"untis rejected this credentials":                                         "WebUntis hat die Zugangsdaten abgelehnt",
+	"this field is required":                                                  "Dieses Feld ist erforderlich",
+	"this value isn't allowed":                                                "Dieser Wert ist nicht erlaubt",
+	"this value is too small":                                                 "Dieser Wert ist zu klein",
+	"this value is too large":                                                 "Dieser Wert ist zu groß",
+	"this has to be after start_time":                                         "Dies muss nach start_time liegen",
+	"this has to be in the future":                                            "Dies muss in der Zukunft liegen",
+	"this has to contain one entry per class":                                 "Dies muss einen Eintrag pro Klasse enthalten",
+	"you are not logged in":                                                   "Sie sind nicht angemeldet",
+	"you have no permission to do this":                                       "Sie haben keine Berechtigung dafür",
+	"at most %d applications may be updated at once":                          "Es können höchstens %d Anträge auf einmal geändert werden",
+	"at most %d applications may be requested at once":                        "Es können höchstens %d Anträge auf einmal abgefragt werden",
+	"at most %d teachers may be requested at once":                            "Es können höchstens %d Lehrkräfte auf einmal abgefragt werden",
+	fmt.Sprintf("the range may not be longer than %d days", MaxTimetableDays): fmt.Sprintf("Der Zeitraum darf höchstens %d Tage lang sein", MaxTimetableDays),
+}
+
+// language returns the language error messages and generated forms should be returned in for the request, which is
+// the supported language (German or English) preferred in its Accept-Language header or German if it prefers none of them
+func language(con *gin.Context) string {
+	type preference struct {
+		lang    string
+		quality float64
+	}
+	preferences := make([]preference, 0)
+	for _, part := range strings.Split(con.GetHeader("Accept-Language"), ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		lang := strings.ToLower(strings.TrimSpace(fields[0]))
+		if lang == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		preferences = append(preferences, preference{lang, quality})
+	}
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].quality > preferences[j].quality
+	})
+	for _, pref := range preferences {
+		if pref.quality <= 0 {
+			continue
+		}
+		primary := strings.SplitN(pref.lang, "-", 2)[0]
+		if primary == German || primary == English {
+			return primary
+		}
+	}
+	return German
+}
+
+// localize translates the english message into the language of the request, it is returned unchanged if it
+// is requested in English or there is no translation
+func localize(con *gin.Context, message string) string {
+	if language(con) == German {
+		if translated, ok := germanMessages[message]; ok {
+			return translated
+		}
+	}
+	return message
+}
+
+// localizef is like localize but formats the translated format with the arguments
+func localizef(con *gin.Context, format string, args ...interface{}) string {
+	return fmt.Sprintf(localize(con, format), args...)
+}
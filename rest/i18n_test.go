@@ -0,0 +1,84 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorMessagesAreLocalized(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"", "Sie sind nicht angemeldet"},
+		{"de", "Sie sind nicht angemeldet"},
+		{"de-AT", "Sie sind nicht angemeldet"},
+		{"en", "you are not logged in"},
+		{"en-US,en;q=0.9", "you are not logged in"},
+		{"de;q=0.5, en", "you are not logged in"},
+		{"en;q=0, de", "Sie sind nicht angemeldet"},
+		{"fr", "Sie sind nicht angemeldet"},
+		{"fr, en;q=0.8", "you are not logged in"},
+	}
+	for _, test := range tests {
+		t.Run(test.acceptLanguage, func(t *testing.T) {
+			con, recorder := testContext(http.MethodGet, "/auditLog", "")
+			if test.acceptLanguage != "" {
+				con.Request.Header.Set("Accept-Language", test.acceptLanguage)
+			}
+			GetAuditLog(con)
+			res := Error{}
+			decodeJSON(t, recorder, &res)
+			if res.Message != test.want {
+				t.Errorf("responded with %q, want %q", res.Message, test.want)
+			}
+		})
+	}
+}
+
+func TestRouterErrorsAreLocalized(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"de", "Der Endpunkt wurde nicht gefunden"},
+		{"en", "endpoint not found"},
+	}
+	for _, test := range tests {
+		t.Run(test.acceptLanguage, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/doesNotExist", nil)
+			req.Header.Set("Accept-Language", test.acceptLanguage)
+			res := Error{}
+			decodeJSON(t, serveRouter(t, req), &res)
+			if res.Message != test.want {
+				t.Errorf("responded with %q, want %q", res.Message, test.want)
+			}
+		})
+	}
+}
+
+func TestLocalizef(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"de", "Es können höchstens 20 Lehrkräfte auf einmal abgefragt werden"},
+		{"en", "at most 20 teachers may be requested at once"},
+	}
+	for _, test := range tests {
+		con, _ := testContext(http.MethodGet, "/", "")
+		con.Request.Header.Set("Accept-Language", test.acceptLanguage)
+		if got := localizef(con, "at most %d teachers may be requested at once", 20); got != test.want {
+			t.Errorf("localized %q as %q, want %q", test.acceptLanguage, got, test.want)
+		}
+	}
+}
+
+func TestUntranslatedMessagesAreReturnedUnchanged(t *testing.T) {
+	con, _ := testContext(http.MethodGet, "/", "")
+	con.Request.Header.Set("Accept-Language", "de")
+	if got := localize(con, "a message without a translation"); got != "a message without a translation" {
+		t.Errorf("localized an untranslated message as %q", got)
+	}
+}
@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// NewsCacheControl is the Cache-Control header of news responses, they may be cached by the client for a short time
+// and are revalidated with If-Modified-Since afterwards
+const NewsCacheControl = "private, max-age=30"
+
+// modificationTracker records the time applications were changed the last time
+type modificationTracker struct {
+	// mutex guards last
+	mutex sync.RWMutex
+	// last is the time of the last change
+	last time.Time
+}
+
+// applicationsModified tracks changes of all applications, every change publishes an event which updates it;
+// changes before the service started are unknown, so it starts at the current time
+var applicationsModified = &modificationTracker{last: time.Now()}
+
+// touch records a change at now, earlier times are ignored
+func (tracker *modificationTracker) touch(now time.Time) {
+	tracker.mutex.Lock()
+	if now.After(tracker.last) {
+		tracker.last = now
+	}
+	tracker.mutex.Unlock()
+}
+
+// lastModified returns the time of the last change
+func (tracker *modificationTracker) lastModified() time.Time {
+	tracker.mutex.RLock()
+	defer tracker.mutex.RUnlock()
+	return tracker.last
+}
+
+// newsLastModified returns the time the news made of the applications changed the last time: the latest of the
+// persisted marker of the database, which covers changes of all instances including deletions and status changes,
+// the last change known to this instance and the latest change of the applications themselves
+func newsLastModified(persisted time.Time, applications []mongo.Application) time.Time {
+	modified := applicationsModified.lastModified()
+	if persisted.After(modified) {
+		modified = persisted
+	}
+	for _, application := range applications {
+		if application.LastChanged.After(modified) {
+			modified = application.LastChanged
+		}
+	}
+	return modified
+}
+
+// notModifiedSince sets the Last-Modified header to modified and checks whether the request contains an
+// If-Modified-Since header not before it, in which case 304 Not Modified should be answered
+// Last-Modified only has a precision of seconds, so it isn't set while the second of modified isn't over at now:
+// a later change in the same second would have the same Last-Modified and be answered with 304
+func notModifiedSince(con *gin.Context, modified, now time.Time) bool {
+	modified = modified.UTC().Truncate(time.Second)
+	if now.Before(modified.Add(time.Second)) {
+		return false
+	}
+	con.Header("Last-Modified", modified.Format(http.TimeFormat))
+	since, err := http.ParseTime(con.GetHeader("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !modified.After(since)
+}
@@ -0,0 +1,165 @@
+package rest
+
+import (
+	mongo "github.com/refundable-tgm/huginn/db"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// trackingModificationsSince replaces applicationsModified by a tracker starting at last until the test finishes
+func trackingModificationsSince(t *testing.T, last time.Time) {
+	t.Helper()
+	previous := applicationsModified
+	applicationsModified = &modificationTracker{last: last}
+	t.Cleanup(func() { applicationsModified = previous })
+}
+
+func TestModificationTrackerKeepsTheLatestChange(t *testing.T) {
+	start := time.Date(2021, time.March, 1, 8, 0, 0, 0, time.UTC)
+	tracker := &modificationTracker{last: start}
+	tracker.touch(start.Add(-time.Hour))
+	if last := tracker.lastModified(); !last.Equal(start) {
+		t.Errorf("an earlier change moved the last modification to %v", last)
+	}
+	tracker.touch(start.Add(time.Hour))
+	if last := tracker.lastModified(); !last.Equal(start.Add(time.Hour)) {
+		t.Errorf("the last modification is %v, want the later change", last)
+	}
+}
+
+func TestNewsLastModified(t *testing.T) {
+	start := time.Date(2021, time.March, 1, 8, 0, 0, 0, time.UTC)
+	trackingModificationsSince(t, start)
+	changed := []mongo.Application{{LastChanged: start.Add(-time.Hour)}, {LastChanged: start.Add(2 * time.Hour)}}
+	tests := []struct {
+		name         string
+		persisted    time.Time
+		applications []mongo.Application
+		want         time.Time
+	}{
+		{"the last change of this instance", start.Add(-time.Hour), nil, start},
+		{"a later change of another instance", start.Add(time.Hour), nil, start.Add(time.Hour)},
+		{"a later change of an application", start.Add(time.Hour), changed, start.Add(2 * time.Hour)},
+		{"no persisted marker", time.Time{}, changed[:1], start},
+	}
+	for _, test := range tests {
+		if got := newsLastModified(test.persisted, test.applications); !got.Equal(test.want) {
+			t.Errorf("%v: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestNotModifiedSince(t *testing.T) {
+	modified := time.Date(2021, time.March, 1, 8, 0, 0, 500, time.UTC)
+	tests := []struct {
+		name, since string
+		want        bool
+	}{
+		{"no If-Modified-Since", "", false},
+		{"the time of the change", "Mon, 01 Mar 2021 08:00:00 GMT", true},
+		{"a later time", "Mon, 01 Mar 2021 09:00:00 GMT", true},
+		{"an earlier time", "Mon, 01 Mar 2021 07:59:59 GMT", false},
+		{"an invalid time", "yesterday", false},
+	}
+	for _, test := range tests {
+		con, recorder := testContext(http.MethodGet, "/getNews", "")
+		if test.since != "" {
+			con.Request.Header.Set("If-Modified-Since", test.since)
+		}
+		if got := notModifiedSince(con, modified, modified.Add(time.Hour)); got != test.want {
+			t.Errorf("%v: got %v, want %v", test.name, got, test.want)
+		}
+		if last := recorder.Header().Get("Last-Modified"); last != "Mon, 01 Mar 2021 08:00:00 GMT" {
+			t.Errorf("%v: the response was last modified %q, want the change in seconds", test.name, last)
+		}
+	}
+}
+
+func TestChangesWithinTheSecondOfTheLastModificationAreNotMissed(t *testing.T) {
+	noon := time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC)
+	revalidate := func(modified, now time.Time, since string) (bool, string) {
+		con, recorder := testContext(http.MethodGet, "/getNews", "")
+		if since != "" {
+			con.Request.Header.Set("If-Modified-Since", since)
+		}
+		return notModifiedSince(con, modified, now), recorder.Header().Get("Last-Modified")
+	}
+
+	// fetched at 12:00:00.2 after a change at 12:00:00.1, a change at 12:00:00.7 would have the same Last-Modified
+	if notModified, last := revalidate(noon.Add(100*time.Millisecond), noon.Add(200*time.Millisecond), ""); notModified || last != "" {
+		t.Errorf("got %v with Last-Modified %q within the second of the change, want no Last-Modified", notModified, last)
+	}
+	if notModified, _ := revalidate(noon.Add(700*time.Millisecond), noon.Add(900*time.Millisecond), "Mon, 01 Mar 2021 12:00:00 GMT"); notModified {
+		t.Error("a change within the second of If-Modified-Since was answered with 304")
+	}
+	notModified, last := revalidate(noon.Add(700*time.Millisecond), noon.Add(time.Second), "Mon, 01 Mar 2021 11:59:59 GMT")
+	if notModified || last != "Mon, 01 Mar 2021 12:00:00 GMT" {
+		t.Fatalf("got %v with Last-Modified %q once the second is over, want 200 with the second of the change", notModified, last)
+	}
+	if notModified, _ := revalidate(noon.Add(700*time.Millisecond), noon.Add(2*time.Second), last); !notModified {
+		t.Error("revalidating with the Last-Modified handed out after the second was over wasn't answered with 304")
+	}
+}
+
+// waitForTheNextSecond waits until the current second is over, so changes made before get a Last-Modified
+func waitForTheNextSecond() {
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+}
+
+func TestGetNewsAnswersNotModifiedUntilTheNewsChange(t *testing.T) {
+	db := requireDatabase(t)
+	trackingModificationsSince(t, time.Now().Add(-time.Hour))
+	reader := storeTeacher(t, db, "polling", Permissions{})
+	app := otherReason(reader.Longname)
+	app.LastChanged = time.Now().Add(-time.Minute)
+	app = storeApplication(t, db, app)
+	waitForTheNextSecond()
+	getNews := func(since string) (int, http.Header, NewsPage) {
+		con, recorder := authorizedContext(t, reader.Short, http.MethodGet, "/getNews", "")
+		if since != "" {
+			con.Request.Header.Set("If-Modified-Since", since)
+		}
+		GetNews(con)
+		// the router writes the status of responses without a body once the handler returned
+		con.Writer.WriteHeaderNow()
+		page := NewsPage{}
+		if recorder.Code == http.StatusOK {
+			decodeJSON(t, recorder, &page)
+		} else if recorder.Body.Len() != 0 {
+			t.Errorf("the %d response has the body %s", recorder.Code, recorder.Body)
+		}
+		return recorder.Code, recorder.Header(), page
+	}
+
+	status, header, _ := getNews("")
+	if status != http.StatusOK || header.Get("Cache-Control") != NewsCacheControl {
+		t.Fatalf("got %d with Cache-Control %q, want %d with %q", status, header.Get("Cache-Control"), http.StatusOK, NewsCacheControl)
+	}
+	lastModified := header.Get("Last-Modified")
+	if status, _, _ := getNews(lastModified); status != http.StatusNotModified {
+		t.Errorf("revalidating unchanged news responded with %d, want %d", status, http.StatusNotModified)
+	}
+
+	app.Name = "Exkursion"
+	app.LastChanged = time.Now()
+	if !db.UpdateApplication(app.UUID, app) {
+		t.Fatal("couldn't update the application")
+	}
+	status, header, page := getNews(lastModified)
+	if status != http.StatusOK || header.Get("Last-Modified") != "" {
+		t.Errorf("revalidating within the second of a change responded with %d and Last-Modified %q, want %d without it",
+			status, header.Get("Last-Modified"), http.StatusOK)
+	}
+	waitForTheNextSecond()
+	status, header, page = getNews(lastModified)
+	if status != http.StatusOK {
+		t.Fatalf("revalidating changed news responded with %d, want %d", status, http.StatusOK)
+	}
+	if header.Get("Last-Modified") == lastModified {
+		t.Errorf("the changed news were last modified %v like before", lastModified)
+	}
+	if len(page.Items) != 1 || page.Items[0].Title != "Exkursion" {
+		t.Errorf("got the news %+v, want the changed application", page.Items)
+	}
+}
@@ -0,0 +1,240 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"github.com/refundable-tgm/huginn/untis"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestMain runs the tests in gin's test mode with a token manager using fixed secrets instead of the ones of /vol/secrets
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	accessSecret = "test-access-secret-of-32-letters"
+	refreshSecret = "test-refresh-secret-of-64-letters-test-refresh-secret-of-64-lett"
+	activeTokens = make(map[string]EntityInformation)
+	revokedTokens = make(map[string]time.Time)
+	os.Exit(m.Run())
+}
+
+// testContext creates a gin context for a request with the method, target and json body (none if it is empty)
+// and returns it together with the recorder of its response
+func testContext(method, target, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	recorder := httptest.NewRecorder()
+	con, _ := gin.CreateTestContext(recorder)
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	con.Request = httptest.NewRequest(method, target, reader)
+	if body != "" {
+		con.Request.Header.Set("Content-Type", "application/json")
+	}
+	return con, recorder
+}
+
+// loginAs creates and saves a token pair of the username like Login and returns the value of an Authorization header
+// carrying its access token, the tokens are revoked when the test finishes
+func loginAs(t *testing.T, username string) string {
+	t.Helper()
+	token, err := CreateToken(username)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SaveToken(username, token)
+	t.Cleanup(func() { RevokeToken(token.AccessUUID) })
+	return "Bearer " + token.AccessToken
+}
+
+// authorizedContext is like testContext but the request carries an access token of the username
+func authorizedContext(t *testing.T, username, method, target, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+	con, recorder := testContext(method, target, body)
+	con.Request.Header.Set("Authorization", loginAs(t, username))
+	return con, recorder
+}
+
+// fakeUntis is a json-rpc server answering the methods of the untis api with fixed results
+type fakeUntis struct {
+	*httptest.Server
+	// mutex guards the fields below
+	mutex sync.Mutex
+	// results are the results the methods are answered with, authenticate and logout are answered by the server
+	// itself and methods without a result are answered with an empty list
+	results map[string]interface{}
+	// params are the parameters of all calls received so far per method
+	params map[string][]json.RawMessage
+}
+
+// serve answers a single json-rpc request
+func (fake *fakeUntis) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	req := struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}{}
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fake.mutex.Lock()
+	fake.params[req.Method] = append(fake.params[req.Method], req.Params)
+	result, ok := fake.results[req.Method]
+	fake.mutex.Unlock()
+	if !ok {
+		switch req.Method {
+		case "authenticate":
+			result = map[string]interface{}{"sessionId": "session", "personType": int(untis.PersonTypeTeacher), "personId": 7}
+		case "logout":
+			result = nil
+		default:
+			result = []interface{}{}
+		}
+	}
+	// untis answers with the id as string, no matter whether it was sent as number or string
+	id := strings.Trim(string(req.ID), `"`)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": id, "result": result})
+}
+
+// callsOf returns the parameters of the calls of the method received so far
+func (fake *fakeUntis) callsOf(method string) []json.RawMessage {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	return append([]json.RawMessage(nil), fake.params[method]...)
+}
+
+// untisServing starts a fakeUntis answering with the results and registers an untis client of the username using it,
+// like Login does; both are removed when the test finishes
+func untisServing(t *testing.T, username string, results map[string]interface{}) *fakeUntis {
+	t.Helper()
+	fake := &fakeUntis{results: results, params: make(map[string][]json.RawMessage)}
+	fake.Server = httptest.NewServer(http.HandlerFunc(fake.serve))
+	t.Cleanup(fake.Close)
+	client := untis.CreateClientForSchool(fake.URL, "test", username, "password")
+	client.HTTPClient = fake.Client()
+	client.RateLimit = 0
+	client.RetryDelay = 0
+	client.Logf = func(string, ...interface{}) {}
+	t.Cleanup(client.DeleteClient)
+	return fake
+}
+
+// decodeJSON decodes the body of the response into v
+func decodeJSON(t *testing.T, recorder *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(recorder.Body.Bytes(), v); err != nil {
+		t.Fatalf("couldn't decode the response %s: %v", recorder.Body, err)
+	}
+}
+
+// requireDatabase connects to the database configured like in production (MONGO_DATABASE, MONGO_USERNAME_FILE and
+// MONGO_PASSWORD_FILE) and skips the test if there is none, the connection is closed when the test finishes
+func requireDatabase(t *testing.T) mongo.MongoDatabaseConnector {
+	t.Helper()
+	db := mongo.MongoDatabaseConnector{}
+	if !db.Connect() {
+		t.Skip("no database is configured")
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// storeTeacher stores a teacher with the short name and permissions, it is deleted when the test finishes
+func storeTeacher(t *testing.T, db mongo.MongoDatabaseConnector, short string, perm Permissions) mongo.Teacher {
+	t.Helper()
+	teacher := mongo.Teacher{
+		UUID:           uuid.NewString(),
+		Short:          short,
+		Longname:       "Test " + short,
+		SuperUser:      perm.SuperUser,
+		Administration: perm.Administration,
+		AV:             perm.AV,
+		PEK:            perm.PEK,
+	}
+	if !db.CreateTeacher(teacher) {
+		t.Fatalf("couldn't store the teacher %v", short)
+	}
+	t.Cleanup(func() { db.DeleteTeacher(teacher.UUID) })
+	return teacher
+}
+
+// storeApplication stores the application under a new uuid and returns it as stored, it is deleted when the test finishes
+func storeApplication(t *testing.T, db mongo.MongoDatabaseConnector, app mongo.Application) mongo.Application {
+	t.Helper()
+	app.UUID = uuid.NewString()
+	if !db.CreateApplication(app) {
+		t.Fatalf("couldn't store the application %v", app.Name)
+	}
+	t.Cleanup(func() { db.DeleteApplication(app.UUID) })
+	return db.GetApplication(app.UUID)
+}
+
+// masterData returns the results of getTeachers, getRooms, getKlassen and getSubjects of the school served by a
+// fakeUntis together with the results
+func masterData(results map[string]interface{}) map[string]interface{} {
+	data := map[string]interface{}{
+		"getTeachers": []untis.Teacher{
+			{ID: 1, Name: "BOR", ForeName: "Michael", LongName: "Borko"},
+			{ID: 2, Name: "HUD", ForeName: "Anna", LongName: "van der Hude"},
+		},
+		"getRooms": []untis.Room{
+			{ID: 10, Name: "H1102", LongName: "Hörsaal 1102"},
+			{ID: 11, Name: "L2201", LongName: "Labor 2201"},
+		},
+		"getKlassen": []untis.Class{
+			{ID: 20, Name: "5AHIT", LongName: "Informationstechnologie 5A", Teacher1: 1},
+			{ID: 21, Name: "4BHIT", LongName: "Informationstechnologie 4B", Teacher1: 2},
+		},
+		"getSubjects": []untis.Subject{
+			{ID: 30, Name: "SEW", LongName: "Softwareentwicklung"},
+			{ID: 31, Name: "D", LongName: "Deutsch"},
+		},
+	}
+	for method, result := range results {
+		data[method] = result
+	}
+	return data
+}
+
+// untisLesson returns a lesson as returned by getTimetable on the date (e.g. 20210301) from start to end
+// (e.g. 800 and 850), it belongs to the class, teacher, subject and room with the ids
+func untisLesson(id, date, start, end, class, teacher, subject, room int) map[string]interface{} {
+	return map[string]interface{}{
+		"id":        id,
+		"date":      date,
+		"startTime": start,
+		"endTime":   end,
+		"kl":        []map[string]int{{"id": class}},
+		"te":        []map[string]int{{"id": teacher}},
+		"su":        []map[string]int{{"id": subject}},
+		"ro":        []map[string]int{{"id": room}},
+	}
+}
+
+// setenv sets the environment variable to value until the test finishes
+func setenv(t *testing.T, key, value string) {
+	t.Helper()
+	previous, ok := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if ok {
+			_ = os.Setenv(key, previous)
+		} else {
+			_ = os.Unsetenv(key)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/refundable-tgm/huginn/untis"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// metricsNamespace is the namespace of all metrics exposed by this api
+const metricsNamespace = "huginn"
+
+// httpRequestsTotal counts the handled http requests by route, method and status
+var httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metricsNamespace,
+	Name:      "http_requests_total",
+	Help:      "Amount of handled http requests by route, method and status.",
+}, []string{"route", "method", "status"})
+
+// httpRequestDuration observes the time it took to handle http requests by route, method and status
+var httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: metricsNamespace,
+	Name:      "http_request_duration_seconds",
+	Help:      "Time it took to handle http requests by route, method and status.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"route", "method", "status"})
+
+// untisRequestDuration observes the time requests to the untis api took by json-rpc method and result
+var untisRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: metricsNamespace,
+	Name:      "untis_request_duration_seconds",
+	Help:      "Time requests to the untis api took by json-rpc method and result (ok or error).",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"method", "result"})
+
+// registerMetricsOnce guards the registration of the metrics
+var registerMetricsOnce sync.Once
+
+// registerMetrics registers all metrics at the default prometheus registry and hooks into the untis client
+func registerMetrics() {
+	registerMetricsOnce.Do(func() {
+		prometheus.MustRegister(httpRequestsTotal, httpRequestDuration, untisRequestDuration)
+		untis.ObserveRequest = func(method string, duration time.Duration, err error) {
+			result := "ok"
+			if err != nil {
+				result = "error"
+			}
+			untisRequestDuration.WithLabelValues(method, result).Observe(duration.Seconds())
+		}
+	})
+}
+
+// Metrics records the duration and status of every request under its route
+// requests not matching any route are recorded under the route "unmatched"
+func Metrics() gin.HandlerFunc {
+	return func(con *gin.Context) {
+		start := time.Now()
+		con.Next()
+		route := con.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(con.Writer.Status())
+		httpRequestsTotal.WithLabelValues(route, con.Request.Method, status).Inc()
+		httpRequestDuration.WithLabelValues(route, con.Request.Method, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// MetricsHandler represents the metrics endpoint providing all metrics in the prometheus text format
+// like the health checks it isn't part of the api group to be scrapable without authentication
+func MetricsHandler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
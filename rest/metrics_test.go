@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"errors"
+	"github.com/refundable-tgm/huginn/untis"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scrapeMetrics requests /metrics like prometheus and returns the exposed metrics
+func scrapeMetrics(t *testing.T) string {
+	t.Helper()
+	recorder := serveRouter(t, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("scraping /metrics responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	return recorder.Body.String()
+}
+
+func TestMetricsExposeRequestDurations(t *testing.T) {
+	serveRouter(t, httptest.NewRequest(http.MethodGet, "/version", nil))
+	serveRouter(t, httptest.NewRequest(http.MethodGet, "/api/doesNotExist", nil))
+	metrics := scrapeMetrics(t)
+	for _, want := range []string{
+		`huginn_http_requests_total{method="GET",route="/version",status="200"}`,
+		`huginn_http_request_duration_seconds_bucket{method="GET",route="/version",status="200",le="+Inf"}`,
+		`huginn_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"}`,
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("the metrics don't contain %v", want)
+		}
+	}
+}
+
+func TestMetricsExposeUntisRequestDurations(t *testing.T) {
+	scrapeMetrics(t)
+	if untis.ObserveRequest == nil {
+		t.Fatal("the router doesn't hook into the requests to untis")
+	}
+	untis.ObserveRequest("getTimetable", 120*time.Millisecond, nil)
+	untis.ObserveRequest("getTeachers", time.Second, errors.New("untis is unavailable"))
+	metrics := scrapeMetrics(t)
+	for _, want := range []string{
+		`huginn_untis_request_duration_seconds_bucket{method="getTimetable",result="ok",le="0.25"}`,
+		`huginn_untis_request_duration_seconds_count{method="getTeachers",result="error"}`,
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("the metrics don't contain %v", want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"strings"
+)
+
+const (
+	// FormatJSON is the format of representations encoded as json
+	FormatJSON = "json"
+	// FormatXLSX is the format of representations as excel files
+	FormatXLSX = "xlsx"
+	// FormatPDF is the format of representations as pdf files
+	FormatPDF = "pdf"
+)
+
+// formatContentTypes are the content types of the formats
+var formatContentTypes = map[string]string{
+	FormatJSON: gin.MIMEJSON,
+	FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	FormatPDF:  "application/pdf",
+}
+
+// negotiateFormat returns which of the supported formats the request asks for and whether it asks for one of them
+// the format query parameter is used if it is set, otherwise the first media range of the Accept header matching
+// a supported format; the first supported format is returned if neither is set
+// the response varies on the Accept header, which is announced to caches by adding it to the Vary header
+func negotiateFormat(con *gin.Context, supported ...string) (string, bool) {
+	con.Writer.Header().Add("Vary", "Accept")
+	if format := strings.ToLower(strings.TrimSpace(con.Query("format"))); format != "" {
+		for _, s := range supported {
+			if format == s {
+				return s, true
+			}
+		}
+		return "", false
+	}
+	accept := strings.TrimSpace(con.GetHeader("Accept"))
+	if accept == "" {
+		return supported[0], true
+	}
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaRange = strings.ToLower(strings.TrimSpace(strings.SplitN(mediaRange, ";", 2)[0]))
+		for _, s := range supported {
+			contentType := formatContentTypes[s]
+			if mediaRange == "*/*" || mediaRange == contentType ||
+				(strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(mediaRange, "*"))) {
+				return s, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,126 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"github.com/refundable-tgm/huginn/files"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		query, accept string
+		want          string
+		ok            bool
+	}{
+		{"", "", FormatJSON, true},
+		{"format=pdf", "", FormatPDF, true},
+		{"format=XLSX", "application/json", FormatXLSX, true},
+		{"format=docx", "", "", false},
+		{"", "application/pdf", FormatPDF, true},
+		{"", "text/html, application/vnd.openxmlformats-officedocument.spreadsheetml.sheet;q=0.9", FormatXLSX, true},
+		{"", "application/*", FormatJSON, true},
+		{"", "*/*", FormatJSON, true},
+		{"", "text/html", "", false},
+	}
+	for _, test := range tests {
+		con, recorder := testContext(http.MethodGet, "/getTravelInvoice?"+test.query, "")
+		if test.accept != "" {
+			con.Request.Header.Set("Accept", test.accept)
+		}
+		format, ok := negotiateFormat(con, FormatJSON, FormatXLSX, FormatPDF)
+		if format != test.want || ok != test.ok {
+			t.Errorf("%q accepting %q: got %q, %v, want %q, %v", test.query, test.accept, format, ok, test.want, test.ok)
+		}
+		if vary := recorder.Header().Get("Vary"); vary != "Accept" {
+			t.Errorf("%q accepting %q: the response varies by %q, want Accept", test.query, test.accept, vary)
+		}
+	}
+}
+
+func TestGetTravelInvoiceRejectsUnsupportedFormats(t *testing.T) {
+	tests := []struct {
+		query, accept string
+	}{
+		{"format=docx", ""},
+		{"format=csv", "application/json"},
+		{"", "text/html"},
+	}
+	for _, test := range tests {
+		con, recorder := authorizedContext(t, "negotiating", http.MethodGet, "/getTravelInvoice?uuid=1&short=bor&ti_id=1&"+test.query, "")
+		con.Request.Header.Set("Accept-Language", "en")
+		if test.accept != "" {
+			con.Request.Header.Set("Accept", test.accept)
+		}
+		GetTravelInvoice(con)
+		if recorder.Code != http.StatusNotAcceptable {
+			t.Errorf("%q accepting %q: got %d, want %d", test.query, test.accept, recorder.Code, http.StatusNotAcceptable)
+		}
+		if !strings.Contains(recorder.Body.String(), "unsupported format requested") {
+			t.Errorf("%q accepting %q: got %s, want the format to be reported", test.query, test.accept, recorder.Body)
+		}
+	}
+}
+
+func TestGetTravelInvoiceReturnsTheRequestedFormat(t *testing.T) {
+	db := requireDatabase(t)
+	filer := storeTeacher(t, db, "invoicing", Permissions{})
+	app := otherReason(filer.Longname)
+	app.TravelInvoices = []mongo.TravelInvoice{{ID: 1, Surname: "Borko", Name: "Michael", TripBeginTime: time.Now().AddDate(0, 0, 1)}}
+	app = storeApplication(t, db, app)
+	getTravelInvoice := func(t *testing.T, query, accept string, handler func(*gin.Context)) *httptest.ResponseRecorder {
+		t.Helper()
+		con, recorder := authorizedContext(t, filer.Short, http.MethodGet, "/getTravelInvoice?uuid="+app.UUID+"&short="+filer.Short+"&ti_id=1"+query, "")
+		if accept != "" {
+			con.Request.Header.Set("Accept", accept)
+		}
+		handler(con)
+		return recorder
+	}
+
+	for _, accept := range []string{"", "application/json"} {
+		recorder := getTravelInvoice(t, "", accept, GetTravelInvoice)
+		if recorder.Code != http.StatusOK || !strings.HasPrefix(recorder.Header().Get("Content-Type"), gin.MIMEJSON) {
+			t.Fatalf("accepting %q responded with %d as %q, want json", accept, recorder.Code, recorder.Header().Get("Content-Type"))
+		}
+		ti := mongo.TravelInvoice{}
+		decodeJSON(t, recorder, &ti)
+		if ti.ID != 1 || ti.Surname != "Borko" {
+			t.Errorf("got the travel invoice %+v, want the stored one", ti)
+		}
+	}
+
+	if _, err := os.Stat(files.TemplatePath); err != nil {
+		t.Skipf("the excel templates aren't available: %v", err)
+	}
+	for _, format := range []string{FormatXLSX, FormatPDF} {
+		for name, recorder := range map[string]*httptest.ResponseRecorder{
+			"the format parameter": getTravelInvoice(t, "&format="+format, "", GetTravelInvoice),
+			"the accept header":    getTravelInvoice(t, "", formatContentTypes[format], GetTravelInvoice),
+		} {
+			if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Type") != formatContentTypes[format] {
+				t.Errorf("%v %v responded with %d as %q, want %q", format, name, recorder.Code, recorder.Header().Get("Content-Type"), formatContentTypes[format])
+			}
+			if !strings.HasPrefix(recorder.Header().Get("Content-Disposition"), "attachment;") {
+				t.Errorf("%v %v is sent with the disposition %q, want an attachment", format, name, recorder.Header().Get("Content-Disposition"))
+			}
+		}
+	}
+	aliases := map[string]func(*gin.Context){"excel": GetTravelInvoiceExcel, "pdf": GetTravelInvoiceForm}
+	for field, handler := range aliases {
+		recorder := getTravelInvoice(t, "", "", handler)
+		encoded := make(map[string]string)
+		if recorder.Code == http.StatusOK {
+			_ = json.Unmarshal(recorder.Body.Bytes(), &encoded)
+		}
+		if encoded[field] == "" {
+			t.Errorf("the deprecated %v endpoint responded with %d: %s, want the file base64 encoded", field, recorder.Code, recorder.Body)
+		}
+	}
+}
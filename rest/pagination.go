@@ -0,0 +1,108 @@
+package rest
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"strconv"
+)
+
+// DefaultPageSize is the amount of items on a page if no limit is provided
+const DefaultPageSize = 20
+
+// MaxPageSize is the maximum amount of items on a page, bigger limits are clamped to it
+const MaxPageSize = 100
+
+// MaxBatchApplications is the maximum amount of applications which may be requested in one call of get applications
+const MaxBatchApplications = 50
+
+// MaxBulkStatusApplications is the maximum amount of applications whose status may be set in one call of bulk update application status
+const MaxBulkStatusApplications = 100
+
+// NewsPageSize is the amount of news on a page if no limit is provided
+const NewsPageSize = 10
+
+// newsSortOrders are the allowed values of the sort query parameter of the news, mapped to whether they put the
+// newest news first
+var newsSortOrders = map[string]bool{
+	"date_desc": true,
+	"date_asc":  false,
+}
+
+// DefaultNewsSort is the order of the news if no sort is provided
+const DefaultNewsSort = "date_desc"
+
+// parsePagination reads the offset and limit query parameters of a request
+// missing parameters default to the first page of DefaultPageSize items, limits above MaxPageSize are clamped
+// ok is false if a parameter is not a number or out of bounds (negative offset, limit below 1)
+func parsePagination(con *gin.Context) (offset, limit int, ok bool) {
+	return parsePaginationOf(con, DefaultPageSize)
+}
+
+// parsePaginationOf is like parsePagination but pages contain defaultLimit items if no limit is provided
+func parsePaginationOf(con *gin.Context, defaultLimit int) (offset, limit int, ok bool) {
+	offset, limit = 0, defaultLimit
+	var err error
+	if raw, present := con.GetQuery("offset"); present {
+		offset, err = strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return 0, 0, false
+		}
+	}
+	if raw, present := con.GetQuery("limit"); present {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return 0, 0, false
+		}
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+	return offset, limit, true
+}
+
+// parseNewsSort reads the sort query parameter of the news and returns whether the newest news come first
+// an error is returned if it isn't one of newsSortOrders
+func parseNewsSort(con *gin.Context) (bool, error) {
+	raw := con.DefaultQuery("sort", DefaultNewsSort)
+	newestFirst, ok := newsSortOrders[raw]
+	if !ok {
+		return false, fmt.Errorf("invalid sort provided")
+	}
+	return newestFirst, nil
+}
+
+// pageBounds returns the indices of the first and behind the last of total items on the page starting at offset
+// containing up to limit items and the offset of the next page, which is -1 if it is the last page
+// an offset after the last item results in an empty page
+func pageBounds(total, offset, limit int) (start, end, next int) {
+	if offset >= total {
+		return total, total, -1
+	}
+	end = offset + limit
+	if end < total {
+		return offset, end, end
+	}
+	return offset, total, -1
+}
+
+// paginateApplications returns the page of applications starting at offset containing up to limit applications
+// an offset after the last application results in an empty page
+func paginateApplications(applications []mongo.Application, offset, limit int) ApplicationPage {
+	start, end, next := pageBounds(len(applications), offset, limit)
+	return ApplicationPage{
+		Items:      append(make([]mongo.Application, 0, end-start), applications[start:end]...),
+		Total:      len(applications),
+		NextOffset: next,
+	}
+}
+
+// paginateNews returns the page of news starting at offset containing up to limit news
+func paginateNews(news []News, offset, limit int) NewsPage {
+	start, end, next := pageBounds(len(news), offset, limit)
+	return NewsPage{
+		Items:      append(make([]News, 0, end-start), news[start:end]...),
+		Total:      len(news),
+		NextOffset: next,
+	}
+}
@@ -0,0 +1,116 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"net/http"
+	"testing"
+)
+
+// applicationsNamed creates amount applications named after their index
+func applicationsNamed(amount int) []mongo.Application {
+	applications := make([]mongo.Application, 0, amount)
+	for i := 0; i < amount; i++ {
+		applications = append(applications, mongo.Application{UUID: fmt.Sprint(i), Name: fmt.Sprint("application ", i)})
+	}
+	return applications
+}
+
+func TestPaginateApplications(t *testing.T) {
+	applications := applicationsNamed(250)
+	tests := []struct {
+		name        string
+		query       string
+		first       string
+		items, next int
+	}{
+		{name: "first page", query: "", first: "0", items: DefaultPageSize, next: DefaultPageSize},
+		{name: "middle page", query: "offset=40&limit=30", first: "40", items: 30, next: 70},
+		{name: "last page", query: "offset=240&limit=30", first: "240", items: 10, next: -1},
+		{name: "over-max limit is clamped", query: "limit=1000", first: "0", items: MaxPageSize, next: MaxPageSize},
+		{name: "out-of-range offset", query: "offset=300", items: 0, next: -1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			con, _ := testContext(http.MethodGet, "/api/getAllApplications?"+test.query, "")
+			offset, limit, ok := parsePagination(con)
+			if !ok {
+				t.Fatal("valid pagination was rejected")
+			}
+			page := paginateApplications(applications, offset, limit)
+			if len(page.Items) != test.items || page.NextOffset != test.next || page.Total != len(applications) {
+				t.Fatalf("got %d items, next offset %d and total %d, want %d, %d and %d", len(page.Items), page.NextOffset, page.Total, test.items, test.next, len(applications))
+			}
+			if test.items > 0 && page.Items[0].UUID != test.first {
+				t.Errorf("the page starts with %v, want %v", page.Items[0].UUID, test.first)
+			}
+			encoded, _ := json.Marshal(page)
+			var decoded struct {
+				Items []mongo.Application `json:"items"`
+			}
+			if err := json.Unmarshal(encoded, &decoded); err != nil || decoded.Items == nil {
+				t.Errorf("an empty page has to encode its items as an empty list: %s", encoded)
+			}
+		})
+	}
+}
+
+func TestGetAllApplicationsRejectsInvalidPagination(t *testing.T) {
+	for _, query := range []string{"offset=-1", "offset=abc", "limit=0", "limit=-5", "limit=ten"} {
+		con, recorder := authorizedContext(t, "pager", http.MethodGet, "/api/getAllApplications?"+query, "")
+		GetAllApplications(con)
+		if recorder.Code != http.StatusUnprocessableEntity {
+			t.Errorf("got %d for %v, want %d", recorder.Code, query, http.StatusUnprocessableEntity)
+		}
+	}
+}
+
+func TestParseNewsSort(t *testing.T) {
+	tests := []struct {
+		query       string
+		newestFirst bool
+		valid       bool
+	}{
+		{"", true, true},
+		{"sort=date_desc", true, true},
+		{"sort=date_asc", false, true},
+		{"sort=name", false, false},
+		{"sort=DATE_ASC", false, false},
+		{"sort=", false, false},
+	}
+	for _, test := range tests {
+		con, _ := testContext(http.MethodGet, "/api/getNews?"+test.query, "")
+		newestFirst, err := parseNewsSort(con)
+		if (err == nil) != test.valid || newestFirst != test.newestFirst {
+			t.Errorf("%q: got %v, %v, want %v and valid %v", test.query, newestFirst, err, test.newestFirst, test.valid)
+		}
+	}
+}
+
+func TestPaginateNews(t *testing.T) {
+	news := make([]News, 0, 25)
+	for i := 0; i < 25; i++ {
+		news = append(news, News{UUID: fmt.Sprint(i)})
+	}
+	tests := []struct {
+		offset, limit int
+		first         string
+		items, next   int
+	}{
+		{0, NewsPageSize, "0", NewsPageSize, NewsPageSize},
+		{20, 5, "20", 5, -1},
+		{24, 10, "24", 1, -1},
+		{25, 10, "", 0, -1},
+	}
+	for _, test := range tests {
+		page := paginateNews(news, test.offset, test.limit)
+		if len(page.Items) != test.items || page.NextOffset != test.next || page.Total != len(news) {
+			t.Errorf("offset %d: got %d items, next offset %d and total %d, want %d, %d and %d", test.offset, len(page.Items), page.NextOffset, page.Total, test.items, test.next, len(news))
+			continue
+		}
+		if test.items > 0 && page.Items[0].UUID != test.first {
+			t.Errorf("offset %d: the page starts with %v, want %v", test.offset, page.Items[0].UUID, test.first)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"io/ioutil"
+	"net/http"
+)
+
+// protectedFields are the fields of an application a patch may not change
+var protectedFields = []string{"uuid", "deleted_at", "version"}
+
+// mergePatch applies an RFC 7396 merge patch onto target: fields absent in the patch are kept, fields set to null
+// are removed and objects are merged recursively, every other value replaces the one of target
+func mergePatch(target, patch map[string]interface{}) map[string]interface{} {
+	if target == nil {
+		target = make(map[string]interface{})
+	}
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+		if patchObject, ok := value.(map[string]interface{}); ok {
+			targetObject, _ := target[key].(map[string]interface{})
+			target[key] = mergePatch(targetObject, patchObject)
+			continue
+		}
+		target[key] = value
+	}
+	return target
+}
+
+// patchApplication applies the merge patch onto the application and returns the result
+// fields removed by the patch are reset to their zero value, protected fields are kept
+func patchApplication(application mongo.Application, patch map[string]interface{}) (mongo.Application, error) {
+	for _, field := range protectedFields {
+		delete(patch, field)
+	}
+	var fields map[string]interface{}
+	if err := remarshal(application, &fields); err != nil {
+		return application, err
+	}
+	patched := mongo.Application{}
+	if err := remarshal(mergePatch(fields, patch), &patched); err != nil {
+		return application, err
+	}
+	patched.UUID = application.UUID
+	patched.DeletedAt = application.DeletedAt
+	patched.Version = application.Version
+	return patched, nil
+}
+
+// PatchApplication represents the patch applications endpoint
+// @Summary Partially updates an existing application
+// @Description Updates only the fields of an application identified by a uuid contained in the body (a JSON merge patch, RFC 7396): omitted fields are kept and fields set to null are cleared
+// @Description If the body contains a version, the application is only updated if it still has this version, otherwise 409 is returned
+// @ID patch-application
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Access Token" default(Bearer <Add access token here>)
+// @Param application body object true "The fields of the application to change"
+// @Param uuid query string true "Identifier of the application to update"
+// @Success 200 {object} db.Application
+// @Failure 400 {object} ValidationError
+// @Failure 401 {object} Error
+// @Failure 404 {object} Error
+// @Failure 409 {object} Error
+// @Failure 422 {object} Error
+// @Failure 500 {object} Error
+// @Router /updateApplication [patch]
+func PatchApplication(con *gin.Context) {
+	auth, err := ExtractTokenMeta(con.Request)
+	if err != nil {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "you are not logged in")})
+		return
+	}
+	uuid := con.Query("uuid")
+	body, err := ioutil.ReadAll(con.Request.Body)
+	var patch map[string]interface{}
+	if err != nil || uuid == "" || json.Unmarshal(body, &patch) != nil || patch == nil {
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
+		return
+	}
+	var version struct {
+		Version *int `json:"version"`
+	}
+	if err := json.Unmarshal(body, &version); err != nil {
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
+		return
+	}
+	db := mongo.MongoDatabaseConnector{}
+	if !db.Connect() {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "database didn't respond")})
+		return
+	}
+	defer db.Close()
+	requestTeacher := db.GetTeacherByShort(auth.Username)
+	if !db.DoesApplicationExist(uuid) {
+		con.JSON(http.StatusNotFound, Error{localize(con, "application not found")})
+		return
+	}
+	application := db.GetApplication(uuid)
+	if application.DeletedAt != nil {
+		con.JSON(http.StatusNotFound, Error{localize(con, "application not found")})
+		return
+	}
+	if !(isParticipant(application, requestTeacher) || requestTeacher.Administration || requestTeacher.AV || requestTeacher.PEK || requestTeacher.SuperUser) {
+		con.JSON(http.StatusUnauthorized, Error{localize(con, "unauthorized")})
+		return
+	}
+	patched, err := patchApplication(application, patch)
+	if err != nil {
+		con.JSON(http.StatusUnprocessableEntity, Error{localize(con, "invalid request structure provided")})
+		return
+	}
+	var req CreateApplicationRequest
+	if err := remarshal(patched, &req); err != nil {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "error; application not updated")})
+		return
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		con.JSON(http.StatusBadRequest, validationError(con, err, req))
+		return
+	}
+	expected := application.Version
+	if version.Version != nil {
+		expected = *version.Version
+	}
+	updated, conflict := db.UpdateApplicationIfVersion(uuid, patched, expected)
+	if conflict {
+		con.JSON(http.StatusConflict, Error{localize(con, "application was changed in the meantime; reload it and apply the changes again")})
+		return
+	}
+	if !updated {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "error; application not updated")})
+		return
+	}
+	stored := db.GetApplication(uuid)
+	if stored.Progress != application.Progress {
+		applicationEvents.publish(ApplicationStatusChanged, stored)
+	} else {
+		applicationEvents.publish(ApplicationUpdated, stored)
+	}
+	recordAudit(con, auth.Username, AuditUpdateApplication, uuid, application, stored)
+	con.JSON(http.StatusOK, stored)
+}
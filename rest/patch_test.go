@@ -0,0 +1,153 @@
+package rest
+
+import (
+	"encoding/json"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// decodePatch decodes the json merge patch
+func decodePatch(t *testing.T, raw string) map[string]interface{} {
+	t.Helper()
+	var patch map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &patch); err != nil {
+		t.Fatal(err)
+	}
+	return patch
+}
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name                string
+		target, patch, want string
+	}{
+		{"a changed field", `{"a":1,"b":2}`, `{"a":3}`, `{"a":3,"b":2}`},
+		{"an added field", `{"a":1}`, `{"b":2}`, `{"a":1,"b":2}`},
+		{"a field set to null", `{"a":1,"b":2}`, `{"a":null}`, `{"b":2}`},
+		{"a nested field", `{"a":{"b":1,"c":2}}`, `{"a":{"b":3}}`, `{"a":{"b":3,"c":2}}`},
+		{"a nested field set to null", `{"a":{"b":1,"c":2}}`, `{"a":{"b":null}}`, `{"a":{"c":2}}`},
+		{"a replaced list", `{"a":[1,2]}`, `{"a":[3]}`, `{"a":[3]}`},
+		{"an empty patch", `{"a":1}`, `{}`, `{"a":1}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := mergePatch(decodePatch(t, test.target), decodePatch(t, test.patch))
+			if want := decodePatch(t, test.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("merged into %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestPatchApplicationChangesOnlyThePatchedFields(t *testing.T) {
+	start := time.Date(2021, time.March, 1, 8, 0, 0, 0, time.UTC)
+	application := mongo.Application{
+		UUID:               "3fcf7f67-e0ed-4339-99b4-a6765aaa3dc4",
+		Name:               "Dienstreise",
+		Kind:               mongo.OtherReason,
+		Progress:           1,
+		StartTime:          start,
+		EndTime:            start.Add(8 * time.Hour),
+		Notes:              "Zug um 7:12",
+		StartAddress:       "TGM, Wexstraße 19-23, 1200 Wien",
+		Version:            4,
+		OtherReasonDetails: mongo.OtherReasonDetails{Filer: "Michael Borko"},
+	}
+
+	renamed, err := patchApplication(application, decodePatch(t, `{"name":"Dienstreise nach Graz"}`))
+	if err != nil {
+		t.Fatalf("patching returned %v", err)
+	}
+	want := application
+	want.Name = "Dienstreise nach Graz"
+	if !reflect.DeepEqual(renamed, want) {
+		t.Errorf("patching the name resulted in %+v, want %+v", renamed, want)
+	}
+
+	cleared, err := patchApplication(application, decodePatch(t, `{"notes":null,"other_reason_details":{"filer":"Anna van der Hude"}}`))
+	if err != nil {
+		t.Fatalf("patching returned %v", err)
+	}
+	want = application
+	want.Notes = ""
+	want.OtherReasonDetails.Filer = "Anna van der Hude"
+	if !reflect.DeepEqual(cleared, want) {
+		t.Errorf("clearing the notes resulted in %+v, want %+v", cleared, want)
+	}
+
+	protected, err := patchApplication(application, decodePatch(t, `{"uuid":"other","version":9,"deleted_at":"2021-03-01T08:00:00Z"}`))
+	if err != nil {
+		t.Fatalf("patching returned %v", err)
+	}
+	if !reflect.DeepEqual(protected, application) {
+		t.Errorf("patching the protected fields resulted in %+v, want them to be kept", protected)
+	}
+
+	if _, err := patchApplication(application, decodePatch(t, `{"progress":"done"}`)); err == nil {
+		t.Error("patching a field with a value of another type returned no error")
+	}
+}
+
+func TestPatchApplicationRejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name, target, body string
+	}{
+		{"no uuid", "/updateApplication", `{"name":"Dienstreise"}`},
+		{"malformed json", "/updateApplication?uuid=1", `{"name":`},
+		{"a list", "/updateApplication?uuid=1", `["name"]`},
+		{"null", "/updateApplication?uuid=1", `null`},
+		{"an invalid version", "/updateApplication?uuid=1", `{"version":"one"}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			con, recorder := authorizedContext(t, "patcher", http.MethodPatch, test.target, test.body)
+			PatchApplication(con)
+			if recorder.Code != http.StatusUnprocessableEntity {
+				t.Errorf("patching responded with %d, want %d", recorder.Code, http.StatusUnprocessableEntity)
+			}
+		})
+	}
+	con, recorder := testContext(http.MethodPatch, "/updateApplication?uuid=1", `{"name":"Dienstreise"}`)
+	PatchApplication(con)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("patching without a login responded with %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestPatchApplicationPreservesTheOtherFields(t *testing.T) {
+	db := requireDatabase(t)
+	filer := storeTeacher(t, db, "patcher", Permissions{})
+	app := otherReason(filer.Longname)
+	app.Notes = "Zug um 7:12"
+	stored := storeApplication(t, db, app)
+	patch := func(body string) mongo.Application {
+		t.Helper()
+		con, recorder := authorizedContext(t, filer.Short, http.MethodPatch, "/updateApplication?uuid="+stored.UUID, body)
+		PatchApplication(con)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("patching %v responded with %d, want %d", body, recorder.Code, http.StatusOK)
+		}
+		patched := mongo.Application{}
+		decodeJSON(t, recorder, &patched)
+		return patched
+	}
+
+	renamed := patch(`{"name":"Dienstreise nach Graz"}`)
+	if renamed.Name != "Dienstreise nach Graz" || renamed.Version != stored.Version+1 {
+		t.Errorf("the patched application is %q in version %d, want the new name in version %d", renamed.Name, renamed.Version, stored.Version+1)
+	}
+	if renamed.Notes != stored.Notes || renamed.OtherReasonDetails != stored.OtherReasonDetails || !renamed.StartTime.Equal(stored.StartTime) {
+		t.Errorf("patching the name changed the other fields to %+v", renamed)
+	}
+
+	cleared := patch(`{"notes":null}`)
+	if cleared.Notes != "" || cleared.Name != renamed.Name {
+		t.Errorf("clearing the notes resulted in %q named %q, want no notes and the patched name", cleared.Notes, cleared.Name)
+	}
+	if stored := db.GetApplication(stored.UUID); stored.Notes != "" || stored.Name != renamed.Name {
+		t.Errorf("the stored application has the notes %q and the name %q, want the patches applied", stored.Notes, stored.Name)
+	}
+}
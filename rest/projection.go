@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"net/http"
+	"strings"
+)
+
+// applicationFields are the json names of the fields of an application, which may be selected by the fields query parameter
+var applicationFields = map[string]bool{
+	"uuid":                       true,
+	"name":                       true,
+	"kind":                       true,
+	"miscellaneous_reason":       true,
+	"progress":                   true,
+	"start_time":                 true,
+	"end_time":                   true,
+	"notes":                      true,
+	"start_address":              true,
+	"destination_address":        true,
+	"last_changed":               true,
+	"deleted_at":                 true,
+	"version":                    true,
+	"school_event_details":       true,
+	"training_details":           true,
+	"other_reason_details":       true,
+	"business_trip_applications": true,
+	"travel_invoices":            true,
+}
+
+// parseFields reads the comma separated fields query parameter of a request
+// unknown fields are ignored, nil is returned if it contains no known field, so whole applications are returned
+func parseFields(con *gin.Context) []string {
+	fields := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(con.Query("fields"), ",") {
+		field = strings.TrimSpace(field)
+		if applicationFields[field] && !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// projectApplications returns the applications reduced to the fields, they are returned unchanged if fields is nil
+func projectApplications(applications []mongo.Application, fields []string) (interface{}, error) {
+	if fields == nil {
+		return applications, nil
+	}
+	projected := make([]map[string]json.RawMessage, 0, len(applications))
+	for _, application := range applications {
+		encoded, err := json.Marshal(application)
+		if err != nil {
+			return nil, err
+		}
+		all := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(encoded, &all); err != nil {
+			return nil, err
+		}
+		selected := make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, ok := all[field]; ok {
+				selected[field] = value
+			}
+		}
+		projected = append(projected, selected)
+	}
+	return projected, nil
+}
+
+// projectApplicationPage returns the page with its applications reduced to the fields like projectApplications
+func projectApplicationPage(page ApplicationPage, fields []string) (interface{}, error) {
+	if fields == nil {
+		return page, nil
+	}
+	items, err := projectApplications(page.Items, fields)
+	if err != nil {
+		return nil, err
+	}
+	return ProjectedApplicationPage{Items: items, Total: page.Total, NextOffset: page.NextOffset}, nil
+}
+
+// respondApplications responds with the applications reduced to the fields
+func respondApplications(con *gin.Context, applications []mongo.Application, fields []string) {
+	projected, err := projectApplications(applications, fields)
+	if err != nil {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't select the requested fields")})
+		return
+	}
+	con.JSON(http.StatusOK, projected)
+}
+
+// respondApplicationPage responds with the page with its applications reduced to the fields
+func respondApplicationPage(con *gin.Context, page ApplicationPage, fields []string) {
+	projected, err := projectApplicationPage(page, fields)
+	if err != nil {
+		con.JSON(http.StatusInternalServerError, Error{localize(con, "couldn't select the requested fields")})
+		return
+	}
+	con.JSON(http.StatusOK, projected)
+}
@@ -0,0 +1,118 @@
+package rest
+
+import (
+	"encoding/json"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// keysOf returns the sorted keys of the json objects in the body of the response
+func keysOf(t *testing.T, recorder *httptest.ResponseRecorder, path func(json.RawMessage) json.RawMessage) [][]string {
+	t.Helper()
+	var body json.RawMessage
+	decodeJSON(t, recorder, &body)
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(path(body), &objects); err != nil {
+		t.Fatalf("the response %s contains no list of objects: %v", body, err)
+	}
+	keys := make([][]string, 0, len(objects))
+	for _, object := range objects {
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		keys = append(keys, names)
+	}
+	return keys
+}
+
+func TestParseFields(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", nil},
+		{"fields=", nil},
+		{"fields=uuid,kind,progress,start_time", []string{"uuid", "kind", "progress", "start_time"}},
+		{"fields=+uuid+,+name", []string{"uuid", "name"}},
+		{"fields=uuid,password,uuid,../notes", []string{"uuid"}},
+		{"fields=password,UUID", nil},
+	}
+	for _, test := range tests {
+		con, _ := testContext(http.MethodGet, "/getActiveApplications?"+test.query, "")
+		if got := parseFields(con); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %v, want %v", test.query, got, test.want)
+		}
+	}
+}
+
+func TestOnlyTheRequestedFieldsAreReturned(t *testing.T) {
+	applications := applicationsNamed(2)
+	con, recorder := testContext(http.MethodGet, "/getActiveApplications", "")
+	respondApplications(con, applications, []string{"uuid", "name"})
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d", recorder.Code, http.StatusOK)
+	}
+	whole := func(body json.RawMessage) json.RawMessage { return body }
+	for i, keys := range keysOf(t, recorder, whole) {
+		if !reflect.DeepEqual(keys, []string{"name", "uuid"}) {
+			t.Errorf("application %d has the fields %v, want only name and uuid", i, keys)
+		}
+	}
+	projected := make([]mongo.Application, 0)
+	decodeJSON(t, recorder, &projected)
+	if len(projected) != 2 || projected[1].UUID != "1" || projected[1].Name != "application 1" {
+		t.Errorf("got %+v, want the uuids and names of the applications", projected)
+	}
+
+	con, recorder = testContext(http.MethodGet, "/getActiveApplications", "")
+	respondApplications(con, applications, nil)
+	unprojected := make([]mongo.Application, 0)
+	decodeJSON(t, recorder, &unprojected)
+	if !reflect.DeepEqual(unprojected, applications) {
+		t.Errorf("got %+v without fields, want the whole applications", unprojected)
+	}
+}
+
+func TestPagesOnlyContainTheRequestedFields(t *testing.T) {
+	con, recorder := testContext(http.MethodGet, "/getAllApplications", "")
+	respondApplicationPage(con, paginateApplications(applicationsNamed(3), 1, 1), []string{"progress"})
+	page := struct {
+		Total      int `json:"total"`
+		NextOffset int `json:"next_offset"`
+	}{}
+	decodeJSON(t, recorder, &page)
+	if page.Total != 3 || page.NextOffset != 2 {
+		t.Errorf("the page has the total %d and next offset %d, want 3 and 2", page.Total, page.NextOffset)
+	}
+	items := func(body json.RawMessage) json.RawMessage {
+		var page struct {
+			Items json.RawMessage `json:"items"`
+		}
+		_ = json.Unmarshal(body, &page)
+		return page.Items
+	}
+	if keys := keysOf(t, recorder, items); len(keys) != 1 || !reflect.DeepEqual(keys[0], []string{"progress"}) {
+		t.Errorf("the items have the fields %v, want one item with only the progress", keys)
+	}
+}
+
+func TestGetActiveApplicationsProjectsTheFields(t *testing.T) {
+	db := requireDatabase(t)
+	filer := storeTeacher(t, db, "projected", Permissions{})
+	storeApplication(t, db, otherReason(filer.Longname))
+	con, recorder := authorizedContext(t, filer.Short, http.MethodGet, "/getActiveApplications?username="+filer.Short+"&fields=uuid,kind,unknown", "")
+	GetActiveApplications(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d", recorder.Code, http.StatusOK)
+	}
+	keys := keysOf(t, recorder, func(body json.RawMessage) json.RawMessage { return body })
+	if len(keys) != 1 || !reflect.DeepEqual(keys[0], []string{"kind", "uuid"}) {
+		t.Errorf("the applications have the fields %v, want only kind and uuid", keys)
+	}
+}
@@ -0,0 +1,127 @@
+package rest
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxUserLoginFailures is the amount of failed logins of a username before it is locked out
+	MaxUserLoginFailures = 5
+	// MaxIPLoginFailures is the amount of failed logins from an ip before it is locked out, it is higher than
+	// MaxUserLoginFailures as many teachers might share the ip of the school network
+	MaxIPLoginFailures = 20
+	// LoginLockout is the duration of the first lockout, every further failure doubles it
+	LoginLockout = 30 * time.Second
+	// MaxLoginLockout is the longest duration a lockout can last
+	MaxLoginLockout = 15 * time.Minute
+	// LoginFailureWindow is the duration after which the failures of a key without a new failure are forgotten
+	LoginFailureWindow = time.Hour
+)
+
+// loginAttempts counts the failed logins of a username or an ip
+type loginAttempts struct {
+	// failures is the amount of failed logins since the last successful one
+	failures int
+	// last is the time of the last failed login
+	last time.Time
+	// lockedUntil is the time until which no further logins are accepted
+	lockedUntil time.Time
+}
+
+// loginLimiter throttles logins per username and per ip with an exponentially growing lockout after repeated failures
+type loginLimiter struct {
+	// mutex guards attempts
+	mutex sync.Mutex
+	// attempts are the failed logins of every username and ip, keyed by userKey and ipKey
+	attempts map[string]*loginAttempts
+}
+
+// logins is the limiter the Login endpoint uses
+var logins = newLoginLimiter()
+
+// newLoginLimiter creates a limiter without any recorded failures
+func newLoginLimiter() *loginLimiter {
+	return &loginLimiter{attempts: make(map[string]*loginAttempts)}
+}
+
+// userKey is the key the failures of the username are recorded under, usernames are case insensitive
+func userKey(username string) string {
+	return "user:" + strings.ToLower(strings.TrimSpace(username))
+}
+
+// ipKey is the key the failures of the ip are recorded under
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// retryAfter returns how long logins of the username from the ip are locked out, zero if they are allowed
+func (l *loginLimiter) retryAfter(username, ip string) time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := time.Now()
+	wait := time.Duration(0)
+	for _, key := range []string{userKey(username), ipKey(ip)} {
+		attempts, ok := l.attempts[key]
+		if !ok {
+			continue
+		}
+		if now.Sub(attempts.last) > LoginFailureWindow && !now.Before(attempts.lockedUntil) {
+			delete(l.attempts, key)
+			continue
+		}
+		if remaining := attempts.lockedUntil.Sub(now); remaining > wait {
+			wait = remaining
+		}
+	}
+	return wait
+}
+
+// fail records a failed login of the username from the ip and locks them out once they reached their limit
+func (l *loginLimiter) fail(username, ip string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := time.Now()
+	l.prune(now)
+	l.record(userKey(username), MaxUserLoginFailures, now)
+	l.record(ipKey(ip), MaxIPLoginFailures, now)
+}
+
+// record adds a failure to the key and locks it out for LoginLockout doubled for every failure above limit
+func (l *loginLimiter) record(key string, limit int, now time.Time) {
+	attempts, ok := l.attempts[key]
+	if !ok || now.Sub(attempts.last) > LoginFailureWindow {
+		attempts = &loginAttempts{}
+		l.attempts[key] = attempts
+	}
+	attempts.failures++
+	attempts.last = now
+	if attempts.failures < limit {
+		return
+	}
+	lockout := MaxLoginLockout
+	if exponent := attempts.failures - limit; exponent < 32 {
+		lockout = time.Duration(math.Min(float64(LoginLockout)*math.Pow(2, float64(exponent)), float64(MaxLoginLockout)))
+	}
+	attempts.lockedUntil = now.Add(lockout)
+}
+
+// prune forgets all keys whose failures are outside of LoginFailureWindow and which aren't locked out anymore,
+// so usernames tried once don't pile up
+func (l *loginLimiter) prune(now time.Time) {
+	for key, attempts := range l.attempts {
+		if now.Sub(attempts.last) > LoginFailureWindow && !now.Before(attempts.lockedUntil) {
+			delete(l.attempts, key)
+		}
+	}
+}
+
+// succeed resets the failures of the username and the ip after a successful login
+func (l *loginLimiter) succeed(username, ip string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.attempts, userKey(username))
+	delete(l.attempts, ipKey(ip))
+}
@@ -0,0 +1,62 @@
+package rest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoginLockoutsGrowExponentially(t *testing.T) {
+	limiter := newLoginLimiter()
+	for i := 1; i < MaxUserLoginFailures; i++ {
+		limiter.fail("szakall", "10.0.0.1")
+		if wait := limiter.retryAfter("szakall", "10.0.0.1"); wait != 0 {
+			t.Fatalf("%d failures are locked out for %v, want no lockout below %d", i, wait, MaxUserLoginFailures)
+		}
+	}
+	want := LoginLockout
+	for i := 0; i < 8; i++ {
+		limiter.fail("szakall", "10.0.0.1")
+		wait := limiter.retryAfter("Szakall ", "10.0.0.2")
+		if wait > want || wait < want-time.Second {
+			t.Errorf("failure %d is locked out for %v, want %v", MaxUserLoginFailures+i, wait, want)
+		}
+		want *= 2
+		if want > MaxLoginLockout {
+			want = MaxLoginLockout
+		}
+	}
+	limiter.succeed("szakall", "10.0.0.1")
+	if wait := limiter.retryAfter("szakall", "10.0.0.1"); wait != 0 {
+		t.Errorf("a successful login is still locked out for %v", wait)
+	}
+}
+
+func TestLoginsFromAnIPAreLockedOut(t *testing.T) {
+	limiter := newLoginLimiter()
+	for i := 0; i < MaxIPLoginFailures; i++ {
+		limiter.fail("teacher"+string(rune('a'+i)), "10.0.0.1")
+	}
+	if wait := limiter.retryAfter("borko", "10.0.0.1"); wait <= 0 {
+		t.Error("a new username from an ip with too many failures isn't locked out")
+	}
+	if wait := limiter.retryAfter("borko", "10.0.0.2"); wait != 0 {
+		t.Errorf("a login from another ip is locked out for %v", wait)
+	}
+}
+
+func TestOldLoginFailuresAreForgotten(t *testing.T) {
+	limiter := newLoginLimiter()
+	for i := 1; i < MaxUserLoginFailures; i++ {
+		limiter.fail("szakall", "10.0.0.1")
+	}
+	for _, attempts := range limiter.attempts {
+		attempts.last = attempts.last.Add(-LoginFailureWindow - time.Minute)
+	}
+	limiter.fail("szakall", "10.0.0.1")
+	if wait := limiter.retryAfter("szakall", "10.0.0.1"); wait != 0 {
+		t.Errorf("a failure after the window of the previous ones is locked out for %v", wait)
+	}
+	if failures := limiter.attempts[userKey("szakall")].failures; failures != 1 {
+		t.Errorf("%d failures are counted, want only the new one", failures)
+	}
+}
@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/refundable-tgm/huginn/untis"
+	"net/http"
+	"time"
+)
+
+// RequestIDHeader is the header the id of a request is read from and echoed back in
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the key the id of a request is stored under in the gin context
+const requestIDKey = "request_id"
+
+// maxRequestIDLength is the maximum length of a request id provided by a client, longer ids are replaced
+const maxRequestIDLength = 128
+
+// RequestID assigns every request an id, which is either the valid id provided in RequestIDHeader or a new uuid
+// the id is stored in the gin context and the context of the request (to tag calls to the untis api)
+// and is echoed back in RequestIDHeader
+func RequestID() gin.HandlerFunc {
+	return func(con *gin.Context) {
+		id := con.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		con.Set(requestIDKey, id)
+		con.Request = con.Request.WithContext(untis.ContextWithRequestID(con.Request.Context(), id))
+		con.Header(RequestIDHeader, id)
+		con.Next()
+	}
+}
+
+// GetRequestID returns the id assigned to the request by the RequestID middleware
+func GetRequestID(con *gin.Context) string {
+	return con.GetString(requestIDKey)
+}
+
+// validRequestID checks whether a request id provided by a client is not empty, not too long and only printable ascii
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// requestLogFormatter formats the log line of a request as key value pairs tagged with the request id
+// the level is error for server errors, warn for client errors and info otherwise
+func requestLogFormatter(param gin.LogFormatterParams) string {
+	level := "info"
+	if param.StatusCode >= http.StatusInternalServerError {
+		level = "error"
+	} else if param.StatusCode >= http.StatusBadRequest {
+		level = "warn"
+	}
+	requestID, _ := param.Keys[requestIDKey].(string)
+	return fmt.Sprintf("time=%v level=%v request_id=%v method=%v path=%q status=%d latency=%v client_ip=%v\n",
+		param.TimeStamp.Format(time.RFC3339), level, requestID, param.Method, param.Path, param.StatusCode,
+		param.Latency, param.ClientIP)
+}
@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/refundable-tgm/huginn/untis"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serveRequestID sends req through the RequestID middleware and returns the response together with the request
+// ids the handler found in the gin context and in the context of the request
+func serveRequestID(req *http.Request) (recorder *httptest.ResponseRecorder, stored, tagged string) {
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/", func(con *gin.Context) {
+		stored = GetRequestID(con)
+		tagged = untis.RequestIDFromContext(con.Request.Context())
+		con.Status(http.StatusNoContent)
+	})
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder, stored, tagged
+}
+
+func TestRequestIDIsAssignedToEveryRequest(t *testing.T) {
+	recorder, stored, tagged := serveRequestID(httptest.NewRequest(http.MethodGet, "/", nil))
+	id := recorder.Header().Get(RequestIDHeader)
+	if _, err := uuid.Parse(id); err != nil {
+		t.Fatalf("the response carries the request id %q, want a uuid", id)
+	}
+	if stored != id || tagged != id {
+		t.Errorf("the handler found the request ids %q and %q, want the echoed %q", stored, tagged, id)
+	}
+	second, _, _ := serveRequestID(httptest.NewRequest(http.MethodGet, "/", nil))
+	if second.Header().Get(RequestIDHeader) == id {
+		t.Error("two requests were assigned the same id")
+	}
+}
+
+func TestInboundRequestIDsAreHonored(t *testing.T) {
+	tests := []struct {
+		name    string
+		inbound string
+		honored bool
+	}{
+		{"a uuid", "3fcf7f67-e0ed-4339-99b4-a6765aaa3dc4", true},
+		{"an id of another service", "frontend:42/7", true},
+		{"an id with spaces", "two words", false},
+		{"an id with a line break", "id\r\nSet-Cookie: x", false},
+		{"a too long id", strings.Repeat("a", maxRequestIDLength+1), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(RequestIDHeader, test.inbound)
+			recorder, stored, _ := serveRequestID(req)
+			id := recorder.Header().Get(RequestIDHeader)
+			if test.honored && id != test.inbound {
+				t.Errorf("the response carries the request id %q, want the inbound %q", id, test.inbound)
+			}
+			if !test.honored {
+				if _, err := uuid.Parse(id); err != nil {
+					t.Errorf("the response carries the request id %q, want a new uuid", id)
+				}
+			}
+			if stored != id {
+				t.Errorf("the handler found the request id %q, want %q", stored, id)
+			}
+		})
+	}
+}
+
+func TestRequestLogLinesAreTaggedWithTheRequestID(t *testing.T) {
+	tests := []struct {
+		status int
+		level  string
+	}{
+		{http.StatusOK, "level=info"},
+		{http.StatusNotFound, "level=warn"},
+		{http.StatusBadGateway, "level=error"},
+	}
+	for _, test := range tests {
+		line := requestLogFormatter(gin.LogFormatterParams{
+			TimeStamp:  time.Date(2021, time.March, 1, 8, 0, 0, 0, time.UTC),
+			StatusCode: test.status,
+			Method:     http.MethodGet,
+			Path:       "/api/getTeacherByShort",
+			Keys:       map[string]interface{}{requestIDKey: "frontend-42"},
+		})
+		if !strings.Contains(line, "request_id=frontend-42 ") {
+			t.Errorf("the log line %q isn't tagged with the request id", line)
+		}
+		if !strings.Contains(line, test.level) {
+			t.Errorf("the log line %q of status %d doesn't have %v", line, test.status, test.level)
+		}
+	}
+}
@@ -1,25 +1,64 @@
 package rest
 
 import (
+	"context"
+	"fmt"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	// import to make swagger docs accessible
 	_ "github.com/refundable-tgm/huginn/docs"
+	"github.com/refundable-tgm/huginn/files"
+	"github.com/refundable-tgm/huginn/untis"
 	ginSwagger "github.com/swaggo/gin-swagger"   // gin swagger middleware
 	"github.com/swaggo/gin-swagger/swaggerFiles" // swagger files
 	"log"
+	"net"
 	"net/http"
 	"os"
-	"strconv"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 )
 
-// Port is the port this api will listen to
+// Port is the port this api listens on if no address is configured
 const Port = 8080
 
+// AddressEnv is the environment variable the address this api listens on can be specified with (e.g. 127.0.0.1:8080)
+const AddressEnv = "HUGINN_ADDRESS"
+
+// DateFormat is the format dates are expected in when provided as query parameters
+const DateFormat = "2006-01-02"
+
+// CORSOriginsEnv is the environment variable a comma separated list of origins allowed to access this api can be
+// specified with (e.g. https://refundable.tgm.ac.at), all origins are allowed if it is empty
+const CORSOriginsEnv = "HUGINN_CORS_ORIGINS"
+
+// DefaultSearchLimit is the amount of results a search returns if no limit is provided
+const DefaultSearchLimit = 10
+
+// MaxSearchLimit is the maximum amount of results a search returns, bigger limits are clamped to it
+const MaxSearchLimit = 50
+
+// ShutdownTimeout is the time in-flight requests are given to finish when the service shuts down
+const ShutdownTimeout = 10 * time.Second
+
+// SessionTTLEnv is the environment variable the time an untis client may stay unused before it is evicted can be
+// specified with (e.g. 12h), the lifetime of refresh tokens is used if it is empty
+const SessionTTLEnv = "HUGINN_UNTIS_SESSION_TTL"
+
+// ReceiptsDirEnv is the environment variable the directory uploaded receipts are stored in can be specified with
+// (e.g. /vol/receipts), files.DefaultReceiptsPath is used if it is empty
+const ReceiptsDirEnv = "HUGINN_RECEIPTS_DIR"
+
+// JanitorInterval is the interval in which idle untis clients are evicted
+const JanitorInterval = time.Minute
+
 // DebugFilePath to where a .debug file lies
 const DebugFilePath = "/vol/files/.debug"
 
-// StartService starts the rest service
+// StartService starts the rest service with the configuration cfg and blocks until it is shut down
+// it returns an error if the configuration is invalid or the service couldn't be started
 // @title Refundable
 // @version 1.1
 // @description This REST-API provides the backend of Refundable
@@ -34,26 +73,76 @@ const DebugFilePath = "/vol/files/.debug"
 // @host localhost:8080
 // @BasePath /api
 // @query.collection.format multi
-func StartService() {
+func StartService(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	startTime = time.Now()
+
 	// initializing Token Manager
 	InitTokenManager()
 
 	// Setting Mode of API
-	if debugMode() {
-		gin.SetMode(gin.DebugMode)
-	} else {
-		gin.SetMode(gin.ReleaseMode)
+	gin.SetMode(cfg.mode())
+
+	// All teachers share the same school, so they can share its master data as well
+	untis.Server = cfg.UntisServer
+	untis.School = cfg.UntisSchool
+	untis.SharedCache = true
+
+	// Sending requests to untis through the configured proxy, trusting the configured root CAs
+	if err := cfg.untisTransport(); err != nil {
+		return err
+	}
+
+	// Evicting untis clients of users who weren't active for a long time
+	ttl, err := sessionTTL(cfg.SessionTTL)
+	if err != nil {
+		return err
+	}
+	stopJanitor := untis.StartJanitor(ttl, JanitorInterval)
+	defer stopJanitor()
+
+	// Storing uploaded receipts in the configured directory
+	files.ReceiptsPath = cfg.ReceiptsDir
+
+	// Limiting the size of request bodies
+	MaxBodySize = cfg.MaxBodySize
+	if MaxBodySize == 0 {
+		MaxBodySize = DefaultMaxBodySize
 	}
 
 	// Creating new Router
-	router := gin.Default()
+	router, err := newRouter(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Starting
+	listener, err := net.Listen("tcp", cfg.Address)
+	if err != nil {
+		return err
+	}
+	log.Printf("listening on %v", listener.Addr())
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(quit)
+	return serve(listener, router, quit)
+}
+
+// newRouter creates the router providing all routes of the api with the middlewares configured by cfg
+func newRouter(cfg Config) (*gin.Engine, error) {
+	router := gin.New()
+	registerMetrics()
+	router.Use(RequestID(), gin.LoggerWithFormatter(requestLogFormatter), gin.Recovery(), Metrics(), Gzip(), BodyLimit())
 
 	// Handling CORS Requests
-	config := cors.DefaultConfig()
-	config.AllowAllOrigins = true
-	config.AllowCredentials = true
-	config.AddAllowHeaders("Authorization")
-	router.Use(cors.New(config))
+	corsCfg := corsConfig(cfg.CORSOrigins)
+	if err := corsCfg.Validate(); err != nil {
+		return nil, err
+	}
+	router.Use(cors.New(corsCfg))
+	upgrader = newUpgrader(corsCfg)
 
 	// Registering routes under API Group
 	api := router.Group("/api")
@@ -65,28 +154,70 @@ func StartService() {
 		api.GET("/getTeacher", AuthWall(), GetTeacher)
 		api.GET("/getTeacherByUntis", AuthWall(), GetTeacherByUntis)
 		api.POST("/setTeacherPermissions", AuthWall(), SetTeacherPermissions)
+		api.POST("/updateTeacherPermissions", AuthWall(), UpdateTeacherPermissions)
 		api.PUT("/updateTeacherInformation", AuthWall(), UpdateTeacherInformation)
 		api.GET("/getActiveApplications", AuthWall(), GetActiveApplications)
-		api.GET("/getAllApplications", AuthWall(), GetAllApplications)
+		api.GET("/getAllApplications", AuthWall(), ETag(), GetAllApplications)
 		api.GET("/getNews", AuthWall(), GetNews)
 		api.GET("/getAdminApplications", AuthWall(), GetAdminApplications)
-		api.GET("/getApplication", AuthWall(), GetApplication)
+		api.GET("/getApplication", AuthWall(), ETag(), GetApplication)
+		api.POST("/getApplications", AuthWall(), GetApplications)
 		api.POST("/createApplication", AuthWall(), CreateApplication)
+		api.POST("/validateApplication", AuthWall(), ValidateApplication)
 		api.PUT("/updateApplication", AuthWall(), UpdateApplication)
+		api.PATCH("/updateApplication", AuthWall(), PatchApplication)
+		api.POST("/bulkUpdateApplicationStatus", AuthWall(), BulkUpdateApplicationStatus)
 		api.DELETE("/deleteApplication", AuthWall(), DeleteApplication)
-		api.GET("/getAbsenceFormForClasses", AuthWall(), GetAbsenceFormForClasses)
-		api.GET("/getAbsenceFormForTeacher", AuthWall(), GetAbsenceFormForTeacher)
-		api.GET("/getCompensationForEducationalSupportForm", AuthWall(), GetCompensationForEducationalSupportForm)
-		api.GET("/getTravelInvoiceForm", AuthWall(), GetTravelInvoiceForm)
-		api.GET("/getBusinessTripApplicationForm", AuthWall(), GetBusinessTripApplicationForm)
-		api.GET("/getTravelInvoiceExcel", AuthWall(), GetTravelInvoiceExcel)
-		api.GET("/getBusinessTripApplicationExcel", AuthWall(), GetBusinessTripApplicationExcel)
+		api.POST("/restoreApplication", AuthWall(), RestoreApplication)
+		api.GET("/getAbsenceFormForClasses", AuthWall(), ETag(), GetAbsenceFormForClasses)
+		api.GET("/getAbsenceFormForTeacher", AuthWall(), ETag(), GetAbsenceFormForTeacher)
+		api.GET("/getCompensationForEducationalSupportForm", AuthWall(), ETag(), GetCompensationForEducationalSupportForm)
+		api.GET("/getTravelInvoice", AuthWall(), ETag(), GetTravelInvoice)
+		api.GET("/getTravelInvoiceForm", AuthWall(), ETag(), GetTravelInvoiceForm)
+		api.GET("/getTravelInvoicePDF", AuthWall(), ETag(), GetTravelInvoicePDF)
+		api.GET("/getBusinessTripApplicationForm", AuthWall(), ETag(), GetBusinessTripApplicationForm)
+		api.GET("/getTravelInvoiceExcel", AuthWall(), ETag(), GetTravelInvoiceExcel)
+		api.GET("/getBusinessTripApplicationExcel", AuthWall(), ETag(), GetBusinessTripApplicationExcel)
 		api.POST("/saveBillingReceipt", AuthWall(), SaveBillingReceipt)
+		api.GET("/getTimetableICal", AuthWall(), GetTimetableICal)
+		api.GET("/getMyTimetable", AuthWall(), GetMyTimetable)
+		api.GET("/getTimetableCSV", AuthWall(), GetTimetableCSV)
+		api.GET("/getRoomTimetable", AuthWall(), GetRoomTimetable)
+		api.GET("/getCurrentLesson", AuthWall(), GetCurrentLesson)
+		api.GET("/getSubstitutions", AuthWall(), GetSubstitutions)
+		api.GET("/getExams", AuthWall(), GetExams)
+		api.POST("/getTimetablesForTeachers", AuthWall(), GetTimetablesForTeachers)
+		api.GET("/ws/applications", AuthWall(), ApplicationsWebSocket)
+		api.GET("/auditLog", AuthWall(), GetAuditLog)
+		api.GET("/getHolidays", AuthWall(), GetHolidays)
+		api.GET("/searchTeachers", AuthWall(), SearchTeachers)
+		api.GET("/getTeachers", AuthWall(), GetTeachers)
+		api.GET("/getClasses", AuthWall(), GetClasses)
+		api.GET("/getClassTeachers", AuthWall(), GetClassTeachers)
+		api.GET("/getRooms", AuthWall(), GetRooms)
+		api.GET("/getMe", AuthWall(), GetMe)
+		api.GET("/exportMyApplications", AuthWall(), ExportMyApplications)
+		api.GET("/exportApplication", AuthWall(), ExportApplication)
+		api.POST("/importApplication", AuthWall(), ImportApplication)
 	}
 
-	// Not Found Route
+	// Health Checks
+	router.GET("/healthz", Healthz)
+	router.GET("/readyz", Readyz)
+
+	// Build Information
+	router.GET("/version", GetVersion)
+
+	// Metrics
+	router.GET("/metrics", MetricsHandler())
+
+	// Not Found and Method Not Allowed Routes
+	router.HandleMethodNotAllowed = true
 	router.NoRoute(func(context *gin.Context) {
-		context.JSON(http.StatusNotFound, Error{"this endpoint doesn't exist"})
+		context.JSON(http.StatusNotFound, RouteError{localize(context, "endpoint not found"), context.Request.URL.Path})
+	})
+	router.NoMethod(func(context *gin.Context) {
+		context.JSON(http.StatusMethodNotAllowed, RouteError{localize(context, "method not allowed"), context.Request.URL.Path})
 	})
 
 	// Providing API
@@ -94,9 +225,69 @@ func StartService() {
 	router.GET("/", func(context *gin.Context) {
 		context.Redirect(http.StatusMovedPermanently, "swagger/index.html")
 	})
+	return router, nil
+}
 
-	// Starting
-	log.Fatal(router.Run(":" + strconv.Itoa(Port)))
+// serve provides the handler on the listener until a signal is received on quit, then it shuts down gracefully:
+// in-flight requests are given ShutdownTimeout to finish and the sessions of all untis clients are closed
+func serve(listener net.Listener, handler http.Handler, quit <-chan os.Signal) error {
+	server := &http.Server{
+		Handler: handler,
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Serve(listener)
+	}()
+	select {
+	case err := <-serverErr:
+		return err
+	case <-quit:
+	}
+
+	// Shutting down gracefully
+	log.Println("shutting down")
+	ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	shutdownErr := server.Shutdown(ctx)
+	if err := untis.CloseAllClients(ctx); err != nil {
+		log.Printf("couldn't close all untis sessions: %v", err)
+	}
+	return shutdownErr
+}
+
+// corsConfig creates the CORS configuration allowing the comma separated list of origins
+// if origins is empty, all origins are allowed
+func corsConfig(origins string) cors.Config {
+	config := cors.DefaultConfig()
+	config.AllowCredentials = true
+	config.AddAllowHeaders("Authorization", RequestIDHeader)
+	config.AddExposeHeaders(RequestIDHeader)
+	allowed := make([]string, 0)
+	for _, origin := range strings.Split(origins, ",") {
+		origin = strings.TrimSuffix(strings.TrimSpace(origin), "/")
+		if origin != "" {
+			allowed = append(allowed, origin)
+		}
+	}
+	if len(allowed) == 0 {
+		config.AllowAllOrigins = true
+	} else {
+		config.AllowOrigins = allowed
+	}
+	return config
+}
+
+// sessionTTL parses the time an untis client may stay unused, the lifetime of refresh tokens if ttl is empty,
+// shorter durations would evict clients of users still logged in
+func sessionTTL(ttl string) (time.Duration, error) {
+	if ttl == "" {
+		return refreshDuration, nil
+	}
+	duration, err := time.ParseDuration(ttl)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid %v provided: %q", SessionTTLEnv, ttl)
+	}
+	return duration, nil
 }
 
 // setDebugMode analyzes whether a .debug File is present (DebugFilePath)
@@ -0,0 +1,198 @@
+package rest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServeShutsDownGracefullyOnSignal(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte("finished"))
+	})
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt)
+	defer signal.Stop(quit)
+	served := make(chan error, 1)
+	go func() {
+		served <- serve(listener, handler, quit)
+	}()
+
+	// a request in flight while the signal is received is still answered
+	response := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err != nil {
+			response <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		response <- string(body)
+	}()
+	<-started
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := process.Signal(os.Interrupt); err != nil {
+		t.Skipf("can't send signals on this platform: %v", err)
+	}
+
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Fatalf("the server didn't shut down cleanly: %v", err)
+		}
+	case <-time.After(ShutdownTimeout):
+		t.Fatalf("the server didn't stop within %v", ShutdownTimeout)
+	}
+	if body := <-response; body != "finished" {
+		t.Errorf("the in-flight request got %q, want it to finish", body)
+	}
+	if _, err := http.Get("http://" + listener.Addr().String()); err == nil {
+		t.Error("the server still accepts requests after shutting down")
+	}
+}
+
+// startTestServer serves the router of the default configuration on an ephemeral port of the loopback interface
+// and returns its base url, the server is shut down when the test finishes
+func startTestServer(t *testing.T) string {
+	t.Helper()
+	router, err := newRouter(DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	quit := make(chan os.Signal, 1)
+	served := make(chan error, 1)
+	go func() {
+		served <- serve(listener, router, quit)
+	}()
+	t.Cleanup(func() {
+		quit <- os.Interrupt
+		<-served
+	})
+	return "http://" + listener.Addr().String()
+}
+
+func TestServiceListensOnAnEphemeralPort(t *testing.T) {
+	base := startTestServer(t)
+	_, port, err := net.SplitHostPort(strings.TrimPrefix(base, "http://"))
+	if err != nil || port == "0" {
+		t.Fatalf("no port was assigned to %v", base)
+	}
+	resp, err := http.Get(base + "/unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var body RouteError
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("the NoRoute handler didn't respond with json: %v", err)
+	}
+	if resp.StatusCode != http.StatusNotFound || body.Path != "/unknown" {
+		t.Errorf("got %d %+v, want 404 for the path", resp.StatusCode, body)
+	}
+}
+
+// serveRouter sends the request to the router of the default configuration and returns the recorded response
+func serveRouter(t *testing.T, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	return serveRouterWith(t, DefaultConfig(), req)
+}
+
+// serveRouterWith is like serveRouter but uses the router of cfg
+func serveRouterWith(t *testing.T, cfg Config, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	router, err := newRouter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestUnknownRoutesRespondWithJSONErrors(t *testing.T) {
+	tests := []struct {
+		method, path string
+		status       int
+		message      string
+	}{
+		{http.MethodGet, "/api/doesNotExist", http.StatusNotFound, "endpoint not found"},
+		{http.MethodGet, "/api/login", http.StatusMethodNotAllowed, "method not allowed"},
+		{http.MethodDelete, "/healthz", http.StatusMethodNotAllowed, "method not allowed"},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.path, nil)
+		req.Header.Set("Accept-Language", "en")
+		recorder := serveRouter(t, req)
+		var body RouteError
+		if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+			t.Errorf("%v %v: the body %q isn't valid json: %v", test.method, test.path, recorder.Body.String(), err)
+			continue
+		}
+		if recorder.Code != test.status || body.Message != test.message || body.Path != test.path {
+			t.Errorf("%v %v: got %d %+v, want %d %q", test.method, test.path, recorder.Code, body, test.status, test.message)
+		}
+		if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/json") {
+			t.Errorf("%v %v: got content type %q, want json", test.method, test.path, contentType)
+		}
+	}
+}
+
+func TestCORSAllowsOnlyConfiguredOrigins(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CORSOrigins = "https://refundable.tgm.ac.at, https://staging.refundable.tgm.ac.at/"
+	request := func(method, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/healthz", nil)
+		req.Header.Set("Origin", origin)
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			req.Header.Set("Access-Control-Request-Headers", "Authorization")
+		}
+		return serveRouterWith(t, cfg, req)
+	}
+
+	allowed := request(http.MethodGet, "https://staging.refundable.tgm.ac.at")
+	if allowed.Code != http.StatusOK || allowed.Header().Get("Access-Control-Allow-Origin") != "https://staging.refundable.tgm.ac.at" {
+		t.Errorf("got %d with allowed origin %q for a configured origin", allowed.Code, allowed.Header().Get("Access-Control-Allow-Origin"))
+	}
+	if allowed.Header().Get("Access-Control-Allow-Credentials") != "true" {
+		t.Error("credentials aren't allowed for a configured origin")
+	}
+
+	disallowed := request(http.MethodGet, "https://evil.example.com")
+	if disallowed.Code != http.StatusForbidden || disallowed.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("got %d with allowed origin %q for an unknown origin, want 403", disallowed.Code, disallowed.Header().Get("Access-Control-Allow-Origin"))
+	}
+
+	preflight := request(http.MethodOptions, "https://refundable.tgm.ac.at")
+	if preflight.Code != http.StatusNoContent {
+		t.Errorf("got %d for a preflight request, want 204", preflight.Code)
+	}
+	if methods := preflight.Header().Get("Access-Control-Allow-Methods"); !strings.Contains(methods, http.MethodGet) {
+		t.Errorf("the preflight allows the methods %q", methods)
+	}
+	if headers := preflight.Header().Get("Access-Control-Allow-Headers"); !strings.Contains(strings.ToLower(headers), "authorization") {
+		t.Errorf("the preflight allows the headers %q, want Authorization", headers)
+	}
+}
@@ -0,0 +1,55 @@
+package rest
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/refundable-tgm/huginn/untis"
+	"time"
+)
+
+// MaxTimetableDays is the maximum amount of days a timetable may be requested for at once to protect the untis api
+const MaxTimetableDays = 60
+
+// MaxBulkTeachers is the maximum amount of teachers whose timetables may be requested in one call
+const MaxBulkTeachers = 50
+
+// parseTimetableRange reads the from and to query parameters of a request as described by timetableRange
+func parseTimetableRange(con *gin.Context) (time.Time, time.Time, error) {
+	return timetableRange(con.Query("from"), con.Query("to"))
+}
+
+// timetableRange parses from and to as dates in DateFormat
+// from defaults to the monday of the current week and to defaults to the sunday of the week from is in if they are empty,
+// both days are included and the range may not be longer than MaxTimetableDays
+func timetableRange(rawFrom, rawTo string) (time.Time, time.Time, error) {
+	loc := untis.Location()
+	from := startOfWeek(time.Now().In(loc))
+	if rawFrom != "" {
+		parsed, err := time.ParseInLocation(DateFormat, rawFrom, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from date provided")
+		}
+		from = parsed
+	}
+	to := startOfWeek(from).AddDate(0, 0, 6)
+	if rawTo != "" {
+		parsed, err := time.ParseInLocation(DateFormat, rawTo, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to date provided")
+		}
+		to = parsed
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("to date is before from date")
+	}
+	if !from.AddDate(0, 0, MaxTimetableDays).After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("the range may not be longer than %d days", MaxTimetableDays)
+	}
+	return from, to, nil
+}
+
+// startOfWeek returns the start of the monday of the week t is in
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"github.com/refundable-tgm/huginn/untis"
+	"testing"
+	"time"
+)
+
+func TestTimetableRange(t *testing.T) {
+	loc := untis.Location()
+	monday := startOfWeek(time.Now().In(loc))
+	tests := []struct {
+		name     string
+		from, to string
+		valid    bool
+		want     [2]time.Time
+	}{
+		{"the current week", "", "", true, [2]time.Time{monday, monday.AddDate(0, 0, 6)}},
+		{"the week of from", "2021-03-03", "", true, [2]time.Time{time.Date(2021, 3, 3, 0, 0, 0, 0, loc), time.Date(2021, 3, 7, 0, 0, 0, 0, loc)}},
+		{"a single day", "2021-03-01", "2021-03-01", true, [2]time.Time{time.Date(2021, 3, 1, 0, 0, 0, 0, loc), time.Date(2021, 3, 1, 0, 0, 0, 0, loc)}},
+		{"the longest range", "2021-03-01", "2021-04-29", true, [2]time.Time{time.Date(2021, 3, 1, 0, 0, 0, 0, loc), time.Date(2021, 4, 29, 0, 0, 0, 0, loc)}},
+		{"a too long range", "2021-03-01", "2021-04-30", false, [2]time.Time{}},
+		{"to before from", "2021-03-02", "2021-03-01", false, [2]time.Time{}},
+		{"an invalid from", "01.03.2021", "", false, [2]time.Time{}},
+		{"an invalid to", "2021-03-01", "2021-02-30", false, [2]time.Time{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			from, to, err := timetableRange(test.from, test.to)
+			if !test.valid {
+				if err == nil {
+					t.Errorf("got %v to %v, want an error", from, to)
+				}
+				return
+			}
+			if err != nil || !from.Equal(test.want[0]) || !to.Equal(test.want[1]) {
+				t.Errorf("got %v to %v (%v), want %v to %v", from, to, err, test.want[0], test.want[1])
+			}
+		})
+	}
+}
+
+func TestStartOfWeek(t *testing.T) {
+	loc := untis.Location()
+	monday := time.Date(2021, 3, 1, 0, 0, 0, 0, loc)
+	for offset := 0; offset < 7; offset++ {
+		day := monday.AddDate(0, 0, offset).Add(13 * time.Hour)
+		if got := startOfWeek(day); !got.Equal(monday) {
+			t.Errorf("the week of %v starts at %v, want %v", day, got, monday)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -31,6 +32,18 @@ const accessDuration = time.Minute * 15
 // refreshDuration is the time for which a refresh token is valid (default 7 days)
 const refreshDuration = time.Hour * 24 * 7
 
+// AccessTokenCookie is the cookie the access token is read from if the request has no Authorization header
+const AccessTokenCookie = "access_token"
+
+const (
+	// ReasonMissing is the reason requests without a token are rejected with
+	ReasonMissing = "missing"
+	// ReasonExpired is the reason requests with an expired token are rejected with
+	ReasonExpired = "expired"
+	// ReasonInvalid is the reason requests with a malformed, forged or revoked token are rejected with
+	ReasonInvalid = "invalid"
+)
+
 // accessSecret is the secret used to encode access tokens
 var accessSecret string
 
@@ -40,6 +53,13 @@ var refreshSecret string
 // activeTokens stores all token information of active tokens
 var activeTokens map[string]EntityInformation
 
+// revokedTokens stores the uuids of revoked tokens mapped to the time they would have expired at
+// they are kept until then to be able to reject reused refresh tokens
+var revokedTokens map[string]time.Time
+
+// tokenMutex guards activeTokens and revokedTokens against concurrent access
+var tokenMutex sync.RWMutex
+
 // Token represents a token pair
 type Token struct {
 	// AccessToken is the access token itself
@@ -70,6 +90,8 @@ type EntityInformation struct {
 	Username string
 	// ExpiresAt marks the time the token expires at
 	ExpiresAt time.Time
+	// Pair is the uuid of the other token of the token pair this token was created with
+	Pair string
 }
 
 // InitTokenManager initializes the token manager
@@ -77,10 +99,20 @@ type EntityInformation struct {
 func InitTokenManager() {
 	readRefreshSecret()
 	readAccessSecret()
+	tokenMutex.Lock()
 	activeTokens = make(map[string]EntityInformation)
+	revokedTokens = make(map[string]time.Time)
+	tokenMutex.Unlock()
 	go ttlCheck()
 }
 
+// tokenManagerInitialized reports whether InitTokenManager has been run and both secrets are available
+func tokenManagerInitialized() bool {
+	tokenMutex.RLock()
+	defer tokenMutex.RUnlock()
+	return activeTokens != nil && accessSecret != "" && refreshSecret != ""
+}
+
 // CreateToken creates a token pair based on a username
 func CreateToken(username string) (*Token, error) {
 	token := &Token{}
@@ -122,17 +154,58 @@ func SaveToken(username string, token *Token) {
 	acExp := time.Unix(token.AccessExpires, 0)
 	refExp := time.Unix(token.RefreshExpires, 0)
 
-	activeTokens[token.AccessUUID] = EntityInformation{username, acExp}
-	activeTokens[token.RefreshUUID] = EntityInformation{username, refExp}
+	tokenMutex.Lock()
+	activeTokens[token.AccessUUID] = EntityInformation{username, acExp, token.RefreshUUID}
+	activeTokens[token.RefreshUUID] = EntityInformation{username, refExp, token.AccessUUID}
+	tokenMutex.Unlock()
+}
+
+// IsTokenActive checks whether the token with the uuid is saved and neither expired nor revoked
+func IsTokenActive(uuid string) bool {
+	tokenMutex.RLock()
+	defer tokenMutex.RUnlock()
+	_, ok := activeTokens[uuid]
+	return ok
+}
+
+// IsTokenRevoked checks whether the token with the uuid was revoked
+func IsTokenRevoked(uuid string) bool {
+	tokenMutex.RLock()
+	defer tokenMutex.RUnlock()
+	_, ok := revokedTokens[uuid]
+	return ok
+}
+
+// RevokeToken invalidates the token with the uuid and the other token of its pair and returns whether the token was
+// active, the check and the revocation happen at once, so only one of several concurrent calls for a token succeeds
+// revoked tokens are remembered until they would have expired, so that a reuse can be detected
+func RevokeToken(uuid string) bool {
+	tokenMutex.Lock()
+	defer tokenMutex.Unlock()
+	info, ok := activeTokens[uuid]
+	if !ok {
+		return false
+	}
+	delete(activeTokens, uuid)
+	revokedTokens[uuid] = info.ExpiresAt
+	if pair, ok := activeTokens[info.Pair]; ok {
+		delete(activeTokens, info.Pair)
+		revokedTokens[info.Pair] = pair.ExpiresAt
+	}
+	return true
 }
 
 // ExtractToken parses the token string out of a request
+// it is taken from the Authorization header using the Bearer scheme (case-insensitive), requests without such a
+// header fall back to the AccessTokenCookie, so the header wins if both are present
 func ExtractToken(r *http.Request) string {
-	bear := r.Header.Get("Authorization")
-	split := strings.Split(bear, " ")
-	if len(split) == 2 {
+	split := strings.Fields(r.Header.Get("Authorization"))
+	if len(split) == 2 && strings.EqualFold(split[0], "Bearer") {
 		return split[1]
 	}
+	if cookie, err := r.Cookie(AccessTokenCookie); err == nil {
+		return cookie.Value
+	}
 	return ""
 }
 
@@ -163,6 +236,26 @@ func TokenValid(r *http.Request) (bool, error) {
 	return true, nil
 }
 
+// RejectionReason checks the access token of the request and returns why it has to be rejected
+// (ReasonMissing, ReasonExpired or ReasonInvalid), it returns an empty string if the token is valid and active
+func RejectionReason(r *http.Request) string {
+	if ExtractToken(r) == "" {
+		return ReasonMissing
+	}
+	_, err := VerifyToken(r)
+	if ve, ok := err.(*jwt.ValidationError); ok && ve.Errors&jwt.ValidationErrorExpired != 0 {
+		return ReasonExpired
+	}
+	if err != nil {
+		return ReasonInvalid
+	}
+	auth, err := ExtractTokenMeta(r)
+	if err != nil || auth == nil || !IsTokenActive(auth.AccessUUID) {
+		return ReasonInvalid
+	}
+	return ""
+}
+
 // ExtractTokenMeta extracts the meta information encoded in the token and returns both uuid and username
 func ExtractTokenMeta(r *http.Request) (*AccessToken, error) {
 	token, err := VerifyToken(r)
@@ -189,7 +282,9 @@ func ExtractTokenMeta(r *http.Request) (*AccessToken, error) {
 
 // DeleteToken deletes a token
 func DeleteToken(uuid string) {
+	tokenMutex.Lock()
 	delete(activeTokens, uuid)
+	tokenMutex.Unlock()
 }
 
 // readAccessSecret manages the refresh secret generation
@@ -266,11 +361,18 @@ func readRefreshSecret() {
 func ttlCheck() {
 	for {
 		now := time.Now()
+		tokenMutex.Lock()
 		for key, value := range activeTokens {
 			if value.ExpiresAt.Before(now) {
 				delete(activeTokens, key)
 			}
 		}
+		for key, expiresAt := range revokedTokens {
+			if expiresAt.Before(now) {
+				delete(revokedTokens, key)
+			}
+		}
+		tokenMutex.Unlock()
 		time.Sleep(time.Minute)
 	}
 }
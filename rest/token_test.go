@@ -0,0 +1,236 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// refresh sends the refresh token to Refresh and returns the status and the token pair it responded with
+func refresh(t *testing.T, refreshToken string) (int, TokenPair) {
+	t.Helper()
+	body, err := json.Marshal(RefreshToken{refreshToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+	con, recorder := testContext(http.MethodPost, "/login/refresh", string(body))
+	Refresh(con)
+	pair := TokenPair{}
+	if recorder.Code == http.StatusCreated {
+		if err := json.Unmarshal(recorder.Body.Bytes(), &pair); err != nil {
+			t.Fatalf("couldn't decode the token pair %s: %v", recorder.Body, err)
+		}
+	}
+	return recorder.Code, pair
+}
+
+// savedToken creates and saves a token pair of the username like Login, it is revoked when the test finishes
+func savedToken(t *testing.T, username string) *Token {
+	t.Helper()
+	token, err := CreateToken(username)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SaveToken(username, token)
+	t.Cleanup(func() { RevokeToken(token.AccessUUID) })
+	return token
+}
+
+// accessGranted returns whether AuthWall lets a request with the access token pass
+func accessGranted(accessToken string) bool {
+	con, _ := testContext(http.MethodGet, "/getTeacherByShort", "")
+	con.Request.Header.Set("Authorization", "Bearer "+accessToken)
+	AuthWall()(con)
+	return !con.IsAborted()
+}
+
+func TestRefreshRotatesTheTokenPair(t *testing.T) {
+	token := savedToken(t, "szakall")
+	status, pair := refresh(t, token.RefreshToken)
+	if status != http.StatusCreated {
+		t.Fatalf("refreshing responded with %d, want %d", status, http.StatusCreated)
+	}
+	if pair.RefreshToken == "" || pair.RefreshToken == token.RefreshToken {
+		t.Error("refreshing didn't issue a new refresh token")
+	}
+	if pair.AccessToken == "" || pair.AccessToken == token.AccessToken {
+		t.Error("refreshing didn't issue a new access token")
+	}
+	if !accessGranted(pair.AccessToken) {
+		t.Error("the new access token is rejected")
+	}
+	if accessGranted(token.AccessToken) {
+		t.Error("the access token of the old pair is still accepted")
+	}
+	if IsTokenActive(token.RefreshUUID) || !IsTokenRevoked(token.RefreshUUID) {
+		t.Error("the old refresh token wasn't revoked")
+	}
+	if status, _ := refresh(t, pair.RefreshToken); status != http.StatusCreated {
+		t.Errorf("refreshing with the new refresh token responded with %d, want %d", status, http.StatusCreated)
+	}
+}
+
+func TestReplayedRefreshTokensAreRejected(t *testing.T) {
+	token := savedToken(t, "szakall")
+	if status, _ := refresh(t, token.RefreshToken); status != http.StatusCreated {
+		t.Fatalf("refreshing responded with %d, want %d", status, http.StatusCreated)
+	}
+	if status, _ := refresh(t, token.RefreshToken); status != http.StatusUnauthorized {
+		t.Errorf("replaying the refresh token responded with %d, want %d", status, http.StatusUnauthorized)
+	}
+}
+
+func TestUnknownRefreshTokensAreRejected(t *testing.T) {
+	token, err := CreateToken("szakall")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"a token which was never saved", token.RefreshToken},
+		{"an access token", token.AccessToken},
+		{"garbage", "not-a-jwt"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if status, _ := refresh(t, test.token); status != http.StatusUnauthorized {
+				t.Errorf("refreshing responded with %d, want %d", status, http.StatusUnauthorized)
+			}
+		})
+	}
+}
+
+func TestLogoutRevokesTheTokenPair(t *testing.T) {
+	token := savedToken(t, "szakall")
+	if !accessGranted(token.AccessToken) {
+		t.Fatal("the access token is rejected before logging out")
+	}
+	con, recorder := testContext(http.MethodPost, "/logout", "")
+	con.Request.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	Logout(con)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("logging out responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	if accessGranted(token.AccessToken) {
+		t.Error("the access token is still accepted after logging out")
+	}
+	if status, _ := refresh(t, token.RefreshToken); status != http.StatusUnauthorized {
+		t.Errorf("refreshing after logging out responded with %d, want %d", status, http.StatusUnauthorized)
+	}
+}
+
+// signedAccessToken signs an access token of the username like CreateToken which expires at exp
+func signedAccessToken(t *testing.T, username string, exp time.Time) string {
+	t.Helper()
+	claims := jwt.MapClaims{"authorized": true, "access_uuid": uuid.NewString(), "username": username, "exp": exp.Unix()}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(accessSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestAuthWallRejectsRequestsWithTheirReason(t *testing.T) {
+	token := savedToken(t, "szakall")
+	revoked := savedToken(t, "szakall")
+	RevokeToken(revoked.AccessUUID)
+	foreign, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"username": "szakall"}).SignedString([]byte("another-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name          string
+		authorization string
+		reason        string
+	}{
+		{"no header", "", ReasonMissing},
+		{"another scheme", "Basic c3pha2FsbDpwYXNzd29yZA==", ReasonMissing},
+		{"an expired token", "Bearer " + signedAccessToken(t, "szakall", time.Now().Add(-time.Minute)), ReasonExpired},
+		{"garbage", "Bearer not-a-jwt", ReasonInvalid},
+		{"a token of another secret", "Bearer " + foreign, ReasonInvalid},
+		{"a refresh token", "Bearer " + token.RefreshToken, ReasonInvalid},
+		{"a revoked token", "Bearer " + revoked.AccessToken, ReasonInvalid},
+		{"a token which was never saved", "Bearer " + signedAccessToken(t, "szakall", time.Now().Add(time.Minute)), ReasonInvalid},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			con, recorder := testContext(http.MethodGet, "/getTeacherByShort", "")
+			if test.authorization != "" {
+				con.Request.Header.Set("Authorization", test.authorization)
+			}
+			AuthWall()(con)
+			if !con.IsAborted() || recorder.Code != http.StatusUnauthorized {
+				t.Fatalf("AuthWall responded with %d, want to abort with %d", recorder.Code, http.StatusUnauthorized)
+			}
+			if challenge := recorder.Header().Get("WWW-Authenticate"); challenge != "Bearer" {
+				t.Errorf("the challenge is %q, want Bearer", challenge)
+			}
+			res := AuthError{}
+			decodeJSON(t, recorder, &res)
+			if res.Message != "unauthorized" || res.Reason != test.reason {
+				t.Errorf("rejected with %+v, want unauthorized because the token is %v", res, test.reason)
+			}
+		})
+	}
+}
+
+func TestAuthWallLetsActiveTokensPass(t *testing.T) {
+	token := savedToken(t, "szakall")
+	con, recorder := testContext(http.MethodGet, "/getTeacherByShort", "")
+	con.Request.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	if reason := RejectionReason(con.Request); reason != "" {
+		t.Errorf("an active token is rejected because it is %v", reason)
+	}
+	AuthWall()(con)
+	if con.IsAborted() || recorder.Header().Get("WWW-Authenticate") != "" {
+		t.Error("AuthWall rejected an active token")
+	}
+}
+
+func TestAuthWallReadsTheTokenFromTheHeaderOrTheCookie(t *testing.T) {
+	header := savedToken(t, "szakall")
+	cookie := savedToken(t, "borko")
+	tests := []struct {
+		name          string
+		authorization string
+		cookie        string
+		username      string
+	}{
+		{"a bearer header", "Bearer " + header.AccessToken, "", "szakall"},
+		{"a lower case scheme", "bearer " + header.AccessToken, "", "szakall"},
+		{"a cookie", "", cookie.AccessToken, "borko"},
+		{"both", "Bearer " + header.AccessToken, cookie.AccessToken, "szakall"},
+		{"neither", "", "", ""},
+		{"an invalid header and a valid cookie", "Bearer not-a-jwt", cookie.AccessToken, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			con, recorder := testContext(http.MethodGet, "/getTeacherByShort", "")
+			if test.authorization != "" {
+				con.Request.Header.Set("Authorization", test.authorization)
+			}
+			if test.cookie != "" {
+				con.Request.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: test.cookie})
+			}
+			AuthWall()(con)
+			if test.username == "" {
+				if !con.IsAborted() || recorder.Code != http.StatusUnauthorized {
+					t.Errorf("AuthWall responded with %d, want to abort with %d", recorder.Code, http.StatusUnauthorized)
+				}
+				return
+			}
+			if con.IsAborted() {
+				t.Fatalf("AuthWall rejected the request with %d", recorder.Code)
+			}
+			auth, err := ExtractTokenMeta(con.Request)
+			if err != nil || auth.Username != test.username {
+				t.Errorf("the request is authenticated as %+v, %v, want %v", auth, err, test.username)
+			}
+		})
+	}
+}
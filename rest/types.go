@@ -1,5 +1,11 @@
 package rest
 
+import (
+	mongo "github.com/refundable-tgm/huginn/db"
+	"github.com/refundable-tgm/huginn/untis"
+	"time"
+)
+
 // User data input
 type User struct {
 	// Username of the user
@@ -22,6 +28,22 @@ type Error struct {
 	Message string `json:"error" example:"couldn't convert token"`
 }
 
+// RouteError maps an error message regarding the requested route
+type RouteError struct {
+	// the message that should be sent
+	Message string `json:"error" example:"endpoint not found"`
+	// Path is the path that was requested
+	Path string `json:"path" example:"/api/doesNotExist"`
+}
+
+// AuthError maps the machine readable reason why a request was rejected for its token
+type AuthError struct {
+	// the message that should be sent, always unauthorized
+	Message string `json:"error" example:"unauthorized"`
+	// Reason is either missing, expired or invalid
+	Reason string `json:"reason" example:"expired"`
+}
+
 // Information maps an information message
 type Information struct {
 	// the message that should be sent
@@ -66,6 +88,14 @@ type Permissions struct {
 	PEK bool `json:"pek" example:"true"`
 }
 
+// PermissionsUpdate sets the permissions of a teacher to the listed ones
+type PermissionsUpdate struct {
+	// TeacherShort is the short name of the teacher whose permissions are changed
+	TeacherShort string `json:"teacher_short" binding:"required" example:"szakall"`
+	// Permissions are the names of all permissions the teacher should have (super_user, administration, av or pek), permissions not listed are revoked
+	Permissions []string `json:"permissions" example:"administration,av"`
+}
+
 // News is a news object for applications
 type News struct {
 	// UUID of the application
@@ -78,15 +108,60 @@ type News struct {
 	LastChanged string `json:"last_changed" example:"2009-11-10 23:00:00 +0000 UTC m=+0.000000001"`
 }
 
+// NewsPage is a page of the news
+type NewsPage struct {
+	// Items are the news on this page
+	Items []News `json:"items"`
+	// Total is the amount of news on all pages
+	Total int `json:"total" example:"42"`
+	// NextOffset is the offset of the next page or -1 if this is the last page
+	NextOffset int `json:"next_offset" example:"10"`
+}
+
 // PDF represents a pdf file
 type PDF struct {
 	// Content is the content of this file
 	Content string `json:"pdf" example:"<base64>"`
 }
 
+// Receipt represents an uploaded receipt
+type Receipt struct {
+	// Content is the content of this file
+	Content string `json:"pdf" example:"<base64>"`
+	// Name is the original file name, only its extension is used
+	Name string `json:"name,omitempty" example:"rechnung.pdf"`
+}
+
 // PDFs is a wrapper for a single pdf
 type PDFs struct {
-	Files []PDF `json:"files"`
+	Files []Receipt `json:"files"`
+}
+
+// SavedReceipts lists the receipts stored by the save billing receipt endpoint
+type SavedReceipts struct {
+	// Message is the message that should be sent
+	Message string `json:"info" example:"saving successful"`
+	// Files are the paths of the stored receipts relative to the receipts directory
+	Files []string `json:"files" example:"2021/0f8fad5b-d9cb-469f-a165-70867728950e_mbeier_20210504T120000.000000000.pdf"`
+}
+
+// ApplicationExport is the versioned document of an application returned by the export application endpoint
+// and accepted by the import application endpoint
+type ApplicationExport struct {
+	// SchemaVersion is the version of the layout of this document, see ApplicationExportVersion
+	SchemaVersion int `json:"schema_version" binding:"required" example:"1"`
+	// ExportedAt is the time the application was exported at
+	ExportedAt time.Time `json:"exported_at"`
+	// Application is the exported application
+	Application mongo.Application `json:"application"`
+}
+
+// ImportedApplication is the response of the import application endpoint
+type ImportedApplication struct {
+	// Message is the message that should be sent
+	Message string `json:"info" example:"success; application imported"`
+	// UUID is the uuid of the recreated application
+	UUID string `json:"uuid" example:"693aa616-9895-418b-8904-765f0f6d26a4"`
 }
 
 // Excel represents an excel output
@@ -94,3 +169,164 @@ type Excel struct {
 	// Content is the content of the excel file
 	Content string `json:"excel" example:"<base64>"`
 }
+
+// ApplicationPage is a page of a list of applications
+type ApplicationPage struct {
+	// Items are the applications on this page
+	Items []mongo.Application `json:"items"`
+	// Total is the amount of applications on all pages
+	Total int `json:"total" example:"42"`
+	// NextOffset is the offset of the next page or -1 if this is the last page
+	NextOffset int `json:"next_offset" example:"20"`
+}
+
+// ProjectedApplicationPage is a page of a list of applications reduced to the fields selected by the fields query parameter
+type ProjectedApplicationPage struct {
+	// Items are the selected fields of the applications on this page
+	Items interface{} `json:"items"`
+	// Total is the amount of applications on all pages
+	Total int `json:"total" example:"42"`
+	// NextOffset is the offset of the next page or -1 if this is the last page
+	NextOffset int `json:"next_offset" example:"20"`
+}
+
+// Health reports the state of this api
+type Health struct {
+	// Status is either ok or degraded
+	Status string `json:"status" example:"ok"`
+	// Uptime is the time this api is running for
+	Uptime string `json:"uptime" example:"3h25m12s"`
+	// TokenManager whether the token manager is initialized
+	TokenManager bool `json:"token_manager" example:"true"`
+	// Untis whether the untis api is reachable, only reported by the readiness check
+	Untis *bool `json:"untis,omitempty" example:"true"`
+}
+
+// Version reports the build of this api
+type Version struct {
+	// Commit is the git commit (or module version) this api was built from, empty if unknown
+	Commit string `json:"commit" example:"3b6e462"`
+	// BuildTime is the time this api was built at, empty if unknown
+	BuildTime string `json:"build_time" example:"2021-05-04T12:00:00Z"`
+	// GoVersion is the version of go this api was built with
+	GoVersion string `json:"go_version" example:"go1.15"`
+}
+
+// CreateApplicationRequest is the data of a new application as accepted by the create application endpoint
+type CreateApplicationRequest struct {
+	// The name on how this Application should be referenced by
+	Name string `json:"name" binding:"required" example:"Sommersportwoche"`
+	// The kind of this Application, only SchoolEvent (0), Training (1) and OtherReason (6) are applicable
+	Kind int `json:"kind" binding:"oneof=0 1 6" example:"0"`
+	// The Reasoning of this Application (there is none if this isn't of the type Miscellaneous)
+	MiscellaneousReason string `json:"miscellaneous_reason" example:"Guter Grund"`
+	// The Progress of this Application in filing (for more see the Enum for the Progress)
+	Progress int `json:"progress" binding:"min=0,max=7" example:"1"`
+	// the time the underlying event of this Application starts
+	StartTime time.Time `json:"start_time" binding:"required"`
+	// the time the underlying event of this Application ends, it has to be after the start time
+	EndTime time.Time `json:"end_time" binding:"required,gtfield=StartTime"`
+	// Other Notes regarding this Application
+	Notes string `json:"notes" example:"Wichtig ist, dass wir die Reise bewilligen lassen!"`
+	// The starting address of this Application
+	StartAddress string `json:"start_address" example:"TGM, Wexstraße 19-23, 1220 Wien"`
+	// The Destination Address of this Application
+	DestinationAddress string `json:"destination_address" example:"Karl Hönck Heim, Kärnten"`
+	// Further Details if this is of the kind SchoolEvent, if not this will be empty
+	SchoolEventDetails mongo.SchoolEventDetails `json:"school_event_details"`
+	// Further Details if this is of the kind Training, if not this will be empty
+	TrainingDetails mongo.TrainingDetails `json:"training_details"`
+	// Further Details if this is of the kind of any other, if not this will be empty
+	OtherReasonDetails mongo.OtherReasonDetails `json:"other_reason_details"`
+	// The regarding BusinessTripApplication for each teacher
+	BusinessTripApplications []mongo.BusinessTripApplication `json:"business_trip_applications"`
+	// The regarding TravelInvoice for each teacher
+	TravelInvoices []mongo.TravelInvoice `json:"travel_invoices"`
+}
+
+// FieldError describes why the value of a field of a request is invalid
+type FieldError struct {
+	// Field is the json name of the invalid field
+	Field string `json:"field" example:"end_time"`
+	// Reason is the validation rule the field violates
+	Reason string `json:"reason" example:"gtfield"`
+	// Message describes the rule, it is omitted for rules without a description
+	Message string `json:"message,omitempty" example:"this has to be after start_time"`
+}
+
+// ApplicationValidation is the result of checking an application with the validate application endpoint
+type ApplicationValidation struct {
+	// Valid whether the application meets all rules and would be accepted by the create application endpoint
+	Valid bool `json:"valid" example:"false"`
+	// Violations are the fields violating a rule
+	Violations []FieldError `json:"violations"`
+}
+
+// ValidationError maps an error message with the list of invalid fields of a request
+type ValidationError struct {
+	// the message that should be sent
+	Message string `json:"error" example:"invalid request structure provided"`
+	// Fields are the invalid fields
+	Fields []FieldError `json:"fields"`
+}
+
+// TimetablesRequest is the request body of the get timetables for teachers endpoint
+type TimetablesRequest struct {
+	// Teachers are the names of the teachers, either the full name or the short name
+	Teachers []string `json:"teachers" binding:"required,min=1" example:"Max Mustermann"`
+	// From is the first day of the timetables (YYYY-MM-DD), the monday of the current week if it is empty
+	From string `json:"from" example:"2021-05-03"`
+	// To is the last day of the timetables (YYYY-MM-DD), the sunday of the week of from if it is empty
+	To string `json:"to" example:"2021-05-09"`
+}
+
+// ApplicationsRequest is the request body of the get applications endpoint
+type ApplicationsRequest struct {
+	// IDs are the uuids of the requested applications
+	IDs []string `json:"ids" binding:"required,min=1" example:"7e1c3f4a-9b2d-4c8e-a1f0-3d5b6e7f8a9b"`
+}
+
+// ApplicationBatch is the response of the get applications endpoint
+type ApplicationBatch struct {
+	// Applications are the found applications keyed by their uuid
+	Applications map[string]mongo.Application `json:"applications"`
+	// NotFound are the requested uuids without an application the logged in teacher may see
+	NotFound []string `json:"not_found"`
+}
+
+// BulkStatusRequest is the request body of the bulk update application status endpoint
+type BulkStatusRequest struct {
+	// IDs are the uuids of the applications to update
+	IDs []string `json:"ids" binding:"required,min=1" example:"7e1c3f4a-9b2d-4c8e-a1f0-3d5b6e7f8a9b"`
+	// Status is the progress the applications are set to: rejected (0), confirmed (3) or done (7)
+	Status *int `json:"status" binding:"required" example:"3"`
+}
+
+// BulkStatusResult is the response of the bulk update application status endpoint
+type BulkStatusResult struct {
+	// Results are the outcomes keyed by the uuids of the applications: updated, not_found, unauthorized,
+	// invalid_transition, conflict or failed
+	Results map[string]string `json:"results"`
+	// Updated is the amount of updated applications
+	Updated int `json:"updated" example:"2"`
+}
+
+// CurrentLesson is the response of the get current lesson endpoint
+type CurrentLesson struct {
+	// Current is the lesson taking place at the moment, null in a break or outside of school hours
+	Current *untis.Lesson `json:"current"`
+	// CurrentPeriod is the number of the period the current lesson starts in, -1 if there is none
+	CurrentPeriod int `json:"current_period" example:"3"`
+	// Next is the next lesson of the day, null if there is none
+	Next *untis.Lesson `json:"next"`
+	// NextPeriod is the number of the period the next lesson starts in, -1 if there is none
+	NextPeriod int `json:"next_period" example:"5"`
+}
+
+// TeacherTimetable is the timetable of a single teacher as returned by the get timetables for teachers endpoint
+type TeacherTimetable struct {
+	// Lessons are the lessons of the teacher, empty if an error occurred
+	Lessons []untis.Lesson `json:"lessons"`
+	// Error describes why the timetable of the teacher couldn't be fetched, it is omitted on success
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,124 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	mongo "github.com/refundable-tgm/huginn/db"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// toApplication converts the request into a new application
+func (req CreateApplicationRequest) toApplication() mongo.Application {
+	return mongo.Application{
+		Name:                     req.Name,
+		Kind:                     req.Kind,
+		MiscellaneousReason:      req.MiscellaneousReason,
+		Progress:                 req.Progress,
+		StartTime:                req.StartTime,
+		EndTime:                  req.EndTime,
+		Notes:                    req.Notes,
+		StartAddress:             req.StartAddress,
+		DestinationAddress:       req.DestinationAddress,
+		SchoolEventDetails:       req.SchoolEventDetails,
+		TrainingDetails:          req.TrainingDetails,
+		OtherReasonDetails:       req.OtherReasonDetails,
+		BusinessTripApplications: req.BusinessTripApplications,
+		TravelInvoices:           req.TravelInvoices,
+	}
+}
+
+// ruleMessages describes the validation rules, the messages are localized before they are returned
+var ruleMessages = map[string]string{
+	"required": "this field is required",
+	"oneof":    "this value isn't allowed",
+	"min":      "this value is too small",
+	"max":      "this value is too large",
+	"gtfield":  "this has to be after start_time",
+	"future":   "this has to be in the future",
+	"len":      "this has to contain one entry per class",
+}
+
+// validateApplicationRequest binds the body of the request into req and checks it against the binding rules of
+// CreateApplicationRequest and the business rules of applications, the violations are the fields of res.
+// malformed is true if the body couldn't be decoded at all, then the business rules aren't checked
+func validateApplicationRequest(con *gin.Context) (req CreateApplicationRequest, res ValidationError, malformed bool) {
+	err := con.ShouldBindJSON(&req)
+	res = validationError(con, err, req)
+	if _, ok := err.(validator.ValidationErrors); err != nil && !ok {
+		return req, res, true
+	}
+	res.Fields = append(res.Fields, req.businessRuleViolations()...)
+	for i := range res.Fields {
+		if message, ok := ruleMessages[res.Fields[i].Reason]; ok {
+			res.Fields[i].Message = localize(con, message)
+		}
+	}
+	return req, res, false
+}
+
+// businessRuleViolations checks the rules an application has to meet besides the binding rules: it has to start
+// in the future and contain the details required by its kind
+func (req CreateApplicationRequest) businessRuleViolations() []FieldError {
+	violations := make([]FieldError, 0)
+	if !req.StartTime.IsZero() && !req.StartTime.After(time.Now()) {
+		violations = append(violations, FieldError{Field: "start_time", Reason: "future"})
+	}
+	switch req.Kind {
+	case mongo.SchoolEvent:
+		details := req.SchoolEventDetails
+		if len(details.Classes) == 0 {
+			violations = append(violations, FieldError{Field: "school_event_details.classes", Reason: "required"})
+		}
+		if len(details.Teachers) == 0 {
+			violations = append(violations, FieldError{Field: "school_event_details.teachers", Reason: "required"})
+		}
+		if len(details.AmountMaleStudents) != len(details.Classes) {
+			violations = append(violations, FieldError{Field: "school_event_details.amount_male_students", Reason: "len"})
+		}
+		if len(details.AmountFemaleStudents) != len(details.Classes) {
+			violations = append(violations, FieldError{Field: "school_event_details.amount_female_students", Reason: "len"})
+		}
+	case mongo.Training:
+		if strings.TrimSpace(req.TrainingDetails.Filer) == "" {
+			violations = append(violations, FieldError{Field: "training_details.filer", Reason: "required"})
+		}
+		if strings.TrimSpace(req.TrainingDetails.Organizer) == "" {
+			violations = append(violations, FieldError{Field: "training_details.organizer", Reason: "required"})
+		}
+	case mongo.OtherReason:
+		if strings.TrimSpace(req.OtherReasonDetails.Filer) == "" {
+			violations = append(violations, FieldError{Field: "other_reason_details.filer", Reason: "required"})
+		}
+	}
+	return violations
+}
+
+// validationError converts an error returned by binding a request into req to a ValidationError
+// if err isn't caused by a violated validation rule (e.g. malformed json), the list of fields is empty
+func validationError(con *gin.Context, err error, req interface{}) ValidationError {
+	res := ValidationError{localize(con, "invalid request structure provided"), make([]FieldError, 0)}
+	errs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return res
+	}
+	for _, fe := range errs {
+		res.Fields = append(res.Fields, FieldError{Field: jsonFieldName(req, fe.StructField()), Reason: fe.Tag()})
+	}
+	return res
+}
+
+// jsonFieldName returns the name the struct field of req is encoded with in json
+func jsonFieldName(req interface{}, structField string) string {
+	t := reflect.TypeOf(req)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if field, ok := t.FieldByName(structField); ok {
+		if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return structField
+}
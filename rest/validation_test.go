@@ -0,0 +1,169 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// applicationBody encodes a valid application of another reason starting tomorrow as the json body of a request,
+// the fields of overrides replace those of the application and nil values remove them
+func applicationBody(t *testing.T, overrides map[string]interface{}) string {
+	t.Helper()
+	start := time.Now().AddDate(0, 0, 1).Truncate(time.Second)
+	app := map[string]interface{}{
+		"name":                 "Dienstreise",
+		"kind":                 6,
+		"progress":             1,
+		"start_time":           start,
+		"end_time":             start.Add(8 * time.Hour),
+		"other_reason_details": map[string]interface{}{"filer": "Michael Borko"},
+	}
+	for field, value := range overrides {
+		if value == nil {
+			delete(app, field)
+			continue
+		}
+		app[field] = value
+	}
+	body, err := json.Marshal(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+// decodeValidationError decodes the body of a response as ValidationError
+func decodeValidationError(t *testing.T, body []byte) ValidationError {
+	t.Helper()
+	var res ValidationError
+	if err := json.Unmarshal(body, &res); err != nil {
+		t.Fatalf("the response %q isn't a validation error: %v", body, err)
+	}
+	return res
+}
+
+// hasFieldError checks whether the field violates the rule according to the validation error
+func hasFieldError(res ValidationError, field, reason string) bool {
+	for _, fe := range res.Fields {
+		if fe.Field == field && fe.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCreateApplicationValidatesTheBody(t *testing.T) {
+	con, recorder := authorizedContext(t, "creator", http.MethodPost, "/api/createApplication", applicationBody(t, nil))
+	CreateApplication(con)
+	// a valid application passes the validation and is only stored afterwards, which fails without a database
+	if recorder.Code == http.StatusBadRequest {
+		t.Errorf("a valid application was rejected: %s", recorder.Body.String())
+	}
+
+	tests := []struct {
+		name          string
+		overrides     map[string]interface{}
+		field, reason string
+	}{
+		{"missing name", map[string]interface{}{"name": nil}, "name", "required"},
+		{"invalid kind", map[string]interface{}{"kind": 4}, "kind", "oneof"},
+		{"end before start", map[string]interface{}{"end_time": time.Now()}, "end_time", "gtfield"},
+	}
+	for _, test := range tests {
+		con, recorder := authorizedContext(t, "creator", http.MethodPost, "/api/createApplication", applicationBody(t, test.overrides))
+		CreateApplication(con)
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("%v: got %d, want %d", test.name, recorder.Code, http.StatusBadRequest)
+			continue
+		}
+		if res := decodeValidationError(t, recorder.Body.Bytes()); !hasFieldError(res, test.field, test.reason) {
+			t.Errorf("%v: got %+v, want %v to violate %v", test.name, res.Fields, test.field, test.reason)
+		}
+	}
+
+	con, recorder = authorizedContext(t, "creator", http.MethodPost, "/api/createApplication", "{")
+	CreateApplication(con)
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("got %d for malformed json, want %d", recorder.Code, http.StatusBadRequest)
+	}
+}
+
+// validateApplication calls ValidateApplication with the body and returns the recorder of its response
+func validateApplication(t *testing.T, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	con, recorder := authorizedContext(t, "validator", http.MethodPost, "/validateApplication", body)
+	con.Request.Header.Set("Accept-Language", "en")
+	ValidateApplication(con)
+	return recorder
+}
+
+func TestValidateApplicationAcceptsAValidApplication(t *testing.T) {
+	recorder := validateApplication(t, applicationBody(t, nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("validating responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	res := ApplicationValidation{}
+	decodeJSON(t, recorder, &res)
+	if !res.Valid || len(res.Violations) != 0 {
+		t.Errorf("a valid application was reported as %+v", res)
+	}
+}
+
+func TestValidateApplicationReportsEveryViolation(t *testing.T) {
+	start := time.Now().AddDate(0, 0, -1).Truncate(time.Second)
+	body := applicationBody(t, map[string]interface{}{
+		"kind":                 0,
+		"start_time":           start,
+		"end_time":             start.Add(-time.Hour),
+		"other_reason_details": nil,
+		"school_event_details": map[string]interface{}{"amount_male_students": []int{12}},
+	})
+	recorder := validateApplication(t, body)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("validating responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	res := ApplicationValidation{}
+	decodeJSON(t, recorder, &res)
+	if res.Valid {
+		t.Error("an invalid application was reported as valid")
+	}
+	want := []FieldError{
+		{Field: "end_time", Reason: "gtfield", Message: "this has to be after start_time"},
+		{Field: "start_time", Reason: "future", Message: "this has to be in the future"},
+		{Field: "school_event_details.classes", Reason: "required", Message: "this field is required"},
+		{Field: "school_event_details.teachers", Reason: "required", Message: "this field is required"},
+		{Field: "school_event_details.amount_male_students", Reason: "len", Message: "this has to contain one entry per class"},
+	}
+	for _, violation := range want {
+		found := false
+		for _, got := range res.Violations {
+			found = found || got == violation
+		}
+		if !found {
+			t.Errorf("the violation %+v is missing in %+v", violation, res.Violations)
+		}
+	}
+
+	con, created := authorizedContext(t, "validator", http.MethodPost, "/createApplication", body)
+	CreateApplication(con)
+	if created.Code != http.StatusBadRequest {
+		t.Fatalf("creating the invalid application responded with %d, want %d", created.Code, http.StatusBadRequest)
+	}
+	if fields := decodeValidationError(t, created.Body.Bytes()).Fields; len(fields) != len(res.Violations) {
+		t.Errorf("creating reported the violations %+v, want the validated %+v", fields, res.Violations)
+	}
+}
+
+func TestValidateApplicationRejectsMalformedBodies(t *testing.T) {
+	if recorder := validateApplication(t, "{"); recorder.Code != http.StatusBadRequest {
+		t.Errorf("validating malformed json responded with %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+	con, recorder := testContext(http.MethodPost, "/validateApplication", applicationBody(t, nil))
+	ValidateApplication(con)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("validating without a login responded with %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,39 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+)
+
+// Commit is the git commit this api was built from, it is set while building with
+// -ldflags "-X github.com/refundable-tgm/huginn/rest.Commit=<commit>"
+var Commit string
+
+// BuildTime is the time this api was built at, it is set while building with
+// -ldflags "-X github.com/refundable-tgm/huginn/rest.BuildTime=<time>"
+var BuildTime string
+
+// currentVersion collects the build information of this api
+// if Commit wasn't set while building, the module version recorded by the go tool is used instead
+func currentVersion() Version {
+	version := Version{
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+	if version.Commit == "" {
+		if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "(devel)" {
+			version.Commit = info.Main.Version
+		}
+	}
+	return version
+}
+
+// GetVersion represents the version endpoint
+// it isn't part of the api group to be usable by operators without authentication
+// it responds with the build information of this api
+func GetVersion(con *gin.Context) {
+	con.JSON(http.StatusOK, currentVersion())
+}
@@ -0,0 +1,38 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+)
+
+func TestVersionIsAvailableWithoutALogin(t *testing.T) {
+	recorder := serveRouter(t, httptest.NewRequest(http.MethodGet, "/version", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("the version responded with %d, want %d", recorder.Code, http.StatusOK)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &fields); err != nil {
+		t.Fatalf("couldn't decode the version %s: %v", recorder.Body, err)
+	}
+	for _, field := range []string{"commit", "build_time", "go_version"} {
+		if _, ok := fields[field]; !ok {
+			t.Errorf("the version %s has no field %v", recorder.Body, field)
+		}
+	}
+	if fields["go_version"] != runtime.Version() {
+		t.Errorf("the version reports go %v, want %v", fields["go_version"], runtime.Version())
+	}
+}
+
+func TestVersionReportsTheLinkedBuildInfo(t *testing.T) {
+	previousCommit, previousBuildTime := Commit, BuildTime
+	Commit, BuildTime = "3b6e462", "2021-05-04T12:00:00Z"
+	defer func() { Commit, BuildTime = previousCommit, previousBuildTime }()
+	version := currentVersion()
+	if version.Commit != "3b6e462" || version.BuildTime != "2021-05-04T12:00:00Z" {
+		t.Errorf("the version is %+v, want the commit and build time set while linking", version)
+	}
+}
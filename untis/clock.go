@@ -0,0 +1,38 @@
+package untis
+
+import "time"
+
+// Clock tells the current time, it is used for everything time dependent in this package
+// (one-time passwords, caches, session ttls and timestamps), so it can be fixed in tests; only the rate limits
+// wait on real timers and therefore use the monotonic time
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+}
+
+// ClockFunc is a Clock calling the function to tell the time
+type ClockFunc func() time.Time
+
+// Now returns the result of the function
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// realClock is the Clock telling the wall clock time
+type realClock struct{}
+
+// Now returns time.Now
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// DefaultClock is the Clock used by clients without their own Clock and by the functions of this package
+var DefaultClock Clock = realClock{}
+
+// now returns the current time using the Clock of the client or DefaultClock if it has none
+func (client *Client) now() time.Time {
+	if client.Clock != nil {
+		return client.Clock.Now()
+	}
+	return DefaultClock.Now()
+}
@@ -0,0 +1,97 @@
+package untis
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixDefaultClock makes DefaultClock tell the time until the test finishes
+func fixDefaultClock(t *testing.T, now time.Time) {
+	t.Helper()
+	previous := DefaultClock
+	DefaultClock = ClockFunc(func() time.Time { return now })
+	t.Cleanup(func() { DefaultClock = previous })
+}
+
+func TestClientsTellTheTimeOfTheirClock(t *testing.T) {
+	fixed := time.Date(2021, time.March, 1, 8, 0, 0, 0, time.UTC)
+	fixDefaultClock(t, fixed)
+	client := CreateClient("clocked", "password")
+	defer client.DeleteClient()
+	if now := client.now(); !now.Equal(fixed) {
+		t.Errorf("a client without a clock tells the time %v, want the one of DefaultClock %v", now, fixed)
+	}
+	own := fixed.Add(time.Hour)
+	client.Clock = &fakeClock{now: own}
+	if now := client.now(); !now.Equal(own) {
+		t.Errorf("a client with a clock tells the time %v, want the one of its clock %v", now, own)
+	}
+}
+
+func TestICalFeedsAreStampedByTheClock(t *testing.T) {
+	fixDefaultClock(t, time.Date(2021, time.March, 1, 7, 30, 0, 0, time.UTC))
+	lessons := []Lesson{{ID: 1, Start: at(8, 0), End: at(8, 50), Subjects: []string{"SEW"}}}
+	first, err := LessonsToICal(lessons)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := LessonsToICal(lessons)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("two feeds of the same lessons differ at the same time")
+	}
+	if !strings.Contains(string(first), "DTSTAMP:20210301T073000Z") {
+		t.Errorf("the feed isn't stamped with the time of the clock:\n%s", first)
+	}
+}
+
+func TestTheCurrentDayIsTakenFromTheClock(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{"the morning", time.Date(2021, time.March, 1, 7, 0, 0, 0, time.UTC), "20210301"},
+		{"before midnight in utc but after it in vienna", time.Date(2021, time.March, 1, 23, 30, 0, 0, time.UTC), "20210302"},
+		{"the night the clocks are set forward", time.Date(2021, time.March, 28, 1, 30, 0, 0, time.UTC), "20210328"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := newFakeUntis(t, nil)
+			fake.serveMasterData()
+			requests := fake.serveTimetable()
+			client := clockedClient(t, fake, "today", &fakeClock{now: test.now})
+			if _, _, err := client.GetCurrentLesson(); err != nil {
+				t.Fatal(err)
+			}
+			got := requests()
+			if len(got) != 1 || got[0].StartDate != test.want || got[0].EndDate != test.want {
+				t.Errorf("requested the timetables %+v, want the one of %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestExamsAreRequestedWithTheTimestampOfTheClock(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	var params []struct {
+		MasterDataTimestamp int64 `json:"masterDataTimestamp"`
+	}
+	fake.handle("getExams2017", func(call fakeCall) (interface{}, *UntisError) {
+		_ = json.Unmarshal(call.Params, &params)
+		return map[string]interface{}{"exams": []interface{}{}}, nil
+	})
+	client := clockedClient(t, fake, "timestamped", ClockFunc(func() time.Time { return time.Unix(1614585600, 0) }))
+	client.setSecret("JBSWY3DPEHPK3PXP")
+	if _, err := client.GetExams(day, day); err != nil {
+		t.Fatal(err)
+	}
+	if len(params) != 1 || params[0].MasterDataTimestamp != 1614585600000 {
+		t.Errorf("the exams were requested with %+v, want the timestamp of the clock", params)
+	}
+}
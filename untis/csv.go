@@ -0,0 +1,47 @@
+package untis
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CSVHeader are the column names of the header row of a timetable exported by LessonsToCSV
+var CSVHeader = []string{"Datum", "Beginn", "Ende", "Fächer", "Klassen", "Lehrer", "Räume", "Entfällt"}
+
+// LessonsToCSV converts a list of lessons into a csv file with a header row and one row per lesson
+// dates and times are written in the untis time zone, multiple names in a column are separated by ", "
+func LessonsToCSV(lessons []Lesson) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(CSVHeader); err != nil {
+		return nil, err
+	}
+	for _, lesson := range lessons {
+		if lesson.End.Before(lesson.Start) {
+			return nil, fmt.Errorf("lesson %d ends before it starts", lesson.ID)
+		}
+		start := lesson.Start.In(Location())
+		end := lesson.End.In(Location())
+		err := writer.Write([]string{
+			start.Format(isoDateFormat),
+			start.Format("15:04"),
+			end.Format("15:04"),
+			strings.Join(lesson.Subjects, ", "),
+			strings.Join(lesson.Classes, ", "),
+			strings.Join(lesson.Teachers, ", "),
+			strings.Join(lesson.Rooms, ", "),
+			strconv.FormatBool(lesson.Cancelled),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,88 @@
+package untis
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLessonsToCSV(t *testing.T) {
+	first := sew(on(8, 0), on(9, 40))
+	second := Lesson{
+		Start:     on(10, 0),
+		End:       on(10, 50),
+		Subjects:  []string{"D"},
+		Classes:   []string{"4BHIT", "5AHIT"},
+		Teachers:  []string{"van der Hude, A.", "MAY"},
+		Rooms:     []string{"L2201; Labor"},
+		Cancelled: true,
+	}
+	third := sew(on(11, 0), on(11, 50))
+	third.Subjects = []string{`SEW "Praxis"`}
+	file, err := LessonsToCSV([]Lesson{first, second, third})
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(file)).ReadAll()
+	if err != nil {
+		t.Fatalf("the output isn't valid csv: %v\n%s", err, file)
+	}
+	if len(records) != 4 {
+		t.Fatalf("got %d rows, want a header and 3 lessons", len(records))
+	}
+	if strings.Join(records[0], "|") != strings.Join(CSVHeader, "|") {
+		t.Errorf("the header is %q, want %q", records[0], CSVHeader)
+	}
+	want := [][]string{
+		{"2021-03-01", "08:00", "09:40", "SEW", "5AHIT", "BOR", "H1102", "false"},
+		{"2021-03-01", "10:00", "10:50", "D", "4BHIT, 5AHIT", "van der Hude, A., MAY", "L2201; Labor", "true"},
+		{"2021-03-01", "11:00", "11:50", `SEW "Praxis"`, "5AHIT", "BOR", "H1102", "false"},
+	}
+	for i, row := range want {
+		if got := records[i+1]; strings.Join(got, "|") != strings.Join(row, "|") {
+			t.Errorf("row %d is %q, want %q", i+1, got, row)
+		}
+	}
+	lines := strings.Split(strings.TrimSuffix(string(file), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4", len(lines))
+	}
+	if !strings.Contains(lines[2], `"4BHIT, 5AHIT","van der Hude, A., MAY"`) {
+		t.Errorf("names containing commas aren't quoted: %v", lines[2])
+	}
+	if !strings.Contains(lines[3], `"SEW ""Praxis"""`) {
+		t.Errorf("quotes inside names aren't escaped: %v", lines[3])
+	}
+}
+
+func TestLessonsToCSVWithoutLessons(t *testing.T) {
+	file, err := LessonsToCSV(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(file), strings.Join(CSVHeader, ",")+"\n"; got != want {
+		t.Errorf("got %q, want only the header %q", got, want)
+	}
+}
+
+func TestLessonsToCSVRejectsLessonsEndingBeforeTheyStart(t *testing.T) {
+	if _, err := LessonsToCSV([]Lesson{sew(on(9, 0), on(8, 0))}); err == nil {
+		t.Error("a lesson ending before it starts was converted")
+	}
+}
+
+func TestLessonsToCSVUsesTheUntisTimeZone(t *testing.T) {
+	if Location().String() != TimeZone {
+		t.Skipf("the time zone database doesn't contain %v", TimeZone)
+	}
+	start := time.Date(2021, 3, 1, 23, 30, 0, 0, time.UTC)
+	file, err := LessonsToCSV([]Lesson{{Start: start, End: start.Add(50 * time.Minute)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(file), "2021-03-02,00:30,01:20,") {
+		t.Errorf("a lesson at 23:30 UTC isn't written in Vienna time:\n%s", file)
+	}
+}
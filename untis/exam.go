@@ -0,0 +1,182 @@
+package untis
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Exam represents a single exam as provided by untis
+type Exam struct {
+	// ID is the id of the exam in untis
+	ID int
+	// Type is the kind of the exam as configured in untis (e.g. SA for a school exam)
+	Type string
+	// Name is the name of the exam
+	Name string
+	// Text is the text untis provides regarding the exam
+	Text string
+	// Start is the start time of the exam
+	Start time.Time
+	// End is the end time of the exam
+	End time.Time
+	// Period is the number of the period of DefaultSchedule the exam starts in, -1 if it starts in none
+	Period int
+	// SubjectID is the id of the subject of the exam, 0 if it has none
+	SubjectID int
+	// Subject is the name of the subject of the exam, empty if it has none
+	Subject string
+	// ClassIDs are the ids of the classes taking the exam
+	ClassIDs []int
+	// Classes are the names of the classes taking the exam
+	Classes []string
+	// RoomIDs are the ids of the rooms the exam takes place in
+	RoomIDs []int
+	// Rooms are the names of the rooms the exam takes place in
+	Rooms []string
+	// TeacherIDs are the ids of the teachers supervising the exam
+	TeacherIDs []int
+	// Teachers are the names of the teachers supervising the exam
+	Teachers []string
+}
+
+// examEntry represents a single exam as returned by getExams2017
+type examEntry struct {
+	ID         int    `json:"id"`
+	ExamType   string `json:"examType"`
+	Name       string `json:"name"`
+	Text       string `json:"text"`
+	Date       int    `json:"date"`
+	StartTime  int    `json:"startTime"`
+	EndTime    int    `json:"endTime"`
+	SubjectID  int    `json:"subjectId"`
+	KlasseIDs  []int  `json:"klasseIds"`
+	RoomIDs    []int  `json:"roomIds"`
+	TeacherIDs []int  `json:"teacherIds"`
+}
+
+// GetExams returns the exams of the account the client uses in between start and end
+// they are requested from the internal api of untis like GetUserData
+func (client *Client) GetExams(start, end time.Time) ([]Exam, error) {
+	return client.GetExamsContext(context.Background(), start, end)
+}
+
+// GetExamsContext is like GetExams but uses ctx for the requests sent to the untis api
+func (client *Client) GetExamsContext(ctx context.Context, start, end time.Time) ([]Exam, error) {
+	if !client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	personID, personType := client.Person()
+	respBody, id, err := client.sendInternRequest(ctx, "getExams2017", map[string]interface{}{
+		"id":                  personID,
+		"type":                strings.ToUpper(personType.String()),
+		"startDate":           formatUntisDate(start),
+		"endDate":             formatUntisDate(end),
+		"masterDataTimestamp": client.now().UnixNano() / int64(time.Millisecond),
+	})
+	if err != nil {
+		return nil, err
+	}
+	exams, err := parseExamsResponse(respBody, id)
+	if err != nil {
+		return nil, err
+	}
+	err = client.resolveExams(ctx, exams)
+	if err != nil {
+		return nil, err
+	}
+	return exams, nil
+}
+
+// parseExamsResponse decodes the body of a getExams2017 response into exams ordered by their start
+// only the ids of subjects, classes, rooms and teachers are set, the names have to be resolved afterwards
+func parseExamsResponse(respBody []byte, expectedID int) ([]Exam, error) {
+	r := struct {
+		JSONRPC string `json:"jsonrpc"`
+		ID      string `json:"id"`
+		Result  struct {
+			Exams []examEntry `json:"exams"`
+		} `json:"result"`
+	}{}
+	err := json.Unmarshal(respBody, &r)
+	if err != nil {
+		return nil, err
+	}
+	rid, _ := strconv.Atoi(r.ID)
+	if rid != expectedID {
+		return nil, &IDMismatchError{Method: "getExams2017", Expected: expectedID, Got: rid}
+	}
+	exams := make([]Exam, 0, len(r.Result.Exams))
+	for _, e := range r.Result.Exams {
+		year, month, day := parseUntisDateInt(e.Date)
+		startHour, startMinute := parseUntisTime(e.StartTime)
+		endHour, endMinute := parseUntisTime(e.EndTime)
+		exam := Exam{
+			ID:         e.ID,
+			Type:       e.ExamType,
+			Name:       e.Name,
+			Text:       e.Text,
+			Start:      time.Date(year, month, day, startHour, startMinute, 0, 0, Location()),
+			End:        time.Date(year, month, day, endHour, endMinute, 0, 0, Location()),
+			SubjectID:  e.SubjectID,
+			ClassIDs:   e.KlasseIDs,
+			RoomIDs:    e.RoomIDs,
+			TeacherIDs: e.TeacherIDs,
+		}
+		exam.Period = DefaultSchedule.LessonNrByStart(exam.Start)
+		if exam.ClassIDs == nil {
+			exam.ClassIDs = make([]int, 0)
+		}
+		if exam.RoomIDs == nil {
+			exam.RoomIDs = make([]int, 0)
+		}
+		if exam.TeacherIDs == nil {
+			exam.TeacherIDs = make([]int, 0)
+		}
+		exams = append(exams, exam)
+	}
+	sort.SliceStable(exams, func(i, j int) bool {
+		return exams[i].Start.Before(exams[j].Start)
+	})
+	return exams, nil
+}
+
+// resolveExams resolves the names of the subjects, classes, rooms and teachers of all given exams
+// like resolveLessons, nothing is fetched if there are no exams
+func (client *Client) resolveExams(ctx context.Context, exams []Exam) error {
+	if len(exams) == 0 {
+		return nil
+	}
+	if err := client.warmResolvers(ctx); err != nil {
+		return err
+	}
+	for i := range exams {
+		exam := &exams[i]
+		if exam.SubjectID != 0 {
+			subjects, err := client.ResolveSubjectsContext(ctx, []int{exam.SubjectID})
+			if err != nil {
+				return err
+			}
+			if len(subjects) > 0 {
+				exam.Subject = subjects[0]
+			}
+		}
+		var err error
+		exam.Classes, err = client.ResolveClassesContext(ctx, exam.ClassIDs)
+		if err != nil {
+			return err
+		}
+		exam.Rooms, err = client.ResolveRoomsContext(ctx, exam.RoomIDs)
+		if err != nil {
+			return err
+		}
+		exam.Teachers, err = client.ResolveTeachersContext(ctx, exam.TeacherIDs)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package untis
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// examPlan is a getExams2017 result with the exams out of order
+const examPlan = `{"exams": [
+	{"id":2,"examType":"SA","name":"2. Schularbeit","text":"Kapitel 4-6","date":20210304,"startTime":955,"endTime":1135,
+		"subjectId":31,"klasseIds":[21,22],"roomIds":[11],"teacherIds":[2,3]},
+	{"id":1,"examType":"TEST","name":"Test SEW","text":"","date":20210302,"startTime":800,"endTime":850,
+		"subjectId":30,"klasseIds":[20],"roomIds":[10],"teacherIds":[1]},
+	{"id":3,"examType":"MP","name":"Mündliche Prüfung","date":20210305,"startTime":2200,"endTime":2300}
+]}`
+
+func TestParseExamsResponse(t *testing.T) {
+	var plan interface{}
+	if err := json.Unmarshal([]byte(examPlan), &plan); err != nil {
+		t.Fatal(err)
+	}
+	exams, err := parseExamsResponse(rpcResponse(t, 7, plan), 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Exam{
+		{ID: 1, Type: "TEST", Name: "Test SEW", Start: at(8, 0).AddDate(0, 0, 1), End: at(8, 50).AddDate(0, 0, 1), Period: 1,
+			SubjectID: 30, ClassIDs: []int{20}, RoomIDs: []int{10}, TeacherIDs: []int{1}},
+		{ID: 2, Type: "SA", Name: "2. Schularbeit", Text: "Kapitel 4-6", Start: at(9, 55).AddDate(0, 0, 3), End: at(11, 35).AddDate(0, 0, 3), Period: 3,
+			SubjectID: 31, ClassIDs: []int{21, 22}, RoomIDs: []int{11}, TeacherIDs: []int{2, 3}},
+		{ID: 3, Type: "MP", Name: "Mündliche Prüfung", Start: at(22, 0).AddDate(0, 0, 4), End: at(23, 0).AddDate(0, 0, 4), Period: -1,
+			ClassIDs: []int{}, RoomIDs: []int{}, TeacherIDs: []int{}},
+	}
+	if len(exams) != len(want) {
+		t.Fatalf("got %d exams, want %d", len(exams), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(exams[i], want[i]) {
+			t.Errorf("exam %d is %+v, want %+v", i, exams[i], want[i])
+		}
+	}
+
+	if _, err := parseExamsResponse(rpcResponse(t, 8, plan), 7); !errors.Is(err, ErrIDMismatch) {
+		t.Errorf("got %v for a response to another request, want ErrIDMismatch", err)
+	}
+}
+
+func TestGetExamsResolvesTheNames(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	var plan interface{}
+	if err := json.Unmarshal([]byte(examPlan), &plan); err != nil {
+		t.Fatal(err)
+	}
+	var params []struct {
+		ID        int    `json:"id"`
+		Type      string `json:"type"`
+		StartDate string `json:"startDate"`
+		EndDate   string `json:"endDate"`
+	}
+	fake.handle("getExams2017", func(call fakeCall) (interface{}, *UntisError) {
+		_ = json.Unmarshal(call.Params, &params)
+		return plan, nil
+	})
+	client := newAuthenticatedClient(t, fake, "examining")
+	client.setSecret("JBSWY3DPEHPK3PXP")
+
+	exams, err := client.GetExams(day, day.AddDate(0, 0, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	personID, _ := client.Person()
+	if len(params) != 1 || params[0].ID != personID || params[0].Type != "TEACHER" || params[0].StartDate != "20210301" || params[0].EndDate != "20210305" {
+		t.Errorf("the exams were requested with %+v, want the ones of the teacher from 20210301 to 20210305", params)
+	}
+	if len(exams) != 3 {
+		t.Fatalf("got %d exams, want 3", len(exams))
+	}
+	names := func(exam Exam) []interface{} {
+		return []interface{}{exam.Subject, exam.Classes, exam.Rooms, exam.Teachers}
+	}
+	want := [][]interface{}{
+		{"SEW", []string{"5AHIT"}, []string{"H1102"}, []string{"BOR"}},
+		{"D", []string{"4BHIT", "3CHIT"}, []string{"L2201"}, []string{"HUD", "MAY"}},
+		{"", []string{}, []string{}, []string{}},
+	}
+	for i, exam := range exams {
+		if got := names(exam); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("%v has the names %v, want %v", exam.Name, got, want[i])
+		}
+	}
+}
+
+func TestExamsWithoutEntriesFetchNoMasterData(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	fake.handle("getExams2017", func(call fakeCall) (interface{}, *UntisError) {
+		return map[string]interface{}{"exams": []interface{}{}}, nil
+	})
+	client := newAuthenticatedClient(t, fake, "examining")
+	client.setSecret("JBSWY3DPEHPK3PXP")
+	exams, err := client.GetExams(day, day)
+	if err != nil || len(exams) != 0 {
+		t.Fatalf("got %v, %v, want no exams", exams, err)
+	}
+	for _, method := range masterDataMethods {
+		if calls := fake.callsOf(method); calls != 0 {
+			t.Errorf("%v was called %d times without exams", method, calls)
+		}
+	}
+	if _, err := newTestClient(t, fake, "anonymous").GetExams(day, day); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("got %v without a session, want ErrNotAuthenticated", err)
+	}
+}
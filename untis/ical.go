@@ -0,0 +1,68 @@
+package untis
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ICalProductID is the product identifier used in generated iCalendar feeds
+const ICalProductID = "-//Refundable//Huginn//DE"
+
+// ICalTimeFormat is the format of date-times in UTC as used in iCalendar feeds
+const ICalTimeFormat = "20060102T150405Z"
+
+// icalLineLength is the maximum length of a content line in octets before it has to be folded (RFC 5545 3.1)
+const icalLineLength = 75
+
+// LessonsToICal converts a list of lessons into an iCalendar (RFC 5545) feed containing one event per lesson
+func LessonsToICal(lessons []Lesson) ([]byte, error) {
+	var buf bytes.Buffer
+	stamp := DefaultClock.Now().UTC().Format(ICalTimeFormat)
+	writeICalLine(&buf, "BEGIN:VCALENDAR")
+	writeICalLine(&buf, "VERSION:2.0")
+	writeICalLine(&buf, "PRODID:"+ICalProductID)
+	writeICalLine(&buf, "CALSCALE:GREGORIAN")
+	writeICalLine(&buf, "METHOD:PUBLISH")
+	for _, lesson := range lessons {
+		if lesson.End.Before(lesson.Start) {
+			return nil, fmt.Errorf("lesson %d ends before it starts", lesson.ID)
+		}
+		summary := strings.TrimSpace(strings.Join(lesson.Subjects, ", ") + " " + strings.Join(lesson.Rooms, ", "))
+		description := "Lehrer: " + strings.Join(lesson.Teachers, ", ") + "\nKlassen: " + strings.Join(lesson.Classes, ", ")
+		writeICalLine(&buf, "BEGIN:VEVENT")
+		writeICalLine(&buf, fmt.Sprintf("UID:%d-%v@huginn", lesson.ID, lesson.Start.Format("20060102")))
+		writeICalLine(&buf, "DTSTAMP:"+stamp)
+		writeICalLine(&buf, "DTSTART:"+lesson.Start.UTC().Format(ICalTimeFormat))
+		writeICalLine(&buf, "DTEND:"+lesson.End.UTC().Format(ICalTimeFormat))
+		writeICalLine(&buf, "SUMMARY:"+escapeICalText(summary))
+		writeICalLine(&buf, "DESCRIPTION:"+escapeICalText(description))
+		if len(lesson.Rooms) > 0 {
+			writeICalLine(&buf, "LOCATION:"+escapeICalText(strings.Join(lesson.Rooms, ", ")))
+		}
+		writeICalLine(&buf, "END:VEVENT")
+	}
+	writeICalLine(&buf, "END:VCALENDAR")
+	return buf.Bytes(), nil
+}
+
+// escapeICalText escapes backslashes, semicolons, commas and newlines in an iCalendar text value
+func escapeICalText(text string) string {
+	replacer := strings.NewReplacer("\\", "\\\\", ";", "\\;", ",", "\\,", "\r\n", "\\n", "\n", "\\n")
+	return replacer.Replace(text)
+}
+
+// writeICalLine writes a content line terminated by CRLF and folds it if it exceeds icalLineLength octets
+func writeICalLine(buf *bytes.Buffer, line string) {
+	length := 0
+	for _, r := range line {
+		size := len(string(r))
+		if length+size > icalLineLength {
+			buf.WriteString("\r\n ")
+			length = 1
+		}
+		buf.WriteRune(r)
+		length += size
+	}
+	buf.WriteString("\r\n")
+}
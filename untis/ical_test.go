@@ -0,0 +1,93 @@
+package untis
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// unfoldICal splits an iCalendar feed into its content lines, joining folded lines again
+// it fails the test if a line isn't terminated by CRLF or longer than icalLineLength octets
+func unfoldICal(t *testing.T, feed []byte) []string {
+	t.Helper()
+	text := string(feed)
+	if !strings.HasSuffix(text, "\r\n") {
+		t.Fatal("the feed isn't terminated by CRLF")
+	}
+	lines := make([]string, 0)
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\r\n"), "\r\n") {
+		if len(line) > icalLineLength {
+			t.Errorf("line %q is longer than %d octets", line, icalLineLength)
+		}
+		if strings.HasPrefix(line, " ") && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestLessonsToICalCreatesOneEventPerLesson(t *testing.T) {
+	start := time.Date(2021, 3, 1, 8, 0, 0, 0, Location())
+	lessons := []Lesson{
+		{ID: 1, Start: start, End: start.Add(50 * time.Minute), Subjects: []string{"SEW"}, Rooms: []string{"H1102"}, Teachers: []string{"BOR"}, Classes: []string{"5AHIT"}},
+		{ID: 2, Start: start.Add(time.Hour), End: start.Add(110 * time.Minute), Subjects: []string{"D"}, Teachers: []string{"HUD"}},
+		{ID: 3, Start: start.Add(3 * time.Hour), End: start.Add(230 * time.Minute), Subjects: []string{strings.Repeat("Softwareentwicklung, ", 5)}, Rooms: []string{"L2201"}},
+	}
+	feed, err := LessonsToICal(lessons)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := unfoldICal(t, feed)
+	if lines[0] != "BEGIN:VCALENDAR" || lines[len(lines)-1] != "END:VCALENDAR" {
+		t.Fatalf("the feed isn't a single calendar: %q ... %q", lines[0], lines[len(lines)-1])
+	}
+	events, open := 0, false
+	starts := make([]string, 0)
+	for _, line := range lines[1 : len(lines)-1] {
+		name := strings.SplitN(line, ":", 2)[0]
+		switch {
+		case line == "BEGIN:VEVENT":
+			if open {
+				t.Fatal("an event begins inside another one")
+			}
+			open = true
+		case line == "END:VEVENT":
+			if !open {
+				t.Fatal("an event ends without beginning")
+			}
+			open = false
+			events++
+		case name == "DTSTART":
+			starts = append(starts, strings.TrimPrefix(line, "DTSTART:"))
+		case !strings.Contains(line, ":"):
+			t.Errorf("line %q has no value", line)
+		}
+	}
+	if open {
+		t.Error("the last event isn't ended")
+	}
+	if events != len(lessons) {
+		t.Errorf("got %d events, want one per lesson (%d)", events, len(lessons))
+	}
+	for i, lesson := range lessons {
+		if want := lesson.Start.UTC().Format(ICalTimeFormat); i >= len(starts) || starts[i] != want {
+			t.Errorf("event %d doesn't start at %v", i, want)
+		}
+	}
+}
+
+func TestLessonsToICalEscapesText(t *testing.T) {
+	start := time.Date(2021, 3, 1, 8, 0, 0, 0, Location())
+	feed, err := LessonsToICal([]Lesson{{ID: 1, Start: start, End: start.Add(time.Hour), Subjects: []string{"a;b"}, Rooms: []string{"c,d"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(feed), `SUMMARY:a\;b c\,d`) {
+		t.Errorf("semicolons and commas aren't escaped: %q", feed)
+	}
+	if _, err := LessonsToICal([]Lesson{{ID: 1, Start: start, End: start.Add(-time.Hour)}}); err == nil {
+		t.Error("a lesson ending before it starts was exported")
+	}
+}
@@ -0,0 +1,91 @@
+package untis
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultSessionTTL is the time a client may stay unused before it is evicted by the janitor by default
+const DefaultSessionTTL = time.Hour
+
+// usage tracks when a client was used the last time
+type usage struct {
+	// mutex guards last
+	mutex sync.Mutex
+	// last is the time a request was sent the last time or the client was created
+	last time.Time
+}
+
+// touch marks the client as used now
+func (client *Client) touch() {
+	now := client.now()
+	client.usage.mutex.Lock()
+	client.usage.last = now
+	client.usage.mutex.Unlock()
+}
+
+// LastUsed returns the time the client sent a request the last time or was created if it didn't send any yet
+func (client *Client) LastUsed() time.Time {
+	client.usage.mutex.Lock()
+	defer client.usage.mutex.Unlock()
+	return client.usage.last
+}
+
+// EvictIdleClients removes all active clients, whose last use is more than ttl ago, and closes their sessions
+// it returns the errors that occurred while closing the sessions
+func EvictIdleClients(ttl time.Duration) []error {
+	activeClientsMutex.Lock()
+	idle := make([]*Client, 0)
+	for _, clients := range activeClients {
+		for _, client := range clients {
+			if client.now().Sub(client.LastUsed()) > ttl {
+				idle = append(idle, client)
+			}
+		}
+	}
+	for _, client := range idle {
+		removeActiveClient(client)
+	}
+	activeClientsMutex.Unlock()
+	errs := make([]error, 0)
+	for _, client := range idle {
+		if !client.IsAuthenticated() {
+			continue
+		}
+		if err := client.Close(); err != nil {
+			client.ForceClose()
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// StartJanitor starts a goroutine calling EvictIdleClients with ttl every interval,
+// errors are logged; calling the returned function stops the janitor and waits for it to finish
+func StartJanitor(ttl, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				for _, err := range EvictIdleClients(ttl) {
+					log.Printf("level=warn msg=%q", "couldn't close idle untis session: "+err.Error())
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-finished
+		})
+	}
+}
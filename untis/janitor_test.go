@@ -0,0 +1,95 @@
+package untis
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// isActive returns whether the client is one of the active clients of its user
+func isActive(client *Client) bool {
+	activeClientsMutex.RLock()
+	defer activeClientsMutex.RUnlock()
+	for _, active := range activeClients[client.Username] {
+		if active == client {
+			return true
+		}
+	}
+	return false
+}
+
+func TestIdleClientsAreEvicted(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.handle("getLatestImportTime", fake.withSession(1600000000000))
+	clock := &fakeClock{now: time.Date(2021, 3, 1, 8, 0, 0, 0, time.UTC)}
+	idle := clockedClient(t, fake, "idle", clock)
+	busy := clockedClient(t, fake, "busy", clock)
+	if !idle.LastUsed().Equal(clock.Now()) {
+		t.Errorf("the client was last used at %v, want its authentication at %v", idle.LastUsed(), clock.Now())
+	}
+
+	clock.advance(30 * time.Minute)
+	if _, err := busy.GetLatestImportTime(); err != nil {
+		t.Fatal(err)
+	}
+	if !busy.LastUsed().Equal(clock.Now()) {
+		t.Errorf("the client was last used at %v, want its last request at %v", busy.LastUsed(), clock.Now())
+	}
+	clock.advance(31 * time.Minute)
+	if errs := EvictIdleClients(time.Hour); len(errs) > 0 {
+		t.Fatalf("evicting returned %v", errs)
+	}
+
+	if isActive(idle) {
+		t.Error("the idle client is still active")
+	}
+	if idle.IsAuthenticated() || fake.callsOf("logout") != 1 {
+		t.Error("the session of the idle client wasn't closed")
+	}
+	if !isActive(busy) || !busy.IsAuthenticated() {
+		t.Error("the client used within the ttl was evicted")
+	}
+	if fake.openSessions() != 1 {
+		t.Errorf("%d sessions are still open, want the one of the busy client", fake.openSessions())
+	}
+}
+
+func TestEvictionClosesSessionsFailingToLogOut(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.respondRaw("logout", fakeResponse{Status: http.StatusInternalServerError, ContentType: "text/plain", Body: "unavailable"})
+	clock := &fakeClock{now: time.Date(2021, 3, 1, 8, 0, 0, 0, time.UTC)}
+	client := clockedClient(t, fake, "unreachable", clock)
+	client.MaxAttempts = 1
+	clock.advance(2 * time.Hour)
+	if errs := EvictIdleClients(time.Hour); len(errs) != 1 {
+		t.Errorf("evicting returned %v, want the error of the failed logout", errs)
+	}
+	if isActive(client) || client.IsAuthenticated() {
+		t.Error("the client whose logout failed wasn't evicted and closed")
+	}
+}
+
+func TestJanitorEvictsIdleClientsUntilItIsStopped(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	clock := &fakeClock{now: time.Date(2021, 3, 1, 8, 0, 0, 0, time.UTC)}
+	first := clockedClient(t, fake, "janitored", clock)
+	clock.advance(2 * time.Hour)
+
+	stop := StartJanitor(time.Hour, time.Millisecond)
+	deadline := time.Now().Add(5 * time.Second)
+	for isActive(first) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if isActive(first) {
+		t.Fatal("the janitor didn't evict the idle client")
+	}
+	stop()
+	stop()
+
+	second := clockedClient(t, fake, "janitored", clock)
+	clock.advance(2 * time.Hour)
+	time.Sleep(20 * time.Millisecond)
+	if !isActive(second) {
+		t.Error("the stopped janitor evicted a client")
+	}
+}
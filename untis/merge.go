@@ -0,0 +1,79 @@
+package untis
+
+import (
+	"sort"
+	"time"
+)
+
+// MaxMergeGap is the longest break between two lessons that are still merged into one block by MergeConsecutiveLessons
+const MaxMergeGap = 5 * time.Minute
+
+// MergeConsecutiveLessons combines lessons on the same day with identical subjects, classes, teachers and rooms
+// (including the substituted ones), where one begins at most MaxMergeGap after the other one ends, into a single lesson spanning the whole block
+// (e.g. a double period), the lessons are returned sorted by their start and the given slice is left untouched
+func MergeConsecutiveLessons(lessons []Lesson) []Lesson {
+	sorted := make([]Lesson, len(lessons))
+	copy(sorted, lessons)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+	merged := make([]Lesson, 0, len(sorted))
+	for _, lesson := range sorted {
+		found := false
+		for i := len(merged) - 1; i >= 0; i-- {
+			if continues(merged[i], lesson) {
+				if lesson.End.After(merged[i].End) {
+					merged[i].End = lesson.End
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, lesson)
+		}
+	}
+	return merged
+}
+
+// continues checks whether next is the continuation of block, so both can be merged into one lesson
+func continues(block, next Lesson) bool {
+	gap := next.Start.Sub(block.End)
+	if gap < 0 || gap > MaxMergeGap {
+		return false
+	}
+	by, bm, bd := block.End.Date()
+	ny, nm, nd := next.Start.Date()
+	if by != ny || bm != nm || bd != nd {
+		return false
+	}
+	return block.Cancelled == next.Cancelled &&
+		block.Irregular == next.Irregular &&
+		block.SubstitutionText == next.SubstitutionText &&
+		block.LessonText == next.LessonText &&
+		block.Info == next.Info &&
+		sameNames(block.Subjects, next.Subjects) &&
+		sameNames(block.Classes, next.Classes) &&
+		sameNames(block.Teachers, next.Teachers) &&
+		sameNames(block.Rooms, next.Rooms) &&
+		sameNames(block.OriginalTeachers, next.OriginalTeachers) &&
+		sameNames(block.OriginalRooms, next.OriginalRooms)
+}
+
+// sameNames checks whether a and b contain the same names regardless of their order
+func sameNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, name := range a {
+		counts[name]++
+	}
+	for _, name := range b {
+		if counts[name] == 0 {
+			return false
+		}
+		counts[name]--
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package untis
+
+import (
+	"testing"
+	"time"
+)
+
+// sew returns a SEW lesson of 5AHIT with BOR in H1102 from start to end
+func sew(start, end time.Time) Lesson {
+	return Lesson{
+		Start:    start,
+		End:      end,
+		Subjects: []string{"SEW"},
+		Classes:  []string{"5AHIT"},
+		Teachers: []string{"BOR"},
+		Rooms:    []string{"H1102"},
+	}
+}
+
+// on returns hour:minute of day
+func on(hour, minute int) time.Time {
+	return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func TestMergeConsecutiveLessons(t *testing.T) {
+	inL2201 := sew(on(8, 50), on(9, 40))
+	inL2201.Rooms = []string{"L2201"}
+	withHUD := sew(on(8, 50), on(9, 40))
+	withHUD.Teachers = []string{"HUD"}
+	cancelled := sew(on(8, 50), on(9, 40))
+	cancelled.Cancelled = true
+	twoTeachers, swappedTeachers := sew(on(8, 0), on(8, 50)), sew(on(8, 50), on(9, 40))
+	twoTeachers.Teachers = []string{"BOR", "HUD"}
+	swappedTeachers.Teachers = []string{"HUD", "BOR"}
+	nextDay := sew(on(8, 50), on(9, 40))
+	nextDay.Start, nextDay.End = nextDay.Start.AddDate(0, 0, 1), nextDay.End.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name    string
+		lessons []Lesson
+		want    [][2]time.Time
+	}{
+		{"a double period", []Lesson{sew(on(8, 0), on(8, 50)), sew(on(8, 50), on(9, 40))}, [][2]time.Time{{on(8, 0), on(9, 40)}}},
+		{"a double period with a break", []Lesson{sew(on(8, 0), on(8, 50)), sew(on(8, 55), on(9, 45))}, [][2]time.Time{{on(8, 0), on(9, 45)}}},
+		{"a triple period out of order", []Lesson{sew(on(9, 40), on(10, 30)), sew(on(8, 0), on(8, 50)), sew(on(8, 50), on(9, 40))}, [][2]time.Time{{on(8, 0), on(10, 30)}}},
+		{"a gap", []Lesson{sew(on(8, 0), on(8, 50)), sew(on(8, 56), on(9, 46))}, [][2]time.Time{{on(8, 0), on(8, 50)}, {on(8, 56), on(9, 46)}}},
+		{"a free period in between", []Lesson{sew(on(8, 0), on(8, 50)), sew(on(9, 40), on(10, 30))}, [][2]time.Time{{on(8, 0), on(8, 50)}, {on(9, 40), on(10, 30)}}},
+		{"differing rooms", []Lesson{sew(on(8, 0), on(8, 50)), inL2201}, [][2]time.Time{{on(8, 0), on(8, 50)}, {on(8, 50), on(9, 40)}}},
+		{"differing teachers", []Lesson{sew(on(8, 0), on(8, 50)), withHUD}, [][2]time.Time{{on(8, 0), on(8, 50)}, {on(8, 50), on(9, 40)}}},
+		{"a cancelled continuation", []Lesson{sew(on(8, 0), on(8, 50)), cancelled}, [][2]time.Time{{on(8, 0), on(8, 50)}, {on(8, 50), on(9, 40)}}},
+		{"teachers in another order", []Lesson{twoTeachers, swappedTeachers}, [][2]time.Time{{on(8, 0), on(9, 40)}}},
+		{"the same periods on two days", []Lesson{sew(on(8, 0), on(8, 50)), nextDay}, [][2]time.Time{{on(8, 0), on(8, 50)}, {nextDay.Start, nextDay.End}}},
+		{"no lessons", nil, [][2]time.Time{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged := MergeConsecutiveLessons(test.lessons)
+			got := make([][2]time.Time, 0, len(merged))
+			for _, lesson := range merged {
+				got = append(got, [2]time.Time{lesson.Start, lesson.End})
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("got the lessons %v, want %v", got, test.want)
+			}
+			for i := range got {
+				if !got[i][0].Equal(test.want[i][0]) || !got[i][1].Equal(test.want[i][1]) {
+					t.Fatalf("got the lessons %v, want %v", got, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeConsecutiveLessonsLeavesItsInputUntouched(t *testing.T) {
+	lessons := []Lesson{sew(on(8, 50), on(9, 40)), sew(on(8, 0), on(8, 50))}
+	MergeConsecutiveLessons(lessons)
+	if !lessons[0].Start.Equal(on(8, 50)) || !lessons[0].End.Equal(on(9, 40)) || !lessons[1].End.Equal(on(8, 50)) {
+		t.Errorf("the given lessons were changed to %v", lessons)
+	}
+}
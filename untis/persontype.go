@@ -0,0 +1,37 @@
+package untis
+
+import "fmt"
+
+// PersonType is the type of an element (e.g. a teacher or a class) as used by the untis api
+type PersonType int
+
+const (
+	// PersonTypeClass is the type of classes
+	PersonTypeClass PersonType = 1
+	// PersonTypeTeacher is the type of teachers
+	PersonTypeTeacher PersonType = 2
+	// PersonTypeSubject is the type of subjects
+	PersonTypeSubject PersonType = 3
+	// PersonTypeRoom is the type of rooms
+	PersonTypeRoom PersonType = 4
+	// PersonTypeStudent is the type of students
+	PersonTypeStudent PersonType = 5
+)
+
+// String returns the name of the person type
+func (t PersonType) String() string {
+	switch t {
+	case PersonTypeClass:
+		return "class"
+	case PersonTypeTeacher:
+		return "teacher"
+	case PersonTypeSubject:
+		return "subject"
+	case PersonTypeRoom:
+		return "room"
+	case PersonTypeStudent:
+		return "student"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(t))
+	}
+}
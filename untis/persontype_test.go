@@ -0,0 +1,36 @@
+package untis
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+func TestPersonTypesMatchTheUntisValues(t *testing.T) {
+	types := []struct {
+		personType PersonType
+		value      int
+		name       string
+	}{
+		{PersonTypeClass, 1, "class"},
+		{PersonTypeTeacher, 2, "teacher"},
+		{PersonTypeSubject, 3, "subject"},
+		{PersonTypeRoom, 4, "room"},
+		{PersonTypeStudent, 5, "student"},
+	}
+	for _, test := range types {
+		if int(test.personType) != test.value {
+			t.Errorf("%v is %d, want %d", test.name, int(test.personType), test.value)
+		}
+		if test.personType.String() != test.name {
+			t.Errorf("%d is called %v, want %v", test.value, test.personType, test.name)
+		}
+		encoded, _ := json.Marshal(test.personType)
+		if string(encoded) != strconv.Itoa(test.value) {
+			t.Errorf("%v is sent to untis as %s, want %d", test.name, encoded, test.value)
+		}
+	}
+	if name := PersonType(9).String(); name != "unknown(9)" {
+		t.Errorf("an unknown type is called %v", name)
+	}
+}
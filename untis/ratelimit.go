@@ -0,0 +1,82 @@
+package untis
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimit is the amount of requests per second a client sends to the untis api at most by default
+const DefaultRateLimit = 5
+
+// DefaultRateBurst is the amount of requests a client may send at once before being limited by default
+const DefaultRateBurst = 10
+
+// rateLimiter is a token bucket limiting the requests a client sends to the untis api
+// it refills by the monotonic time instead of the Clock of the client, since it waits on real timers
+type rateLimiter struct {
+	// clock tells the time the bucket refills by, the monotonic time of time.Now if it is nil
+	clock Clock
+	// mutex guards tokens and last
+	mutex sync.Mutex
+	// tokens is the amount of requests that may currently be sent
+	tokens float64
+	// last is the time tokens was updated the last time, the zero time if the bucket wasn't used yet
+	last time.Time
+}
+
+// wait blocks until the rate limit of the client allows another request to be sent or ctx is done
+// if RateLimit isn't positive, requests aren't limited
+func (client *Client) wait(ctx context.Context) error {
+	if client.RateLimit <= 0 {
+		return nil
+	}
+	burst := float64(client.RateBurst)
+	if burst < 1 {
+		burst = 1
+	}
+	for {
+		delay := client.reserve(burst)
+		if delay == 0 {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token out of the bucket if one is available and returns 0
+// otherwise it returns the time until the next token becomes available
+func (client *Client) reserve(burst float64) time.Duration {
+	limiter := &client.limiter
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	now := limiter.now()
+	if limiter.last.IsZero() {
+		limiter.tokens = burst
+	} else if elapsed := now.Sub(limiter.last); elapsed > 0 {
+		limiter.tokens += elapsed.Seconds() * client.RateLimit
+		if limiter.tokens > burst {
+			limiter.tokens = burst
+		}
+	}
+	limiter.last = now
+	if limiter.tokens >= 1 {
+		limiter.tokens--
+		return 0
+	}
+	return time.Duration((1 - limiter.tokens) / client.RateLimit * float64(time.Second))
+}
+
+// now returns the current time using the clock of the limiter or time.Now if it has none
+func (limiter *rateLimiter) now() time.Time {
+	if limiter.clock != nil {
+		return limiter.clock.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,96 @@
+package untis
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock which only moves forward when it is told to
+type fakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+// Now returns the time the clock was set to
+func (clock *fakeClock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	return clock.now
+}
+
+// advance moves the clock forward by d
+func (clock *fakeClock) advance(d time.Duration) {
+	clock.mutex.Lock()
+	clock.now = clock.now.Add(d)
+	clock.mutex.Unlock()
+}
+
+func TestRateLimiterSpacesBursts(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2021, 3, 1, 8, 0, 0, 0, time.UTC)}
+	client := &Client{RateLimit: 2, RateBurst: 3}
+	client.limiter.clock = clock
+	burst := float64(client.RateBurst)
+	for i := 0; i < client.RateBurst; i++ {
+		if delay := client.reserve(burst); delay != 0 {
+			t.Fatalf("request %d of the burst was delayed by %v", i, delay)
+		}
+	}
+	if delay := client.reserve(burst); delay != 500*time.Millisecond {
+		t.Errorf("the request after the burst has to wait %v, want 500ms at 2 requests per second", delay)
+	}
+	clock.advance(250 * time.Millisecond)
+	if delay := client.reserve(burst); delay != 250*time.Millisecond {
+		t.Errorf("got a delay of %v after 250ms, want the remaining 250ms", delay)
+	}
+	clock.advance(250 * time.Millisecond)
+	if delay := client.reserve(burst); delay != 0 {
+		t.Errorf("got a delay of %v once a token was refilled", delay)
+	}
+	if delay := client.reserve(burst); delay != 500*time.Millisecond {
+		t.Errorf("got a delay of %v for the next request, want 500ms", delay)
+	}
+	clock.advance(time.Hour)
+	for i := 0; i < client.RateBurst; i++ {
+		if delay := client.reserve(burst); delay != 0 {
+			t.Fatalf("request %d after an idle hour was delayed by %v, want a full burst", i, delay)
+		}
+	}
+	if delay := client.reserve(burst); delay == 0 {
+		t.Error("the bucket refilled above the burst")
+	}
+}
+
+func TestRateLimiterStopsWaitingWhenTheContextIsDone(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2021, 3, 1, 8, 0, 0, 0, time.UTC)}
+	client := &Client{RateLimit: 0.001, RateBurst: 1, Clock: clock}
+	if err := client.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := client.wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("got %v, want the error of the context while waiting for a token", err)
+	}
+	if err := (&Client{}).wait(context.Background()); err != nil {
+		t.Errorf("a client without rate limit has to wait: %v", err)
+	}
+}
+
+func TestRateLimiterRefillsForClientsWithAFixedClock(t *testing.T) {
+	fixed := time.Date(2021, 3, 1, 8, 0, 0, 0, time.UTC)
+	client := &Client{RateLimit: DefaultRateLimit, RateBurst: DefaultRateBurst, Clock: ClockFunc(func() time.Time { return fixed })}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	for i := 0; i < DefaultRateBurst+2; i++ {
+		if err := client.wait(ctx); err != nil {
+			t.Fatalf("request %d didn't get a token: %v", i, err)
+		}
+	}
+	// the two requests after the burst wait for a token each at DefaultRateLimit requests per second
+	if elapsed := time.Since(start); elapsed < 2*time.Second/DefaultRateLimit-50*time.Millisecond {
+		t.Errorf("the requests after the burst were sent after %v, want them to be limited", elapsed)
+	}
+}
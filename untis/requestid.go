@@ -0,0 +1,18 @@
+package untis
+
+import "context"
+
+// requestIDKey is the context key the id of the request causing calls to the untis api is stored under
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the id of the request that causes calls to the untis api
+// failing calls sent with the returned context are logged tagged with the id
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request id stored in ctx by ContextWithRequestID or an empty string if there is none
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
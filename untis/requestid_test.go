@@ -0,0 +1,38 @@
+package untis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFailedCallsAreLoggedWithTheRequestID(t *testing.T) {
+	fake := newFakeUntis(t, map[string]fakeHandler{
+		"getTimetable": func(call fakeCall) (interface{}, *UntisError) {
+			return nil, &UntisError{Code: -7004, Message: "no allowed date"}
+		},
+	})
+	fake.serveMasterData()
+	client := newAuthenticatedClient(t, fake, "tagged")
+	var mutex sync.Mutex
+	lines := make([]string, 0)
+	client.Logf = func(format string, v ...interface{}) {
+		mutex.Lock()
+		lines = append(lines, fmt.Sprintf(format, v...))
+		mutex.Unlock()
+	}
+	ctx := ContextWithRequestID(context.Background(), "frontend-42")
+	if _, err := client.GetTimetableOfTeacherContext(ctx, day, day); err == nil {
+		t.Fatal("the failing call didn't return an error")
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, line := range lines {
+		if strings.Contains(line, "request_id=frontend-42 ") && strings.Contains(line, "untis_method=getTimetable") {
+			return
+		}
+	}
+	t.Errorf("no log line of the failed call is tagged with the request id, got %q", lines)
+}
@@ -0,0 +1,34 @@
+package untis
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"sync"
+)
+
+// rpcIDs generates the ids of the json-rpc requests of a client
+// the ids are consecutive, starting at a random offset so they don't repeat across clients and restarts,
+// which keeps them unique within a client even if requests are sent concurrently
+type rpcIDs struct {
+	// mutex guards seeded and last
+	mutex sync.Mutex
+	// seeded whether last was initialized with a random offset
+	seeded bool
+	// last is the id handed out the last time
+	last int
+}
+
+// nextRPCID returns a new json-rpc id, which wasn't used by the client before
+func (client *Client) nextRPCID() int {
+	client.rpcIDs.mutex.Lock()
+	defer client.rpcIDs.mutex.Unlock()
+	if !client.rpcIDs.seeded {
+		var seed [4]byte
+		if _, err := crand.Read(seed[:]); err == nil {
+			client.rpcIDs.last = int(binary.BigEndian.Uint32(seed[:]) >> 1)
+		}
+		client.rpcIDs.seeded = true
+	}
+	client.rpcIDs.last++
+	return client.rpcIDs.last
+}
@@ -0,0 +1,75 @@
+package untis
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestRPCIDsAreUniqueWithinAClientUnderConcurrency(t *testing.T) {
+	client := CreateClient("rpc-ids", "password")
+	defer client.DeleteClient()
+	const goroutines, perGoroutine = 16, 500
+	ids := make(chan int, goroutines*perGoroutine)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perGoroutine; j++ {
+				ids <- client.nextRPCID()
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+	seen := make(map[int]bool, goroutines*perGoroutine)
+	for id := range ids {
+		if id <= 0 {
+			t.Fatalf("generated the id %d, want positive ids", id)
+		}
+		if seen[id] {
+			t.Fatalf("the id %d was generated twice", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != goroutines*perGoroutine {
+		t.Errorf("generated %d ids, want %d", len(seen), goroutines*perGoroutine)
+	}
+}
+
+func TestRPCIDsStartAtARandomOffset(t *testing.T) {
+	first := CreateClient("rpc-offset-1", "password")
+	defer first.DeleteClient()
+	second := CreateClient("rpc-offset-2", "password")
+	defer second.DeleteClient()
+	a, b := first.nextRPCID(), second.nextRPCID()
+	if a == b {
+		// the offsets are random 31 bit numbers, two equal ones are practically impossible
+		t.Errorf("two clients started with the same id %d", a)
+	}
+	if next := first.nextRPCID(); next != a+1 {
+		t.Errorf("the id after %d is %d, want consecutive ids", a, next)
+	}
+}
+
+func TestConcurrentRequestsAreMatchedToTheirResponses(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.handle("getLatestImportTime", fake.withSession(1600000000000))
+	client := newAuthenticatedClient(t, fake, "concurrent-ids")
+	var wg sync.WaitGroup
+	errs := make(chan error, 32)
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetLatestImportTime(); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("a concurrent request failed: %v", err)
+	}
+}
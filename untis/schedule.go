@@ -0,0 +1,192 @@
+package untis
+
+import (
+	"sort"
+	"time"
+)
+
+// TimeOfDay represents a wall clock time on any day
+type TimeOfDay struct {
+	// Hour of the time (0-23)
+	Hour int
+	// Minute of the time (0-59)
+	Minute int
+}
+
+// Period represents a single lesson period of a bell schedule
+type Period struct {
+	// Nr is the number of the lesson
+	Nr int
+	// Start is the time the lesson starts at
+	Start TimeOfDay
+	// End is the time the lesson ends at
+	End TimeOfDay
+}
+
+// Schedule is the bell schedule of a school
+type Schedule struct {
+	// Periods are all lesson periods of the schedule
+	Periods []Period
+}
+
+// TGMSchedule is the bell schedule of the tgm
+var TGMSchedule = Schedule{
+	Periods: []Period{
+		{1, TimeOfDay{8, 0}, TimeOfDay{8, 50}},
+		{2, TimeOfDay{8, 50}, TimeOfDay{9, 40}},
+		{3, TimeOfDay{9, 55}, TimeOfDay{10, 45}},
+		{4, TimeOfDay{10, 45}, TimeOfDay{11, 35}},
+		{5, TimeOfDay{11, 45}, TimeOfDay{12, 35}},
+		{6, TimeOfDay{12, 35}, TimeOfDay{13, 25}},
+		{7, TimeOfDay{13, 25}, TimeOfDay{14, 15}},
+		{8, TimeOfDay{14, 20}, TimeOfDay{15, 10}},
+		{9, TimeOfDay{15, 10}, TimeOfDay{16, 0}},
+		{10, TimeOfDay{16, 0}, TimeOfDay{16, 50}},
+		{11, TimeOfDay{17, 0}, TimeOfDay{17, 45}},
+		{12, TimeOfDay{17, 45}, TimeOfDay{18, 30}},
+		{13, TimeOfDay{18, 30}, TimeOfDay{19, 15}},
+		{14, TimeOfDay{19, 25}, TimeOfDay{20, 10}},
+		{15, TimeOfDay{20, 15}, TimeOfDay{21, 0}},
+	},
+}
+
+// DefaultSchedule is the schedule used by GetLessonNrByStart and GetLessonNrByEnd
+var DefaultSchedule = TGMSchedule
+
+// LessonNrByStart computes the lesson number by its start time
+// it returns -1 if no period starts at this time
+func (schedule Schedule) LessonNrByStart(start time.Time) int {
+	return schedule.lookup(start, func(period Period) TimeOfDay {
+		return period.Start
+	})
+}
+
+// LessonNrByEnd computes the lesson number by its end time
+// it returns -1 if no period ends at this time
+func (schedule Schedule) LessonNrByEnd(end time.Time) int {
+	return schedule.lookup(end, func(period Period) TimeOfDay {
+		return period.End
+	})
+}
+
+// lookup searches the period whose time (selected by key) matches t
+// a period matches if hour and minute are equal, or if it is the only period of the schedule in this hour
+func (schedule Schedule) lookup(t time.Time, key func(Period) TimeOfDay) int {
+	nr := -1
+	inHour := 0
+	for _, period := range schedule.Periods {
+		k := key(period)
+		if k.Hour != t.Hour() {
+			continue
+		}
+		if k.Minute == t.Minute() {
+			return period.Nr
+		}
+		nr = period.Nr
+		inHour++
+	}
+	if inHour == 1 {
+		return nr
+	}
+	return -1
+}
+
+// on returns the time of day on the date of day in its location
+func (t TimeOfDay) on(day time.Time) time.Time {
+	year, month, date := day.Date()
+	return time.Date(year, month, date, t.Hour, t.Minute, 0, 0, day.Location())
+}
+
+// FreePeriods returns the periods of the schedule on the date of day (in its location), in which none of the
+// lessons (e.g. the timetable of a teacher) take place; cancelled lessons don't occupy a period
+// a period is occupied if a lesson overlaps it at least partly, the periods are returned in the order of the schedule
+func FreePeriods(lessons []Lesson, day time.Time, schedule Schedule) []Period {
+	free := make([]Period, 0, len(schedule.Periods))
+	for _, period := range schedule.Periods {
+		start, end := period.Start.on(day), period.End.on(day)
+		occupied := false
+		for _, lesson := range lessons {
+			if !lesson.Cancelled && lesson.Start.Before(end) && lesson.End.After(start) {
+				occupied = true
+				break
+			}
+		}
+		if !occupied {
+			free = append(free, period)
+		}
+	}
+	return free
+}
+
+// SumTeachingPeriods returns the amount of periods of the schedule the lessons (e.g. the timetable of a teacher) take
+// place in; cancelled lessons aren't counted
+// a lesson counts for every period it overlaps at least partly, so a merged double period counts twice, while a period
+// overlapped by multiple lessons only counts once
+func SumTeachingPeriods(lessons []Lesson, schedule Schedule) int {
+	type slot struct {
+		year  int
+		month time.Month
+		day   int
+		nr    int
+	}
+	taught := make(map[slot]bool)
+	for _, lesson := range lessons {
+		if lesson.Cancelled {
+			continue
+		}
+		year, month, day := lesson.Start.Date()
+		for _, period := range schedule.Periods {
+			start, end := period.Start.on(lesson.Start), period.End.on(lesson.Start)
+			if lesson.Start.Before(end) && lesson.End.After(start) {
+				taught[slot{year, month, day, period.Nr}] = true
+			}
+		}
+	}
+	return len(taught)
+}
+
+// SumTeachingDuration returns the wall clock time the lessons (e.g. the timetable of a teacher) take; cancelled
+// lessons aren't counted and overlapping lessons only count once for the time they overlap
+func SumTeachingDuration(lessons []Lesson) time.Duration {
+	intervals := make([]Lesson, 0, len(lessons))
+	for _, lesson := range lessons {
+		if !lesson.Cancelled && lesson.End.After(lesson.Start) {
+			intervals = append(intervals, lesson)
+		}
+	}
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].Start.Before(intervals[j].Start)
+	})
+	var total time.Duration
+	var blockStart, blockEnd time.Time
+	for i, lesson := range intervals {
+		if i > 0 && !lesson.Start.After(blockEnd) {
+			if lesson.End.After(blockEnd) {
+				blockEnd = lesson.End
+			}
+			continue
+		}
+		total += blockEnd.Sub(blockStart)
+		blockStart, blockEnd = lesson.Start, lesson.End
+	}
+	return total + blockEnd.Sub(blockStart)
+}
+
+// CurrentAndNextLesson returns the lesson taking place at now (its start is before or at now and its end after it)
+// and the next lesson starting after now, either is nil if there is none (e.g. in a break or after school)
+// cancelled lessons are skipped and consecutive lessons are merged first, so a double period is a single lesson
+func CurrentAndNextLesson(lessons []Lesson, now time.Time) (current, next *Lesson) {
+	for _, lesson := range MergeConsecutiveLessons(lessons) {
+		lesson := lesson
+		if lesson.Cancelled {
+			continue
+		}
+		if current == nil && !lesson.Start.After(now) && lesson.End.After(now) {
+			current = &lesson
+		}
+		if next == nil && lesson.Start.After(now) {
+			next = &lesson
+		}
+	}
+	return current, next
+}
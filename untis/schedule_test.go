@@ -0,0 +1,161 @@
+package untis
+
+import (
+	"testing"
+	"time"
+)
+
+// at returns the time of day on the day the lessons of the tests take place on
+func at(hour, minute int) time.Time {
+	return TimeOfDay{hour, minute}.on(day)
+}
+
+func TestLessonNrsOfEveryPeriodBoundary(t *testing.T) {
+	for _, period := range TGMSchedule.Periods {
+		if nr := TGMSchedule.LessonNrByStart(at(period.Start.Hour, period.Start.Minute)); nr != period.Nr {
+			t.Errorf("period starting at %02d:%02d is %d, want %d", period.Start.Hour, period.Start.Minute, nr, period.Nr)
+		}
+		if nr := TGMSchedule.LessonNrByEnd(at(period.End.Hour, period.End.Minute)); nr != period.Nr {
+			t.Errorf("period ending at %02d:%02d is %d, want %d", period.End.Hour, period.End.Minute, nr, period.Nr)
+		}
+	}
+	for _, unknown := range []time.Time{at(7, 0), at(8, 20), at(22, 0)} {
+		if nr := TGMSchedule.LessonNrByStart(unknown); nr != -1 {
+			t.Errorf("no period starts at %v, got %d", unknown.Format("15:04"), nr)
+		}
+	}
+	// 9:40 ends the second period, but nothing starts at 9:40 and the third period starts in the same hour
+	if nr := TGMSchedule.LessonNrByStart(at(9, 40)); nr != 3 {
+		t.Errorf("a lesson starting late in the hour of a single period start belongs to it, got %d", nr)
+	}
+}
+
+func TestLessonNrsOfAnotherSchedule(t *testing.T) {
+	// times matching no period exactly belong to the only period starting or ending in their hour, if there is one
+	schedule := Schedule{Periods: []Period{
+		{1, TimeOfDay{7, 30}, TimeOfDay{8, 15}},
+		{2, TimeOfDay{8, 15}, TimeOfDay{9, 0}},
+		{3, TimeOfDay{9, 10}, TimeOfDay{9, 55}},
+	}}
+	tests := []struct {
+		time       time.Time
+		start, end int
+	}{
+		{at(7, 30), 1, -1},
+		{at(8, 15), 2, 1},
+		{at(9, 0), 3, 2},
+		{at(9, 10), 3, -1},
+		{at(9, 55), 3, 3},
+		{at(10, 0), -1, -1},
+	}
+	for _, test := range tests {
+		if nr := schedule.LessonNrByStart(test.time); nr != test.start {
+			t.Errorf("start at %v is period %d, want %d", test.time.Format("15:04"), nr, test.start)
+		}
+		if nr := schedule.LessonNrByEnd(test.time); nr != test.end {
+			t.Errorf("end at %v is period %d, want %d", test.time.Format("15:04"), nr, test.end)
+		}
+	}
+}
+
+// periodNrs returns the numbers of the periods
+func periodNrs(periods []Period) []int {
+	nrs := make([]int, 0, len(periods))
+	for _, period := range periods {
+		nrs = append(nrs, period.Nr)
+	}
+	return nrs
+}
+
+// during returns a lesson on the day from start to end, given as hour and minute
+func during(startHour, startMinute, endHour, endMinute int) Lesson {
+	return Lesson{Start: at(startHour, startMinute), End: at(endHour, endMinute)}
+}
+
+func TestFreePeriods(t *testing.T) {
+	schedule := Schedule{Periods: []Period{
+		{1, TimeOfDay{8, 0}, TimeOfDay{8, 50}},
+		{2, TimeOfDay{8, 50}, TimeOfDay{9, 40}},
+		{3, TimeOfDay{9, 55}, TimeOfDay{10, 45}},
+		{4, TimeOfDay{10, 45}, TimeOfDay{11, 35}},
+	}}
+	cancelled := during(9, 55, 10, 45)
+	cancelled.Cancelled = true
+	tests := []struct {
+		name    string
+		lessons []Lesson
+		want    []int
+	}{
+		{"an all free day", nil, []int{1, 2, 3, 4}},
+		{"a partially filled day", []Lesson{during(8, 0, 8, 50), during(10, 45, 11, 35)}, []int{2, 3}},
+		{"a double period", []Lesson{during(8, 50, 10, 45)}, []int{1, 4}},
+		{"a lesson overlapping a period partly", []Lesson{during(9, 30, 9, 50)}, []int{1, 3, 4}},
+		{"a lesson in a break", []Lesson{during(9, 40, 9, 55)}, []int{1, 2, 3, 4}},
+		{"a cancelled lesson", []Lesson{during(8, 0, 8, 50), cancelled}, []int{2, 3, 4}},
+		{"a lesson on another day", []Lesson{{Start: at(8, 0).AddDate(0, 0, 1), End: at(8, 50).AddDate(0, 0, 1)}}, []int{1, 2, 3, 4}},
+		{"an all busy day", []Lesson{during(8, 0, 9, 40), during(9, 55, 11, 35)}, []int{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := periodNrs(FreePeriods(test.lessons, day, schedule))
+			if len(got) != len(test.want) {
+				t.Fatalf("the free periods are %v, want %v", got, test.want)
+			}
+			for i := range got {
+				if got[i] != test.want[i] {
+					t.Fatalf("the free periods are %v, want %v", got, test.want)
+				}
+			}
+		})
+	}
+	if free := FreePeriods(nil, day, TGMSchedule); len(free) != len(TGMSchedule.Periods) {
+		t.Errorf("%d periods of the tgm are free on a day without lessons, want all %d", len(free), len(TGMSchedule.Periods))
+	}
+}
+
+func TestSumTeachingPeriods(t *testing.T) {
+	cancelled := during(9, 55, 10, 45)
+	cancelled.Cancelled = true
+	nextDay := Lesson{Start: at(8, 0).AddDate(0, 0, 1), End: at(8, 50).AddDate(0, 0, 1)}
+	tests := []struct {
+		name    string
+		lessons []Lesson
+		want    int
+	}{
+		{"no lessons", nil, 0},
+		{"a single period", []Lesson{during(8, 0, 8, 50)}, 1},
+		{"a double period", []Lesson{during(8, 0, 9, 40)}, 2},
+		{"a double period of two lessons", []Lesson{during(8, 0, 8, 50), during(8, 50, 9, 40)}, 2},
+		{"a cancelled lesson", []Lesson{during(8, 0, 8, 50), cancelled}, 1},
+		{"overlapping lessons", []Lesson{during(8, 0, 8, 50), during(8, 0, 8, 50), during(8, 0, 9, 40)}, 2},
+		{"the same period on two days", []Lesson{during(8, 0, 8, 50), nextDay}, 2},
+		{"a lesson in a break", []Lesson{during(9, 40, 9, 55)}, 0},
+	}
+	for _, test := range tests {
+		if got := SumTeachingPeriods(test.lessons, TGMSchedule); got != test.want {
+			t.Errorf("%v: got %d periods, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSumTeachingDuration(t *testing.T) {
+	cancelled := during(9, 55, 10, 45)
+	cancelled.Cancelled = true
+	tests := []struct {
+		name    string
+		lessons []Lesson
+		want    time.Duration
+	}{
+		{"no lessons", nil, 0},
+		{"a single period", []Lesson{during(8, 0, 8, 50)}, 50 * time.Minute},
+		{"a double period", []Lesson{during(8, 0, 8, 50), during(8, 50, 9, 40)}, 100 * time.Minute},
+		{"a cancelled lesson", []Lesson{during(8, 0, 8, 50), cancelled}, 50 * time.Minute},
+		{"overlapping lessons", []Lesson{during(8, 30, 9, 40), during(8, 0, 8, 50), during(8, 10, 8, 20)}, 100 * time.Minute},
+		{"lessons with a break", []Lesson{during(9, 55, 10, 45), during(8, 50, 9, 40)}, 100 * time.Minute},
+	}
+	for _, test := range tests {
+		if got := SumTeachingDuration(test.lessons); got != test.want {
+			t.Errorf("%v: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,361 @@
+package untis
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// fakeCall is a json-rpc request received by a fakeUntis
+type fakeCall struct {
+	// ID is the id the call was sent with
+	ID string
+	// Method is the called method
+	Method string
+	// Params are the raw parameters of the call
+	Params json.RawMessage
+	// Session is the JSESSIONID cookie sent with the call, empty if there was none
+	Session string
+	// Path is the path of the url the call was sent to
+	Path string
+	// School is the school query parameter of the url the call was sent to
+	School string
+}
+
+// fakeResponse is a raw http response a fakeUntis answers a call with
+type fakeResponse struct {
+	// Status is the http status of the response
+	Status int
+	// ContentType is the content type of the response
+	ContentType string
+	// Body is the body of the response
+	Body string
+}
+
+// fakeHandler answers a call with its result or an untis error
+type fakeHandler func(call fakeCall) (interface{}, *UntisError)
+
+// fakeUntis is a json-rpc server behaving like the untis api: authenticate starts a session, logout ends it and
+// all other methods fail with NotAuthenticatedErrorCode unless they are called with a started session
+type fakeUntis struct {
+	*httptest.Server
+	// mutex guards the fields below
+	mutex sync.Mutex
+	// handlers answer the methods, authenticate and logout are answered by the server itself if they are missing
+	handlers map[string]fakeHandler
+	// calls counts the calls per method
+	calls map[string]int
+	// raw are the responses the next calls of a method are answered with instead of calling its handler
+	raw map[string][]fakeResponse
+	// mismatching are the methods answered with the id following the one of their call
+	mismatching map[string]bool
+	// sessions are the started sessions which didn't end yet
+	sessions map[string]bool
+	// started is the amount of sessions started so far
+	started int
+}
+
+// newFakeUntis starts a fakeUntis answering with the handlers, it is closed when the test finishes
+func newFakeUntis(t testing.TB, handlers map[string]fakeHandler) *fakeUntis {
+	t.Helper()
+	fake := &fakeUntis{handlers: handlers, calls: make(map[string]int), raw: make(map[string][]fakeResponse),
+		mismatching: make(map[string]bool), sessions: make(map[string]bool)}
+	if fake.handlers == nil {
+		fake.handlers = make(map[string]fakeHandler)
+	}
+	fake.Server = httptest.NewServer(http.HandlerFunc(fake.serve))
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+// serve answers a single json-rpc request
+func (fake *fakeUntis) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	req := struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}{}
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	id, _ := strconv.Unquote(string(req.ID))
+	if id == "" {
+		id = string(req.ID)
+	}
+	call := fakeCall{ID: id, Method: req.Method, Params: req.Params, Path: r.URL.Path, School: r.URL.Query().Get("school")}
+	if cookie, err := r.Cookie("JSESSIONID"); err == nil {
+		call.Session = cookie.Value
+	}
+	if raw, ok := fake.nextRaw(call.Method); ok {
+		w.Header().Set("Content-Type", raw.ContentType)
+		w.WriteHeader(raw.Status)
+		_, _ = w.Write([]byte(raw.Body))
+		return
+	}
+	result, untisErr := fake.answer(call)
+	if fake.mismatches(call.Method) {
+		sent, _ := strconv.Atoi(id)
+		id = strconv.Itoa(sent + 1)
+	}
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": id}
+	if untisErr != nil {
+		resp["error"] = untisErr
+	} else {
+		resp["result"] = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// respondRaw makes the fake answer the next calls of the method with the responses, one per call
+func (fake *fakeUntis) respondRaw(method string, responses ...fakeResponse) {
+	fake.mutex.Lock()
+	fake.raw[method] = append(fake.raw[method], responses...)
+	fake.mutex.Unlock()
+}
+
+// mismatchIDs makes the fake answer all calls of the method with the id following the one they were sent with
+func (fake *fakeUntis) mismatchIDs(method string) {
+	fake.mutex.Lock()
+	fake.mismatching[method] = true
+	fake.mutex.Unlock()
+}
+
+// mismatches checks whether the calls of the method are answered with another id
+func (fake *fakeUntis) mismatches(method string) bool {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	return fake.mismatching[method]
+}
+
+// nextRaw records the call of the method and returns the raw response it is answered with, if there is one left
+func (fake *fakeUntis) nextRaw(method string) (fakeResponse, bool) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	queue := fake.raw[method]
+	if len(queue) == 0 {
+		return fakeResponse{}, false
+	}
+	fake.calls[method]++
+	fake.raw[method] = queue[1:]
+	return queue[0], true
+}
+
+// answer records the call and answers it with its handler or the default behaviour of the method
+func (fake *fakeUntis) answer(call fakeCall) (interface{}, *UntisError) {
+	fake.mutex.Lock()
+	fake.calls[call.Method]++
+	handler, ok := fake.handlers[call.Method]
+	fake.mutex.Unlock()
+	if ok {
+		return handler(call)
+	}
+	switch call.Method {
+	case "authenticate":
+		return fake.startSession(), nil
+	case "logout":
+		fake.mutex.Lock()
+		delete(fake.sessions, call.Session)
+		fake.mutex.Unlock()
+		return nil, nil
+	}
+	return fake.withSession([]interface{}{})(call)
+}
+
+// withSession returns a handler answering with result if the call belongs to a started session
+// and with NotAuthenticatedErrorCode otherwise
+func (fake *fakeUntis) withSession(result interface{}) fakeHandler {
+	return func(call fakeCall) (interface{}, *UntisError) {
+		if !fake.hasSession(call.Session) {
+			return nil, &UntisError{Code: NotAuthenticatedErrorCode, Message: "not authenticated"}
+		}
+		return result, nil
+	}
+}
+
+// startSession starts a new session and returns the result of a successful authenticate call
+func (fake *fakeUntis) startSession() map[string]interface{} {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.started++
+	session := "session-" + strconv.Itoa(fake.started)
+	fake.sessions[session] = true
+	return map[string]interface{}{"sessionId": session, "personType": float64(PersonTypeTeacher), "personId": float64(7)}
+}
+
+// hasSession checks whether the session was started and didn't end yet
+func (fake *fakeUntis) hasSession(session string) bool {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	return fake.sessions[session]
+}
+
+// endSessions ends all started sessions, like untis does when they expire
+func (fake *fakeUntis) endSessions() {
+	fake.mutex.Lock()
+	fake.sessions = make(map[string]bool)
+	fake.mutex.Unlock()
+}
+
+// callsOf returns how often the method was called
+func (fake *fakeUntis) callsOf(method string) int {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	return fake.calls[method]
+}
+
+// openSessions returns the amount of started sessions which didn't end yet
+func (fake *fakeUntis) openSessions() int {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	return len(fake.sessions)
+}
+
+// newTestClient creates a client of the username for the fake without rate limits, retry delays and logging,
+// it is removed from the active clients when the test finishes
+func newTestClient(t testing.TB, fake *fakeUntis, username string) *Client {
+	t.Helper()
+	client := CreateClientForSchool(fake.URL, "test", username, "password")
+	client.HTTPClient = fake.Client()
+	client.RateLimit = 0
+	client.RetryDelay = 0
+	client.Logf = func(string, ...interface{}) {}
+	t.Cleanup(client.DeleteClient)
+	return client
+}
+
+// fakeTeachers, fakeRooms, fakeClasses and fakeSubjects are the master data of the school served by serveMasterData
+var (
+	fakeTeachers = []Teacher{
+		{ID: 1, Name: "BOR", ForeName: "Michael", LongName: "Borko", ForeColor: "000000", BackColor: "ff0000"},
+		{ID: 2, Name: "HUD", ForeName: "Anna", LongName: "van der Hude", ForeColor: "ffffff", BackColor: "0000ff"},
+		{ID: 3, Name: "MAY", ForeName: "Eva", LongName: "Mayer", ForeColor: "000000", BackColor: "00ff00"},
+	}
+	fakeRooms = []Room{
+		{ID: 10, Name: "H1102", LongName: "Hörsaal 1102", ForeColor: "000000", BackColor: "eeeeee"},
+		{ID: 11, Name: "L2201", LongName: "Labor 2201", ForeColor: "000000", BackColor: "dddddd"},
+	}
+	fakeClasses = []Class{
+		{ID: 20, Name: "5AHIT", LongName: "Informationstechnologie 5A", ForeColor: "000000", BackColor: "cccccc", Teacher1: 1},
+		{ID: 21, Name: "4BHIT", LongName: "Informationstechnologie 4B", ForeColor: "000000", BackColor: "bbbbbb", Teacher1: 2, Teacher2: 3},
+		{ID: 22, Name: "3CHIT", LongName: "Informationstechnologie 3C", ForeColor: "000000", BackColor: "aaaaaa"},
+	}
+	fakeSubjects = []Subject{
+		{ID: 30, Name: "SEW", LongName: "Softwareentwicklung", ForeColor: "000000", BackColor: "999999"},
+		{ID: 31, Name: "D", LongName: "Deutsch", ForeColor: "000000", BackColor: "888888"},
+	}
+)
+
+// serveMasterData makes the fake answer getTeachers, getRooms, getKlassen and getSubjects with the fake master data
+func (fake *fakeUntis) serveMasterData() {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.handlers["getTeachers"] = fake.withSession(fakeTeachers)
+	fake.handlers["getRooms"] = fake.withSession(fakeRooms)
+	fake.handlers["getKlassen"] = fake.withSession(fakeClasses)
+	fake.handlers["getSubjects"] = fake.withSession(fakeSubjects)
+}
+
+// handle makes the fake answer the method with the handler
+func (fake *fakeUntis) handle(method string, handler fakeHandler) {
+	fake.mutex.Lock()
+	fake.handlers[method] = handler
+	fake.mutex.Unlock()
+}
+
+// fakeLesson is a lesson of a getTimetable result on date (yyyymmdd) from start to end (hmm) with the elements by id
+type fakeLesson struct {
+	ID                    int
+	Date, Start, End      int
+	Code                  string
+	Classes, Teachers     []int
+	Subjects, Rooms       []int
+	OrgTeachers, OrgRooms map[int]int
+	SubstText             string
+	LsText, Info          string
+}
+
+// entry returns the lesson as untis encodes it in the result of getTimetable
+func (lesson fakeLesson) entry() map[string]interface{} {
+	elements := func(ids []int, org map[int]int) []map[string]int {
+		list := make([]map[string]int, 0, len(ids))
+		for _, id := range ids {
+			element := map[string]int{"id": id}
+			if orgID, ok := org[id]; ok {
+				element["orgid"] = orgID
+			}
+			list = append(list, element)
+		}
+		return list
+	}
+	entry := map[string]interface{}{
+		"id":        lesson.ID,
+		"date":      lesson.Date,
+		"startTime": lesson.Start,
+		"endTime":   lesson.End,
+		"kl":        elements(lesson.Classes, nil),
+		"te":        elements(lesson.Teachers, lesson.OrgTeachers),
+		"su":        elements(lesson.Subjects, nil),
+		"ro":        elements(lesson.Rooms, lesson.OrgRooms),
+	}
+	for key, value := range map[string]string{"code": lesson.Code, "substText": lesson.SubstText, "lstext": lesson.LsText, "info": lesson.Info} {
+		if value != "" {
+			entry[key] = value
+		}
+	}
+	return entry
+}
+
+// timetable returns the result of a getTimetable call containing the lessons
+func timetable(lessons ...fakeLesson) []map[string]interface{} {
+	entries := make([]map[string]interface{}, 0, len(lessons))
+	for _, lesson := range lessons {
+		entries = append(entries, lesson.entry())
+	}
+	return entries
+}
+
+// newAuthenticatedClient creates a test client like newTestClient and authenticates it at the fake
+func newAuthenticatedClient(t testing.TB, fake *fakeUntis, username string) *Client {
+	t.Helper()
+	client := newTestClient(t, fake, username)
+	if err := client.Authenticate(); err != nil {
+		t.Fatalf("couldn't authenticate: %v", err)
+	}
+	return client
+}
+
+// timetableRequest are the parameters of a getTimetable call
+type timetableRequest struct {
+	ID        int        `json:"id"`
+	Type      PersonType `json:"type"`
+	StartDate string     `json:"startDate"`
+	EndDate   string     `json:"endDate"`
+}
+
+// serveTimetable makes the fake answer getTimetable with the lessons, the returned function returns the parameters
+// of the getTimetable calls received so far
+func (fake *fakeUntis) serveTimetable(lessons ...fakeLesson) func() []timetableRequest {
+	var mutex sync.Mutex
+	requests := make([]timetableRequest, 0)
+	respond := fake.withSession(timetable(lessons...))
+	fake.handle("getTimetable", func(call fakeCall) (interface{}, *UntisError) {
+		var req timetableRequest
+		_ = json.Unmarshal(call.Params, &req)
+		mutex.Lock()
+		requests = append(requests, req)
+		mutex.Unlock()
+		return respond(call)
+	})
+	return func() []timetableRequest {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]timetableRequest(nil), requests...)
+	}
+}
@@ -0,0 +1,179 @@
+package untis
+
+import "context"
+
+// A client is shared by all concurrent requests of its user (see GetClient), so its session state and caches are
+// guarded by its mutex and its session is reference counted: every user of a shared client calls Acquire before
+// and Release after using it, the session is created by the first Acquire and only closed by the last Release
+
+// Acquire authenticates the client unless its session is already established, in which case the caller joins it
+// each successful Acquire has to be followed by a Release, which logs out once no one uses the session anymore
+// the password is used to authenticate if it is set, the secret otherwise
+func (client *Client) Acquire() error {
+	return client.AcquireContext(context.Background())
+}
+
+// AcquireContext is like Acquire but uses ctx for the requests sent to the untis api
+func (client *Client) AcquireContext(ctx context.Context) error {
+	client.lifecycle.Lock()
+	defer client.lifecycle.Unlock()
+	if !client.IsAuthenticated() {
+		var err error
+		if client.Password != "" {
+			err = client.authenticatePassword(ctx)
+		} else {
+			err = client.authenticateSecret(ctx)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	client.mutex.Lock()
+	client.users++
+	client.mutex.Unlock()
+	return nil
+}
+
+// Release ends the use of the session started by Acquire, the last user closes the session like Close
+// ErrNotAuthenticated is returned if no one acquired the session (e.g. because it was closed in the meantime)
+func (client *Client) Release() error {
+	return client.ReleaseContext(context.Background())
+}
+
+// ReleaseContext is like Release but uses ctx for the requests sent to the untis api
+func (client *Client) ReleaseContext(ctx context.Context) error {
+	client.lifecycle.Lock()
+	defer client.lifecycle.Unlock()
+	client.mutex.Lock()
+	if client.users == 0 {
+		client.mutex.Unlock()
+		return ErrNotAuthenticated
+	}
+	client.users--
+	last := client.users == 0
+	client.mutex.Unlock()
+	if !last || !client.IsAuthenticated() {
+		return nil
+	}
+	return client.close(ctx)
+}
+
+// reauthenticate authenticates again after untis reported the expired session as expired
+// requests failing concurrently with the same session authenticate only once: the others find the session replaced
+// and are replayed in the new one; ErrNotAuthenticated is returned if the session was closed in the meantime
+func (client *Client) reauthenticate(ctx context.Context, expired string) error {
+	client.lifecycle.Lock()
+	defer client.lifecycle.Unlock()
+	client.mutex.Lock()
+	current, authenticated := client.SessionID, client.Authenticated
+	client.mutex.Unlock()
+	if !authenticated {
+		return ErrNotAuthenticated
+	}
+	if current != expired {
+		return nil
+	}
+	client.expireSession()
+	if client.Password != "" {
+		return client.authenticatePassword(ctx)
+	}
+	return client.authenticateSecret(ctx)
+}
+
+// Users returns the amount of callers which acquired the session of the client and didn't release it yet
+func (client *Client) Users() int {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	return client.users
+}
+
+// IsAuthenticated returns whether the client currently has an authenticated session,
+// unlike reading Authenticated it is safe while other goroutines use the client
+func (client *Client) IsAuthenticated() bool {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	return client.Authenticated
+}
+
+// session returns the id of the current session, empty if there is none
+func (client *Client) session() string {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	return client.SessionID
+}
+
+// Person returns the id and type of the account the current session belongs to,
+// unlike reading PersonID and PersonType it is safe while other goroutines use the client
+func (client *Client) Person() (int, PersonType) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	return client.PersonID, client.PersonType
+}
+
+// secret returns the app secret of the account, empty if it isn't known
+func (client *Client) secret() string {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	return client.Secret
+}
+
+// setSecret stores the app secret of the account
+func (client *Client) setSecret(secret string) {
+	client.mutex.Lock()
+	client.Secret = secret
+	client.mutex.Unlock()
+}
+
+// setProfile stores the user data of the account
+func (client *Client) setProfile(profile *UserData) {
+	client.mutex.Lock()
+	client.Profile = profile
+	client.mutex.Unlock()
+}
+
+// startSession stores the session returned by a successful authentication
+func (client *Client) startSession(sessionID string, personType PersonType, personID int) {
+	client.mutex.Lock()
+	client.SessionID = sessionID
+	client.PersonType = personType
+	client.PersonID = personID
+	client.Authenticated = true
+	client.Closed = false
+	client.mutex.Unlock()
+}
+
+// expireSession forgets the current session after untis reported it as expired, the caches are kept
+func (client *Client) expireSession() {
+	client.mutex.Lock()
+	client.Authenticated = false
+	client.SessionID = ""
+	client.mutex.Unlock()
+}
+
+// teachers returns the cached teachers of the session, nil if they weren't fetched yet
+func (client *Client) teachers() map[int]Teacher {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	return client.cachedTeachers
+}
+
+// rooms returns the cached rooms of the session, nil if they weren't fetched yet
+func (client *Client) rooms() map[int]Room {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	return client.cachedRooms
+}
+
+// classes returns the cached classes of the session, nil if they weren't fetched yet
+func (client *Client) classes() map[int]Class {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	return client.cachedClasses
+}
+
+// subjects returns the cached subjects of the session, nil if they weren't fetched yet
+func (client *Client) subjects() map[int]Subject {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	return client.cachedSubjects
+}
@@ -0,0 +1,208 @@
+package untis
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAcquireSharesOneSessionBetweenConcurrentUsers(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.handlers["getLatestImportTime"] = fake.withSession(1600000000000)
+	client := newTestClient(t, fake, "shared")
+	client.AutoReauth = false
+
+	const users = 100
+	var wg sync.WaitGroup
+	errs := make(chan error, 3*users)
+	for i := 0; i < users; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			shared, ok := GetClient("shared")
+			if !ok {
+				t.Error("client isn't registered")
+				return
+			}
+			if err := shared.Acquire(); err != nil {
+				errs <- err
+				return
+			}
+			if _, err := shared.GetLatestImportTime(); err != nil {
+				errs <- err
+			}
+			if err := shared.Release(); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if client.IsAuthenticated() || client.Users() != 0 {
+		t.Errorf("authenticated = %v, users = %d after all users released the session", client.IsAuthenticated(), client.Users())
+	}
+	if fake.openSessions() != 0 {
+		t.Errorf("%d sessions weren't logged out", fake.openSessions())
+	}
+	if fake.callsOf("authenticate") != fake.callsOf("logout") {
+		t.Errorf("%d authenticate but %d logout calls", fake.callsOf("authenticate"), fake.callsOf("logout"))
+	}
+}
+
+func TestReleaseClosesOnlyAfterTheLastUser(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	client := newTestClient(t, fake, "release")
+	if err := client.Acquire(); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Acquire(); err != nil {
+		t.Fatal(err)
+	}
+	if fake.callsOf("authenticate") != 1 {
+		t.Fatalf("expected the second user to join the session, got %d authenticate calls", fake.callsOf("authenticate"))
+	}
+	if err := client.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if !client.IsAuthenticated() || fake.callsOf("logout") != 0 {
+		t.Fatal("the session was closed while it was still used")
+	}
+	if err := client.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if client.IsAuthenticated() || fake.callsOf("logout") != 1 {
+		t.Fatal("the last user didn't close the session")
+	}
+	if err := client.Release(); err != ErrNotAuthenticated {
+		t.Fatalf("expected ErrNotAuthenticated for an unmatched release, got %v", err)
+	}
+}
+
+func TestAcquireFailsWithoutJoining(t *testing.T) {
+	fake := newFakeUntis(t, map[string]fakeHandler{
+		"authenticate": func(fakeCall) (interface{}, *UntisError) {
+			return nil, &UntisError{Code: BadCredentialsErrorCode, Message: "bad credentials"}
+		},
+	})
+	client := newTestClient(t, fake, "rejected")
+	if err := client.Acquire(); !HasErrorCode(err, BadCredentialsErrorCode) {
+		t.Fatalf("expected the bad credentials error, got %v", err)
+	}
+	if client.Users() != 0 {
+		t.Fatalf("a failed acquire counts as %d users", client.Users())
+	}
+}
+
+func TestConcurrentRequestsReauthenticateOnceAfterTheSessionExpired(t *testing.T) {
+	const windows = 8
+	fake := newFakeUntis(t, nil)
+	var arrived sync.WaitGroup
+	arrived.Add(windows)
+	var mutex sync.Mutex
+	first := 0
+	fake.handlers["getTimetable"] = func(call fakeCall) (interface{}, *UntisError) {
+		mutex.Lock()
+		waiting := first < windows
+		first++
+		mutex.Unlock()
+		if waiting {
+			// all windows are requested in the expired session before any of them is answered
+			arrived.Done()
+			arrived.Wait()
+		}
+		return fake.withSession([]interface{}{})(call)
+	}
+	client := newTestClient(t, fake, "reauth")
+	client.Concurrency = windows
+	if err := client.Authenticate(); err != nil {
+		t.Fatal(err)
+	}
+	fake.endSessions()
+
+	start := time.Date(2021, 3, 1, 0, 0, 0, 0, Location())
+	lessons, err := client.GetTimetableOfTeacherRange(start, start.Add((windows-1)*24*time.Hour), 24*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lessons) != 0 {
+		t.Errorf("got %d lessons, want none", len(lessons))
+	}
+	if got := fake.callsOf("authenticate"); got != 2 {
+		t.Errorf("%d authenticate calls, want the initial one and a single re-authentication", got)
+	}
+	if got := fake.callsOf("getTimetable"); got != 2*windows {
+		t.Errorf("%d getTimetable calls, want every window requested and replayed once", got)
+	}
+	if !client.IsAuthenticated() || fake.openSessions() != 1 {
+		t.Errorf("authenticated = %v with %d open sessions, want the single new session", client.IsAuthenticated(), fake.openSessions())
+	}
+}
+
+func TestExpiredSessionsAreRenewedTransparently(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.handle("getLatestImportTime", fake.withSession(1600000000000))
+	client := newAuthenticatedClient(t, fake, "expired")
+	fake.endSessions()
+
+	if _, err := client.GetLatestImportTime(); err != nil {
+		t.Fatalf("got %v, want the replayed request to succeed", err)
+	}
+	if calls := fake.callsOf("authenticate"); calls != 2 {
+		t.Errorf("%d authenticate calls, want the initial one and a re-authentication", calls)
+	}
+	if calls := fake.callsOf("getLatestImportTime"); calls != 2 {
+		t.Errorf("%d getLatestImportTime calls, want the expired one and the replay", calls)
+	}
+	if client.session() != "session-2" {
+		t.Errorf("the client is in %q, want the new session", client.session())
+	}
+
+	client.AutoReauth = false
+	fake.endSessions()
+	if _, err := client.GetLatestImportTime(); !HasErrorCode(err, NotAuthenticatedErrorCode) {
+		t.Errorf("got %v without AutoReauth, want the error of the expired session", err)
+	}
+}
+
+func TestSessionValid(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.handle("getLatestImportTime", fake.withSession(1600000000000))
+	client := newAuthenticatedClient(t, fake, "pinged")
+	if !client.SessionValid() {
+		t.Fatal("the started session isn't valid")
+	}
+
+	fake.endSessions()
+	if client.SessionValid() {
+		t.Error("the expired session is valid")
+	}
+	if client.IsAuthenticated() {
+		t.Error("the client is still authenticated after its session expired")
+	}
+	if calls := fake.callsOf("authenticate"); calls != 1 {
+		t.Errorf("%d authenticate calls, want no re-authentication by the check", calls)
+	}
+	if client.SessionValid() {
+		t.Error("a client without a session is valid")
+	}
+	if calls := fake.callsOf("getLatestImportTime"); calls != 2 {
+		t.Errorf("%d getLatestImportTime calls, want none without a session", calls)
+	}
+}
+
+func TestSessionValidKeepsTheSessionIfUntisFailsOtherwise(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	client := newAuthenticatedClient(t, fake, "unreachable")
+	client.MaxAttempts = 1
+	fake.respondRaw("getLatestImportTime", fakeResponse{Status: http.StatusServiceUnavailable, ContentType: "text/plain", Body: "unavailable"})
+	if client.SessionValid() {
+		t.Error("the session is valid although untis couldn't be asked")
+	}
+	if !client.IsAuthenticated() {
+		t.Error("the session was dropped although untis didn't report it as expired")
+	}
+}
@@ -0,0 +1,118 @@
+package untis
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultSharedCacheTTL is the time teachers, rooms, classes and subjects stay in the shared cache by default
+const DefaultSharedCacheTTL = 6 * time.Hour
+
+// SharedCache whether teachers, rooms, classes and subjects are cached per school and shared between all clients
+// instead of being fetched by every client on its own, it has to be set before any client is used
+var SharedCache bool
+
+// SharedCacheTTL is the time master data stays in the shared cache, DefaultSharedCacheTTL is used if it isn't positive
+var SharedCacheTTL = DefaultSharedCacheTTL
+
+// sharedCaches maps the server and school to the master data cached for that school
+var sharedCaches = make(map[string]*schoolCache)
+
+// sharedCachesMutex guards sharedCaches against concurrent access
+var sharedCachesMutex sync.Mutex
+
+// schoolCache is the master data of one school shared between all clients logged into it
+type schoolCache struct {
+	// mutex guards importTime, entries and fetching
+	mutex sync.Mutex
+	// importTime is the latest import time reported by the untis api, the zero time if it wasn't requested yet
+	importTime time.Time
+	// entries maps the json-rpc method to the data it returned
+	entries map[string]sharedEntry
+	// fetching maps the json-rpc method to the mutex held while its data is fetched, so concurrent clients wait
+	// for the result of the same method while different methods are fetched at the same time
+	fetching map[string]*sync.Mutex
+}
+
+// sharedEntry is the result of a json-rpc method in the shared cache
+type sharedEntry struct {
+	// value is the data the method returned
+	value interface{}
+	// fetched is the time the data was fetched
+	fetched time.Time
+}
+
+// schoolCache returns the shared cache of the school the client is logged into, creating it if necessary
+func (client *Client) schoolCache() *schoolCache {
+	key := client.Server + "|" + client.School
+	sharedCachesMutex.Lock()
+	defer sharedCachesMutex.Unlock()
+	cache, ok := sharedCaches[key]
+	if !ok {
+		cache = &schoolCache{entries: make(map[string]sharedEntry), fetching: make(map[string]*sync.Mutex)}
+		sharedCaches[key] = cache
+	}
+	return cache
+}
+
+// loadShared returns the data of method from the shared cache of the school, fetch is called if SharedCache is
+// disabled, the data isn't cached yet or it is older than SharedCacheTTL
+func (client *Client) loadShared(method string, fetch func() (interface{}, error)) (interface{}, error) {
+	if !SharedCache {
+		return fetch()
+	}
+	ttl := SharedCacheTTL
+	if ttl <= 0 {
+		ttl = DefaultSharedCacheTTL
+	}
+	cache := client.schoolCache()
+	cache.mutex.Lock()
+	fetching, ok := cache.fetching[method]
+	if !ok {
+		fetching = &sync.Mutex{}
+		cache.fetching[method] = fetching
+	}
+	cache.mutex.Unlock()
+	fetching.Lock()
+	defer fetching.Unlock()
+	now := client.now()
+	cache.mutex.Lock()
+	entry, ok := cache.entries[method]
+	cache.mutex.Unlock()
+	if ok && now.Sub(entry.fetched) < ttl {
+		return entry.value, nil
+	}
+	value, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	cache.mutex.Lock()
+	cache.entries[method] = sharedEntry{value: value, fetched: now}
+	cache.mutex.Unlock()
+	return value, nil
+}
+
+// observeImportTime drops the shared cache of the school if the untis api reports an import newer than the last one seen
+func (client *Client) observeImportTime(importTime time.Time) {
+	if !SharedCache {
+		return
+	}
+	cache := client.schoolCache()
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if importTime.After(cache.importTime) {
+		cache.importTime = importTime
+		cache.entries = make(map[string]sharedEntry)
+	}
+}
+
+// invalidateShared drops the shared cache of the school the client is logged into
+func (client *Client) invalidateShared() {
+	if !SharedCache {
+		return
+	}
+	cache := client.schoolCache()
+	cache.mutex.Lock()
+	cache.entries = make(map[string]sharedEntry)
+	cache.mutex.Unlock()
+}
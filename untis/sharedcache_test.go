@@ -0,0 +1,172 @@
+package untis
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// sharingMasterData enables the shared cache with the ttl until the test finishes
+func sharingMasterData(t *testing.T, ttl time.Duration) {
+	t.Helper()
+	previous, previousTTL := SharedCache, SharedCacheTTL
+	SharedCache, SharedCacheTTL = true, ttl
+	t.Cleanup(func() { SharedCache, SharedCacheTTL = previous, previousTTL })
+}
+
+// clockedClient creates an authenticated client like newAuthenticatedClient which uses the clock
+func clockedClient(t *testing.T, fake *fakeUntis, username string, clock Clock) *Client {
+	t.Helper()
+	client := newTestClient(t, fake, username)
+	client.Clock = clock
+	if err := client.Authenticate(); err != nil {
+		t.Fatalf("couldn't authenticate: %v", err)
+	}
+	return client
+}
+
+// resolveBorko resolves the teacher with the id 1 and fails the test unless it is BOR
+func resolveBorko(t *testing.T, client *Client) {
+	t.Helper()
+	teachers, err := client.ResolveTeachers([]int{1})
+	if err != nil || len(teachers) != 1 || teachers[0] != "BOR" {
+		t.Fatalf("got %v, %v, want BOR", teachers, err)
+	}
+}
+
+func TestClientsOfASchoolShareOneFetch(t *testing.T) {
+	sharingMasterData(t, time.Hour)
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	first := newAuthenticatedClient(t, fake, "first")
+	second := newAuthenticatedClient(t, fake, "second")
+	resolveBorko(t, first)
+	resolveBorko(t, second)
+	if calls := fake.callsOf("getTeachers"); calls != 1 {
+		t.Errorf("two clients fetched the teachers %d times, want once", calls)
+	}
+}
+
+func TestConcurrentClientsWaitForTheSameFetch(t *testing.T) {
+	sharingMasterData(t, time.Hour)
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	clients := make([]*Client, 8)
+	for i := range clients {
+		clients[i] = newAuthenticatedClient(t, fake, "concurrent"+string(rune('a'+i)))
+	}
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		wg.Add(1)
+		go func(client *Client) {
+			defer wg.Done()
+			if _, err := client.ResolveTeachers([]int{1}); err != nil {
+				t.Error(err)
+			}
+		}(client)
+	}
+	wg.Wait()
+	if calls := fake.callsOf("getTeachers"); calls != 1 {
+		t.Errorf("%d concurrent clients fetched the teachers %d times, want once", len(clients), calls)
+	}
+}
+
+func TestSharedMasterDataIsFetchedAgainAfterItsTTL(t *testing.T) {
+	sharingMasterData(t, time.Hour)
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	clock := &fakeClock{now: time.Date(2021, 3, 1, 8, 0, 0, 0, time.UTC)}
+	resolveBorko(t, clockedClient(t, fake, "early", clock))
+	clock.advance(59 * time.Minute)
+	resolveBorko(t, clockedClient(t, fake, "fresh", clock))
+	if calls := fake.callsOf("getTeachers"); calls != 1 {
+		t.Fatalf("the teachers were fetched %d times within their ttl, want once", calls)
+	}
+	clock.advance(time.Minute)
+	resolveBorko(t, clockedClient(t, fake, "late", clock))
+	if calls := fake.callsOf("getTeachers"); calls != 2 {
+		t.Errorf("the teachers were fetched %d times after their ttl expired, want twice", calls)
+	}
+}
+
+func TestSharedMasterDataIsFetchedAgainAfterAnImport(t *testing.T) {
+	sharingMasterData(t, time.Hour)
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	fake.handle("getLatestImportTime", fake.withSession(int64(1617198300000)))
+	before := newAuthenticatedClient(t, fake, "before")
+	if _, err := before.GetLatestImportTime(); err != nil {
+		t.Fatal(err)
+	}
+	resolveBorko(t, before)
+	if _, err := before.GetLatestImportTime(); err != nil {
+		t.Fatal(err)
+	}
+	resolveBorko(t, newAuthenticatedClient(t, fake, "unchanged"))
+	if calls := fake.callsOf("getTeachers"); calls != 1 {
+		t.Fatalf("the teachers were fetched %d times without a new import, want once", calls)
+	}
+	fake.handle("getLatestImportTime", fake.withSession(int64(1617284700000)))
+	if _, err := before.GetLatestImportTime(); err != nil {
+		t.Fatal(err)
+	}
+	resolveBorko(t, newAuthenticatedClient(t, fake, "after"))
+	if calls := fake.callsOf("getTeachers"); calls != 2 {
+		t.Errorf("the teachers were fetched %d times after a new import, want twice", calls)
+	}
+}
+
+func TestMasterDataIsFetchedPerClientWithoutTheSharedCache(t *testing.T) {
+	previous := SharedCache
+	SharedCache = false
+	defer func() { SharedCache = previous }()
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	resolveBorko(t, newAuthenticatedClient(t, fake, "first"))
+	resolveBorko(t, newAuthenticatedClient(t, fake, "second"))
+	if calls := fake.callsOf("getTeachers"); calls != 2 {
+		t.Errorf("two clients fetched the teachers %d times, want twice", calls)
+	}
+}
+
+func TestLongLivedClientsPickUpMasterDataFetchedAgain(t *testing.T) {
+	sharingMasterData(t, time.Hour)
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	fake.handle("getLatestImportTime", fake.withSession(int64(1617198300000)))
+	clock := &fakeClock{now: time.Date(2021, 3, 1, 8, 0, 0, 0, time.UTC)}
+	client := clockedClient(t, fake, "long-lived", clock)
+	observer := clockedClient(t, fake, "observer", clock)
+	if _, err := client.GetLatestImportTime(); err != nil {
+		t.Fatal(err)
+	}
+	resolveBorko(t, client)
+	// renaming serves the teacher with the id 1 under another short name from now on
+	rename := func(name string) {
+		fake.handle("getTeachers", fake.withSession([]Teacher{{ID: 1, Name: name, ForeName: "Michael", LongName: "Borko"}}))
+	}
+	resolve := func(want string, calls int) {
+		t.Helper()
+		teachers, err := client.ResolveTeachers([]int{1})
+		if err != nil || len(teachers) != 1 || teachers[0] != want {
+			t.Errorf("got %v, %v, want %v", teachers, err, want)
+		}
+		if got := fake.callsOf("getTeachers"); got != calls {
+			t.Errorf("the teachers were fetched %d times, want %d", got, calls)
+		}
+	}
+
+	rename("MBO")
+	clock.advance(59 * time.Minute)
+	resolve("BOR", 1)
+	clock.advance(time.Minute)
+	resolve("MBO", 2)
+
+	rename("BRK")
+	fake.handle("getLatestImportTime", fake.withSession(int64(1617284700000)))
+	if _, err := observer.GetLatestImportTime(); err != nil {
+		t.Fatal(err)
+	}
+	resolve("BRK", 3)
+	resolve("BRK", 3)
+}
@@ -0,0 +1,240 @@
+package untis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SubstitutionType is the kind of a change of the timetable as used by the untis api
+type SubstitutionType string
+
+const (
+	// SubstitutionCancel is the type of cancelled lessons
+	SubstitutionCancel SubstitutionType = "cancel"
+	// SubstitutionTeacher is the type of lessons held by another teacher
+	SubstitutionTeacher SubstitutionType = "subst"
+	// SubstitutionRoomChange is the type of lessons moved to another room
+	SubstitutionRoomChange SubstitutionType = "rmchg"
+	// SubstitutionAdditional is the type of lessons added to the timetable
+	SubstitutionAdditional SubstitutionType = "add"
+	// SubstitutionShift is the type of lessons moved to another time
+	SubstitutionShift SubstitutionType = "shift"
+)
+
+// String returns the name of the substitution type
+func (t SubstitutionType) String() string {
+	switch t {
+	case SubstitutionCancel:
+		return "cancellation"
+	case SubstitutionTeacher:
+		return "substitution"
+	case SubstitutionRoomChange:
+		return "room change"
+	case SubstitutionAdditional:
+		return "additional lesson"
+	case SubstitutionShift:
+		return "shifted lesson"
+	default:
+		return fmt.Sprintf("unknown(%v)", string(t))
+	}
+}
+
+// Substitution represents a single change of the timetable out of the substitution plan
+type Substitution struct {
+	// Type is the kind of the change
+	Type SubstitutionType
+	// LessonID is the id of the affected lesson in untis
+	LessonID int
+	// Start is the start time of the affected lesson
+	Start time.Time
+	// End is the end time of the affected lesson
+	End time.Time
+	// Period is the number of the period of DefaultSchedule the lesson starts in, -1 if it starts in none
+	Period int
+	// ClassIDs are the ids of the affected classes
+	ClassIDs []int
+	// Classes are the names of the affected classes
+	Classes []string
+	// TeacherIDs are the ids of the teachers teaching the lesson
+	TeacherIDs []int
+	// Teachers are the names of the teachers teaching the lesson
+	Teachers []string
+	// OriginalTeacherIDs are the ids of the teachers substituted by Teachers
+	OriginalTeacherIDs []int
+	// OriginalTeachers are the names of the teachers substituted by Teachers
+	OriginalTeachers []string
+	// RoomIDs are the ids of the rooms the lesson takes place in
+	RoomIDs []int
+	// Rooms are the names of the rooms the lesson takes place in
+	Rooms []string
+	// OriginalRoomIDs are the ids of the rooms the lesson was moved from
+	OriginalRoomIDs []int
+	// OriginalRooms are the names of the rooms the lesson was moved from
+	OriginalRooms []string
+	// SubjectIDs are the ids of the subjects of the lesson
+	SubjectIDs []int
+	// Subjects are the names of the subjects of the lesson
+	Subjects []string
+	// Text is the text untis provides regarding the substitution
+	Text string
+}
+
+// substitutionEntry represents a single substitution as returned by getSubstitutions
+type substitutionEntry struct {
+	Type      string `json:"type"`
+	LsID      int    `json:"lsid"`
+	Date      int    `json:"date"`
+	StartTime int    `json:"startTime"`
+	EndTime   int    `json:"endTime"`
+	Txt       string `json:"txt"`
+	Kl        []struct {
+		ID int `json:"id"`
+	} `json:"kl"`
+	Te []struct {
+		ID    int `json:"id"`
+		OrgID int `json:"orgid"`
+	} `json:"te"`
+	Su []struct {
+		ID int `json:"id"`
+	} `json:"su"`
+	Ro []struct {
+		ID    int `json:"id"`
+		OrgID int `json:"orgid"`
+	} `json:"ro"`
+}
+
+// GetSubstitutions returns the substitution plan of the school in between start and end
+func (client *Client) GetSubstitutions(start, end time.Time) ([]Substitution, error) {
+	return client.GetSubstitutionsContext(context.Background(), start, end)
+}
+
+// GetSubstitutionsContext is like GetSubstitutions but uses ctx for the requests sent to the untis api
+func (client *Client) GetSubstitutionsContext(ctx context.Context, start, end time.Time) ([]Substitution, error) {
+	if !client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	params := map[string]interface{}{
+		"startDate":    formatUntisDate(start),
+		"endDate":      formatUntisDate(end),
+		"departmentId": 0,
+	}
+	respBody, id, err := client.sendRequest(ctx, "getSubstitutions", params)
+	if err != nil {
+		return nil, err
+	}
+	substitutions, err := parseSubstitutionsResponse(respBody, id)
+	if err != nil {
+		return nil, err
+	}
+	err = client.resolveSubstitutions(ctx, substitutions)
+	if err != nil {
+		return nil, err
+	}
+	return substitutions, nil
+}
+
+// parseSubstitutionsResponse decodes the body of a getSubstitutions response into substitutions
+// only the ids of classes, teachers, rooms and subjects are set, the names have to be resolved afterwards
+func parseSubstitutionsResponse(respBody []byte, expectedID int) ([]Substitution, error) {
+	r := struct {
+		JSONRPC string              `json:"jsonrpc"`
+		ID      string              `json:"id"`
+		Result  []substitutionEntry `json:"result"`
+	}{}
+	err := json.Unmarshal(respBody, &r)
+	if err != nil {
+		return nil, err
+	}
+	rid, _ := strconv.Atoi(r.ID)
+	if rid != expectedID {
+		return nil, &IDMismatchError{Method: "getSubstitutions", Expected: expectedID, Got: rid}
+	}
+	substitutions := make([]Substitution, 0, len(r.Result))
+	for _, s := range r.Result {
+		year, month, day := parseUntisDateInt(s.Date)
+		startHour, startMinute := parseUntisTime(s.StartTime)
+		endHour, endMinute := parseUntisTime(s.EndTime)
+		substitution := Substitution{
+			Type:               SubstitutionType(s.Type),
+			LessonID:           s.LsID,
+			Start:              time.Date(year, month, day, startHour, startMinute, 0, 0, Location()),
+			End:                time.Date(year, month, day, endHour, endMinute, 0, 0, Location()),
+			ClassIDs:           make([]int, 0),
+			TeacherIDs:         make([]int, 0),
+			OriginalTeacherIDs: make([]int, 0),
+			RoomIDs:            make([]int, 0),
+			OriginalRoomIDs:    make([]int, 0),
+			SubjectIDs:         make([]int, 0),
+			Text:               s.Txt,
+		}
+		substitution.Period = DefaultSchedule.LessonNrByStart(substitution.Start)
+		for _, kl := range s.Kl {
+			substitution.ClassIDs = append(substitution.ClassIDs, kl.ID)
+		}
+		// a teacher or room without an id was removed from the lesson, only the original one is known
+		for _, te := range s.Te {
+			if te.ID != 0 {
+				substitution.TeacherIDs = append(substitution.TeacherIDs, te.ID)
+			}
+			if te.OrgID != 0 {
+				substitution.OriginalTeacherIDs = append(substitution.OriginalTeacherIDs, te.OrgID)
+			}
+		}
+		for _, ro := range s.Ro {
+			if ro.ID != 0 {
+				substitution.RoomIDs = append(substitution.RoomIDs, ro.ID)
+			}
+			if ro.OrgID != 0 {
+				substitution.OriginalRoomIDs = append(substitution.OriginalRoomIDs, ro.OrgID)
+			}
+		}
+		for _, su := range s.Su {
+			substitution.SubjectIDs = append(substitution.SubjectIDs, su.ID)
+		}
+		substitutions = append(substitutions, substitution)
+	}
+	return substitutions, nil
+}
+
+// resolveSubstitutions resolves the names of the classes, teachers, rooms, subjects and original teachers and rooms of all given substitutions
+// like resolveLessons, nothing is fetched if there are no substitutions
+func (client *Client) resolveSubstitutions(ctx context.Context, substitutions []Substitution) error {
+	if len(substitutions) == 0 {
+		return nil
+	}
+	if err := client.warmResolvers(ctx); err != nil {
+		return err
+	}
+	for i := range substitutions {
+		substitution := &substitutions[i]
+		var err error
+		substitution.Classes, err = client.ResolveClassesContext(ctx, substitution.ClassIDs)
+		if err != nil {
+			return err
+		}
+		substitution.Teachers, err = client.ResolveTeachersContext(ctx, substitution.TeacherIDs)
+		if err != nil {
+			return err
+		}
+		substitution.OriginalTeachers, err = client.ResolveTeachersContext(ctx, substitution.OriginalTeacherIDs)
+		if err != nil {
+			return err
+		}
+		substitution.Rooms, err = client.ResolveRoomsContext(ctx, substitution.RoomIDs)
+		if err != nil {
+			return err
+		}
+		substitution.OriginalRooms, err = client.ResolveRoomsContext(ctx, substitution.OriginalRoomIDs)
+		if err != nil {
+			return err
+		}
+		substitution.Subjects, err = client.ResolveSubjectsContext(ctx, substitution.SubjectIDs)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
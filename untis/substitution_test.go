@@ -0,0 +1,132 @@
+package untis
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// substitutionPlan is a getSubstitutions result containing a substitution of every type
+const substitutionPlan = `[
+	{"type":"cancel","lsid":101,"date":20210301,"startTime":800,"endTime":850,"kl":[{"id":20}],"te":[{"id":1}],"su":[{"id":30}],"ro":[{"id":10}]},
+	{"type":"subst","lsid":102,"date":20210301,"startTime":850,"endTime":940,"kl":[{"id":20}],"te":[{"id":2,"orgid":1}],"su":[{"id":30}],"ro":[{"id":10}],"txt":"Vertretung"},
+	{"type":"rmchg","lsid":103,"date":20210301,"startTime":955,"endTime":1045,"kl":[{"id":21}],"te":[{"id":2}],"su":[{"id":31}],"ro":[{"id":11,"orgid":10}]},
+	{"type":"add","lsid":104,"date":20210302,"startTime":1045,"endTime":1135,"kl":[{"id":21},{"id":22}],"te":[{"id":3}],"su":[{"id":31}],"ro":[]},
+	{"type":"shift","lsid":105,"date":20210302,"startTime":2200,"endTime":2250,"kl":[{"id":22}],"te":[{"orgid":3}],"su":[],"ro":[{"orgid":11}]}
+]`
+
+func TestParseSubstitutionsResponse(t *testing.T) {
+	var plan interface{}
+	if err := json.Unmarshal([]byte(substitutionPlan), &plan); err != nil {
+		t.Fatal(err)
+	}
+	substitutions, err := parseSubstitutionsResponse(rpcResponse(t, 5, plan), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Substitution{
+		{Type: SubstitutionCancel, LessonID: 101, Start: at(8, 0), End: at(8, 50), Period: 1,
+			ClassIDs: []int{20}, TeacherIDs: []int{1}, OriginalTeacherIDs: []int{}, RoomIDs: []int{10}, OriginalRoomIDs: []int{}, SubjectIDs: []int{30}},
+		{Type: SubstitutionTeacher, LessonID: 102, Start: at(8, 50), End: at(9, 40), Period: 2, Text: "Vertretung",
+			ClassIDs: []int{20}, TeacherIDs: []int{2}, OriginalTeacherIDs: []int{1}, RoomIDs: []int{10}, OriginalRoomIDs: []int{}, SubjectIDs: []int{30}},
+		{Type: SubstitutionRoomChange, LessonID: 103, Start: at(9, 55), End: at(10, 45), Period: 3,
+			ClassIDs: []int{21}, TeacherIDs: []int{2}, OriginalTeacherIDs: []int{}, RoomIDs: []int{11}, OriginalRoomIDs: []int{10}, SubjectIDs: []int{31}},
+		{Type: SubstitutionAdditional, LessonID: 104, Start: at(10, 45).AddDate(0, 0, 1), End: at(11, 35).AddDate(0, 0, 1), Period: 4,
+			ClassIDs: []int{21, 22}, TeacherIDs: []int{3}, OriginalTeacherIDs: []int{}, RoomIDs: []int{}, OriginalRoomIDs: []int{}, SubjectIDs: []int{31}},
+		{Type: SubstitutionShift, LessonID: 105, Start: at(22, 0).AddDate(0, 0, 1), End: at(22, 50).AddDate(0, 0, 1), Period: -1,
+			ClassIDs: []int{22}, TeacherIDs: []int{}, OriginalTeacherIDs: []int{3}, RoomIDs: []int{}, OriginalRoomIDs: []int{11}, SubjectIDs: []int{}},
+	}
+	if len(substitutions) != len(want) {
+		t.Fatalf("got %d substitutions, want %d", len(substitutions), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(substitutions[i], want[i]) {
+			t.Errorf("substitution %d is %+v, want %+v", i, substitutions[i], want[i])
+		}
+	}
+
+	if _, err := parseSubstitutionsResponse(rpcResponse(t, 6, plan), 5); !errors.Is(err, ErrIDMismatch) {
+		t.Errorf("got %v for a response to another request, want ErrIDMismatch", err)
+	}
+}
+
+func TestGetSubstitutionsResolvesTheNames(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	var plan interface{}
+	if err := json.Unmarshal([]byte(substitutionPlan), &plan); err != nil {
+		t.Fatal(err)
+	}
+	var params struct {
+		StartDate    string `json:"startDate"`
+		EndDate      string `json:"endDate"`
+		DepartmentID *int   `json:"departmentId"`
+	}
+	respond := fake.withSession(plan)
+	fake.handle("getSubstitutions", func(call fakeCall) (interface{}, *UntisError) {
+		_ = json.Unmarshal(call.Params, &params)
+		return respond(call)
+	})
+	client := newAuthenticatedClient(t, fake, "substitutions")
+
+	substitutions, err := client.GetSubstitutions(day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.StartDate != "20210301" || params.EndDate != "20210302" || params.DepartmentID == nil || *params.DepartmentID != 0 {
+		t.Errorf("the substitutions were requested with %+v, want the range in all departments", params)
+	}
+	if len(substitutions) != 5 {
+		t.Fatalf("got %d substitutions, want 5", len(substitutions))
+	}
+	names := func(s Substitution) [][]string {
+		return [][]string{s.Classes, s.Teachers, s.OriginalTeachers, s.Rooms, s.OriginalRooms, s.Subjects}
+	}
+	want := [][][]string{
+		{{"5AHIT"}, {"BOR"}, {}, {"H1102"}, {}, {"SEW"}},
+		{{"5AHIT"}, {"HUD"}, {"BOR"}, {"H1102"}, {}, {"SEW"}},
+		{{"4BHIT"}, {"HUD"}, {}, {"L2201"}, {"H1102"}, {"D"}},
+		{{"4BHIT", "3CHIT"}, {"MAY"}, {}, {}, {}, {"D"}},
+		{{"3CHIT"}, {}, {"MAY"}, {}, {"L2201"}, {}},
+	}
+	for i, substitution := range substitutions {
+		if got := names(substitution); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("the %v has the names %v, want %v", substitution.Type, got, want[i])
+		}
+	}
+}
+
+func TestSubstitutionsWithoutEntriesFetchNoMasterData(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	client := newAuthenticatedClient(t, fake, "substitutions")
+	substitutions, err := client.GetSubstitutions(day, day)
+	if err != nil || len(substitutions) != 0 {
+		t.Fatalf("got %v, %v, want no substitutions", substitutions, err)
+	}
+	for _, method := range masterDataMethods {
+		if calls := fake.callsOf(method); calls != 0 {
+			t.Errorf("%v was called %d times for an empty plan", method, calls)
+		}
+	}
+	if _, err := newTestClient(t, fake, "anonymous").GetSubstitutions(day, day); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("got %v without a session, want ErrNotAuthenticated", err)
+	}
+}
+
+func TestSubstitutionTypeString(t *testing.T) {
+	tests := map[SubstitutionType]string{
+		SubstitutionCancel:     "cancellation",
+		SubstitutionTeacher:    "substitution",
+		SubstitutionRoomChange: "room change",
+		SubstitutionAdditional: "additional lesson",
+		SubstitutionShift:      "shifted lesson",
+		"free":                 "unknown(free)",
+	}
+	for kind, want := range tests {
+		if got := kind.String(); got != want {
+			t.Errorf("%q is named %q, want %q", string(kind), got, want)
+		}
+	}
+}
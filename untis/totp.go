@@ -0,0 +1,38 @@
+package untis
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// OTPPeriod is the time step a one-time password generated from an app secret is valid for (RFC 6238)
+const OTPPeriod = 30 * time.Second
+
+// OTPDigits is the amount of digits of a one-time password generated from an app secret
+const OTPDigits = 6
+
+// GenerateOTP computes the time-based one-time password (RFC 6238, HMAC-SHA1) of the base32 encoded secret at t
+func GenerateOTP(secret string, t time.Time) (string, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.TrimRight(secret, "="))
+	if err != nil {
+		return "", fmt.Errorf("invalid secret: %w", err)
+	}
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(t.Unix()/int64(OTPPeriod/time.Second)))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < OTPDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", OTPDigits, code%mod), nil
+}
@@ -0,0 +1,59 @@
+package untis
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the base32 encoded secret of the test vectors of RFC 6238 ("12345678901234567890")
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateOTPMatchesTheRFCVectors(t *testing.T) {
+	// the last six digits of the SHA1 vectors of RFC 6238 appendix B
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, want := range vectors {
+		got, err := GenerateOTP(rfc6238Secret, time.Unix(unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("GenerateOTP at %d = %v, want %v", unix, got, want)
+		}
+	}
+	if got, err := GenerateOTP("gezd gnbv gy3t qojq gezd gnbv gy3t qojq", time.Unix(59, 0)); err != nil || got != "287082" {
+		t.Errorf("got %v, %v for a lowercase secret with spaces, want 287082", got, err)
+	}
+	if _, err := GenerateOTP("not base32!", time.Unix(59, 0)); err == nil {
+		t.Error("got no error for an invalid secret")
+	}
+}
+
+func TestAuthenticateWithSecretSendsTheOTPOfTheClock(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	var params struct {
+		User       string `json:"user"`
+		OTP        string `json:"otp"`
+		ClientTime int64  `json:"clientTime"`
+	}
+	fake.handle("authenticate", func(call fakeCall) (interface{}, *UntisError) {
+		_ = json.Unmarshal(call.Params, &params)
+		return fake.startSession(), nil
+	})
+	client := newTestClient(t, fake, "secret")
+	client.Password = ""
+	client.setSecret(rfc6238Secret)
+	client.Clock = ClockFunc(func() time.Time { return time.Unix(1111111109, 0) })
+	if err := client.AuthenticateSecret(); err != nil {
+		t.Fatal(err)
+	}
+	if params.User != "secret" || params.OTP != "081804" || params.ClientTime != 1111111109000 {
+		t.Errorf("authenticated with %+v, want the otp of the clock's time", params)
+	}
+}
@@ -0,0 +1,60 @@
+package untis
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// NewTransport creates a transport for requests to the untis api like the default one, which sends them through
+// the proxy at proxyURL and additionally trusts the pem encoded certificates of caPEM
+// the proxy of the environment (HTTPS_PROXY, NO_PROXY, ...) is used if proxyURL is empty and only the system
+// certificates are trusted if caPEM is empty
+func NewTransport(proxyURL string, caPEM []byte) (*http.Transport, error) {
+	transport := newTransport()
+	transport.Proxy = http.ProxyFromEnvironment
+	if proxyURL != "" {
+		proxy, err := url.Parse(proxyURL)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy url %q", proxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	if len(caPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in the provided root CAs")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return transport, nil
+}
+
+// ConfigureTransport makes the client send its requests through the proxy at proxyURL and trust the certificates of
+// caPEM additionally, as described by NewTransport
+func (client *Client) ConfigureTransport(proxyURL string, caPEM []byte) error {
+	transport, err := NewTransport(proxyURL, caPEM)
+	if err != nil {
+		return err
+	}
+	client.HTTPClient = &http.Client{Timeout: DefaultTimeout, Transport: transport}
+	return nil
+}
+
+// ConfigureDefaultTransport makes all clients not providing their own http client send their requests through the
+// proxy at proxyURL and trust the certificates of caPEM additionally, as described by NewTransport
+// it has to be called before any client is created
+func ConfigureDefaultTransport(proxyURL string, caPEM []byte) error {
+	transport, err := NewTransport(proxyURL, caPEM)
+	if err != nil {
+		return err
+	}
+	defaultTransport = transport
+	defaultHTTPClient = &http.Client{Timeout: DefaultTimeout, Transport: defaultTransport}
+	return nil
+}
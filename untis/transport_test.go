@@ -0,0 +1,191 @@
+package untis
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// trustedServer starts a tls server until the test finishes and returns it with its certificate pem encoded
+func trustedServer(t *testing.T) (*httptest.Server, []byte) {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	// the handshakes of untrusting clients fail on purpose
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+}
+
+// proxyOf returns the proxy the transport sends a request to the url through
+func proxyOf(t *testing.T, transport *http.Transport, rawURL string) *url.URL {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, rawURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy, err := transport.Proxy(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proxy
+}
+
+func TestNewTransportUsesTheProxy(t *testing.T) {
+	var mutex sync.Mutex
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		proxied = append(proxied, r.URL.String())
+		mutex.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer proxy.Close()
+
+	transport, err := NewTransport(proxy.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := proxyOf(t, transport, "https://neilo.webuntis.com/WebUntis/jsonrpc.do"); got == nil || got.String() != proxy.URL {
+		t.Errorf("requests are sent through the proxy %v, want %v", got, proxy.URL)
+	}
+	res, err := (&http.Client{Transport: transport}).Get("http://neilo.webuntis.invalid/WebUntis/jsonrpc.do?school=tgm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(proxied) != 1 || proxied[0] != "http://neilo.webuntis.invalid/WebUntis/jsonrpc.do?school=tgm" {
+		t.Errorf("the proxy received %v, want the request to untis", proxied)
+	}
+	if transport.MaxIdleConnsPerHost != DefaultMaxIdleConnsPerHost || transport.IdleConnTimeout != DefaultIdleConnTimeout {
+		t.Error("the transport doesn't pool connections like the default one")
+	}
+}
+
+func TestNewTransportDefaultsToTheProxyOfTheEnvironment(t *testing.T) {
+	transport, err := NewTransport("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transport.Proxy == nil || reflect.ValueOf(transport.Proxy).Pointer() != reflect.ValueOf(http.ProxyFromEnvironment).Pointer() {
+		t.Error("the transport doesn't use the proxy of the environment")
+	}
+	if transport.TLSClientConfig != nil && transport.TLSClientConfig.RootCAs != nil {
+		t.Error("the transport trusts additional root CAs without any being provided")
+	}
+}
+
+func TestNewTransportTrustsTheRootCAs(t *testing.T) {
+	server, ca := trustedServer(t)
+	transport, err := NewTransport("", ca)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transport.TLSClientConfig == nil || transport.TLSClientConfig.RootCAs == nil {
+		t.Fatal("the transport doesn't have a custom CA pool")
+	}
+	res, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("the server signed by the root CA isn't trusted: %v", err)
+	}
+	res.Body.Close()
+
+	untrusted, err := NewTransport("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, err := (&http.Client{Transport: untrusted}).Get(server.URL); err == nil {
+		res.Body.Close()
+		t.Error("the server is trusted without its root CA")
+	}
+}
+
+func TestNewTransportRejectsInvalidConfigurations(t *testing.T) {
+	tests := []struct {
+		name     string
+		proxyURL string
+		caPEM    []byte
+	}{
+		{"a proxy without a scheme", "proxy.tgm.ac.at:3128", nil},
+		{"a proxy without a host", "http://", nil},
+		{"an unparsable proxy", "http://proxy.tgm.ac.at:%zz", nil},
+		{"root CAs without certificates", "", []byte("not a certificate")},
+	}
+	for _, test := range tests {
+		if transport, err := NewTransport(test.proxyURL, test.caPEM); err == nil {
+			t.Errorf("%v: got the transport %v, want an error", test.name, transport)
+		}
+	}
+}
+
+func TestConfigureTransport(t *testing.T) {
+	_, ca := trustedServer(t)
+	client := CreateClientForSchool("https://neilo.webuntis.com", "tgm", "proxied", "password")
+	defer client.DeleteClient()
+	if err := client.ConfigureTransport("http://proxy.tgm.ac.at:3128", ca); err != nil {
+		t.Fatal(err)
+	}
+	if client.HTTPClient == nil || client.HTTPClient.Timeout != DefaultTimeout {
+		t.Fatal("the client doesn't have its own http client with the default timeout")
+	}
+	transport := client.HTTPClient.Transport.(*http.Transport)
+	if got := proxyOf(t, transport, client.Server); got == nil || got.Host != "proxy.tgm.ac.at:3128" {
+		t.Errorf("the client sends its requests through %v, want the configured proxy", got)
+	}
+	if transport.TLSClientConfig == nil || transport.TLSClientConfig.RootCAs == nil {
+		t.Error("the client doesn't trust the root CAs")
+	}
+	if transport == defaultTransport {
+		t.Error("configuring a client changed the default transport")
+	}
+
+	before := client.HTTPClient
+	if err := client.ConfigureTransport("proxy", nil); err == nil || client.HTTPClient != before {
+		t.Errorf("got %v for an invalid proxy, want an error leaving the http client unchanged", err)
+	}
+}
+
+func TestConfigureDefaultTransport(t *testing.T) {
+	previousTransport, previousClient := defaultTransport, defaultHTTPClient
+	t.Cleanup(func() { defaultTransport, defaultHTTPClient = previousTransport, previousClient })
+	server, ca := trustedServer(t)
+
+	if err := ConfigureDefaultTransport("http://proxy.tgm.ac.at:3128", ca); err != nil {
+		t.Fatal(err)
+	}
+	client := CreateClient("default-transport", "password")
+	defer client.DeleteClient()
+	if client.httpClient() != defaultHTTPClient || defaultHTTPClient.Transport != defaultTransport || defaultTransport == previousTransport {
+		t.Fatal("new clients don't use the configured default transport")
+	}
+	if got := proxyOf(t, defaultTransport, client.Server); got == nil || got.Host != "proxy.tgm.ac.at:3128" {
+		t.Errorf("the default transport sends requests through %v, want the configured proxy", got)
+	}
+	if defaultTransport.TLSClientConfig == nil || defaultTransport.TLSClientConfig.RootCAs == nil {
+		t.Error("the default transport doesn't trust the root CAs")
+	}
+
+	if err := ConfigureDefaultTransport("", ca); err != nil {
+		t.Fatal(err)
+	}
+	res, err := defaultHTTPClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("the default http client doesn't trust the root CA: %v", err)
+	}
+	res.Body.Close()
+
+	configured := defaultTransport
+	if err := ConfigureDefaultTransport("", []byte("not a certificate")); err == nil || defaultTransport != configured {
+		t.Errorf("got %v for invalid root CAs, want an error leaving the default transport unchanged", err)
+	}
+}
@@ -2,46 +2,349 @@ package untis
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
-	"math"
+	"log"
 	"math/rand"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
-// ClientName is the name of this client communicating with the api
+// ClientName is the name clients identify themselves with at the api by default
 const ClientName = "Refundable"
 
+// ErrNotAuthenticated is returned by methods requiring a session if the client isn't authenticated
+var ErrNotAuthenticated = errors.New("not authenticated")
+
+// ErrAlreadyAuthenticated is returned if a client is authenticated again while its session is still active
+var ErrAlreadyAuthenticated = errors.New("already authenticated")
+
+// ErrTeacherNotFound is returned if a teacher name doesn't match any teacher known to untis
+var ErrTeacherNotFound = errors.New("teacher not found")
+
+// ErrStudentNotFound is returned if a student name doesn't match any student known to untis
+var ErrStudentNotFound = errors.New("student not found")
+
+// ErrIDMismatch is matched by every IDMismatchError, so it can be detected with errors.Is
+var ErrIDMismatch = errors.New("ids not matching")
+
+// ErrRoomNotFound is returned if a room name doesn't match any room known to untis
+var ErrRoomNotFound = errors.New("room not found")
+
+// ErrClassNotFound is returned if a class name doesn't match any class known to untis
+var ErrClassNotFound = errors.New("class not found")
+
+// DefaultServer is the untis server clients connect to if none is specified
+const DefaultServer = "https://neilo.webuntis.com"
+
+// DefaultSchool is the school clients log into if none is specified
+const DefaultSchool = "tgm"
+
+// Server is the untis server clients created by CreateClient and CreateClientWithSecret connect to
+var Server = DefaultServer
+
+// School is the school clients created by CreateClient and CreateClientWithSecret log into
+var School = DefaultSchool
+
+// DefaultMaxAttempts is the amount of attempts a request to the untis api is tried by default
+const DefaultMaxAttempts = 3
+
+// DefaultRetryDelay is the base delay between attempts, which doubles with every retry
+const DefaultRetryDelay = 250 * time.Millisecond
+
+// DefaultConcurrency is the amount of requests sent to the untis api at the same time when fetching ranges by default
+const DefaultConcurrency = 4
+
+// DefaultMaxRange is the longest range a timetable may be requested for at once by default
+const DefaultMaxRange = 366 * 24 * time.Hour
+
+// MaxTimetableWindow is the longest range requested with a single getTimetable request, longer ranges are split
+// into windows of this length, as untis caps the lessons of large ranges without marking the response as truncated
+const MaxTimetableWindow = 28 * 24 * time.Hour
+
+// DefaultMaxIdleConnsPerHost is the amount of idle connections to the untis api kept open for reuse,
+// it is larger than DefaultConcurrency so concurrent requests don't have to open new connections
+const DefaultMaxIdleConnsPerHost = 16
+
+// DefaultIdleConnTimeout is the time an idle connection to the untis api is kept open
+const DefaultIdleConnTimeout = 90 * time.Second
+
+// DefaultTimeout is the timeout of the default http client used for requests to the untis api
+const DefaultTimeout = 30 * time.Second
+
+// BadCredentialsErrorCode is the error code the untis api responds with if the username or password is wrong
+const BadCredentialsErrorCode = -8504
+
+// TooManySessionsErrorCode is the error code the untis api responds with if it throttles the client, e.g. because of too many sessions
+const TooManySessionsErrorCode = -8509
+
+// NotAuthenticatedErrorCode is the error code the untis api responds with if the session expired or is missing
+const NotAuthenticatedErrorCode = -8520
+
 // URL is the path this api is available at
-const URL = "https://neilo.webuntis.com/WebUntis/jsonrpc.do?school=tgm"
+const URL = DefaultServer + "/WebUntis/jsonrpc.do?school=" + DefaultSchool
+
+// TimeZone is the name of the time zone untis reports its local times in
+const TimeZone = "Europe/Vienna"
+
+// activeClients is a map that maps a user (the username) to its active clients, ordered from the oldest to the
+// newest one; a user logging in again gets a new client while the sessions of the older ones may still be in use
+var activeClients = make(map[string][]*Client)
 
-// activeClients is a map that maps a user (the username) to the active client during an active session
-var activeClients map[string]Client
+// activeClientsMutex guards activeClients against concurrent access
+var activeClientsMutex sync.RWMutex
+
+// location is the time zone of the untis times, loaded once by Location
+var location *time.Location
+
+// locationOnce guards the loading of location
+var locationOnce sync.Once
+
+// ObserveRequest is called after every request to the untis api with the json-rpc method, the time the request took
+// and its error, it is meant to collect metrics and has to be set before any client is used
+var ObserveRequest func(method string, duration time.Duration, err error)
+
+// defaultTransport is shared by all clients not providing their own http client, so connections to the untis api
+// are kept alive and reused instead of opening a new one for every request
+var defaultTransport = newTransport()
+
+// defaultHTTPClient is the http client used by all clients not providing their own
+var defaultHTTPClient = &http.Client{Timeout: DefaultTimeout, Transport: defaultTransport}
 
 // Client is the struct representing the client
+// a client may be used by several goroutines at once (see Acquire), its session state has to be read using
+// IsAuthenticated then, reading the exported fields directly is only safe while no one else uses the client
 type Client struct {
+	// Server is the untis server the client connects to (e.g. https://neilo.webuntis.com)
+	Server string
+	// School is the name of the school the client logs into
+	School string
 	// Username of the account the client uses
 	Username string
 	// Password of the account the client uses
 	Password string
+	// Name is the name the client identifies its sessions with at untis (e.g. to tell staging and production apart),
+	// ClientName is used if it is empty
+	Name string
+	// Secret is the base32 encoded app secret of the account used to authenticate with one-time passwords
+	Secret string
+	// Clock tells the current time used to compute one-time passwords, cache and session ages,
+	// DefaultClock is used if it is nil
+	Clock Clock
 	// SessionID of the session the client is currently in
 	SessionID string
 	// PersonType of the account the client uses
-	PersonType int
+	PersonType PersonType
 	// PersonID of the account the client uses
 	PersonID int
+	// Profile is the user data of the account, it is set by GetUserData
+	Profile *UserData
 	// Closed whether the current session is closed or not
 	Closed bool
 	// Authenticated whether the current session is active authenticated
 	Authenticated bool
+	// MaxAttempts is the amount of attempts a request is tried on connection or server errors
+	MaxAttempts int
+	// RetryDelay is the base delay between two attempts, it doubles with every retry
+	RetryDelay time.Duration
+	// RateLimit is the amount of requests per second sent to the untis api at most, requests aren't limited if it isn't positive
+	RateLimit float64
+	// RateBurst is the amount of requests that may be sent at once before RateLimit applies
+	RateBurst int
+	// Concurrency is the maximum amount of requests sent at the same time when fetching a timetable range
+	Concurrency int
+	// MaxRange is the longest range a timetable may be requested for at once, DefaultMaxRange is used if it isn't positive
+	MaxRange time.Duration
+	// HTTPClient is the http client used to send requests to the untis api
+	HTTPClient *http.Client
+	// Logf is called to log failed requests to the untis api, log.Printf is used if it is nil
+	Logf func(format string, v ...interface{})
+	// AutoReauth whether the client authenticates again and replays the request once if its session expired
+	AutoReauth bool
+	// ForceCloseOnError whether Close clears the local state of the session even if the logout fails,
+	// otherwise the client stays authenticated, so Close can be retried
+	ForceCloseOnError bool
+	// mutex guards the session state (SessionID, PersonType, PersonID, Authenticated, Closed, Secret, Profile),
+	// users and the caches
+	mutex sync.Mutex
+	// lifecycle serializes authenticating and closing the session
+	lifecycle sync.Mutex
+	// users is the amount of callers which acquired the session and didn't release it yet
+	users int
+	// limiter is the token bucket enforcing RateLimit
+	limiter rateLimiter
+	// usage is the time the client was used the last time
+	usage usage
+	// rpcIDs generates the ids of the requests sent by the client
+	rpcIDs rpcIDs
+	// cachedTeachers are the teachers fetched during the current session mapped by their id
+	cachedTeachers map[int]Teacher
+	// cachedRooms are the rooms fetched during the current session mapped by their id
+	cachedRooms map[int]Room
+	// cachedClasses are the classes fetched during the current session mapped by their id
+	cachedClasses map[int]Class
+	// cachedSubjects are the subjects fetched during the current session mapped by their id
+	cachedSubjects map[int]Subject
+}
+
+// Teacher represents a teacher as provided by untis
+type Teacher struct {
+	// ID is the untis id of the teacher
+	ID int `json:"id"`
+	// Name is the short name of the teacher
+	Name string `json:"name"`
+	// ForeName is the forename of the teacher
+	ForeName string `json:"foreName"`
+	// LongName is the surname of the teacher
+	LongName string `json:"longName"`
+	// ForeColor is the text color used for the teacher in untis
+	ForeColor string `json:"foreColor"`
+	// BackColor is the background color used for the teacher in untis
+	BackColor string `json:"backColor"`
+}
+
+// Room represents a room as provided by untis
+type Room struct {
+	// ID is the untis id of the room
+	ID int `json:"id"`
+	// Name is the short name of the room
+	Name string `json:"name"`
+	// LongName is the full name of the room
+	LongName string `json:"longName"`
+	// ForeColor is the text color used for the room in untis
+	ForeColor string `json:"foreColor"`
+	// BackColor is the background color used for the room in untis
+	BackColor string `json:"backColor"`
+}
+
+// Class represents a class as provided by untis
+type Class struct {
+	// ID is the untis id of the class
+	ID int `json:"id"`
+	// Name is the short name of the class
+	Name string `json:"name"`
+	// LongName is the full name of the class
+	LongName string `json:"longName"`
+	// ForeColor is the text color used for the class in untis
+	ForeColor string `json:"foreColor"`
+	// BackColor is the background color used for the class in untis
+	BackColor string `json:"backColor"`
+	// Teacher1 is the id of the class teacher
+	Teacher1 int `json:"teacher1"`
+	// Teacher2 is the id of the deputy class teacher
+	Teacher2 int `json:"teacher2"`
+}
+
+// Subject represents a subject as provided by untis
+type Subject struct {
+	// ID is the untis id of the subject
+	ID int `json:"id"`
+	// Name is the short name of the subject
+	Name string `json:"name"`
+	// LongName is the full name of the subject
+	LongName string `json:"longName"`
+	// AlternateName is an alternative name of the subject
+	AlternateName string `json:"alternateName"`
+	// ForeColor is the text color used for the subject in untis
+	ForeColor string `json:"foreColor"`
+	// BackColor is the background color used for the subject in untis
+	BackColor string `json:"backColor"`
+}
+
+// Holiday represents holidays (days without school) as provided by untis
+type Holiday struct {
+	// Name is the short name of the holidays
+	Name string `json:"name" example:"Ostern"`
+	// LongName is the full name of the holidays
+	LongName string `json:"long_name" example:"Osterferien"`
+	// Start is the first day of the holidays
+	Start time.Time `json:"start"`
+	// End is the last day of the holidays
+	End time.Time `json:"end"`
+}
+
+// UntisError represents an error object the untis api responds with instead of a result
+type UntisError struct {
+	// Code is the json-rpc error code (e.g. -8504 for bad credentials)
+	Code int `json:"code"`
+	// Message is the error message provided by the untis api
+	Message string `json:"message"`
+}
+
+// Error returns the code and the message of the untis error
+func (err *UntisError) Error() string {
+	return fmt.Sprintf("untis error %d: %v", err.Code, err.Message)
+}
+
+// IDMismatchError is returned if the id of a json-rpc response doesn't match the id of the request it answers
+type IDMismatchError struct {
+	// Method is the json-rpc method of the request
+	Method string
+	// Expected is the id the request was sent with
+	Expected int
+	// Got is the id of the response, 0 if it isn't a number
+	Got int
+}
+
+// Error returns the method and both ids
+func (err *IDMismatchError) Error() string {
+	return fmt.Sprintf("ids not matching for %v: expected %d, got %d", err.Method, err.Expected, err.Got)
+}
+
+// Is reports whether target is ErrIDMismatch
+func (err *IDMismatchError) Is(target error) bool {
+	return target == ErrIDMismatch
+}
+
+// UpstreamUnavailableError is returned if the untis api responds with something else than json (e.g. the html
+// error page shown during outages), the request was tried MaxAttempts times
+type UpstreamUnavailableError struct {
+	// Status is the http status the untis api responded with
+	Status int
+	// ContentType is the content type of the response
+	ContentType string
+}
+
+// Error returns the status and the content type of the response
+func (err *UpstreamUnavailableError) Error() string {
+	return fmt.Sprintf("untis api is unavailable: responded with %d and %q instead of json", err.Status, err.ContentType)
+}
+
+// RangeTooLargeError is returned if a timetable is requested for a range longer than the MaxRange of the client,
+// no request is sent to the untis api then
+type RangeTooLargeError struct {
+	// Start is the start of the requested range
+	Start time.Time
+	// End is the end of the requested range
+	End time.Time
+	// Max is the longest allowed range
+	Max time.Duration
+}
+
+// Error returns the requested range and the longest allowed one
+func (err *RangeTooLargeError) Error() string {
+	return fmt.Sprintf("range from %v to %v is longer than %v", formatUntisDate(err.Start), formatUntisDate(err.End), err.Max)
+}
+
+// HasErrorCode checks whether err is an *UntisError with the code
+func HasErrorCode(err error, code int) bool {
+	var untisErr *UntisError
+	return errors.As(err, &untisErr) && untisErr.Code == code
 }
 
 // Lesson represents a lesson out of a timetable
 type Lesson struct {
+	// ID is the id of the lesson in untis
+	ID int
 	// Start is the start time of the lesson
 	Start time.Time
 	// End is the end time of the lesson
@@ -58,55 +361,193 @@ type Lesson struct {
 	RoomIDs []int
 	// Rooms are the room names this lesson takes place in
 	Rooms []string
+	// OriginalTeacherIDs are the ids of the teachers substituted by Teachers
+	OriginalTeacherIDs []int
+	// OriginalTeachers are the names of the teachers substituted by Teachers
+	OriginalTeachers []string
+	// OriginalRoomIDs are the ids of the rooms this lesson was moved from
+	OriginalRoomIDs []int
+	// OriginalRooms are the names of the rooms this lesson was moved from
+	OriginalRooms []string
+	// SubjectIDs are the ids of the subjects taught in this lesson
+	SubjectIDs []int
+	// Subjects are the names of the subjects taught in this lesson
+	Subjects []string
+	// Cancelled whether this lesson is cancelled
+	Cancelled bool
+	// Irregular whether this lesson is irregular (e.g. a substitution)
+	Irregular bool
+	// SubstitutionText is the text untis provides regarding a substitution of this lesson
+	SubstitutionText string
+	// LessonText is the text untis provides for this lesson (e.g. an exam announcement)
+	LessonText string
+	// Info is the additional information untis provides for this lesson
+	Info string
 }
 
 // CreateClient creates a new client to communicate with the API
 // the username and password are used to authenticate the client at the service
 func CreateClient(username, password string) *Client {
-	client := Client{
+	return CreateClientForSchool(Server, School, username, password)
+}
+
+// CreateClientForSchool creates a new client to communicate with the API of the given untis server and school
+// the username and password are used to authenticate the client at the service
+func CreateClientForSchool(server, school, username, password string) *Client {
+	client := &Client{
+		Server:        server,
+		School:        school,
 		Username:      username,
 		Password:      password,
+		Name:          ClientName,
 		SessionID:     "",
 		PersonType:    -1,
 		PersonID:      -1,
 		Closed:        false,
 		Authenticated: false,
+		MaxAttempts:   DefaultMaxAttempts,
+		RetryDelay:    DefaultRetryDelay,
+		RateLimit:     DefaultRateLimit,
+		RateBurst:     DefaultRateBurst,
+		Concurrency:   DefaultConcurrency,
+		MaxRange:      DefaultMaxRange,
+		HTTPClient:    defaultHTTPClient,
+		AutoReauth:    true,
+	}
+	client.touch()
+	activeClientsMutex.Lock()
+	clients := make([]*Client, 0, len(activeClients[username])+1)
+	for _, previous := range activeClients[username] {
+		// older clients without a session no one uses can't be reached by GetClient anymore
+		if previous.IsAuthenticated() || previous.Users() > 0 {
+			clients = append(clients, previous)
+		}
 	}
-	if activeClients == nil {
-		activeClients = make(map[string]Client)
-	}
-	activeClients[username] = client
-	return &client
+	activeClients[username] = append(clients, client)
+	activeClientsMutex.Unlock()
+	return client
+}
+
+// CreateClientWithSecret creates a client for Server and School which authenticates using
+// one-time passwords computed from the app secret of the account instead of its password
+func CreateClientWithSecret(username, secret string) *Client {
+	client := CreateClientForSchool(Server, School, username, "")
+	client.setSecret(secret)
+	return client
+}
+
+// Location returns the time zone untis reports its local times in (TimeZone)
+// if the time zone database isn't available, UTC is used instead
+func Location() *time.Location {
+	locationOnce.Do(func() {
+		loc, err := time.LoadLocation(TimeZone)
+		if err != nil {
+			log.Printf("couldn't load time zone %v, falling back to UTC: %v", TimeZone, err)
+			loc = time.UTC
+		}
+		location = loc
+	})
+	return location
 }
 
-// GetClient returns an active client using the corresponding username
-func GetClient(username string) *Client {
-	client := activeClients[username]
-	return &client
+// GetClient returns the newest active client of the corresponding username and whether there is one,
+// there is none if the user never logged in or the session ended (e.g. it was evicted after being idle)
+// the returned client is the same instance stored in the active clients, so changes to it persist for the session
+func GetClient(username string) (*Client, bool) {
+	activeClientsMutex.RLock()
+	defer activeClientsMutex.RUnlock()
+	clients := activeClients[username]
+	if len(clients) == 0 {
+		return nil, false
+	}
+	return clients[len(clients)-1], true
+}
+
+// removeActiveClient removes the client out of the active clients of its user, activeClientsMutex has to be locked
+func removeActiveClient(client *Client) {
+	clients := activeClients[client.Username]
+	for i, active := range clients {
+		if active == client {
+			clients = append(clients[:i:i], clients[i+1:]...)
+			break
+		}
+	}
+	if len(clients) == 0 {
+		delete(activeClients, client.Username)
+		return
+	}
+	activeClients[client.Username] = clients
 }
 
 // Authenticate authenticates the client at the untis service
 func (client *Client) Authenticate() error {
-	if client.Authenticated {
-		return fmt.Errorf("already authenticated")
-	}
-	id := rand.Intn(math.MaxInt64)
-	body, _ := json.Marshal(map[string]interface{}{
-		"id":     id,
-		"method": "authenticate",
-		"params": map[string]string{
-			"user":     client.Username,
-			"password": client.Password,
-			"client":   ClientName,
-		},
-		"jsonrpc": "2.0",
+	return client.AuthenticateContext(context.Background())
+}
+
+// AuthenticateContext is like Authenticate but uses ctx for the requests sent to the untis api
+func (client *Client) AuthenticateContext(ctx context.Context) error {
+	client.lifecycle.Lock()
+	defer client.lifecycle.Unlock()
+	if client.IsAuthenticated() {
+		return ErrAlreadyAuthenticated
+	}
+	return client.authenticatePassword(ctx)
+}
+
+// authenticatePassword authenticates the client using its password
+func (client *Client) authenticatePassword(ctx context.Context) error {
+	return client.authenticate(ctx, map[string]interface{}{
+		"user":     client.Username,
+		"password": client.Password,
+		"client":   client.name(),
 	})
-	resp, err := http.Post(URL, "application/json", bytes.NewBuffer(body))
+}
+
+// AuthenticateSecret authenticates the client at the untis service using a one-time password computed from its secret
+func (client *Client) AuthenticateSecret() error {
+	return client.AuthenticateSecretContext(context.Background())
+}
+
+// AuthenticateSecretContext is like AuthenticateSecret but uses ctx for the requests sent to the untis api
+func (client *Client) AuthenticateSecretContext(ctx context.Context) error {
+	client.lifecycle.Lock()
+	defer client.lifecycle.Unlock()
+	if client.IsAuthenticated() {
+		return ErrAlreadyAuthenticated
+	}
+	return client.authenticateSecret(ctx)
+}
+
+// authenticateSecret authenticates the client using a one-time password computed from its secret
+func (client *Client) authenticateSecret(ctx context.Context) error {
+	secret := client.secret()
+	if secret == "" {
+		return fmt.Errorf("no secret set")
+	}
+	now := client.now()
+	otp, err := GenerateOTP(secret, now)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-	respBody, err := ioutil.ReadAll(resp.Body)
+	return client.authenticate(ctx, map[string]interface{}{
+		"user":       client.Username,
+		"otp":        otp,
+		"clientTime": now.UnixNano() / int64(time.Millisecond),
+		"client":     client.name(),
+	})
+}
+
+// name returns the name the client identifies itself with, which is Name or ClientName if it is empty
+func (client *Client) name() string {
+	if client.Name == "" {
+		return ClientName
+	}
+	return client.Name
+}
+
+// authenticate sends an authenticate request with the given credentials and stores the returned session
+func (client *Client) authenticate(ctx context.Context, params map[string]interface{}) error {
+	respBody, id, err := client.sendRequest(ctx, "authenticate", params)
 	if err != nil {
 		return err
 	}
@@ -119,430 +560,611 @@ func (client *Client) Authenticate() error {
 	if err != nil {
 		return err
 	}
+	rid, _ := strconv.Atoi(r.ID)
+	if rid != id {
+		return &IDMismatchError{Method: "authenticate", Expected: id, Got: rid}
+	}
+	sessionID, ok := r.Result["sessionId"].(string)
+	if !ok {
+		return fmt.Errorf("no session id returned")
+	}
 	personType, _ := r.Result["personType"].(float64)
 	personID, _ := r.Result["personId"].(float64)
-	if r.ID == strconv.Itoa(id) {
-		client.SessionID = r.Result["sessionId"].(string)
-		client.PersonType = int(personType)
-		client.PersonID = int(personID)
-		client.Authenticated = true
-		client.Closed = false
-		return nil
-	}
-	return fmt.Errorf("IDs not matching")
+	client.startSession(sessionID, PersonType(personType), int(personID))
+	return nil
 }
 
 // GetTimetableOfTeacher returns a list of lessons the teacher logged in with the client has in between start and end
-func (client Client) GetTimetableOfTeacher(start, end time.Time) ([]Lesson, error) {
-	if !client.Authenticated {
-		return nil, fmt.Errorf("not authenticated")
-	}
-	smonth := strconv.Itoa(int(start.Month()))
-	if len(smonth) == 1 {
-		smonth = "0" + smonth
-	}
-	sday := strconv.Itoa(start.Day())
-	if len(sday) == 1 {
-		sday = "0" + sday
+func (client *Client) GetTimetableOfTeacher(start, end time.Time) ([]Lesson, error) {
+	return client.GetTimetableOfTeacherContext(context.Background(), start, end)
+}
+
+// GetTimetableOfTeacherContext is like GetTimetableOfTeacher but uses ctx for the requests sent to the untis api
+func (client *Client) GetTimetableOfTeacherContext(ctx context.Context, start, end time.Time) ([]Lesson, error) {
+	if !client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
 	}
-	emonth := strconv.Itoa(int(end.Month()))
-	if len(emonth) == 1 {
-		emonth = "0" + emonth
+	if err := client.checkRange(start, end); err != nil {
+		return nil, err
 	}
-	eday := strconv.Itoa(end.Day())
-	if len(eday) == 1 {
-		eday = "0" + eday
+	personID, personType := client.Person()
+	return client.getTimetable(ctx, personID, personType, start, end)
+}
+
+// GetCurrentLesson returns the lesson the teacher logged in with the client is teaching at the moment and its next
+// lesson of the day as described by CurrentAndNextLesson, the current time is taken from the Clock of the client
+func (client *Client) GetCurrentLesson() (current, next *Lesson, err error) {
+	return client.GetCurrentLessonContext(context.Background())
+}
+
+// GetCurrentLessonContext is like GetCurrentLesson but uses ctx for the requests sent to the untis api
+func (client *Client) GetCurrentLessonContext(ctx context.Context) (current, next *Lesson, err error) {
+	now := client.now().In(Location())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, Location())
+	lessons, err := client.GetTimetableOfTeacherContext(ctx, today, today)
+	if err != nil {
+		return nil, nil, err
 	}
-	params := map[string]interface{}{
-		"id":        client.PersonID,
-		"type":      client.PersonType,
-		"startDate": strconv.Itoa(start.Year()) + smonth + sday,
-		"endDate":   strconv.Itoa(end.Year()) + emonth + eday,
+	current, next = CurrentAndNextLesson(lessons, now)
+	return current, next, nil
+}
+
+// GetTimetableOfTeacherRange returns a list of lessons the teacher logged in with the client has in between start
+// and end, sorted by their start time. The range is split into windows of the given duration (at least one day and
+// at most MaxTimetableWindow) which are fetched concurrently, using at most Concurrency requests at the same time
+func (client *Client) GetTimetableOfTeacherRange(start, end time.Time, window time.Duration) ([]Lesson, error) {
+	return client.GetTimetableOfTeacherRangeContext(context.Background(), start, end, window)
+}
+
+// GetTimetableOfTeacherRangeContext is like GetTimetableOfTeacherRange but uses ctx for the requests sent to the untis api
+func (client *Client) GetTimetableOfTeacherRangeContext(ctx context.Context, start, end time.Time, window time.Duration) ([]Lesson, error) {
+	if !client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
 	}
-	resp, id, err := client.sendRequest("getTimetable", params)
-	if err != nil {
+	if err := client.checkRange(start, end); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	respBody, err := ioutil.ReadAll(resp.Body)
+	if end.Before(start) {
+		return nil, fmt.Errorf("end is before start")
+	}
+	personID, personType := client.Person()
+	lessons, err := client.fetchTimetableWindows(ctx, personID, personType, start, end, window)
 	if err != nil {
 		return nil, err
 	}
-	r := struct {
-		JSONRPC string `json:"jsonrpc"`
-		ID      string `json:"id"`
-		Result  []struct {
-			ID        int `json:"id"`
-			Date      int `json:"date"`
-			StartTime int `json:"startTime"`
-			EndTime   int `json:"endTime"`
-			Kl        []struct {
-				ID int `json:"id"`
-			} `json:"kl"`
-			Te []struct {
-				ID int `json:"id"`
-			} `json:"te"`
-			Su []struct {
-				ID int `json:"id"`
-			} `json:"su"`
-			Ro []struct {
-				ID int `json:"id"`
-			} `json:"ro"`
-		} `json:"result"`
-	}{}
-	err = json.Unmarshal(respBody, &r)
+	err = client.resolveLessons(ctx, lessons)
 	if err != nil {
 		return nil, err
 	}
-	rid, _ := strconv.Atoi(r.ID)
-	if rid == id {
-		lessons := make([]Lesson, 0)
-		for _, l := range r.Result {
-			date := strconv.Itoa(l.Date)
-			year, _ := strconv.Atoi(date[0:4])
-			month, _ := strconv.Atoi(date[4:6])
-			day, _ := strconv.Atoi(date[6:8])
-			startTime := strconv.Itoa(l.StartTime)
-			startHour, _ := strconv.Atoi(startTime[0 : len(startTime)-2])
-			startMinute, _ := strconv.Atoi(startTime[len(startTime)-2:])
-			endTime := strconv.Itoa(l.EndTime)
-			endHour, _ := strconv.Atoi(endTime[0 : len(endTime)-2])
-			endMinute, _ := strconv.Atoi(endTime[len(endTime)-2:])
-			classIDArr := make([]int, 0)
-			for _, kls := range l.Kl {
-				classIDArr = append(classIDArr, kls.ID)
-			}
-			classArr, err := client.ResolveClasses(classIDArr)
-			if err != nil {
-				return nil, err
-			}
-			teachIDArr := make([]int, 0)
-			for _, tes := range l.Te {
-				teachIDArr = append(teachIDArr, tes.ID)
-			}
-			teachArr, err := client.ResolveTeachers(teachIDArr)
-			if err != nil {
-				return nil, err
-			}
-			roomIDArr := make([]int, 0)
-			for _, ros := range l.Ro {
-				roomIDArr = append(roomIDArr, ros.ID)
-			}
-			roomArr, err := client.ResolveRooms(roomIDArr)
-			if err != nil {
-				return nil, err
+	sortLessons(lessons)
+	return lessons, nil
+}
+
+// fetchTimetableWindows requests the lessons the element identified by id and personType has in between start and
+// end like fetchTimetable, sorted by their start time. The range is split into windows of the given duration (at least
+// one day and at most MaxTimetableWindow) which are fetched concurrently, using at most Concurrency requests at the same time
+func (client *Client) fetchTimetableWindows(ctx context.Context, id int, personType PersonType, start, end time.Time, window time.Duration) ([]Lesson, error) {
+	if window < 24*time.Hour {
+		window = 24 * time.Hour
+	}
+	if window > MaxTimetableWindow {
+		window = MaxTimetableWindow
+	}
+	windows := make([][2]time.Time, 0)
+	for wstart := start; !wstart.After(end); wstart = wstart.Add(window) {
+		wend := wstart.Add(window)
+		if wend.After(end) {
+			wend = end
+		}
+		windows = append(windows, [2]time.Time{wstart, wend})
+	}
+	concurrency := client.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make([][]Lesson, len(windows))
+	errs := make([]error, len(windows))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, w := range windows {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, wstart, wend time.Time) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i], errs[i] = client.requestTimetable(ctx, id, personType, wstart, wend)
+			if errs[i] != nil {
+				cancel()
 			}
-			lessons = append(lessons, Lesson{
-				Start:      time.Date(year, time.Month(month), day, startHour, startMinute, 0, 0, time.UTC),
-				End:        time.Date(year, time.Month(month), day, endHour, endMinute, 0, 0, time.UTC),
-				ClassIDs:   classIDArr,
-				Classes:    classArr,
-				TeacherIDs: teachIDArr,
-				Teachers:   teachArr,
-				RoomIDs:    roomIDArr,
-				Rooms:      roomArr,
-			})
+		}(i, w[0], w[1])
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil && err != context.Canceled {
+			return nil, err
 		}
-		return lessons, nil
 	}
-	return nil, fmt.Errorf("ids not matching")
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return mergeLessonWindows(results), nil
 }
 
-// GetTimetableOfClass returns a list of lessons a specified class has in between start and end
-func (client Client) GetTimetableOfClass(start, end time.Time, class string) ([]Lesson, error) {
-	if !client.Authenticated {
-		return nil, fmt.Errorf("not authenticated")
+// sortLessons sorts resolved lessons by their start, lessons starting at the same time by the name of their first
+// subject and room and finally by their id and end, so identical timetables are always in the same order
+func sortLessons(lessons []Lesson) {
+	first := func(names []string) string {
+		if len(names) == 0 {
+			return ""
+		}
+		return names[0]
 	}
-	smonth := strconv.Itoa(int(start.Month()))
-	if len(smonth) == 1 {
-		smonth = "0" + smonth
+	sort.SliceStable(lessons, func(i, j int) bool {
+		a, b := lessons[i], lessons[j]
+		if !a.Start.Equal(b.Start) {
+			return a.Start.Before(b.Start)
+		}
+		if sa, sb := first(a.Subjects), first(b.Subjects); sa != sb {
+			return sa < sb
+		}
+		if ra, rb := first(a.Rooms), first(b.Rooms); ra != rb {
+			return ra < rb
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.End.Before(b.End)
+	})
+}
+
+// checkRange returns a *RangeTooLargeError if the range from start to end is longer than the MaxRange of the client
+func (client *Client) checkRange(start, end time.Time) error {
+	max := client.MaxRange
+	if max <= 0 {
+		max = DefaultMaxRange
 	}
-	sday := strconv.Itoa(start.Day())
-	if len(sday) == 1 {
-		sday = "0" + sday
+	if end.Sub(start) > max {
+		return &RangeTooLargeError{Start: start, End: end, Max: max}
 	}
-	emonth := strconv.Itoa(int(end.Month()))
-	if len(emonth) == 1 {
-		emonth = "0" + emonth
+	return nil
+}
+
+// mergeLessonWindows merges the lessons of multiple windows into one list sorted by start time
+// lessons contained in more than one window (having the same id and start time) are only contained once
+func mergeLessonWindows(windows [][]Lesson) []Lesson {
+	type key struct {
+		id    int
+		start int64
+	}
+	seen := make(map[key]bool)
+	lessons := make([]Lesson, 0)
+	for _, window := range windows {
+		for _, lesson := range window {
+			k := key{lesson.ID, lesson.Start.UnixNano()}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			lessons = append(lessons, lesson)
+		}
 	}
-	eday := strconv.Itoa(end.Day())
-	if len(eday) == 1 {
-		eday = "0" + eday
+	sort.SliceStable(lessons, func(i, j int) bool {
+		if lessons[i].Start.Equal(lessons[j].Start) {
+			return lessons[i].ID < lessons[j].ID
+		}
+		return lessons[i].Start.Before(lessons[j].Start)
+	})
+	return lessons
+}
+
+// GetTimetableOfClass returns a list of lessons a specified class has in between start and end
+// ErrClassNotFound is returned if there is no class with this name
+func (client *Client) GetTimetableOfClass(start, end time.Time, class string) ([]Lesson, error) {
+	return client.GetTimetableOfClassContext(context.Background(), start, end, class)
+}
+
+// GetTimetableOfClassContext is like GetTimetableOfClass but uses ctx for the requests sent to the untis api
+func (client *Client) GetTimetableOfClassContext(ctx context.Context, start, end time.Time, class string) ([]Lesson, error) {
+	if !client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
 	}
-	classID, _ := client.ResolveClassID(class)
-	params := map[string]interface{}{
-		"id":        classID,
-		"type":      1,
-		"startDate": strconv.Itoa(start.Year()) + smonth + sday,
-		"endDate":   strconv.Itoa(end.Year()) + emonth + eday,
+	if err := client.checkRange(start, end); err != nil {
+		return nil, err
 	}
-	resp, id, err := client.sendRequest("getTimetable", params)
+	classID, err := client.ResolveClassIDContext(ctx, class)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	respBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
+	return client.getTimetable(ctx, classID, PersonTypeClass, start, end)
+}
+
+// GetTimetableOfRoom returns a list of lessons taking place in a specified room in between start and end
+// ErrRoomNotFound is returned if there is no room with this name
+func (client *Client) GetTimetableOfRoom(start, end time.Time, room string) ([]Lesson, error) {
+	return client.GetTimetableOfRoomContext(context.Background(), start, end, room)
+}
+
+// GetTimetableOfRoomContext is like GetTimetableOfRoom but uses ctx for the requests sent to the untis api
+func (client *Client) GetTimetableOfRoomContext(ctx context.Context, start, end time.Time, room string) ([]Lesson, error) {
+	if !client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	if err := client.checkRange(start, end); err != nil {
 		return nil, err
 	}
-	r := struct {
-		JSONRPC string `json:"jsonrpc"`
-		ID      string `json:"id"`
-		Result  []struct {
-			ID        int `json:"id"`
-			Date      int `json:"date"`
-			StartTime int `json:"startTime"`
-			EndTime   int `json:"endTime"`
-			Kl        []struct {
-				ID int `json:"id"`
-			} `json:"kl"`
-			Te []struct {
-				ID int `json:"id"`
-			} `json:"te"`
-			Su []struct {
-				ID int `json:"id"`
-			} `json:"su"`
-			Ro []struct {
-				ID int `json:"id"`
-			} `json:"ro"`
-		} `json:"result"`
-	}{}
-	err = json.Unmarshal(respBody, &r)
+	roomID, err := client.ResolveRoomIDContext(ctx, room)
 	if err != nil {
 		return nil, err
 	}
-	rid, _ := strconv.Atoi(r.ID)
-	if rid == id {
-		lessons := make([]Lesson, 0)
-		for _, l := range r.Result {
-			date := strconv.Itoa(l.Date)
-			year, _ := strconv.Atoi(date[0:4])
-			month, _ := strconv.Atoi(date[4:6])
-			day, _ := strconv.Atoi(date[6:8])
-			startTime := strconv.Itoa(l.StartTime)
-			startHour, _ := strconv.Atoi(startTime[0 : len(startTime)-2])
-			startMinute, _ := strconv.Atoi(startTime[len(startTime)-2:])
-			endTime := strconv.Itoa(l.EndTime)
-			endHour, _ := strconv.Atoi(endTime[0 : len(endTime)-2])
-			endMinute, _ := strconv.Atoi(endTime[len(endTime)-2:])
-			classIDArr := make([]int, 0)
-			for _, kls := range l.Kl {
-				classIDArr = append(classIDArr, kls.ID)
-			}
-			classArr, err := client.ResolveClasses(classIDArr)
-			if err != nil {
-				return nil, err
-			}
-			teachIDArr := make([]int, 0)
-			for _, tes := range l.Te {
-				teachIDArr = append(teachIDArr, tes.ID)
-			}
-			teachArr, err := client.ResolveTeachers(teachIDArr)
-			if err != nil {
-				return nil, err
-			}
-			roomIDArr := make([]int, 0)
-			for _, ros := range l.Ro {
-				roomIDArr = append(roomIDArr, ros.ID)
-			}
-			roomArr, err := client.ResolveRooms(roomIDArr)
-			if err != nil {
-				return nil, err
-			}
-			lessons = append(lessons, Lesson{
-				Start:      time.Date(year, time.Month(month), day, startHour, startMinute, 0, 0, time.UTC),
-				End:        time.Date(year, time.Month(month), day, endHour, endMinute, 0, 0, time.UTC),
-				ClassIDs:   classIDArr,
-				Classes:    classArr,
-				TeacherIDs: teachIDArr,
-				Teachers:   teachArr,
-				RoomIDs:    roomIDArr,
-				Rooms:      roomArr,
-			})
-		}
-		return lessons, nil
-	}
-	return nil, fmt.Errorf("ids not matching")
+	return client.getTimetable(ctx, roomID, PersonTypeRoom, start, end)
 }
 
 // GetTimetableOfSpecificTeacher returns a list of lessons a specified teacher has in between start and end
-func (client Client) GetTimetableOfSpecificTeacher(start, end time.Time, teacher string) ([]Lesson, error) {
-	if !client.Authenticated {
-		return nil, fmt.Errorf("not authenticated")
-	}
-	smonth := strconv.Itoa(int(start.Month()))
-	if len(smonth) == 1 {
-		smonth = "0" + smonth
-	}
-	sday := strconv.Itoa(start.Day())
-	if len(sday) == 1 {
-		sday = "0" + sday
-	}
-	emonth := strconv.Itoa(int(end.Month()))
-	if len(emonth) == 1 {
-		emonth = "0" + emonth
+func (client *Client) GetTimetableOfSpecificTeacher(start, end time.Time, teacher string) ([]Lesson, error) {
+	return client.GetTimetableOfSpecificTeacherContext(context.Background(), start, end, teacher)
+}
+
+// GetTimetableOfSpecificTeacherContext is like GetTimetableOfSpecificTeacher but uses ctx for the requests sent to the untis api
+func (client *Client) GetTimetableOfSpecificTeacherContext(ctx context.Context, start, end time.Time, teacher string) ([]Lesson, error) {
+	if !client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
 	}
-	eday := strconv.Itoa(end.Day())
-	if len(eday) == 1 {
-		eday = "0" + eday
+	if err := client.checkRange(start, end); err != nil {
+		return nil, err
 	}
-	id, err := client.ResolveTeacherID(teacher)
+	id, err := client.ResolveTeacherIDContext(ctx, teacher)
 	if err != nil {
 		return nil, err
 	}
-	params := map[string]interface{}{
-		"id":        id,
-		"type":      2,
-		"startDate": strconv.Itoa(start.Year()) + smonth + sday,
-		"endDate":   strconv.Itoa(end.Year()) + emonth + eday,
+	return client.getTimetable(ctx, id, PersonTypeTeacher, start, end)
+}
+
+// GetTimetablesOfTeachers returns the lessons each of the teachers (given by their names as accepted by
+// ResolveTeacherID) has in between start and end, the timetables are fetched concurrently using at most
+// Concurrency requests at the same time. Teachers failing to resolve or fetch are contained in the map of errors
+// instead of failing the whole batch
+func (client *Client) GetTimetablesOfTeachers(start, end time.Time, teachers []string) (map[string][]Lesson, map[string]error) {
+	return client.GetTimetablesOfTeachersContext(context.Background(), start, end, teachers)
+}
+
+// GetTimetablesOfTeachersContext is like GetTimetablesOfTeachers but uses ctx for the requests sent to the untis api
+func (client *Client) GetTimetablesOfTeachersContext(ctx context.Context, start, end time.Time, teachers []string) (map[string][]Lesson, map[string]error) {
+	timetables := make(map[string][]Lesson)
+	errs := make(map[string]error)
+	if !client.IsAuthenticated() {
+		for _, teacher := range teachers {
+			errs[teacher] = ErrNotAuthenticated
+		}
+		return timetables, errs
 	}
-	resp, id, err := client.sendRequest("getTimetable", params)
-	if err != nil {
+	if err := client.checkRange(start, end); err != nil {
+		for _, teacher := range teachers {
+			errs[teacher] = err
+		}
+		return timetables, errs
+	}
+	ids := make(map[string]int)
+	for _, teacher := range teachers {
+		id, err := client.ResolveTeacherIDContext(ctx, teacher)
+		if err != nil {
+			errs[teacher] = err
+			continue
+		}
+		ids[teacher] = id
+	}
+	concurrency := client.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	type result struct {
+		teacher string
+		lessons []Lesson
+		err     error
+	}
+	results := make(chan result, len(ids))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for teacher, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(teacher string, id int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			lessons, err := client.fetchTimetable(ctx, id, PersonTypeTeacher, start, end)
+			results <- result{teacher, lessons, err}
+		}(teacher, id)
+	}
+	wg.Wait()
+	close(results)
+	for res := range results {
+		if res.err == nil {
+			res.err = client.resolveLessons(ctx, res.lessons)
+		}
+		if res.err != nil {
+			errs[res.teacher] = res.err
+			continue
+		}
+		sortLessons(res.lessons)
+		timetables[res.teacher] = res.lessons
+	}
+	return timetables, errs
+}
+
+// GetTimetableOfStudent returns a list of lessons the student identified by studentID has in between start and end
+func (client *Client) GetTimetableOfStudent(start, end time.Time, studentID int) ([]Lesson, error) {
+	return client.GetTimetableOfStudentContext(context.Background(), start, end, studentID)
+}
+
+// GetTimetableOfStudentContext is like GetTimetableOfStudent but uses ctx for the requests sent to the untis api
+func (client *Client) GetTimetableOfStudentContext(ctx context.Context, start, end time.Time, studentID int) ([]Lesson, error) {
+	if !client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	if err := client.checkRange(start, end); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	respBody, err := ioutil.ReadAll(resp.Body)
+	return client.getTimetable(ctx, studentID, PersonTypeStudent, start, end)
+}
+
+// getTimetable returns a list of lessons the element identified by id and personType has in between start and end
+// the lessons are resolved and ordered by sortLessons
+func (client *Client) getTimetable(ctx context.Context, id int, personType PersonType, start, end time.Time) ([]Lesson, error) {
+	lessons, err := client.fetchTimetable(ctx, id, personType, start, end)
 	if err != nil {
 		return nil, err
 	}
-	r := struct {
-		JSONRPC string `json:"jsonrpc"`
-		ID      string `json:"id"`
-		Result  []struct {
-			ID        int `json:"id"`
-			Date      int `json:"date"`
-			StartTime int `json:"startTime"`
-			EndTime   int `json:"endTime"`
-			Kl        []struct {
-				ID int `json:"id"`
-			} `json:"kl"`
-			Te []struct {
-				ID int `json:"id"`
-			} `json:"te"`
-			Su []struct {
-				ID int `json:"id"`
-			} `json:"su"`
-			Ro []struct {
-				ID int `json:"id"`
-			} `json:"ro"`
-		} `json:"result"`
-	}{}
-	err = json.Unmarshal(respBody, &r)
+	err = client.resolveLessons(ctx, lessons)
 	if err != nil {
 		return nil, err
 	}
-	rid, _ := strconv.Atoi(r.ID)
-	if rid == id {
-		lessons := make([]Lesson, 0)
-		for _, l := range r.Result {
-			date := strconv.Itoa(l.Date)
-			year, _ := strconv.Atoi(date[0:4])
-			month, _ := strconv.Atoi(date[4:6])
-			day, _ := strconv.Atoi(date[6:8])
-			startTime := strconv.Itoa(l.StartTime)
-			startHour, _ := strconv.Atoi(startTime[0 : len(startTime)-2])
-			startMinute, _ := strconv.Atoi(startTime[len(startTime)-2:])
-			endTime := strconv.Itoa(l.EndTime)
-			endHour, _ := strconv.Atoi(endTime[0 : len(endTime)-2])
-			endMinute, _ := strconv.Atoi(endTime[len(endTime)-2:])
-			classIDArr := make([]int, 0)
-			for _, kls := range l.Kl {
-				classIDArr = append(classIDArr, kls.ID)
-			}
-			classArr, err := client.ResolveClasses(classIDArr)
-			if err != nil {
-				return nil, err
-			}
-			teachIDArr := make([]int, 0)
-			for _, tes := range l.Te {
-				teachIDArr = append(teachIDArr, tes.ID)
-			}
-			teachArr, err := client.ResolveTeachers(teachIDArr)
-			if err != nil {
-				return nil, err
-			}
-			roomIDArr := make([]int, 0)
-			for _, ros := range l.Ro {
-				roomIDArr = append(roomIDArr, ros.ID)
-			}
-			roomArr, err := client.ResolveRooms(roomIDArr)
-			if err != nil {
-				return nil, err
-			}
-			lessons = append(lessons, Lesson{
-				Start:      time.Date(year, time.Month(month), day, startHour, startMinute, 0, 0, time.UTC),
-				End:        time.Date(year, time.Month(month), day, endHour, endMinute, 0, 0, time.UTC),
-				ClassIDs:   classIDArr,
-				Classes:    classArr,
-				TeacherIDs: teachIDArr,
-				Teachers:   teachArr,
-				RoomIDs:    roomIDArr,
-				Rooms:      roomArr,
-			})
-		}
-		return lessons, nil
-	}
-	return nil, fmt.Errorf("ids not matching")
+	sortLessons(lessons)
+	return lessons, nil
 }
 
-// ResolveTeachers converts an array of teacher ids into an array of teacher names
-func (client Client) ResolveTeachers(ids []int) ([]string, error) {
-	if !client.Authenticated {
-		return nil, fmt.Errorf("not authenticated")
-	}
-	resp, id, err := client.sendRequest("getTeachers", map[string]interface{}{})
+// fetchTimetable requests the lessons the element identified by id and personType has in between start and end
+// without resolving the names of the classes, teachers, rooms and subjects
+// ranges longer than MaxTimetableWindow are split up and fetched by fetchTimetableWindows
+func (client *Client) fetchTimetable(ctx context.Context, id int, personType PersonType, start, end time.Time) ([]Lesson, error) {
+	if end.Sub(start) > MaxTimetableWindow {
+		return client.fetchTimetableWindows(ctx, id, personType, start, end, MaxTimetableWindow)
+	}
+	return client.requestTimetable(ctx, id, personType, start, end)
+}
+
+// requestTimetable sends a single getTimetable request for the lessons the element identified by id and personType
+// has in between start and end
+func (client *Client) requestTimetable(ctx context.Context, id int, personType PersonType, start, end time.Time) ([]Lesson, error) {
+	respBody, reqID, err := client.sendRequest(ctx, "getTimetable", buildTimetableParams(id, personType, start, end))
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	respBody, err := ioutil.ReadAll(resp.Body)
+	return parseTimetableResponse(respBody, reqID)
+}
+
+// buildTimetableParams creates the parameters of a getTimetable request for the element identified by id and personType
+func buildTimetableParams(id int, personType PersonType, start, end time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"id":        id,
+		"type":      personType,
+		"startDate": formatUntisDate(start),
+		"endDate":   formatUntisDate(end),
+	}
+}
+
+// UntisDateFormat is the format dates are sent to and received from the untis api in
+const UntisDateFormat = "20060102"
+
+// isoDateFormat is the ISO 8601 format of dates additionally accepted by parseUntisDate
+const isoDateFormat = "2006-01-02"
+
+// formatUntisDate formats the date of t in the untis time zone as used by untis (e.g. 20210412)
+func formatUntisDate(t time.Time) string {
+	return t.In(Location()).Format(UntisDateFormat)
+}
+
+// parseUntisDate parses a date either in the ISO form (2021-04-12) or the untis form (20210412)
+// the returned time is the start of the day in the untis time zone
+func parseUntisDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	layout := UntisDateFormat
+	if strings.Contains(s, "-") {
+		layout = isoDateFormat
+	}
+	t, err := time.ParseInLocation(layout, s, Location())
 	if err != nil {
-		return nil, err
+		return time.Time{}, fmt.Errorf("invalid date %q", s)
 	}
+	return t, nil
+}
+
+// timetableEntry represents a single lesson as returned by getTimetable
+type timetableEntry struct {
+	ID        int    `json:"id"`
+	Date      int    `json:"date"`
+	StartTime int    `json:"startTime"`
+	EndTime   int    `json:"endTime"`
+	Code      string `json:"code"`
+	SubstText string `json:"substText"`
+	LsText    string `json:"lstext"`
+	Info      string `json:"info"`
+	Kl        []struct {
+		ID int `json:"id"`
+	} `json:"kl"`
+	Te []struct {
+		ID    int `json:"id"`
+		OrgID int `json:"orgid"`
+	} `json:"te"`
+	Su []struct {
+		ID int `json:"id"`
+	} `json:"su"`
+	Ro []struct {
+		ID    int `json:"id"`
+		OrgID int `json:"orgid"`
+	} `json:"ro"`
+}
+
+// parseTimetableResponse decodes the body of a getTimetable response into lessons
+// only the ids of classes, teachers, rooms and subjects are set, the names have to be resolved afterwards
+// the original teachers and rooms are only set for substituted teachers and moved rooms (an orgid in untis)
+func parseTimetableResponse(respBody []byte, expectedID int) ([]Lesson, error) {
 	r := struct {
-		JSONRPC string `json:"jsonrpc"`
-		ID      string `json:"id"`
-		Result  []struct {
-			ID        int    `json:"id"`
-			Name      string `json:"name"`
-			Forename  string `json:"foreName"`
-			Longname  string `json:"longName"`
-			ForeColor string `json:"foreColor"`
-			BackColor string `json:"backColor"`
-		} `json:"result"`
+		JSONRPC string           `json:"jsonrpc"`
+		ID      string           `json:"id"`
+		Result  []timetableEntry `json:"result"`
 	}{}
-	err = json.Unmarshal(respBody, &r)
+	err := json.Unmarshal(respBody, &r)
 	if err != nil {
 		return nil, err
 	}
 	rid, _ := strconv.Atoi(r.ID)
-	if id == rid {
-		teacher := make([]string, 0)
-		for _, id := range ids {
-			for _, res := range r.Result {
-				if id == res.ID {
-					teacher = append(teacher, res.Name)
-				}
+	if rid != expectedID {
+		return nil, &IDMismatchError{Method: "getTimetable", Expected: expectedID, Got: rid}
+	}
+	lessons := make([]Lesson, 0)
+	for _, l := range r.Result {
+		year, month, day := parseUntisDateInt(l.Date)
+		startHour, startMinute := parseUntisTime(l.StartTime)
+		endHour, endMinute := parseUntisTime(l.EndTime)
+		classIDArr := make([]int, 0)
+		for _, kls := range l.Kl {
+			classIDArr = append(classIDArr, kls.ID)
+		}
+		teachIDArr := make([]int, 0)
+		orgTeachIDArr := make([]int, 0)
+		for _, tes := range l.Te {
+			teachIDArr = append(teachIDArr, tes.ID)
+			if tes.OrgID != 0 {
+				orgTeachIDArr = append(orgTeachIDArr, tes.OrgID)
 			}
 		}
-		return teacher, nil
-	}
-	return nil, fmt.Errorf("ids not matching")
+		roomIDArr := make([]int, 0)
+		orgRoomIDArr := make([]int, 0)
+		for _, ros := range l.Ro {
+			roomIDArr = append(roomIDArr, ros.ID)
+			if ros.OrgID != 0 {
+				orgRoomIDArr = append(orgRoomIDArr, ros.OrgID)
+			}
+		}
+		subjectIDArr := make([]int, 0)
+		for _, sus := range l.Su {
+			subjectIDArr = append(subjectIDArr, sus.ID)
+		}
+		lessons = append(lessons, Lesson{
+			ID:                 l.ID,
+			Start:              time.Date(year, month, day, startHour, startMinute, 0, 0, Location()),
+			End:                time.Date(year, month, day, endHour, endMinute, 0, 0, Location()),
+			ClassIDs:           classIDArr,
+			TeacherIDs:         teachIDArr,
+			RoomIDs:            roomIDArr,
+			OriginalTeacherIDs: orgTeachIDArr,
+			OriginalRoomIDs:    orgRoomIDArr,
+			SubjectIDs:         subjectIDArr,
+			Cancelled:          l.Code == "cancelled",
+			Irregular:          l.Code == "irregular",
+			SubstitutionText:   l.SubstText,
+			LessonText:         l.LsText,
+			Info:               l.Info,
+		})
+	}
+	return lessons, nil
 }
 
-// ResolveTeacherID converts a teacher name to the corersponding teacher id
-func (client Client) ResolveTeacherID(teacher string) (int, error) {
-	if !client.Authenticated {
-		return -1, fmt.Errorf("not authenticated")
-	}
-	resp, id, err := client.sendRequest("getTeachers", map[string]interface{}{})
+// parseUntisDateInt splits a date as used by untis (e.g. 20210412) into year, month and day
+// if the date is invalid, all of them are 0
+func parseUntisDateInt(d int) (int, time.Month, int) {
+	t, err := parseUntisDate(strconv.Itoa(d))
 	if err != nil {
-		return -1, err
+		return 0, 0, 0
 	}
-	defer resp.Body.Close()
-	respBody, err := ioutil.ReadAll(resp.Body)
+	return t.Date()
+}
+
+// parseUntisTime splits a time as used by untis (e.g. 745 for 07:45 or 55 for 00:55) into hour and minute
+// the time is zero-padded to four digits before it is split
+func parseUntisTime(t int) (int, int) {
+	padded := fmt.Sprintf("%04d", t)
+	hour, _ := strconv.Atoi(padded[0 : len(padded)-2])
+	minute, _ := strconv.Atoi(padded[len(padded)-2:])
+	return hour, minute
+}
+
+// warmResolvers fetches the teachers, rooms, classes and subjects not cached yet concurrently,
+// so resolving the names of lessons afterwards only looks them up in the caches
+func (client *Client) warmResolvers(ctx context.Context) error {
+	fetches := []func(context.Context) error{client.fetchTeachers, client.fetchRooms, client.fetchClasses, client.fetchSubjects}
+	errs := make([]error, len(fetches))
+	var wg sync.WaitGroup
+	for i, fetch := range fetches {
+		wg.Add(1)
+		go func(i int, fetch func(context.Context) error) {
+			defer wg.Done()
+			errs[i] = fetch(ctx)
+		}(i, fetch)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveLessons resolves the names of the classes, teachers, rooms, subjects and original teachers and rooms of all given lessons
+// the master data is fetched once up front by warmResolvers, nothing is fetched if there are no lessons
+func (client *Client) resolveLessons(ctx context.Context, lessons []Lesson) error {
+	if len(lessons) == 0 {
+		return nil
+	}
+	if err := client.warmResolvers(ctx); err != nil {
+		return err
+	}
+	for i := range lessons {
+		lesson := &lessons[i]
+		var err error
+		lesson.Classes, err = client.ResolveClassesContext(ctx, lesson.ClassIDs)
+		if err != nil {
+			return err
+		}
+		lesson.Teachers, err = client.ResolveTeachersContext(ctx, lesson.TeacherIDs)
+		if err != nil {
+			return err
+		}
+		lesson.Rooms, err = client.ResolveRoomsContext(ctx, lesson.RoomIDs)
+		if err != nil {
+			return err
+		}
+		lesson.OriginalTeachers, err = client.ResolveTeachersContext(ctx, lesson.OriginalTeacherIDs)
+		if err != nil {
+			return err
+		}
+		lesson.OriginalRooms, err = client.ResolveRoomsContext(ctx, lesson.OriginalRoomIDs)
+		if err != nil {
+			return err
+		}
+		lesson.Subjects, err = client.ResolveSubjectsContext(ctx, lesson.SubjectIDs)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetHolidays returns all holidays known to untis
+func (client *Client) GetHolidays() ([]Holiday, error) {
+	return client.GetHolidaysContext(context.Background())
+}
+
+// GetHolidaysContext is like GetHolidays but uses ctx for the requests sent to the untis api
+func (client *Client) GetHolidaysContext(ctx context.Context) ([]Holiday, error) {
+	if !client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	respBody, id, err := client.sendRequest(ctx, "getHolidays", map[string]interface{}{})
 	if err != nil {
-		return -1, err
+		return nil, err
 	}
 	r := struct {
 		JSONRPC string `json:"jsonrpc"`
@@ -550,279 +1172,1142 @@ func (client Client) ResolveTeacherID(teacher string) (int, error) {
 		Result  []struct {
 			ID        int    `json:"id"`
 			Name      string `json:"name"`
-			ForeName  string `json:"foreName"`
 			LongName  string `json:"longName"`
-			ForeColor string `json:"foreColor"`
-			BackColor string `json:"backColor"`
+			StartDate int    `json:"startDate"`
+			EndDate   int    `json:"endDate"`
 		} `json:"result"`
 	}{}
 	err = json.Unmarshal(respBody, &r)
 	if err != nil {
-		return -1, err
+		return nil, err
 	}
 	rid, _ := strconv.Atoi(r.ID)
-	if rid == id {
-		split := strings.Split(teacher, " ")
-		forename := split[0]
-		longname := strings.ToUpper(split[1])
-		for _, res := range r.Result {
-			surname := strings.Split(res.LongName, " ")[0]
-			if forename == res.ForeName && longname == surname {
-				return res.ID, nil
-			}
+	if rid != id {
+		return nil, &IDMismatchError{Method: "getHolidays", Expected: id, Got: rid}
+	}
+	holidays := make([]Holiday, 0)
+	for _, res := range r.Result {
+		startYear, startMonth, startDay := parseUntisDateInt(res.StartDate)
+		endYear, endMonth, endDay := parseUntisDateInt(res.EndDate)
+		holidays = append(holidays, Holiday{
+			Name:     res.Name,
+			LongName: res.LongName,
+			Start:    time.Date(startYear, startMonth, startDay, 0, 0, 0, 0, Location()),
+			End:      time.Date(endYear, endMonth, endDay, 0, 0, 0, 0, Location()),
+		})
+	}
+	return holidays, nil
+}
+
+// GetLatestImportTime returns the time the timetable data was last imported into untis
+func (client *Client) GetLatestImportTime() (time.Time, error) {
+	return client.GetLatestImportTimeContext(context.Background())
+}
+
+// GetLatestImportTimeContext is like GetLatestImportTime but uses ctx for the requests sent to the untis api
+func (client *Client) GetLatestImportTimeContext(ctx context.Context) (time.Time, error) {
+	if !client.IsAuthenticated() {
+		return time.Time{}, ErrNotAuthenticated
+	}
+	respBody, id, err := client.sendRequest(ctx, "getLatestImportTime", map[string]interface{}{})
+	if err != nil {
+		return time.Time{}, err
+	}
+	importTime, err := parseLatestImportTimeResponse(respBody, id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	client.observeImportTime(importTime)
+	return importTime, nil
+}
+
+// SessionValid checks whether the session of the client is still accepted by the untis api with a cheap
+// getLatestImportTime request, which isn't retried with a new session even if AutoReauth is set
+// Authenticated is reset if untis reports the session as expired; false is also returned if the request failed otherwise
+func (client *Client) SessionValid() bool {
+	return client.SessionValidContext(context.Background())
+}
+
+// SessionValidContext is like SessionValid but uses ctx for the request sent to the untis api
+func (client *Client) SessionValidContext(ctx context.Context) bool {
+	session := client.session()
+	if !client.IsAuthenticated() || session == "" {
+		return false
+	}
+	respBody, id, err := client.doRequest(ctx, "getLatestImportTime", map[string]interface{}{})
+	if err == nil {
+		_, err = parseLatestImportTimeResponse(respBody, id)
+	}
+	if HasErrorCode(err, NotAuthenticatedErrorCode) {
+		if client.session() == session {
+			client.expireSession()
 		}
-		return -1, fmt.Errorf("teacher not found")
+		return false
+	}
+	if err != nil {
+		client.logf("level=warn request_id=%v untis_method=getLatestImportTime msg=%q", RequestIDFromContext(ctx), "couldn't check session: "+err.Error())
+		return false
 	}
-	return -1, fmt.Errorf("ids not matching")
+	return true
 }
 
-// ResolveRooms converts an array of room ids into an array of room names
-func (client Client) ResolveRooms(ids []int) ([]string, error) {
-	if !client.Authenticated {
-		return nil, fmt.Errorf("not authenticated")
+// parseLatestImportTimeResponse decodes the unix millisecond timestamp of a getLatestImportTime response into a time in UTC
+func parseLatestImportTimeResponse(respBody []byte, expectedID int) (time.Time, error) {
+	r := struct {
+		JSONRPC string `json:"jsonrpc"`
+		ID      string `json:"id"`
+		Result  int64  `json:"result"`
+	}{}
+	err := json.Unmarshal(respBody, &r)
+	if err != nil {
+		return time.Time{}, err
 	}
-	resp, id, err := client.sendRequest("getRooms", map[string]interface{}{})
+	rid, _ := strconv.Atoi(r.ID)
+	if rid != expectedID {
+		return time.Time{}, &IDMismatchError{Method: "getLatestImportTime", Expected: expectedID, Got: rid}
+	}
+	return time.Unix(0, r.Result*int64(time.Millisecond)).UTC(), nil
+}
+
+// namesOf returns the names of the ids of the kind (e.g. teacher) in the order of the ids, known maps the ids untis
+// knows to their names; unknown ids are replaced by unresolvedName and logged, so names and ids stay the same length
+func (client *Client) namesOf(ctx context.Context, kind string, ids []int, known map[int]string) []string {
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		name, ok := known[id]
+		if !ok {
+			client.logf("level=warn request_id=%v msg=%q", RequestIDFromContext(ctx), fmt.Sprintf("couldn't resolve %v id %v", kind, id))
+			name = unresolvedName(id)
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// unresolvedName is the placeholder used for an id untis doesn't know
+func unresolvedName(id int) string {
+	return "#" + strconv.Itoa(id)
+}
+
+// ResolveTeachers converts an array of teacher ids into an array of teacher names
+// the names have the same order as the ids, unknown ids are replaced by a placeholder (e.g. "#42")
+func (client *Client) ResolveTeachers(ids []int) ([]string, error) {
+	return client.ResolveTeachersContext(context.Background(), ids)
+}
+
+// ResolveTeachersContext is like ResolveTeachers but uses ctx for the requests sent to the untis api
+func (client *Client) ResolveTeachersContext(ctx context.Context, ids []int) ([]string, error) {
+	full, err := client.ResolveTeachersFullContext(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	respBody, err := ioutil.ReadAll(resp.Body)
+	known := make(map[int]string, len(full))
+	for _, res := range full {
+		known[res.ID] = res.Name
+	}
+	return client.namesOf(ctx, "teacher", ids, known), nil
+}
+
+// ResolveTeachersFull converts an array of teacher ids into an array of teachers with all fields provided by untis
+func (client *Client) ResolveTeachersFull(ids []int) ([]Teacher, error) {
+	return client.ResolveTeachersFullContext(context.Background(), ids)
+}
+
+// ResolveTeachersFullContext is like ResolveTeachersFull but uses ctx for the requests sent to the untis api
+func (client *Client) ResolveTeachersFullContext(ctx context.Context, ids []int) ([]Teacher, error) {
+	if !client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	err := client.fetchTeachers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Teacher, 0)
+	for _, id := range ids {
+		if entry, ok := client.teachers()[id]; ok {
+			res = append(res, entry)
+		}
+	}
+	return res, nil
+}
+
+// ListTeachers returns all teachers known to untis sorted by their long name
+func (client *Client) ListTeachers() ([]Teacher, error) {
+	return client.ListTeachersContext(context.Background())
+}
+
+// ListTeachersContext is like ListTeachers but uses ctx for the requests sent to the untis api
+func (client *Client) ListTeachersContext(ctx context.Context) ([]Teacher, error) {
+	if !client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	err := client.fetchTeachers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	teachers := make([]Teacher, 0, len(client.teachers()))
+	for _, res := range client.teachers() {
+		teachers = append(teachers, res)
+	}
+	sort.Slice(teachers, func(i, j int) bool {
+		if teachers[i].LongName == teachers[j].LongName {
+			return teachers[i].ID < teachers[j].ID
+		}
+		return teachers[i].LongName < teachers[j].LongName
+	})
+	return teachers, nil
+}
+
+// ListClasses returns all classes known to untis sorted by their name
+func (client *Client) ListClasses() ([]Class, error) {
+	return client.ListClassesContext(context.Background())
+}
+
+// ListClassesContext is like ListClasses but uses ctx for the requests sent to the untis api
+func (client *Client) ListClassesContext(ctx context.Context) ([]Class, error) {
+	if !client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	err := client.fetchClasses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	classes := make([]Class, 0, len(client.classes()))
+	for _, res := range client.classes() {
+		classes = append(classes, res)
+	}
+	sort.Slice(classes, func(i, j int) bool {
+		if classes[i].Name == classes[j].Name {
+			return classes[i].ID < classes[j].ID
+		}
+		return classes[i].Name < classes[j].Name
+	})
+	return classes, nil
+}
+
+// ListRooms returns all rooms known to untis sorted by their name
+func (client *Client) ListRooms() ([]Room, error) {
+	return client.ListRoomsContext(context.Background())
+}
+
+// ListRoomsContext is like ListRooms but uses ctx for the requests sent to the untis api
+func (client *Client) ListRoomsContext(ctx context.Context) ([]Room, error) {
+	if !client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	err := client.fetchRooms(ctx)
 	if err != nil {
 		return nil, err
 	}
+	rooms := make([]Room, 0, len(client.rooms()))
+	for _, res := range client.rooms() {
+		rooms = append(rooms, res)
+	}
+	sort.Slice(rooms, func(i, j int) bool {
+		if rooms[i].Name == rooms[j].Name {
+			return rooms[i].ID < rooms[j].ID
+		}
+		return rooms[i].Name < rooms[j].Name
+	})
+	return rooms, nil
+}
+
+// SearchTeachers returns up to limit teachers whose forename, long name or short name contains the query
+// case-insensitively. The teachers are ranked by match quality: exact matches of a name come first,
+// followed by names starting with the query and names only containing it. An empty query matches no teacher
+func SearchTeachers(teachers []Teacher, query string, limit int) []Teacher {
+	query = normalizeName(query)
+	res := make([]Teacher, 0)
+	if query == "" || limit < 1 {
+		return res
+	}
+	ranks := make(map[int]int)
+	for _, teacher := range teachers {
+		best := -1
+		for _, name := range []string{teacher.Name, teacher.ForeName, teacher.LongName, teacher.ForeName + " " + teacher.LongName} {
+			name = normalizeName(name)
+			rank := -1
+			switch {
+			case name == query:
+				rank = 0
+			case strings.HasPrefix(name, query):
+				rank = 1
+			case strings.Contains(name, query):
+				rank = 2
+			}
+			if rank >= 0 && (best < 0 || rank < best) {
+				best = rank
+			}
+		}
+		if best >= 0 {
+			ranks[teacher.ID] = best
+			res = append(res, teacher)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return ranks[res[i].ID] < ranks[res[j].ID]
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res
+}
+
+// ResolveTeacherID converts a teacher name to the corersponding teacher id
+// ErrTeacherNotFound is returned if there is no teacher with this name
+func (client *Client) ResolveTeacherID(teacher string) (int, error) {
+	return client.ResolveTeacherIDContext(context.Background(), teacher)
+}
+
+// ResolveTeacherIDContext is like ResolveTeacherID but uses ctx for the requests sent to the untis api
+func (client *Client) ResolveTeacherIDContext(ctx context.Context, teacher string) (int, error) {
+	if !client.IsAuthenticated() {
+		return -1, ErrNotAuthenticated
+	}
+	err := client.fetchTeachers(ctx)
+	if err != nil {
+		return -1, err
+	}
+	return matchTeacher(teacher, client.teachers())
+}
+
+// matchTeacher looks up the id of a teacher by either the full name (forename followed by long name) or,
+// if only a single word is given, the short name. Names are compared case-insensitively, where the full name may
+// also be a prefix of the untis name, e.g. if untis appends a title to the long name. Exact matches take precedence
+// over prefix matches, ErrTeacherNotFound is returned if no teacher matches
+func matchTeacher(teacher string, teachers map[int]Teacher) (int, error) {
+	name := normalizeName(teacher)
+	if name == "" {
+		return -1, ErrTeacherNotFound
+	}
+	exact := make([]int, 0)
+	prefix := make([]int, 0)
+	if !strings.Contains(name, " ") {
+		for _, res := range teachers {
+			if name == normalizeName(res.Name) {
+				exact = append(exact, res.ID)
+			}
+		}
+	} else {
+		for _, res := range teachers {
+			full := normalizeName(res.ForeName + " " + res.LongName)
+			if name == full {
+				exact = append(exact, res.ID)
+			} else if strings.HasPrefix(full, name+" ") {
+				prefix = append(prefix, res.ID)
+			}
+		}
+	}
+	if len(exact) == 0 {
+		exact = prefix
+	}
+	switch len(exact) {
+	case 0:
+		return -1, ErrTeacherNotFound
+	case 1:
+		return exact[0], nil
+	default:
+		return -1, fmt.Errorf("teacher name %q is ambiguous, %d teachers match", teacher, len(exact))
+	}
+}
+
+// normalizeName lowercases a name and collapses all whitespace to single spaces
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// ResolveStudentID converts the forename and surname of a student to the corresponding student id
+// ErrStudentNotFound is returned if there is no student with this name
+func (client *Client) ResolveStudentID(forename, surname string) (int, error) {
+	return client.ResolveStudentIDContext(context.Background(), forename, surname)
+}
+
+// ResolveStudentIDContext is like ResolveStudentID but uses ctx for the requests sent to the untis api
+func (client *Client) ResolveStudentIDContext(ctx context.Context, forename, surname string) (int, error) {
+	if !client.IsAuthenticated() {
+		return -1, ErrNotAuthenticated
+	}
+	respBody, id, err := client.sendRequest(ctx, "getStudents", map[string]interface{}{})
+	if err != nil {
+		return -1, err
+	}
 	r := struct {
 		JSONRPC string `json:"jsonrpc"`
 		ID      string `json:"id"`
 		Result  []struct {
-			ID        int    `json:"id"`
-			Name      string `json:"name"`
-			Longname  string `json:"longName"`
-			ForeColor string `json:"foreColor"`
-			BackColor string `json:"backColor"`
+			ID       int    `json:"id"`
+			Key      string `json:"key"`
+			Name     string `json:"name"`
+			ForeName string `json:"foreName"`
+			LongName string `json:"longName"`
+			Gender   string `json:"gender"`
 		} `json:"result"`
 	}{}
 	err = json.Unmarshal(respBody, &r)
 	if err != nil {
-		return nil, err
+		return -1, err
 	}
 	rid, _ := strconv.Atoi(r.ID)
-	if id == rid {
-		rooms := make([]string, 0)
-		for _, id := range ids {
-			for _, res := range r.Result {
-				if id == res.ID {
-					rooms = append(rooms, res.Name)
-				}
-			}
+	if rid != id {
+		return -1, &IDMismatchError{Method: "getStudents", Expected: id, Got: rid}
+	}
+	for _, res := range r.Result {
+		if strings.EqualFold(forename, res.ForeName) && strings.EqualFold(surname, res.LongName) {
+			return res.ID, nil
 		}
-		return rooms, nil
 	}
-	return nil, fmt.Errorf("ids not matching")
+	return -1, ErrStudentNotFound
+}
+
+// ResolveRooms converts an array of room ids into an array of room names
+// the names have the same order as the ids, unknown ids are replaced by a placeholder (e.g. "#42")
+func (client *Client) ResolveRooms(ids []int) ([]string, error) {
+	return client.ResolveRoomsContext(context.Background(), ids)
+}
+
+// ResolveRoomsContext is like ResolveRooms but uses ctx for the requests sent to the untis api
+func (client *Client) ResolveRoomsContext(ctx context.Context, ids []int) ([]string, error) {
+	full, err := client.ResolveRoomsFullContext(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[int]string, len(full))
+	for _, res := range full {
+		known[res.ID] = res.Name
+	}
+	return client.namesOf(ctx, "room", ids, known), nil
+}
+
+// ResolveRoomsFull converts an array of room ids into an array of rooms with all fields provided by untis
+func (client *Client) ResolveRoomsFull(ids []int) ([]Room, error) {
+	return client.ResolveRoomsFullContext(context.Background(), ids)
+}
+
+// ResolveRoomsFullContext is like ResolveRoomsFull but uses ctx for the requests sent to the untis api
+func (client *Client) ResolveRoomsFullContext(ctx context.Context, ids []int) ([]Room, error) {
+	if !client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	err := client.fetchRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Room, 0)
+	for _, id := range ids {
+		if entry, ok := client.rooms()[id]; ok {
+			res = append(res, entry)
+		}
+	}
+	return res, nil
+}
+
+// ResolveRoomID converts a room name (its short or its full name, regardless of the case) to the corresponding room id
+// ErrRoomNotFound is returned if there is no room with this name
+func (client *Client) ResolveRoomID(room string) (int, error) {
+	return client.ResolveRoomIDContext(context.Background(), room)
+}
+
+// ResolveRoomIDContext is like ResolveRoomID but uses ctx for the requests sent to the untis api
+func (client *Client) ResolveRoomIDContext(ctx context.Context, room string) (int, error) {
+	if !client.IsAuthenticated() {
+		return -1, ErrNotAuthenticated
+	}
+	err := client.fetchRooms(ctx)
+	if err != nil {
+		return -1, err
+	}
+	return matchRoom(room, client.rooms())
+}
+
+// matchRoom returns the id of the room whose short name or otherwise whose full name equals room ignoring the case
+func matchRoom(room string, rooms map[int]Room) (int, error) {
+	room = strings.TrimSpace(room)
+	if room == "" {
+		return -1, ErrRoomNotFound
+	}
+	byLongName := -1
+	for _, res := range rooms {
+		if strings.EqualFold(room, res.Name) {
+			return res.ID, nil
+		}
+		if strings.EqualFold(room, res.LongName) {
+			byLongName = res.ID
+		}
+	}
+	if byLongName == -1 {
+		return -1, ErrRoomNotFound
+	}
+	return byLongName, nil
+}
+
+// ResolveSubjects converts an array of subject ids into an array of subject names
+// the names have the same order as the ids, unknown ids are replaced by a placeholder (e.g. "#42")
+func (client *Client) ResolveSubjects(ids []int) ([]string, error) {
+	return client.ResolveSubjectsContext(context.Background(), ids)
+}
+
+// ResolveSubjectsContext is like ResolveSubjects but uses ctx for the requests sent to the untis api
+func (client *Client) ResolveSubjectsContext(ctx context.Context, ids []int) ([]string, error) {
+	full, err := client.ResolveSubjectsFullContext(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[int]string, len(full))
+	for _, res := range full {
+		known[res.ID] = res.Name
+	}
+	return client.namesOf(ctx, "subject", ids, known), nil
+}
+
+// ResolveSubjectsFull converts an array of subject ids into an array of subjects with all fields provided by untis
+func (client *Client) ResolveSubjectsFull(ids []int) ([]Subject, error) {
+	return client.ResolveSubjectsFullContext(context.Background(), ids)
+}
+
+// ResolveSubjectsFullContext is like ResolveSubjectsFull but uses ctx for the requests sent to the untis api
+func (client *Client) ResolveSubjectsFullContext(ctx context.Context, ids []int) ([]Subject, error) {
+	if !client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	err := client.fetchSubjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Subject, 0)
+	for _, id := range ids {
+		if entry, ok := client.subjects()[id]; ok {
+			res = append(res, entry)
+		}
+	}
+	return res, nil
 }
 
 // ResolveClasses converts an array of class ids into an array of class names
-func (client Client) ResolveClasses(ids []int) ([]string, error) {
-	if !client.Authenticated {
-		return nil, fmt.Errorf("not authenticated")
+// the names have the same order as the ids, unknown ids are replaced by a placeholder (e.g. "#42")
+func (client *Client) ResolveClasses(ids []int) ([]string, error) {
+	return client.ResolveClassesContext(context.Background(), ids)
+}
+
+// ResolveClassesContext is like ResolveClasses but uses ctx for the requests sent to the untis api
+func (client *Client) ResolveClassesContext(ctx context.Context, ids []int) ([]string, error) {
+	full, err := client.ResolveClassesFullContext(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[int]string, len(full))
+	for _, res := range full {
+		known[res.ID] = res.Name
 	}
-	resp, id, err := client.sendRequest("getKlassen", map[string]interface{}{})
+	return client.namesOf(ctx, "class", ids, known), nil
+}
+
+// ResolveClassesFull converts an array of class ids into an array of classes with all fields provided by untis
+func (client *Client) ResolveClassesFull(ids []int) ([]Class, error) {
+	return client.ResolveClassesFullContext(context.Background(), ids)
+}
+
+// ResolveClassesFullContext is like ResolveClassesFull but uses ctx for the requests sent to the untis api
+func (client *Client) ResolveClassesFullContext(ctx context.Context, ids []int) ([]Class, error) {
+	if !client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	err := client.fetchClasses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Class, 0)
+	for _, id := range ids {
+		if entry, ok := client.classes()[id]; ok {
+			res = append(res, entry)
+		}
+	}
+	return res, nil
+}
+
+// ResolveClassID converts a class name to the corresponding class id
+// ErrClassNotFound is returned if there is no class with this name
+func (client *Client) ResolveClassID(class string) (int, error) {
+	return client.ResolveClassIDContext(context.Background(), class)
+}
+
+// ResolveClassIDContext is like ResolveClassID but uses ctx for the requests sent to the untis api
+func (client *Client) ResolveClassIDContext(ctx context.Context, class string) (int, error) {
+	if !client.IsAuthenticated() {
+		return -1, ErrNotAuthenticated
+	}
+	err := client.fetchClasses(ctx)
+	if err != nil {
+		return -1, err
+	}
+	for _, res := range client.classes() {
+		if class == res.Name {
+			return res.ID, nil
+		}
+	}
+	return -1, ErrClassNotFound
+}
+
+// GetClassTeachers returns the class teacher and the deputy class teacher of the class with the given name,
+// teachers not assigned or unknown to untis are left out, so it contains zero, one or two teachers
+// ErrClassNotFound is returned if there is no class with this name
+func (client *Client) GetClassTeachers(class string) ([]Teacher, error) {
+	return client.GetClassTeachersContext(context.Background(), class)
+}
+
+// GetClassTeachersContext is like GetClassTeachers but uses ctx for the requests sent to the untis api
+func (client *Client) GetClassTeachersContext(ctx context.Context, class string) ([]Teacher, error) {
+	id, err := client.ResolveClassIDContext(ctx, class)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	respBody, err := ioutil.ReadAll(resp.Body)
+	ids := make([]int, 0, 2)
+	for _, teacher := range []int{client.classes()[id].Teacher1, client.classes()[id].Teacher2} {
+		if teacher != 0 {
+			ids = append(ids, teacher)
+		}
+	}
+	return client.ResolveTeachersFullContext(ctx, ids)
+}
+
+// RefreshCaches drops the cached teachers, rooms, classes and subjects of the client (and the shared cache of its school) and fetches them again
+func (client *Client) RefreshCaches() error {
+	return client.RefreshCachesContext(context.Background())
+}
+
+// RefreshCachesContext is like RefreshCaches but uses ctx for the requests sent to the untis api
+func (client *Client) RefreshCachesContext(ctx context.Context) error {
+	if !client.IsAuthenticated() {
+		return ErrNotAuthenticated
+	}
+	client.clearCaches()
+	client.invalidateShared()
+	err := client.fetchTeachers(ctx)
+	if err != nil {
+		return err
+	}
+	err = client.fetchRooms(ctx)
+	if err != nil {
+		return err
+	}
+	err = client.fetchClasses(ctx)
+	if err != nil {
+		return err
+	}
+	return client.fetchSubjects(ctx)
+}
+
+// fetchTeachers fills the teacher cache of the client using getTeachers if it isn't filled yet, the shared cache is asked
+// on every call instead, so the client picks up the data fetched again after the ttl or a new import
+func (client *Client) fetchTeachers(ctx context.Context) error {
+	if !SharedCache && client.teachers() != nil {
+		return nil
+	}
+	value, err := client.loadShared("getTeachers", func() (interface{}, error) {
+		return client.requestTeachers(ctx)
+	})
+	if err != nil {
+		return err
+	}
+	client.mutex.Lock()
+	client.cachedTeachers = value.(map[int]Teacher)
+	client.mutex.Unlock()
+	return nil
+}
+
+// requestTeachers requests all teachers using getTeachers and maps them by their id
+func (client *Client) requestTeachers(ctx context.Context) (map[int]Teacher, error) {
+	respBody, id, err := client.sendRequest(ctx, "getTeachers", map[string]interface{}{})
 	if err != nil {
 		return nil, err
 	}
 	r := struct {
-		JSONRPC string `json:"jsonrpc"`
-		ID      string `json:"id"`
-		Result  []struct {
-			ID        int    `json:"id"`
-			Name      string `json:"name"`
-			Longname  string `json:"longName"`
-			ForeColor string `json:"foreColor"`
-			BackColor string `json:"backColor"`
-			Teacher1  int    `json:"teacher1"`
-			Teacher2  int    `json:"teacher2"`
-		} `json:"result"`
+		JSONRPC string    `json:"jsonrpc"`
+		ID      string    `json:"id"`
+		Result  []Teacher `json:"result"`
 	}{}
 	err = json.Unmarshal(respBody, &r)
 	if err != nil {
 		return nil, err
 	}
 	rid, _ := strconv.Atoi(r.ID)
-	if rid == id {
-		classes := make([]string, 0)
-		for _, id := range ids {
-			for _, res := range r.Result {
-				if id == res.ID {
-					classes = append(classes, res.Name)
-				}
-			}
-		}
-		return classes, nil
+	if rid != id {
+		return nil, &IDMismatchError{Method: "getTeachers", Expected: id, Got: rid}
+	}
+	result := make(map[int]Teacher)
+	for _, res := range r.Result {
+		result[res.ID] = res
 	}
-	return nil, fmt.Errorf("ids not matching")
+	return result, nil
 }
 
-// ResolveClassID converts a class name to the corresponding class id
-func (client Client) ResolveClassID(class string) (int, error) {
-	if !client.Authenticated {
-		return -1, fmt.Errorf("not authenticated")
+// fetchRooms fills the room cache of the client using getRooms if it isn't filled yet, the shared cache is asked
+// on every call instead, so the client picks up the data fetched again after the ttl or a new import
+func (client *Client) fetchRooms(ctx context.Context) error {
+	if !SharedCache && client.rooms() != nil {
+		return nil
 	}
-	resp, id, err := client.sendRequest("getKlassen", map[string]interface{}{})
+	value, err := client.loadShared("getRooms", func() (interface{}, error) {
+		return client.requestRooms(ctx)
+	})
 	if err != nil {
-		return -1, err
+		return err
 	}
-	defer resp.Body.Close()
-	respBody, err := ioutil.ReadAll(resp.Body)
+	client.mutex.Lock()
+	client.cachedRooms = value.(map[int]Room)
+	client.mutex.Unlock()
+	return nil
+}
+
+// requestRooms requests all rooms using getRooms and maps them by their id
+func (client *Client) requestRooms(ctx context.Context) (map[int]Room, error) {
+	respBody, id, err := client.sendRequest(ctx, "getRooms", map[string]interface{}{})
 	if err != nil {
-		return -1, err
+		return nil, err
 	}
 	r := struct {
 		JSONRPC string `json:"jsonrpc"`
 		ID      string `json:"id"`
-		Result  []struct {
-			ID        int    `json:"id"`
-			Name      string `json:"name"`
-			Longname  string `json:"longName"`
-			ForeColor string `json:"foreColor"`
-			BackColor string `json:"backColor"`
-			Teacher1  int    `json:"teacher1"`
-			Teacher2  int    `json:"teacher2"`
-		} `json:"result"`
+		Result  []Room `json:"result"`
 	}{}
 	err = json.Unmarshal(respBody, &r)
 	if err != nil {
-		return -1, err
+		return nil, err
 	}
 	rid, _ := strconv.Atoi(r.ID)
-	if rid == id {
-		for _, res := range r.Result {
-			if class == res.Name {
-				return res.ID, nil
-			}
-		}
-		return -1, fmt.Errorf("class not found")
+	if rid != id {
+		return nil, &IDMismatchError{Method: "getRooms", Expected: id, Got: rid}
+	}
+	result := make(map[int]Room)
+	for _, res := range r.Result {
+		result[res.ID] = res
 	}
-	return -1, fmt.Errorf("ids not matching")
+	return result, nil
+}
+
+// fetchClasses fills the class cache of the client using getKlassen if it isn't filled yet, the shared cache is asked
+// on every call instead, so the client picks up the data fetched again after the ttl or a new import
+func (client *Client) fetchClasses(ctx context.Context) error {
+	if !SharedCache && client.classes() != nil {
+		return nil
+	}
+	value, err := client.loadShared("getKlassen", func() (interface{}, error) {
+		return client.requestClasses(ctx)
+	})
+	if err != nil {
+		return err
+	}
+	client.mutex.Lock()
+	client.cachedClasses = value.(map[int]Class)
+	client.mutex.Unlock()
+	return nil
+}
+
+// requestClasses requests all classes using getKlassen and maps them by their id
+func (client *Client) requestClasses(ctx context.Context) (map[int]Class, error) {
+	respBody, id, err := client.sendRequest(ctx, "getKlassen", map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	r := struct {
+		JSONRPC string  `json:"jsonrpc"`
+		ID      string  `json:"id"`
+		Result  []Class `json:"result"`
+	}{}
+	err = json.Unmarshal(respBody, &r)
+	if err != nil {
+		return nil, err
+	}
+	rid, _ := strconv.Atoi(r.ID)
+	if rid != id {
+		return nil, &IDMismatchError{Method: "getKlassen", Expected: id, Got: rid}
+	}
+	result := make(map[int]Class)
+	for _, res := range r.Result {
+		result[res.ID] = res
+	}
+	return result, nil
+}
+
+// fetchSubjects fills the subject cache of the client using getSubjects if it isn't filled yet, the shared cache is asked
+// on every call instead, so the client picks up the data fetched again after the ttl or a new import
+func (client *Client) fetchSubjects(ctx context.Context) error {
+	if !SharedCache && client.subjects() != nil {
+		return nil
+	}
+	value, err := client.loadShared("getSubjects", func() (interface{}, error) {
+		return client.requestSubjects(ctx)
+	})
+	if err != nil {
+		return err
+	}
+	client.mutex.Lock()
+	client.cachedSubjects = value.(map[int]Subject)
+	client.mutex.Unlock()
+	return nil
+}
+
+// requestSubjects requests all subjects using getSubjects and maps them by their id
+func (client *Client) requestSubjects(ctx context.Context) (map[int]Subject, error) {
+	respBody, id, err := client.sendRequest(ctx, "getSubjects", map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	r := struct {
+		JSONRPC string    `json:"jsonrpc"`
+		ID      string    `json:"id"`
+		Result  []Subject `json:"result"`
+	}{}
+	err = json.Unmarshal(respBody, &r)
+	if err != nil {
+		return nil, err
+	}
+	rid, _ := strconv.Atoi(r.ID)
+	if rid != id {
+		return nil, &IDMismatchError{Method: "getSubjects", Expected: id, Got: rid}
+	}
+	result := make(map[int]Subject)
+	for _, res := range r.Result {
+		result[res.ID] = res
+	}
+	return result, nil
+}
+
+// clearCaches drops all cached teachers, rooms, classes and subjects of the client
+func (client *Client) clearCaches() {
+	client.mutex.Lock()
+	client.cachedTeachers = nil
+	client.cachedRooms = nil
+	client.cachedClasses = nil
+	client.cachedSubjects = nil
+	client.mutex.Unlock()
 }
 
 // Close closes an authenticated connection to the untis api
+// the client is only marked closed once the logout succeeded (or untis reports the session ended already),
+// if it fails the client stays authenticated unless ForceCloseOnError is set
 func (client *Client) Close() error {
-	if !client.Authenticated {
-		return fmt.Errorf("not authenticated")
+	return client.CloseContext(context.Background())
+}
+
+// CloseContext is like Close but uses ctx for the requests sent to the untis api
+func (client *Client) CloseContext(ctx context.Context) error {
+	client.lifecycle.Lock()
+	defer client.lifecycle.Unlock()
+	return client.close(ctx)
+}
+
+// close logs out of the current session, the callers have to hold the lifecycle lock
+func (client *Client) close(ctx context.Context) error {
+	if !client.IsAuthenticated() {
+		return ErrNotAuthenticated
+	}
+	_, _, err := client.sendRequest(ctx, "logout", map[string]interface{}{})
+	if err != nil && !HasErrorCode(err, NotAuthenticatedErrorCode) {
+		if client.ForceCloseOnError {
+			client.ForceClose()
+		}
+		return err
 	}
+	client.ForceClose()
+	return nil
+}
+
+// ForceClose clears the local state of the session without logging out at untis,
+// e.g. after Close failed because untis couldn't be reached; the session at untis expires on its own
+// the users which acquired the session are dropped, their Release returns ErrNotAuthenticated
+func (client *Client) ForceClose() {
+	client.mutex.Lock()
 	client.Closed = true
 	client.Authenticated = false
-	_, _, err := client.sendRequest("logout", map[string]interface{}{})
+	client.SessionID = ""
+	client.users = 0
+	client.mutex.Unlock()
+	client.clearCaches()
+}
+
+// DeleteClient deletes the current client out of the map of active clients, other clients of the user are kept
+func (client *Client) DeleteClient() {
+	activeClientsMutex.Lock()
+	removeActiveClient(client)
+	activeClientsMutex.Unlock()
+}
+
+// CloseAllForUser closes the sessions of all active clients of the user and removes them from the active clients
+// clients without an authenticated session are only removed, the errors of all failed logouts are returned
+func CloseAllForUser(username string) []error {
+	activeClientsMutex.Lock()
+	clients := activeClients[username]
+	delete(activeClients, username)
+	activeClientsMutex.Unlock()
+	errs := make([]error, 0)
+	for _, client := range clients {
+		if !client.IsAuthenticated() {
+			continue
+		}
+		if err := client.Close(); err != nil {
+			client.ForceClose()
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// CheckReachability checks whether the untis server (Server) responds to http requests at all
+// it doesn't authenticate, so any response that isn't a server error (5xx) counts as reachable
+func CheckReachability(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", Server, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := defaultHTTPClient.Do(req)
 	if err != nil {
 		return err
 	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("untis api responded with %v", resp.Status)
+	}
 	return nil
 }
 
-// DeleteClient deletes the current client out of the map of active clients
-func (client Client) DeleteClient() {
-	delete(activeClients, client.Username)
+// CloseAllClients closes the sessions of all active clients and removes them from the active clients
+// it returns the first error that occurred while closing a session
+func CloseAllClients(ctx context.Context) error {
+	activeClientsMutex.Lock()
+	clients := make([]*Client, 0, len(activeClients))
+	for username, active := range activeClients {
+		clients = append(clients, active...)
+		delete(activeClients, username)
+	}
+	activeClientsMutex.Unlock()
+	var firstErr error
+	for _, client := range clients {
+		if !client.IsAuthenticated() {
+			continue
+		}
+		if err := client.CloseContext(ctx); err != nil {
+			client.ForceClose()
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
 }
 
 // sendRequest helps this api to send requests to the untis api
-func (client Client) sendRequest(method string, params map[string]interface{}) (*http.Response, int, error) {
-	id := rand.Intn(math.MaxInt64)
+// it returns the body of the response and the id used for the request
+// if the untis api responds with an error object, it is returned as *UntisError
+// if the session expired and AutoReauth is set, the client authenticates again and replays the request once
+// failed requests are logged tagged with the request id stored in ctx
+func (client *Client) sendRequest(ctx context.Context, method string, params map[string]interface{}) ([]byte, int, error) {
+	client.touch()
+	start := client.now()
+	respBody, id, err := client.sendRequestOnce(ctx, method, params)
+	if ObserveRequest != nil {
+		ObserveRequest(method, client.now().Sub(start), err)
+	}
+	if err != nil {
+		client.logf("level=error request_id=%v untis_method=%v msg=%q", RequestIDFromContext(ctx), method, err.Error())
+	}
+	return respBody, id, err
+}
+
+// sendRequestOnce sends the request, authenticating again and replaying it once if the session expired
+func (client *Client) sendRequestOnce(ctx context.Context, method string, params map[string]interface{}) ([]byte, int, error) {
+	session := client.session()
+	respBody, id, err := client.doRequestTo(ctx, client.endpoint(), session, method, params)
+	untisErr, ok := err.(*UntisError)
+	if !ok || untisErr.Code != NotAuthenticatedErrorCode || !client.AutoReauth ||
+		method == "authenticate" || method == "logout" || client.Username == "" || (client.Password == "" && client.secret() == "") {
+		return respBody, id, err
+	}
+	if err := client.reauthenticate(ctx, session); err != nil {
+		return nil, id, err
+	}
+	return client.doRequest(ctx, method, params)
+}
+
+// doRequest sends a single json-rpc request to the untis api in the current session
+// and returns the body of the response and the id used
+func (client *Client) doRequest(ctx context.Context, method string, params map[string]interface{}) ([]byte, int, error) {
+	return client.doRequestTo(ctx, client.endpoint(), client.session(), method, params)
+}
+
+// doRequestTo is like doRequest but sends the request to the given endpoint of the untis api in the given session
+func (client *Client) doRequestTo(ctx context.Context, endpoint, session, method string, params interface{}) ([]byte, int, error) {
+	id := client.nextRPCID()
 	body, _ := json.Marshal(map[string]interface{}{
 		"id":      id,
 		"method":  method,
 		"params":  params,
 		"jsonrpc": "2.0",
 	})
-	req, err := http.NewRequest("POST", URL, bytes.NewBuffer(body))
+	respBody, err := client.post(ctx, endpoint, session, body)
 	if err != nil {
-		return nil, -1, err
+		return nil, id, err
+	}
+	r := struct {
+		Error *UntisError `json:"error"`
+	}{}
+	if err = json.Unmarshal(respBody, &r); err == nil && r.Error != nil {
+		return nil, id, r.Error
+	}
+	return respBody, id, nil
+}
+
+// post sends a json-rpc request body to the endpoint of the untis api in the session (none if it is empty), waiting for
+// the rate limit of the client before every attempt, and returns the body of the response
+// connection errors, server errors (5xx) and responses which aren't json are retried with an exponential backoff
+// up to MaxAttempts times, the latter result in an *UpstreamUnavailableError; client errors (4xx) are returned immediately
+func (client *Client) post(ctx context.Context, endpoint, session string, body []byte) ([]byte, error) {
+	attempts := client.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(client.backoff(attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+		if err := client.wait(ctx); err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if session != "" {
+			req.AddCookie(&http.Cookie{Name: "JSESSIONID", Value: session})
+		}
+		resp, err := client.httpClient().Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			discard(resp)
+			lastErr = fmt.Errorf("untis api responded with %v", resp.Status)
+			continue
+		}
+		if resp.StatusCode >= http.StatusBadRequest {
+			discard(resp)
+			return nil, fmt.Errorf("untis api responded with %v", resp.Status)
+		}
+		respBody, err := ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+		if !isJSONResponse(resp, respBody) {
+			lastErr = &UpstreamUnavailableError{Status: resp.StatusCode, ContentType: resp.Header.Get("Content-Type")}
+			continue
+		}
+		return respBody, nil
 	}
-	reqClient := http.Client{}
-	req.AddCookie(&http.Cookie{Name: "JSESSIONID", Value: client.SessionID})
-	resp, err := reqClient.Do(req)
-	return resp, id, err
+	return nil, lastErr
 }
 
-// GetLessonNrByStart computes the lesson number by its start time
+// isJSONResponse checks whether the response looks like json, which it doesn't if it is declared as html
+// or its body starts with a '<'
+func isJSONResponse(resp *http.Response, body []byte) bool {
+	if strings.Contains(strings.ToLower(resp.Header.Get("Content-Type")), "html") {
+		return false
+	}
+	trimmed := bytes.TrimSpace(body)
+	return len(trimmed) == 0 || trimmed[0] != '<'
+}
+
+// logf logs using the Logf hook of the client or log.Printf if none is set
+func (client *Client) logf(format string, v ...interface{}) {
+	if client.Logf != nil {
+		client.Logf(format, v...)
+		return
+	}
+	log.Printf(format, v...)
+}
+
+// newTransport creates the transport used by defaultHTTPClient with keep-alives enabled,
+// like http.DefaultTransport it uses the proxy of the environment
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DisableKeepAlives = false
+	transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	transport.IdleConnTimeout = DefaultIdleConnTimeout
+	return transport
+}
+
+// discard reads the rest of the body of the response and closes it, so its connection can be reused
+func discard(resp *http.Response) {
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
+
+// httpClient returns the http client of the client or the default http client if none is set
+func (client *Client) httpClient() *http.Client {
+	if client.HTTPClient == nil {
+		return defaultHTTPClient
+	}
+	return client.HTTPClient
+}
+
+// backoff computes the delay before the given retry attempt, doubling RetryDelay per attempt and adding a random jitter
+func (client *Client) backoff(attempt int) time.Duration {
+	if client.RetryDelay <= 0 {
+		return 0
+	}
+	delay := client.RetryDelay << uint(attempt-1)
+	return delay + time.Duration(rand.Int63n(int64(client.RetryDelay)))
+}
+
+// endpoint builds the url of the json-rpc api of the server and school the client uses
+// if no server or school is set the defaults are used
+func (client *Client) endpoint() string {
+	return client.endpointOf("jsonrpc.do")
+}
+
+// internEndpoint returns the url of the internal json-rpc api of the school of the client, which is used by the
+// untis apps and authenticates every request with a one-time password instead of a session
+func (client *Client) internEndpoint() string {
+	return client.endpointOf("jsonrpc_intern.do")
+}
+
+// endpointOf returns the url of the api at path below the WebUntis directory of the server for the school of the client
+func (client *Client) endpointOf(path string) string {
+	server := client.Server
+	if server == "" {
+		server = DefaultServer
+	}
+	if !strings.Contains(server, "://") {
+		server = "https://" + server
+	}
+	school := client.School
+	if school == "" {
+		school = DefaultSchool
+	}
+	return strings.TrimSuffix(server, "/") + "/WebUntis/" + path + "?school=" + url.QueryEscape(school)
+}
+
+// GetLessonNrByStart computes the lesson number by its start time using the DefaultSchedule
 func GetLessonNrByStart(start time.Time) int {
-	switch start.Hour() {
-	case 8:
-		if start.Minute() == 0 {
-			return 1
-		} else if start.Minute() == 50 {
-			return 2
-		}
-	case 9:
-		return 3
-	case 10:
-		return 4
-	case 11:
-		return 5
-	case 12:
-		return 6
-	case 13:
-		return 7
-	case 14:
-		return 8
-	case 15:
-		return 9
-	case 16:
-		return 10
-	case 17:
-		if start.Minute() == 0 {
-			return 11
-		} else if start.Minute() == 45 {
-			return 12
-		}
-	case 18:
-		return 13
-	case 19:
-		return 14
-	case 20:
-		return 15
-	}
-	return -1
-}
-
-// GetLessonNrByEnd computes the lesson number by its end time
+	return DefaultSchedule.LessonNrByStart(start)
+}
+
+// GetLessonNrByEnd computes the lesson number by its end time using the DefaultSchedule
 func GetLessonNrByEnd(end time.Time) int {
-	switch end.Hour() {
-	case 8:
-		return 1
-	case 9:
-		return 2
-	case 10:
-		return 3
-	case 11:
-		return 4
-	case 12:
-		return 5
-	case 13:
-		return 6
-	case 14:
-		return 7
-	case 15:
-		return 8
-	case 16:
-		if end.Minute() == 0 {
-			return 9
-		} else if end.Minute() == 50 {
-			return 10
-		}
-	case 17:
-		return 11
-	case 18:
-		return 12
-	case 19:
-		return 13
-	case 20:
-		return 14
-	case 21:
-		return 15
-	}
-	return -1
+	return DefaultSchedule.LessonNrByEnd(end)
 }
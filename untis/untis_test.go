@@ -0,0 +1,1761 @@
+package untis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetClientReturnsTheLiveClient(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	created := newTestClient(t, fake, "live")
+	client, ok := GetClient("live")
+	if !ok {
+		t.Fatal("client isn't registered")
+	}
+	if client != created {
+		t.Fatal("GetClient returned a copy instead of the registered client")
+	}
+	if err := client.Authenticate(); err != nil {
+		t.Fatal(err)
+	}
+	activeClientsMutex.RLock()
+	stored := activeClients["live"][0]
+	activeClientsMutex.RUnlock()
+	if !stored.IsAuthenticated() || stored.SessionID != "session-1" {
+		t.Fatalf("the registered client isn't authenticated: %+v", stored)
+	}
+}
+
+func TestResponsesToAnotherRequestReportBothIDs(t *testing.T) {
+	tests := []struct {
+		method string
+		call   func(client *Client) error
+	}{
+		{"authenticate", func(client *Client) error {
+			return client.Authenticate()
+		}},
+		{"getLatestImportTime", func(client *Client) error {
+			_, err := client.GetLatestImportTime()
+			return err
+		}},
+		{"getTeachers", func(client *Client) error {
+			_, err := client.ResolveTeachers([]int{1})
+			return err
+		}},
+		{"getTimetable", func(client *Client) error {
+			_, err := client.GetTimetableOfTeacher(day, day)
+			return err
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.method, func(t *testing.T) {
+			fake := newFakeUntis(t, nil)
+			fake.serveMasterData()
+			fake.serveTimetable()
+			fake.handle("getLatestImportTime", fake.withSession(1600000000000))
+			client := newTestClient(t, fake, "mismatched")
+			if test.method != "authenticate" {
+				if err := client.Authenticate(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			fake.mutex.Lock()
+			served, ok := fake.handlers[test.method]
+			fake.mutex.Unlock()
+			var mutex sync.Mutex
+			var sent []string
+			fake.handle(test.method, func(call fakeCall) (interface{}, *UntisError) {
+				mutex.Lock()
+				sent = append(sent, call.ID)
+				mutex.Unlock()
+				if !ok {
+					return fake.startSession(), nil
+				}
+				return served(call)
+			})
+			fake.mismatchIDs(test.method)
+
+			err := test.call(client)
+			var mismatch *IDMismatchError
+			if !errors.As(err, &mismatch) {
+				t.Fatalf("got %v, want an IDMismatchError", err)
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			if len(sent) != 1 {
+				t.Fatalf("%v was called %d times, want once", test.method, len(sent))
+			}
+			expected, _ := strconv.Atoi(sent[0])
+			if mismatch.Method != test.method || mismatch.Expected != expected || mismatch.Got != expected+1 {
+				t.Errorf("got %+v, want the method %v sent with the id %d and answered with %d", mismatch, test.method, expected, expected+1)
+			}
+		})
+	}
+}
+
+func TestErrorsCanBeDistinguishedWithErrorsIs(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	fake.handle("getStudents", fake.withSession([]map[string]interface{}{}))
+	sentinels := []error{ErrNotAuthenticated, ErrAlreadyAuthenticated, ErrTeacherNotFound, ErrStudentNotFound,
+		ErrIDMismatch, ErrRoomNotFound, ErrClassNotFound}
+	tests := []struct {
+		name  string
+		cause func(client *Client) error
+		want  error
+	}{
+		{"a request without a session", func(client *Client) error {
+			client.ForceClose()
+			_, err := client.GetTimetableOfTeacher(day, day)
+			return err
+		}, ErrNotAuthenticated},
+		{"authenticating twice", func(client *Client) error {
+			return client.Authenticate()
+		}, ErrAlreadyAuthenticated},
+		{"authenticating twice with the secret", func(client *Client) error {
+			return client.AuthenticateSecret()
+		}, ErrAlreadyAuthenticated},
+		{"an unknown teacher", func(client *Client) error {
+			_, err := client.ResolveTeacherID("Peter Unbekannt")
+			return err
+		}, ErrTeacherNotFound},
+		{"the timetable of an unknown teacher", func(client *Client) error {
+			_, err := client.GetTimetableOfSpecificTeacher(day, day, "Peter Unbekannt")
+			return err
+		}, ErrTeacherNotFound},
+		{"an unknown student", func(client *Client) error {
+			_, err := client.ResolveStudentID("Erika", "Muster")
+			return err
+		}, ErrStudentNotFound},
+		{"a response to another request", func(client *Client) error {
+			fake.respondRaw("getLatestImportTime", fakeResponse{Status: http.StatusOK, ContentType: "application/json",
+				Body: `{"jsonrpc":"2.0","id":"999999","result":1600000000000}`})
+			_, err := client.GetLatestImportTime()
+			return err
+		}, ErrIDMismatch},
+		{"an unknown room", func(client *Client) error {
+			_, err := client.ResolveRoomID("Turnsaal")
+			return err
+		}, ErrRoomNotFound},
+		{"an unknown class", func(client *Client) error {
+			_, err := client.ResolveClassID("1XHIT")
+			return err
+		}, ErrClassNotFound},
+		{"the timetable of an unknown class", func(client *Client) error {
+			_, err := client.GetTimetableOfClass(day, day, "1XHIT")
+			return err
+		}, ErrClassNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.cause(newAuthenticatedClient(t, fake, "failing"))
+			if !errors.Is(err, test.want) {
+				t.Fatalf("got %v, want %v", err, test.want)
+			}
+			if wrapped := fmt.Errorf("fetching: %w", err); !errors.Is(wrapped, test.want) {
+				t.Errorf("the wrapped error %v doesn't match %v", wrapped, test.want)
+			}
+			for _, sentinel := range sentinels {
+				if sentinel != test.want && errors.Is(err, sentinel) {
+					t.Errorf("%v matches %v as well", err, sentinel)
+				}
+			}
+		})
+	}
+}
+
+func TestGetClientReportsUnknownUsers(t *testing.T) {
+	if client, ok := GetClient("nobody"); ok || client != nil {
+		t.Errorf("got %v, %v for a user without a client, want nil and not found", client, ok)
+	}
+	fake := newFakeUntis(t, nil)
+	created := newTestClient(t, fake, "leaving")
+	created.DeleteClient()
+	if client, ok := GetClient("leaving"); ok || client != nil {
+		t.Errorf("got %v, %v for a user whose client was deleted, want nil and not found", client, ok)
+	}
+}
+
+func TestCloseAllForUserClosesEverySessionOfTheUser(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	clients := make([]*Client, 3)
+	for i := range clients {
+		clients[i] = newTestClient(t, fake, "many")
+		if err := clients[i].Authenticate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if newest, _ := GetClient("many"); newest != clients[len(clients)-1] {
+		t.Fatal("GetClient didn't return the newest client of the user")
+	}
+	if errs := CloseAllForUser("many"); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	for i, client := range clients {
+		if client.IsAuthenticated() {
+			t.Errorf("client %d is still authenticated", i)
+		}
+	}
+	if fake.openSessions() != 0 {
+		t.Errorf("%d sessions weren't logged out", fake.openSessions())
+	}
+	if _, ok := GetClient("many"); ok {
+		t.Error("the clients of the user are still registered")
+	}
+}
+
+func TestDeleteClientKeepsTheOtherClientsOfTheUser(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	older := newTestClient(t, fake, "kept")
+	if err := older.Authenticate(); err != nil {
+		t.Fatal(err)
+	}
+	newer := newTestClient(t, fake, "kept")
+	newer.DeleteClient()
+	client, ok := GetClient("kept")
+	if !ok || client != older {
+		t.Fatal("deleting the newer client removed the older one too")
+	}
+	abandoned := newTestClient(t, fake, "pruned")
+	newTestClient(t, fake, "pruned")
+	activeClientsMutex.RLock()
+	defer activeClientsMutex.RUnlock()
+	for _, active := range activeClients["pruned"] {
+		if active == abandoned {
+			t.Fatal("an older client without a session is still registered")
+		}
+	}
+}
+
+// masterDataMethods are the methods fetching the master data resolved in timetables
+var masterDataMethods = []string{"getTeachers", "getRooms", "getKlassen", "getSubjects"}
+
+// day is the date the lessons of the tests take place on
+var day = time.Date(2021, 3, 1, 0, 0, 0, 0, Location())
+
+func TestTimetableFetchesEachEntityTypeOnce(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	lessons := make([]fakeLesson, 0)
+	for i := 0; i < 8; i++ {
+		lessons = append(lessons, fakeLesson{
+			ID: i + 1, Date: 20210301, Start: 800 + 100*i, End: 850 + 100*i,
+			Classes: []int{20 + i%3}, Teachers: []int{1 + i%3}, Subjects: []int{30 + i%2}, Rooms: []int{10 + i%2},
+		})
+	}
+	fake.handle("getTimetable", fake.withSession(timetable(lessons...)))
+	client := newAuthenticatedClient(t, fake, "resolver")
+
+	for i := 0; i < 2; i++ {
+		got, err := client.GetTimetableOfTeacher(day, day)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(lessons) {
+			t.Fatalf("got %d lessons, want %d", len(got), len(lessons))
+		}
+	}
+	for _, method := range masterDataMethods {
+		if calls := fake.callsOf(method); calls != 1 {
+			t.Errorf("%v was called %d times, want once for all lessons of the session", method, calls)
+		}
+	}
+
+	if err := client.RefreshCaches(); err != nil {
+		t.Fatal(err)
+	}
+	for _, method := range masterDataMethods {
+		if calls := fake.callsOf(method); calls != 2 {
+			t.Errorf("%v was called %d times, want once more after RefreshCaches", method, calls)
+		}
+	}
+}
+
+func TestTimetableFetchesTheMasterDataOnceRegardlessOfTheLessonCount(t *testing.T) {
+	for _, count := range []int{0, 1, 10, 500} {
+		t.Run(strconv.Itoa(count), func(t *testing.T) {
+			fake := newFakeUntis(t, nil)
+			fake.serveMasterData()
+			lessons := make([]fakeLesson, 0, count)
+			for i := 0; i < count; i++ {
+				lessons = append(lessons, fakeLesson{
+					ID: i + 1, Date: 20210301, Start: 800, End: 850,
+					Classes: []int{20 + i%3}, Teachers: []int{1 + i%3}, Subjects: []int{30 + i%2}, Rooms: []int{10 + i%2},
+				})
+			}
+			fake.serveTimetable(lessons...)
+			client := newAuthenticatedClient(t, fake, "resolver")
+			counting := &countingTransport{transport: fake.Client().Transport}
+			client.HTTPClient = &http.Client{Transport: counting}
+
+			got, err := client.GetTimetableOfTeacher(day, day)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != count {
+				t.Fatalf("got %d lessons, want %d", len(got), count)
+			}
+			want := 0
+			if count > 0 {
+				want = 1
+			}
+			for _, method := range masterDataMethods {
+				if calls := fake.callsOf(method); calls != want {
+					t.Errorf("%v was called %d times, want %d", method, calls, want)
+				}
+			}
+			if counting.requests != 1+want*len(masterDataMethods) {
+				t.Errorf("%d requests were sent, want the timetable and %d per master data", counting.requests, want)
+			}
+		})
+	}
+}
+
+func TestActiveClientsAreSafeForConcurrentAccess(t *testing.T) {
+	const goroutines = 100
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// every second goroutine shares its username with another one
+			username := "concurrent-" + strconv.Itoa(i/2)
+			if i%2 == 0 {
+				username = "concurrent-distinct-" + strconv.Itoa(i)
+			}
+			client := CreateClient(username, "password")
+			if found, ok := GetClient(username); !ok || found == nil {
+				t.Errorf("client of %v isn't registered", username)
+			}
+			client.DeleteClient()
+		}(i)
+	}
+	wg.Wait()
+	activeClientsMutex.RLock()
+	defer activeClientsMutex.RUnlock()
+	for username := range activeClients {
+		if strings.HasPrefix(username, "concurrent-") {
+			t.Errorf("clients of %v weren't deleted", username)
+		}
+	}
+}
+
+func TestUntisErrorsAreReturnedTyped(t *testing.T) {
+	fake := newFakeUntis(t, map[string]fakeHandler{
+		"authenticate": func(call fakeCall) (interface{}, *UntisError) {
+			return nil, &UntisError{Code: BadCredentialsErrorCode, Message: "bad credentials"}
+		},
+	})
+	client := newTestClient(t, fake, "typed")
+	err := client.Authenticate()
+	var untisErr *UntisError
+	if !errors.As(err, &untisErr) {
+		t.Fatalf("got %T (%v), want an *UntisError", err, err)
+	}
+	if untisErr.Code != BadCredentialsErrorCode || untisErr.Message != "bad credentials" {
+		t.Errorf("got %+v, want the code and message of the payload", untisErr)
+	}
+	if !HasErrorCode(err, BadCredentialsErrorCode) || HasErrorCode(err, NotAuthenticatedErrorCode) {
+		t.Error("HasErrorCode doesn't report the code of the payload")
+	}
+	if client.IsAuthenticated() {
+		t.Error("the client is authenticated although untis rejected the credentials")
+	}
+
+	fake.handle("authenticate", func(call fakeCall) (interface{}, *UntisError) {
+		return fake.startSession(), nil
+	})
+	fake.handle("getTimetable", func(call fakeCall) (interface{}, *UntisError) {
+		return nil, &UntisError{Code: -7004, Message: "no allowed date"}
+	})
+	if err := client.Authenticate(); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetTimetableOfTeacher(day, day)
+	if !HasErrorCode(err, -7004) {
+		t.Errorf("got %v, want the untis error of getTimetable", err)
+	}
+}
+
+func TestCancelledContextAbortsRequestsPromptly(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	release := make(chan struct{})
+	// the slow handler has to return before the fake is closed
+	t.Cleanup(func() { close(release) })
+	fake.handle("getLatestImportTime", func(call fakeCall) (interface{}, *UntisError) {
+		select {
+		case <-release:
+		case <-time.After(10 * time.Second):
+		}
+		return 1600000000000, nil
+	})
+	client := newAuthenticatedClient(t, fake, "cancelled")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.GetLatestImportTimeContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want the error of the context", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("the request returned %v after the context was done", elapsed)
+	}
+	if calls := fake.callsOf("getLatestImportTime"); calls != 1 {
+		t.Errorf("the request was sent %d times, want no retries after the context was done", calls)
+	}
+}
+
+func TestLessonsCarryTheNamesOfTheirSubjects(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	fake.handle("getTimetable", fake.withSession(timetable(
+		fakeLesson{ID: 1, Date: 20210301, Start: 800, End: 850, Subjects: []int{30, 31}, Teachers: []int{1}},
+		fakeLesson{ID: 2, Date: 20210301, Start: 1000, End: 1050, Subjects: []int{31}, Teachers: []int{1}},
+	)))
+	client := newAuthenticatedClient(t, fake, "subjects")
+	lessons, err := client.GetTimetableOfTeacher(day, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lessons) != 2 {
+		t.Fatalf("got %d lessons, want 2", len(lessons))
+	}
+	if want := []string{"SEW", "D"}; !reflect.DeepEqual(lessons[0].Subjects, want) {
+		t.Errorf("got subjects %v, want %v", lessons[0].Subjects, want)
+	}
+	if want := []string{"D"}; !reflect.DeepEqual(lessons[1].Subjects, want) {
+		t.Errorf("got subjects %v, want %v", lessons[1].Subjects, want)
+	}
+}
+
+func TestClientsSendRequestsToTheirServerAndSchool(t *testing.T) {
+	var mutex sync.Mutex
+	calls := make([]fakeCall, 0)
+	record := func(call fakeCall) {
+		mutex.Lock()
+		calls = append(calls, call)
+		mutex.Unlock()
+	}
+	fake := newFakeUntis(t, nil)
+	fake.handle("authenticate", func(call fakeCall) (interface{}, *UntisError) {
+		record(call)
+		return fake.startSession(), nil
+	})
+	fake.handle("getLatestImportTime", func(call fakeCall) (interface{}, *UntisError) {
+		record(call)
+		return fake.withSession(1600000000000)(call)
+	})
+	client := newAuthenticatedClient(t, fake, "school")
+	if _, err := client.GetLatestImportTime(); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("the server received %d calls, want 2", len(calls))
+	}
+	for _, call := range calls {
+		if call.Path != "/WebUntis/jsonrpc.do" || call.School != "test" {
+			t.Errorf("%v was sent to %v?school=%v, want the json-rpc api of the school", call.Method, call.Path, call.School)
+		}
+	}
+}
+
+func TestTransientFailuresAreRetried(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.handle("getLatestImportTime", fake.withSession(1600000000000))
+	client := newAuthenticatedClient(t, fake, "retry")
+	client.MaxAttempts = 3
+	unavailable := fakeResponse{Status: http.StatusServiceUnavailable, ContentType: "text/plain", Body: "unavailable"}
+	fake.respondRaw("getLatestImportTime", unavailable, unavailable)
+
+	imported, err := client.GetLatestImportTime()
+	if err != nil {
+		t.Fatalf("got %v, want the data of the third attempt", err)
+	}
+	if want := time.Unix(1600000000, 0); !imported.Equal(want) {
+		t.Errorf("got %v, want %v", imported, want)
+	}
+	if calls := fake.callsOf("getLatestImportTime"); calls != 3 {
+		t.Errorf("the request was sent %d times, want 3", calls)
+	}
+}
+
+func TestPermanentFailuresAreNotRetried(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	client := newAuthenticatedClient(t, fake, "permanent")
+	client.MaxAttempts = 3
+	fake.respondRaw("getLatestImportTime", fakeResponse{Status: http.StatusBadRequest, ContentType: "text/plain", Body: "bad request"})
+
+	if _, err := client.GetLatestImportTime(); err == nil {
+		t.Fatal("got no error for a permanent failure")
+	}
+	if calls := fake.callsOf("getLatestImportTime"); calls != 1 {
+		t.Errorf("the request was sent %d times, want a single attempt", calls)
+	}
+}
+
+func TestHTMLResponsesAreReturnedAsUnavailableUpstream(t *testing.T) {
+	outage := fakeResponse{Status: http.StatusOK, ContentType: "text/html; charset=utf-8", Body: "<html><body>Wartungsarbeiten</body></html>"}
+	mislabeled := fakeResponse{Status: http.StatusOK, ContentType: "application/json", Body: "\n  <!DOCTYPE html><html></html>"}
+	tests := []struct {
+		name     string
+		response fakeResponse
+	}{
+		{"an html page", outage},
+		{"html declared as json", mislabeled},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := newFakeUntis(t, nil)
+			client := newTestClient(t, fake, "outage")
+			client.MaxAttempts = 2
+			fake.respondRaw("authenticate", test.response, test.response)
+			var unavailable *UpstreamUnavailableError
+			if err := client.Authenticate(); !errors.As(err, &unavailable) {
+				t.Fatalf("authenticating returned %v, want an *UpstreamUnavailableError", err)
+			}
+			if unavailable.Status != http.StatusOK || unavailable.ContentType != test.response.ContentType {
+				t.Errorf("got %+v, want the status and content type of the response", unavailable)
+			}
+			if calls := fake.callsOf("authenticate"); calls != 2 {
+				t.Errorf("authenticate was sent %d times, want MaxAttempts times", calls)
+			}
+
+			if err := client.Authenticate(); err != nil {
+				t.Fatal(err)
+			}
+			fake.respondRaw("getLatestImportTime", test.response, test.response)
+			if _, err := client.GetLatestImportTime(); !errors.As(err, &unavailable) {
+				t.Errorf("a request returned %v, want an *UpstreamUnavailableError", err)
+			}
+		})
+	}
+}
+
+func TestGetCurrentLesson(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	fake.serveTimetable(
+		fakeLesson{ID: 1, Date: 20210301, Start: 800, End: 850, Classes: []int{20}, Teachers: []int{1}, Subjects: []int{30}, Rooms: []int{10}},
+		fakeLesson{ID: 2, Date: 20210301, Start: 955, End: 1045, Code: "cancelled", Classes: []int{21}, Teachers: []int{1}, Subjects: []int{31}, Rooms: []int{10}},
+		fakeLesson{ID: 3, Date: 20210301, Start: 1045, End: 1135, Classes: []int{22}, Teachers: []int{1}, Subjects: []int{31}, Rooms: []int{11}},
+	)
+	id := func(lesson *Lesson) int {
+		if lesson == nil {
+			return 0
+		}
+		return lesson.ID
+	}
+	tests := []struct {
+		name          string
+		now           time.Time
+		current, next int
+	}{
+		{"before school", at(7, 0), 0, 1},
+		{"mid-lesson", at(8, 20), 1, 3},
+		{"in a break", at(9, 0), 0, 3},
+		{"during a cancelled lesson", at(10, 0), 0, 3},
+		{"at the start of a lesson", at(10, 45), 3, 0},
+		{"in the last lesson", at(11, 0), 3, 0},
+		{"after school", at(15, 0), 0, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := clockedClient(t, fake, "current", &fakeClock{now: test.now})
+			current, next, err := client.GetCurrentLesson()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id(current) != test.current || id(next) != test.next {
+				t.Errorf("got the current lesson %d and the next %d, want %d and %d", id(current), id(next), test.current, test.next)
+			}
+		})
+	}
+	if _, _, err := newTestClient(t, fake, "anonymous").GetCurrentLesson(); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("got %v without a session, want ErrNotAuthenticated", err)
+	}
+}
+
+func TestGetTimetableOfStudent(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	requests := fake.serveTimetable(fakeLesson{ID: 1, Date: 20210301, Start: 800, End: 850, Classes: []int{20}, Teachers: []int{2}, Subjects: []int{30}, Rooms: []int{11}})
+	fake.handle("getStudents", fake.withSession([]map[string]interface{}{
+		{"id": 4711, "key": "s4711", "name": "mmuster", "foreName": "Max", "longName": "Muster", "gender": "male"},
+	}))
+	client := newAuthenticatedClient(t, fake, "student")
+
+	id, err := client.ResolveStudentID("max", "MUSTER")
+	if err != nil || id != 4711 {
+		t.Fatalf("got %d, %v, want the id of the student", id, err)
+	}
+	if _, err := client.ResolveStudentID("Erika", "Muster"); !errors.Is(err, ErrStudentNotFound) {
+		t.Errorf("got %v for an unknown student, want ErrStudentNotFound", err)
+	}
+	lessons, err := client.GetTimetableOfStudent(day, day.AddDate(0, 0, 4), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := timetableRequest{ID: 4711, Type: PersonTypeStudent, StartDate: "20210301", EndDate: "20210305"}
+	if got := requests(); len(got) != 1 || got[0] != want {
+		t.Errorf("got requests %+v, want %+v", got, want)
+	}
+	if len(lessons) != 1 {
+		t.Fatalf("got %d lessons, want 1", len(lessons))
+	}
+	lesson := lessons[0]
+	if !reflect.DeepEqual(lesson.Classes, []string{"5AHIT"}) || !reflect.DeepEqual(lesson.Teachers, []string{"HUD"}) ||
+		!reflect.DeepEqual(lesson.Subjects, []string{"SEW"}) || !reflect.DeepEqual(lesson.Rooms, []string{"L2201"}) {
+		t.Errorf("the names of the lesson aren't resolved: %+v", lesson)
+	}
+}
+
+func TestGetTimetableOfRoom(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	requests := fake.serveTimetable(fakeLesson{ID: 1, Date: 20210301, Start: 800, End: 850, Classes: []int{20}, Teachers: []int{1}, Subjects: []int{30}, Rooms: []int{11}})
+	client := newAuthenticatedClient(t, fake, "occupancy")
+
+	lessons, err := client.GetTimetableOfRoom(day, day.AddDate(0, 0, 4), "labor 2201")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := timetableRequest{ID: 11, Type: PersonTypeRoom, StartDate: "20210301", EndDate: "20210305"}
+	if got := requests(); len(got) != 1 || got[0] != want {
+		t.Errorf("got requests %+v, want %+v", got, want)
+	}
+	if len(lessons) != 1 || !reflect.DeepEqual(lessons[0].Rooms, []string{"L2201"}) || !reflect.DeepEqual(lessons[0].Teachers, []string{"BOR"}) {
+		t.Errorf("got %+v, want the lesson of BOR in L2201", lessons)
+	}
+
+	if _, err := client.GetTimetableOfRoom(day, day, "Turnsaal"); !errors.Is(err, ErrRoomNotFound) {
+		t.Errorf("got %v for an unknown room, want ErrRoomNotFound", err)
+	}
+	if len(requests()) != 1 {
+		t.Error("untis was asked for the timetable of an unknown room")
+	}
+}
+
+// rpcResponse encodes a successful json-rpc response to the request with the id like untis does
+func rpcResponse(t *testing.T, id int, result interface{}) []byte {
+	t.Helper()
+	body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": strconv.Itoa(id), "result": result})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestParseTimetableResponse(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2021, 3, 1, hour, minute, 0, 0, Location())
+	}
+	tests := []struct {
+		name    string
+		body    []byte
+		want    []Lesson
+		wantErr error
+	}{
+		{name: "empty result", body: rpcResponse(t, 5, []interface{}{}), want: []Lesson{}},
+		{name: "missing result", body: []byte(`{"jsonrpc":"2.0","id":"5"}`), want: []Lesson{}},
+		{
+			name: "lesson with all elements",
+			body: rpcResponse(t, 5, timetable(fakeLesson{ID: 1, Date: 20210301, Start: 800, End: 850, Classes: []int{20, 21}, Teachers: []int{1}, Subjects: []int{30}, Rooms: []int{10}, LsText: "SA", Info: "Mitbringen: Laptop"})),
+			want: []Lesson{{
+				ID: 1, Start: at(8, 0), End: at(8, 50), ClassIDs: []int{20, 21}, TeacherIDs: []int{1}, RoomIDs: []int{10},
+				OriginalTeacherIDs: []int{}, OriginalRoomIDs: []int{}, SubjectIDs: []int{30}, LessonText: "SA", Info: "Mitbringen: Laptop",
+			}},
+		},
+		{
+			name: "substituted and moved lessons",
+			body: rpcResponse(t, 5, timetable(
+				fakeLesson{ID: 2, Date: 20210301, Start: 955, End: 1045, Code: "irregular", Teachers: []int{2}, OrgTeachers: map[int]int{2: 1}, Rooms: []int{11}, OrgRooms: map[int]int{11: 10}, SubstText: "Vertretung"},
+				fakeLesson{ID: 3, Date: 20210301, Start: 1050, End: 1140, Code: "cancelled"},
+			)),
+			want: []Lesson{
+				{
+					ID: 2, Start: at(9, 55), End: at(10, 45), ClassIDs: []int{}, TeacherIDs: []int{2}, RoomIDs: []int{11},
+					OriginalTeacherIDs: []int{1}, OriginalRoomIDs: []int{10}, SubjectIDs: []int{}, Irregular: true, SubstitutionText: "Vertretung",
+				},
+				{
+					ID: 3, Start: at(10, 50), End: at(11, 40), ClassIDs: []int{}, TeacherIDs: []int{}, RoomIDs: []int{},
+					OriginalTeacherIDs: []int{}, OriginalRoomIDs: []int{}, SubjectIDs: []int{}, Cancelled: true,
+				},
+			},
+		},
+		{name: "id mismatch", body: rpcResponse(t, 6, []interface{}{}), wantErr: ErrIDMismatch},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseTimetableResponse(test.body, 5)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("got %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("got %d lessons, want %d", len(got), len(test.want))
+			}
+			for i := range got {
+				if !got[i].Start.Equal(test.want[i].Start) || !got[i].End.Equal(test.want[i].End) {
+					t.Errorf("lesson %d lasts from %v to %v, want %v to %v", i, got[i].Start, got[i].End, test.want[i].Start, test.want[i].End)
+				}
+				got[i].Start, got[i].End = test.want[i].Start, test.want[i].End
+				if !reflect.DeepEqual(got[i], test.want[i]) {
+					t.Errorf("lesson %d is\n%+v\nwant\n%+v", i, got[i], test.want[i])
+				}
+			}
+		})
+	}
+	if _, err := parseTimetableResponse([]byte("{"), 5); err == nil {
+		t.Error("got no error for a malformed body")
+	}
+}
+
+func TestParseUntisTime(t *testing.T) {
+	tests := []struct {
+		time         int
+		hour, minute int
+	}{
+		{0, 0, 0},
+		{5, 0, 5},
+		{55, 0, 55},
+		{700, 7, 0},
+		{745, 7, 45},
+		{959, 9, 59},
+		{1000, 10, 0},
+		{1605, 16, 5},
+		{2359, 23, 59},
+	}
+	for _, test := range tests {
+		hour, minute := parseUntisTime(test.time)
+		if hour != test.hour || minute != test.minute {
+			t.Errorf("parseUntisTime(%d) = %02d:%02d, want %02d:%02d", test.time, hour, minute, test.hour, test.minute)
+		}
+	}
+}
+
+func TestParseUntisDate(t *testing.T) {
+	tests := []struct {
+		date  string
+		valid bool
+		want  time.Time
+	}{
+		{"20210412", true, time.Date(2021, time.April, 12, 0, 0, 0, 0, Location())},
+		{"2021-04-12", true, time.Date(2021, time.April, 12, 0, 0, 0, 0, Location())},
+		{" 2021-04-12 ", true, time.Date(2021, time.April, 12, 0, 0, 0, 0, Location())},
+		{"20210101", true, time.Date(2021, time.January, 1, 0, 0, 0, 0, Location())},
+		{"2021-01-01", true, time.Date(2021, time.January, 1, 0, 0, 0, 0, Location())},
+		{"20201231", true, time.Date(2020, time.December, 31, 0, 0, 0, 0, Location())},
+		{"20200229", true, time.Date(2020, time.February, 29, 0, 0, 0, 0, Location())},
+		{"2000-02-29", true, time.Date(2000, time.February, 29, 0, 0, 0, 0, Location())},
+		{"20210229", false, time.Time{}},
+		{"1900-02-29", false, time.Time{}},
+		{"20210431", false, time.Time{}},
+		{"20211301", false, time.Time{}},
+		{"20210001", false, time.Time{}},
+		{"20210100", false, time.Time{}},
+		{"2021-4-12", false, time.Time{}},
+		{"2021412", false, time.Time{}},
+		{"12.04.2021", false, time.Time{}},
+		{"", false, time.Time{}},
+		{"tomorrow", false, time.Time{}},
+	}
+	for _, test := range tests {
+		got, err := parseUntisDate(test.date)
+		if !test.valid {
+			if err == nil {
+				t.Errorf("parseUntisDate(%q) = %v, want an error", test.date, got)
+			}
+			continue
+		}
+		if err != nil || !got.Equal(test.want) || got.Location() != Location() {
+			t.Errorf("parseUntisDate(%q) = %v, %v, want %v", test.date, got, err, test.want)
+		}
+	}
+}
+
+func TestFormatUntisDate(t *testing.T) {
+	tests := []struct {
+		time time.Time
+		want string
+	}{
+		{time.Date(2021, time.April, 12, 10, 0, 0, 0, Location()), "20210412"},
+		{time.Date(2021, time.January, 5, 0, 0, 0, 0, Location()), "20210105"},
+		{time.Date(2020, time.February, 29, 23, 59, 0, 0, Location()), "20200229"},
+		{time.Date(2021, time.December, 31, 0, 0, 0, 0, Location()), "20211231"},
+	}
+	for _, test := range tests {
+		if got := formatUntisDate(test.time); got != test.want {
+			t.Errorf("formatUntisDate(%v) = %v, want %v", test.time, got, test.want)
+		}
+		if parsed, err := parseUntisDate(test.want); err != nil || formatUntisDate(parsed) != test.want {
+			t.Errorf("%v doesn't survive parsing and formatting: %v, %v", test.want, parsed, err)
+		}
+	}
+	if Location().String() != TimeZone {
+		t.Skipf("the time zone database doesn't contain %v", TimeZone)
+	}
+	// 23:30 UTC is already the next day in Vienna
+	if got := formatUntisDate(time.Date(2021, time.March, 1, 23, 30, 0, 0, time.UTC)); got != "20210302" {
+		t.Errorf("formatUntisDate formats 2021-03-01 23:30 UTC as %v, want the day in Vienna 20210302", got)
+	}
+}
+
+func TestCancelledAndSubstitutedLessonsAreDetected(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	fake.serveTimetable(
+		fakeLesson{ID: 1, Date: 20210301, Start: 800, End: 850, Code: "cancelled", Teachers: []int{1}, Subjects: []int{30}},
+		fakeLesson{ID: 2, Date: 20210301, Start: 850, End: 940, Code: "irregular", Teachers: []int{2}, OrgTeachers: map[int]int{2: 1}, SubstText: "Supplierung"},
+		fakeLesson{ID: 3, Date: 20210301, Start: 955, End: 1045, Teachers: []int{1}},
+	)
+	client := newAuthenticatedClient(t, fake, "cancelled-lessons")
+	lessons, err := client.GetTimetableOfTeacher(day, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lessons) != 3 {
+		t.Fatalf("got %d lessons, want 3", len(lessons))
+	}
+	if !lessons[0].Cancelled || lessons[0].Irregular {
+		t.Errorf("the cancelled lesson is cancelled = %v, irregular = %v", lessons[0].Cancelled, lessons[0].Irregular)
+	}
+	if lessons[1].Cancelled || !lessons[1].Irregular || lessons[1].SubstitutionText != "Supplierung" {
+		t.Errorf("the substituted lesson isn't irregular with its text: %+v", lessons[1])
+	}
+	if lessons[2].Cancelled || lessons[2].Irregular {
+		t.Errorf("the regular lesson is cancelled = %v, irregular = %v", lessons[2].Cancelled, lessons[2].Irregular)
+	}
+}
+
+func TestGetHolidaysDecodesTheHolidays(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.handle("getHolidays", fake.withSession([]map[string]interface{}{
+		{"id": 1, "name": "Ostern", "longName": "Osterferien", "startDate": 20210327, "endDate": 20210405},
+		{"id": 2, "name": "NatF", "longName": "Nationalfeiertag", "startDate": 20211026, "endDate": 20211026},
+	}))
+	client := newAuthenticatedClient(t, fake, "holidays")
+	holidays, err := client.GetHolidays()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Holiday{
+		{Name: "Ostern", LongName: "Osterferien", Start: time.Date(2021, 3, 27, 0, 0, 0, 0, Location()), End: time.Date(2021, 4, 5, 0, 0, 0, 0, Location())},
+		{Name: "NatF", LongName: "Nationalfeiertag", Start: time.Date(2021, 10, 26, 0, 0, 0, 0, Location()), End: time.Date(2021, 10, 26, 0, 0, 0, 0, Location())},
+	}
+	if !reflect.DeepEqual(holidays, want) {
+		t.Errorf("got %+v, want %+v", holidays, want)
+	}
+}
+
+// countingTransport counts the requests sent through its transport
+type countingTransport struct {
+	transport http.RoundTripper
+	mutex     sync.Mutex
+	requests  int
+}
+
+// RoundTrip counts the request and sends it through the transport
+func (counting *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	counting.mutex.Lock()
+	counting.requests++
+	counting.mutex.Unlock()
+	return counting.transport.RoundTrip(req)
+}
+
+func TestInjectedHTTPClientIsUsedForAllRequests(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.handle("getLatestImportTime", fake.withSession(1600000000000))
+	client := newTestClient(t, fake, "injected")
+	counting := &countingTransport{transport: fake.Client().Transport}
+	client.HTTPClient = &http.Client{Transport: counting}
+	if err := client.Authenticate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetLatestImportTime(); err != nil {
+		t.Fatal(err)
+	}
+	if counting.requests != 2 {
+		t.Errorf("%d requests were sent through the injected client, want authenticate and getLatestImportTime", counting.requests)
+	}
+	defaulted := CreateClient("default-http", "password")
+	defer defaulted.DeleteClient()
+	if defaulted.HTTPClient != defaultHTTPClient || defaultHTTPClient.Timeout != DefaultTimeout {
+		t.Error("clients don't default to the shared http client with a timeout")
+	}
+}
+
+func TestMatchTeacher(t *testing.T) {
+	teachers := map[int]Teacher{
+		1: {ID: 1, Name: "BOR", ForeName: "Michael", LongName: "Borko"},
+		2: {ID: 2, Name: "HUD", ForeName: "Anna", LongName: "van der Hude"},
+		3: {ID: 3, Name: "MAY", ForeName: "Eva", LongName: "Mayer"},
+		4: {ID: 4, Name: "MAE", ForeName: "Eva", LongName: "Mayer"},
+		5: {ID: 5, Name: "SCH", ForeName: "Karl", LongName: "Schmid Dipl.-Ing."},
+		6: {ID: 6, Name: "SCM", ForeName: "Karl", LongName: "Schmid"},
+	}
+	tests := []struct {
+		name string
+		want int
+		err  error
+	}{
+		{name: "Michael Borko", want: 1},
+		{name: "Anna van der Hude", want: 2},
+		{name: "  anna   VAN der hude ", want: 2},
+		{name: "Anna van", want: 2},
+		{name: "BOR", want: 1},
+		{name: "hud", want: 2},
+		{name: "Karl Schmid", want: 6},
+		{name: "Karl Schmid Dipl.-Ing.", want: 5},
+		{name: "Borko", err: ErrTeacherNotFound},
+		{name: "Peter Unbekannt", err: ErrTeacherNotFound},
+		{name: "", err: ErrTeacherNotFound},
+	}
+	for _, test := range tests {
+		id, err := matchTeacher(test.name, teachers)
+		if test.err != nil {
+			if !errors.Is(err, test.err) {
+				t.Errorf("matchTeacher(%q) = %d, %v, want %v", test.name, id, err, test.err)
+			}
+			continue
+		}
+		if err != nil || id != test.want {
+			t.Errorf("matchTeacher(%q) = %d, %v, want %d", test.name, id, err, test.want)
+		}
+	}
+	if _, err := matchTeacher("Eva Mayer", teachers); err == nil || errors.Is(err, ErrTeacherNotFound) || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("got %v for two teachers of the same name, want an error telling the name is ambiguous", err)
+	}
+}
+
+func TestMatchRoom(t *testing.T) {
+	rooms := map[int]Room{
+		10: {ID: 10, Name: "H1102", LongName: "Hörsaal 1102"},
+		11: {ID: 11, Name: "L2201", LongName: "Labor 2201"},
+		12: {ID: 12, Name: "Labor", LongName: "Medienlabor"},
+		13: {ID: 13, Name: "M0101", LongName: "Labor"},
+	}
+	tests := []struct {
+		name string
+		want int
+		err  error
+	}{
+		{name: "H1102", want: 10},
+		{name: " h1102 ", want: 10},
+		{name: "hörsaal 1102", want: 10},
+		{name: "Labor 2201", want: 11},
+		{name: "labor", want: 12},
+		{name: "Medienlabor", want: 12},
+		{name: "H11", err: ErrRoomNotFound},
+		{name: "Turnsaal", err: ErrRoomNotFound},
+		{name: "", err: ErrRoomNotFound},
+	}
+	for _, test := range tests {
+		id, err := matchRoom(test.name, rooms)
+		if test.err != nil {
+			if !errors.Is(err, test.err) {
+				t.Errorf("matchRoom(%q) = %d, %v, want %v", test.name, id, err, test.err)
+			}
+			continue
+		}
+		if err != nil || id != test.want {
+			t.Errorf("matchRoom(%q) = %d, %v, want %d", test.name, id, err, test.want)
+		}
+	}
+}
+
+func TestResolveFullReturnsTheWholeObjects(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	client := newAuthenticatedClient(t, fake, "full")
+
+	teachers, err := client.ResolveTeachersFull([]int{2, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Teacher{fakeTeachers[1], fakeTeachers[0]}; !reflect.DeepEqual(teachers, want) {
+		t.Errorf("got teachers %+v, want %+v", teachers, want)
+	}
+	rooms, err := client.ResolveRoomsFull([]int{10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rooms) != 1 || rooms[0].LongName != "Hörsaal 1102" || rooms[0].BackColor != "eeeeee" {
+		t.Errorf("got rooms %+v, want the room with its long name and colors", rooms)
+	}
+	classes, err := client.ResolveClassesFull([]int{21})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Class{fakeClasses[1]}; !reflect.DeepEqual(classes, want) {
+		t.Errorf("got classes %+v, want %+v", classes, want)
+	}
+	subjects, err := client.ResolveSubjectsFull([]int{30, 99})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Subject{fakeSubjects[0]}; !reflect.DeepEqual(subjects, want) {
+		t.Errorf("got subjects %+v, want %+v without the unknown one", subjects, want)
+	}
+}
+
+func TestTimetableWindowsAreMergedAndBounded(t *testing.T) {
+	const days, concurrency = 10, 3
+	fake := newFakeUntis(t, nil)
+	var mutex sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	fake.handle("getTimetable", func(call fakeCall) (interface{}, *UntisError) {
+		mutex.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mutex.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mutex.Lock()
+		inFlight--
+		mutex.Unlock()
+		var req timetableRequest
+		_ = json.Unmarshal(call.Params, &req)
+		date, _ := strconv.Atoi(req.StartDate)
+		// every window returns its own lessons out of order and a lesson contained in all windows
+		return fake.withSession(timetable(
+			fakeLesson{ID: date*10 + 2, Date: date, Start: 1000, End: 1050},
+			fakeLesson{ID: date*10 + 1, Date: date, Start: 800, End: 850},
+			fakeLesson{ID: 1, Date: 20210301, Start: 700, End: 750},
+		))(call)
+	})
+	client := newAuthenticatedClient(t, fake, "windows")
+	client.Concurrency = concurrency
+
+	lessons, err := client.GetTimetableOfTeacherRange(day, day.AddDate(0, 0, days-1), 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls := fake.callsOf("getTimetable"); calls != days {
+		t.Errorf("%d windows were requested, want %d", calls, days)
+	}
+	if maxInFlight > concurrency {
+		t.Errorf("%d windows were requested at once, want at most %d", maxInFlight, concurrency)
+	}
+	if len(lessons) != 2*days+1 {
+		t.Fatalf("got %d lessons, want %d without duplicates", len(lessons), 2*days+1)
+	}
+	for i := 1; i < len(lessons); i++ {
+		if !lessons[i-1].Start.Before(lessons[i].Start) {
+			t.Fatalf("lesson %d starts at %v, not after lesson %d at %v", i, lessons[i].Start, i-1, lessons[i-1].Start)
+		}
+	}
+}
+
+func TestWideRangesAreSplitIntoCompleteWindows(t *testing.T) {
+	const days = 90
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	var mutex sync.Mutex
+	requests := make([]timetableRequest, 0)
+	fake.handle("getTimetable", func(call fakeCall) (interface{}, *UntisError) {
+		var req timetableRequest
+		_ = json.Unmarshal(call.Params, &req)
+		mutex.Lock()
+		requests = append(requests, req)
+		mutex.Unlock()
+		// like untis, the lessons of a range are silently capped after the lessons of MaxTimetableWindow
+		from, _ := parseUntisDate(req.StartDate)
+		to, _ := parseUntisDate(req.EndDate)
+		if capped := from.Add(MaxTimetableWindow); to.After(capped) {
+			to = capped
+		}
+		lessons := make([]fakeLesson, 0)
+		for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+			untisDate, _ := strconv.Atoi(formatUntisDate(date))
+			lessons = append(lessons, fakeLesson{ID: untisDate, Date: untisDate, Start: 800, End: 850, Classes: []int{20}, Teachers: []int{1}, Subjects: []int{30}, Rooms: []int{10}})
+		}
+		return fake.withSession(timetable(lessons...))(call)
+	})
+	client := newAuthenticatedClient(t, fake, "wide")
+
+	end := day.AddDate(0, 0, days-1)
+	lessons, err := client.GetTimetableOfClass(day, end, "5AHIT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(requests) < 2 {
+		t.Fatalf("the range was requested with %d requests, want it to be split", len(requests))
+	}
+	for _, req := range requests {
+		from, _ := parseUntisDate(req.StartDate)
+		to, _ := parseUntisDate(req.EndDate)
+		if to.Sub(from) > MaxTimetableWindow {
+			t.Errorf("the window from %v to %v is longer than MaxTimetableWindow", req.StartDate, req.EndDate)
+		}
+	}
+	if len(lessons) != days {
+		t.Fatalf("got %d lessons, want one for each of the %d days", len(lessons), days)
+	}
+	for i, lesson := range lessons {
+		if want := day.AddDate(0, 0, i); formatUntisDate(lesson.Start) != formatUntisDate(want) {
+			t.Fatalf("lesson %d takes place on %v, want %v", i, formatUntisDate(lesson.Start), formatUntisDate(want))
+		}
+	}
+}
+
+func TestTimetablesOfTooLargeRangesAreRejectedBeforeAnyRequest(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	fake.serveTimetable()
+	fake.handle("getStudents", fake.withSession([]map[string]interface{}{}))
+	client := newAuthenticatedClient(t, fake, "ranged")
+	client.MaxRange = 30 * 24 * time.Hour
+	counting := &countingTransport{transport: fake.Client().Transport}
+	client.HTTPClient = &http.Client{Transport: counting}
+	timetables := map[string]func(start, end time.Time) error{
+		"teacher": func(start, end time.Time) error {
+			_, err := client.GetTimetableOfTeacher(start, end)
+			return err
+		},
+		"teacher range": func(start, end time.Time) error {
+			_, err := client.GetTimetableOfTeacherRange(start, end, 7*24*time.Hour)
+			return err
+		},
+		"specific teacher": func(start, end time.Time) error {
+			_, err := client.GetTimetableOfSpecificTeacher(start, end, "BOR")
+			return err
+		},
+		"teachers": func(start, end time.Time) error {
+			_, errs := client.GetTimetablesOfTeachers(start, end, []string{"BOR"})
+			return errs["BOR"]
+		},
+		"class": func(start, end time.Time) error {
+			_, err := client.GetTimetableOfClass(start, end, "5AHIT")
+			return err
+		},
+		"room": func(start, end time.Time) error {
+			_, err := client.GetTimetableOfRoom(start, end, "H1102")
+			return err
+		},
+		"student": func(start, end time.Time) error {
+			_, err := client.GetTimetableOfStudent(start, end, 4711)
+			return err
+		},
+	}
+	for name, fetch := range timetables {
+		t.Run(name, func(t *testing.T) {
+			counting.mutex.Lock()
+			counting.requests = 0
+			counting.mutex.Unlock()
+			end := day.Add(client.MaxRange + time.Hour)
+			var tooLarge *RangeTooLargeError
+			if err := fetch(day, end); !errors.As(err, &tooLarge) {
+				t.Fatalf("got %v, want a *RangeTooLargeError", err)
+			}
+			if !tooLarge.Start.Equal(day) || !tooLarge.End.Equal(end) || tooLarge.Max != client.MaxRange {
+				t.Errorf("got %+v, want the requested range and the maximum", tooLarge)
+			}
+			if counting.requests != 0 {
+				t.Errorf("%d requests were sent for a too large range", counting.requests)
+			}
+			if err := fetch(day, day.Add(client.MaxRange)); err != nil {
+				t.Fatalf("got %v for the longest allowed range", err)
+			}
+			if counting.requests == 0 {
+				t.Error("no request was sent for an allowed range")
+			}
+		})
+	}
+}
+
+func TestMaxRangeDefaultsToAYear(t *testing.T) {
+	client := newAuthenticatedClient(t, newFakeUntis(t, nil), "defaulted")
+	if err := client.checkRange(day, day.Add(DefaultMaxRange)); err != nil {
+		t.Errorf("got %v for a range of DefaultMaxRange", err)
+	}
+	if err := client.checkRange(day, day.Add(DefaultMaxRange+time.Hour)); err == nil {
+		t.Error("a range longer than DefaultMaxRange was accepted")
+	}
+}
+
+func TestLatestImportTimeIsDecodedInUTC(t *testing.T) {
+	imported, err := parseLatestImportTimeResponse(rpcResponse(t, 3, int64(1617198300123)), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2021, 3, 31, 13, 45, 0, 123*int(time.Millisecond), time.UTC)
+	if !imported.Equal(want) || imported.Location() != time.UTC {
+		t.Errorf("got %v, want %v", imported, want)
+	}
+	if _, err := parseLatestImportTimeResponse(rpcResponse(t, 4, 1617198300123), 3); !errors.Is(err, ErrIDMismatch) {
+		t.Errorf("got %v for another id, want ErrIDMismatch", err)
+	}
+
+	fake := newFakeUntis(t, nil)
+	fake.handle("getLatestImportTime", fake.withSession(int64(1617198300123)))
+	client := newAuthenticatedClient(t, fake, "import")
+	if imported, err := client.GetLatestImportTime(); err != nil || !imported.Equal(want) {
+		t.Errorf("got %v, %v, want %v", imported, err, want)
+	}
+}
+
+func TestLessonTimesAreInViennaTime(t *testing.T) {
+	if Location().String() != TimeZone {
+		t.Skipf("the time zone database doesn't contain %v", TimeZone)
+	}
+	lessons, err := parseTimetableResponse(rpcResponse(t, 1, timetable(
+		fakeLesson{ID: 1, Date: 20210115, Start: 800, End: 850},
+		fakeLesson{ID: 2, Date: 20210715, Start: 800, End: 850},
+	)), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	winter, summer := lessons[0].Start, lessons[1].Start
+	if _, offset := winter.Zone(); offset != 3600 {
+		t.Errorf("a winter lesson has an offset of %ds, want CET (+1h)", offset)
+	}
+	if _, offset := summer.Zone(); offset != 7200 {
+		t.Errorf("a summer lesson has an offset of %ds, want CEST (+2h)", offset)
+	}
+	if winter.UTC().Hour() != 7 || summer.UTC().Hour() != 6 {
+		t.Errorf("lessons at 8:00 start at %v and %v in UTC, want 7:00 and 6:00", winter.UTC(), summer.UTC())
+	}
+}
+
+func TestDefaultClientsShareOneTransport(t *testing.T) {
+	first := CreateClient("shared-transport-1", "password")
+	defer first.DeleteClient()
+	second := CreateClientForSchool("https://example.webuntis.com", "other", "shared-transport-2", "password")
+	defer second.DeleteClient()
+	if first.httpClient() != second.httpClient() || first.httpClient().Transport != defaultTransport {
+		t.Fatal("clients without their own http client don't share the default transport")
+	}
+	if defaultTransport.DisableKeepAlives {
+		t.Error("the default transport doesn't keep connections alive")
+	}
+	if defaultTransport.MaxIdleConnsPerHost != DefaultMaxIdleConnsPerHost {
+		t.Errorf("the default transport keeps %d idle connections per host, want %d", defaultTransport.MaxIdleConnsPerHost, DefaultMaxIdleConnsPerHost)
+	}
+	if defaultTransport.IdleConnTimeout != DefaultIdleConnTimeout {
+		t.Errorf("the default transport closes idle connections after %v, want %v", defaultTransport.IdleConnTimeout, DefaultIdleConnTimeout)
+	}
+}
+
+// dialCounter counts the connections opened by the transports it creates
+type dialCounter struct {
+	dials int32
+}
+
+// transport returns a transport like the default one which counts the connections it opens,
+// its connections are only reused if keepAlive is set
+func (counter *dialCounter) transport(keepAlive bool) *http.Transport {
+	transport := newTransport()
+	transport.DisableKeepAlives = !keepAlive
+	dialer := &net.Dialer{}
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		atomic.AddInt32(&counter.dials, 1)
+		return dialer.DialContext(ctx, network, address)
+	}
+	return transport
+}
+
+// weekOfLessons returns 40 lessons spread over the week starting on day
+func weekOfLessons() []fakeLesson {
+	lessons := make([]fakeLesson, 0, 40)
+	for i := 0; i < 40; i++ {
+		lessons = append(lessons, fakeLesson{
+			ID: i + 1, Date: 20210301 + i/8, Start: 800 + 100*(i%8), End: 850 + 100*(i%8),
+			Classes: []int{20 + i%3}, Teachers: []int{1 + i%3}, Subjects: []int{30 + i%2}, Rooms: []int{10 + i%2},
+		})
+	}
+	return lessons
+}
+
+// fetchWeek authenticates a new client of the username with the transport, fetches the timetable of weekOfLessons
+// and logs out again, it returns the amount of requests sent
+func fetchWeek(tb testing.TB, fake *fakeUntis, username string, transport http.RoundTripper) int {
+	tb.Helper()
+	client := newTestClient(tb, fake, username)
+	counting := &countingTransport{transport: transport}
+	client.HTTPClient = &http.Client{Transport: counting}
+	if err := client.Authenticate(); err != nil {
+		tb.Fatal(err)
+	}
+	lessons, err := client.GetTimetableOfTeacher(day, day.AddDate(0, 0, 4))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if len(lessons) != 40 {
+		tb.Fatalf("got %d lessons, want 40", len(lessons))
+	}
+	if err := client.Close(); err != nil {
+		tb.Fatal(err)
+	}
+	return counting.requests
+}
+
+func TestCloseLogsOutAndClearsTheSession(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	client := newAuthenticatedClient(t, fake, "leaving")
+	if err := client.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if client.IsAuthenticated() || !client.Closed || client.SessionID != "" {
+		t.Errorf("the client is authenticated %v, closed %v with the session %q, want it closed without a session",
+			client.IsAuthenticated(), client.Closed, client.SessionID)
+	}
+	if sessions := fake.openSessions(); sessions != 0 {
+		t.Errorf("%d sessions are open at untis, want the session logged out", sessions)
+	}
+	if err := client.Close(); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("closing again returned %v, want ErrNotAuthenticated", err)
+	}
+
+	expired := newAuthenticatedClient(t, fake, "expired")
+	fake.handle("logout", func(call fakeCall) (interface{}, *UntisError) {
+		return nil, &UntisError{Code: NotAuthenticatedErrorCode, Message: "not authenticated"}
+	})
+	if err := expired.Close(); err != nil || expired.IsAuthenticated() {
+		t.Errorf("closing a session untis ended already returned %v, authenticated %v, want it closed", err, expired.IsAuthenticated())
+	}
+}
+
+func TestFailedLogoutsLeaveTheClientRecoverable(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.handle("getLatestImportTime", fake.withSession(1600000000000))
+	client := newAuthenticatedClient(t, fake, "unreachable")
+	client.MaxAttempts = 1
+	fake.respondRaw("logout", fakeResponse{Status: http.StatusServiceUnavailable, ContentType: "text/plain", Body: "unavailable"})
+	if err := client.Close(); err == nil {
+		t.Fatal("closing returned no error although the logout failed")
+	}
+	if !client.IsAuthenticated() || client.Closed {
+		t.Fatalf("the client is authenticated %v and closed %v after a failed logout, want it still usable", client.IsAuthenticated(), client.Closed)
+	}
+	if _, err := client.GetLatestImportTime(); err != nil {
+		t.Errorf("the session can't be used after a failed logout: %v", err)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("retrying the logout returned %v", err)
+	}
+	if client.IsAuthenticated() || fake.openSessions() != 0 {
+		t.Errorf("the retried logout left the client authenticated %v with %d sessions at untis", client.IsAuthenticated(), fake.openSessions())
+	}
+
+	forced := newAuthenticatedClient(t, fake, "forced")
+	forced.MaxAttempts = 1
+	forced.ForceCloseOnError = true
+	fake.respondRaw("logout", fakeResponse{Status: http.StatusServiceUnavailable, ContentType: "text/plain", Body: "unavailable"})
+	if err := forced.Close(); err == nil {
+		t.Fatal("closing returned no error although the logout failed")
+	}
+	if forced.IsAuthenticated() || !forced.Closed {
+		t.Errorf("the client is authenticated %v and closed %v, want ForceCloseOnError to close it anyway", forced.IsAuthenticated(), forced.Closed)
+	}
+
+	cleared := newAuthenticatedClient(t, fake, "cleared")
+	cleared.ForceClose()
+	if cleared.IsAuthenticated() || !cleared.Closed || cleared.SessionID != "" {
+		t.Errorf("ForceClose left the client authenticated %v, closed %v with the session %q", cleared.IsAuthenticated(), cleared.Closed, cleared.SessionID)
+	}
+	if err := cleared.Close(); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("closing after ForceClose returned %v, want ErrNotAuthenticated", err)
+	}
+}
+
+func TestConnectionsAreReused(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	fake.serveTimetable(weekOfLessons()...)
+	counter := &dialCounter{}
+	transport := counter.transport(true)
+	defer transport.CloseIdleConnections()
+	requests := fetchWeek(t, fake, "keep-alive", transport)
+	opened := atomic.LoadInt32(&counter.dials)
+	if int(opened) >= requests {
+		t.Errorf("%d requests opened %d connections, want them to reuse connections", requests, opened)
+	}
+	requests = fetchWeek(t, fake, "keep-alive", transport)
+	if dials := atomic.LoadInt32(&counter.dials); dials != opened {
+		t.Errorf("fetching again opened %d new connections for %d requests, want all of them to reuse the idle ones", dials-opened, requests)
+	}
+}
+
+// BenchmarkTimetableConnections compares the connections opened for a timetable of 40 lessons
+// by a transport without keep-alives, like a new http.Client per request, and the shared transport
+func BenchmarkTimetableConnections(b *testing.B) {
+	for _, keepAlive := range []bool{false, true} {
+		name := "new connection per request"
+		if keepAlive {
+			name = "shared transport"
+		}
+		b.Run(name, func(b *testing.B) {
+			fake := newFakeUntis(b, nil)
+			fake.serveMasterData()
+			fake.serveTimetable(weekOfLessons()...)
+			counter := &dialCounter{}
+			transport := counter.transport(keepAlive)
+			defer transport.CloseIdleConnections()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				fetchWeek(b, fake, "benchmark", transport)
+			}
+			b.ReportMetric(float64(atomic.LoadInt32(&counter.dials))/float64(b.N), "connections/op")
+		})
+	}
+}
+
+func TestListTeachersAndClassesReturnTheWholeLists(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	client := newAuthenticatedClient(t, fake, "lister")
+
+	teachers, err := client.ListTeachers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// sorted by the long name: Borko, Mayer, van der Hude
+	wantTeachers := []Teacher{fakeTeachers[0], fakeTeachers[2], fakeTeachers[1]}
+	if !reflect.DeepEqual(teachers, wantTeachers) {
+		t.Errorf("listed the teachers %+v, want %+v", teachers, wantTeachers)
+	}
+	classes, err := client.ListClasses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantClasses := []Class{fakeClasses[2], fakeClasses[1], fakeClasses[0]}
+	if !reflect.DeepEqual(classes, wantClasses) {
+		t.Errorf("listed the classes %+v, want %+v", classes, wantClasses)
+	}
+
+	if _, err := client.ListTeachers(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListClasses(); err != nil {
+		t.Fatal(err)
+	}
+	if fake.callsOf("getTeachers") != 1 || fake.callsOf("getKlassen") != 1 {
+		t.Errorf("getTeachers and getKlassen were called %d and %d times, want the lists to be cached", fake.callsOf("getTeachers"), fake.callsOf("getKlassen"))
+	}
+}
+
+func TestGetClassTeachers(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	client := newAuthenticatedClient(t, fake, "classes")
+	tests := []struct {
+		class string
+		want  []Teacher
+	}{
+		{"5AHIT", []Teacher{fakeTeachers[0]}},
+		{"4BHIT", []Teacher{fakeTeachers[1], fakeTeachers[2]}},
+		{"3CHIT", []Teacher{}},
+	}
+	for _, test := range tests {
+		teachers, err := client.GetClassTeachers(test.class)
+		if err != nil {
+			t.Fatalf("%v: %v", test.class, err)
+		}
+		if !reflect.DeepEqual(teachers, test.want) {
+			t.Errorf("%v has the class teachers %+v, want %+v", test.class, teachers, test.want)
+		}
+	}
+	if _, err := client.GetClassTeachers("1XHIT"); !errors.Is(err, ErrClassNotFound) {
+		t.Errorf("got %v for an unknown class, want ErrClassNotFound", err)
+	}
+}
+
+func TestListingRequiresASession(t *testing.T) {
+	client := newTestClient(t, newFakeUntis(t, nil), "unlisted")
+	if _, err := client.ListTeachers(); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("listing the teachers without a session returned %v, want %v", err, ErrNotAuthenticated)
+	}
+	if _, err := client.ListClasses(); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("listing the classes without a session returned %v, want %v", err, ErrNotAuthenticated)
+	}
+}
+
+func TestResolveRoomID(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	client := newAuthenticatedClient(t, fake, "rooms")
+	tests := []struct {
+		name string
+		want int
+		err  error
+	}{
+		{name: "H1102", want: 10},
+		{name: "l2201", want: 11},
+		{name: "Hörsaal 1102", want: 10},
+		{name: "LABOR 2201", want: 11},
+		{name: "Turnsaal", err: ErrRoomNotFound},
+	}
+	for _, test := range tests {
+		id, err := client.ResolveRoomID(test.name)
+		if test.err != nil {
+			if !errors.Is(err, test.err) || id != -1 {
+				t.Errorf("ResolveRoomID(%q) = %d, %v, want -1 and %v", test.name, id, err, test.err)
+			}
+			continue
+		}
+		if err != nil || id != test.want {
+			t.Errorf("ResolveRoomID(%q) = %d, %v, want %d", test.name, id, err, test.want)
+		}
+	}
+	if calls := fake.callsOf("getRooms"); calls != 1 {
+		t.Errorf("getRooms was called %d times, want the rooms to be cached", calls)
+	}
+	if _, err := newTestClient(t, fake, "unresolved").ResolveRoomID("H1102"); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("resolving without a session returned %v, want %v", err, ErrNotAuthenticated)
+	}
+}
+
+func TestListRoomsReturnsThemByName(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.handle("getRooms", fake.withSession([]Room{fakeRooms[1], fakeRooms[0]}))
+	client := newAuthenticatedClient(t, fake, "lister")
+	rooms, err := client.ListRooms()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rooms, fakeRooms) {
+		t.Errorf("listed the rooms %+v, want %+v", rooms, fakeRooms)
+	}
+	if _, err := newTestClient(t, fake, "unlisted").ListRooms(); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("listing the rooms without a session returned %v, want %v", err, ErrNotAuthenticated)
+	}
+}
+
+func TestSubstitutedLessonsCarryTheNamesOfTheOriginalTeachersAndRooms(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	fake.serveTimetable(
+		fakeLesson{ID: 1, Date: 20210301, Start: 800, End: 850, Code: "irregular", Teachers: []int{2, 3}, OrgTeachers: map[int]int{2: 1}, Rooms: []int{11}, OrgRooms: map[int]int{11: 10}, SubstText: "Vertretung"},
+		fakeLesson{ID: 2, Date: 20210301, Start: 850, End: 940, Teachers: []int{1}, Rooms: []int{10}},
+	)
+	client := newAuthenticatedClient(t, fake, "substituted-lessons")
+	lessons, err := client.GetTimetableOfTeacher(day, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lessons) != 2 {
+		t.Fatalf("got %d lessons, want 2", len(lessons))
+	}
+	substituted := lessons[0]
+	if !reflect.DeepEqual(substituted.Teachers, []string{"HUD", "MAY"}) || !reflect.DeepEqual(substituted.OriginalTeachers, []string{"BOR"}) {
+		t.Errorf("the substituted lesson is taught by %v for %v, want HUD and MAY for BOR", substituted.Teachers, substituted.OriginalTeachers)
+	}
+	if !reflect.DeepEqual(substituted.Rooms, []string{"L2201"}) || !reflect.DeepEqual(substituted.OriginalRooms, []string{"H1102"}) {
+		t.Errorf("the moved lesson takes place in %v instead of %v, want L2201 instead of H1102", substituted.Rooms, substituted.OriginalRooms)
+	}
+	regular := lessons[1]
+	if len(regular.OriginalTeachers) != 0 || len(regular.OriginalRooms) != 0 {
+		t.Errorf("the regular lesson has the original teachers %v and rooms %v, want none", regular.OriginalTeachers, regular.OriginalRooms)
+	}
+}
+
+func TestUnknownIDsAreResolvedToPlaceholders(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	fake.serveTimetable(fakeLesson{ID: 1, Date: 20210301, Start: 800, End: 850, Classes: []int{20, 29}, Teachers: []int{1, 42}, Subjects: []int{39}, Rooms: []int{10}})
+	client := newAuthenticatedClient(t, fake, "unknown-ids")
+	var warnings []string
+	var mutex sync.Mutex
+	client.Logf = func(format string, v ...interface{}) {
+		mutex.Lock()
+		warnings = append(warnings, fmt.Sprintf(format, v...))
+		mutex.Unlock()
+	}
+	lessons, err := client.GetTimetableOfTeacher(day, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lessons) != 1 {
+		t.Fatalf("got %d lessons, want 1", len(lessons))
+	}
+	lesson := lessons[0]
+	if !reflect.DeepEqual(lesson.Teachers, []string{"BOR", "#42"}) {
+		t.Errorf("the lesson is taught by %v, want BOR and the placeholder #42", lesson.Teachers)
+	}
+	if !reflect.DeepEqual(lesson.Classes, []string{"5AHIT", "#29"}) || !reflect.DeepEqual(lesson.Subjects, []string{"#39"}) {
+		t.Errorf("the lesson belongs to %v in %v, want 5AHIT and #29 in #39", lesson.Classes, lesson.Subjects)
+	}
+	if len(lesson.Teachers) != len(lesson.TeacherIDs) || len(lesson.Classes) != len(lesson.ClassIDs) ||
+		len(lesson.Subjects) != len(lesson.SubjectIDs) || len(lesson.Rooms) != len(lesson.RoomIDs) {
+		t.Errorf("the names and ids of the lesson have different lengths: %+v", lesson)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	logged := strings.Join(warnings, "\n")
+	for _, want := range []string{"teacher id 42", "class id 29", "subject id 39"} {
+		if !strings.Contains(logged, want) {
+			t.Errorf("the unknown %v wasn't logged in %q", want, logged)
+		}
+	}
+}
+
+func TestClientsAuthenticateWithTheirName(t *testing.T) {
+	tests := []struct {
+		name       string
+		clientName string
+		secret     string
+		want       string
+	}{
+		{"a custom name", "Refundable-Staging", "", "Refundable-Staging"},
+		{"no name", "", "", ClientName},
+		{"a custom name with a one-time password", "Refundable-Staging", "JBSWY3DPEHPK3PXP", "Refundable-Staging"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := newFakeUntis(t, nil)
+			var mutex sync.Mutex
+			var params map[string]interface{}
+			fake.handle("authenticate", func(call fakeCall) (interface{}, *UntisError) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				if err := json.Unmarshal(call.Params, &params); err != nil {
+					t.Errorf("couldn't decode the parameters %s: %v", call.Params, err)
+				}
+				return fake.startSession(), nil
+			})
+			client := newTestClient(t, fake, "named")
+			client.Name = test.clientName
+			var err error
+			if test.secret != "" {
+				client.Password = ""
+				client.Secret = test.secret
+				err = client.AuthenticateSecret()
+			} else {
+				err = client.Authenticate()
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			if params["client"] != test.want {
+				t.Errorf("authenticated as the client %v, want %v", params["client"], test.want)
+			}
+		})
+	}
+	client := CreateClient("default-name", "password")
+	defer client.DeleteClient()
+	if client.Name != ClientName {
+		t.Errorf("a new client is named %q, want %q", client.Name, ClientName)
+	}
+}
+
+func TestTimetablesAreSortedDeterministically(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	fake.serveTimetable(
+		fakeLesson{ID: 6, Date: 20210302, Start: 800, End: 850, Classes: []int{20}, Teachers: []int{1}, Subjects: []int{30}, Rooms: []int{10}},
+		fakeLesson{ID: 5, Date: 20210301, Start: 850, End: 940, Classes: []int{20}, Teachers: []int{1}, Subjects: []int{30}, Rooms: []int{11}},
+		fakeLesson{ID: 4, Date: 20210301, Start: 850, End: 940, Classes: []int{20}, Teachers: []int{1}, Subjects: []int{30}, Rooms: []int{10}},
+		fakeLesson{ID: 3, Date: 20210301, Start: 850, End: 940, Classes: []int{20}, Teachers: []int{1}, Subjects: []int{31}, Rooms: []int{11}},
+		fakeLesson{ID: 2, Date: 20210301, Start: 800, End: 850, Classes: []int{20}, Teachers: []int{1}, Subjects: []int{30}, Rooms: []int{10}},
+		fakeLesson{ID: 1, Date: 20210301, Start: 800, End: 850, Classes: []int{20}, Teachers: []int{1}, Subjects: []int{30}, Rooms: []int{10}},
+	)
+	client := newAuthenticatedClient(t, fake, "sorted")
+	timetables := map[string]func() ([]Lesson, error){
+		"teacher": func() ([]Lesson, error) { return client.GetTimetableOfTeacher(day, day.AddDate(0, 0, 1)) },
+		"specific teacher": func() ([]Lesson, error) {
+			return client.GetTimetableOfSpecificTeacher(day, day.AddDate(0, 0, 1), "BOR")
+		},
+		"class":   func() ([]Lesson, error) { return client.GetTimetableOfClass(day, day.AddDate(0, 0, 1), "5AHIT") },
+		"room":    func() ([]Lesson, error) { return client.GetTimetableOfRoom(day, day.AddDate(0, 0, 1), "H1102") },
+		"student": func() ([]Lesson, error) { return client.GetTimetableOfStudent(day, day.AddDate(0, 0, 1), 4711) },
+		"teachers": func() ([]Lesson, error) {
+			timetables, errs := client.GetTimetablesOfTeachers(day, day.AddDate(0, 0, 1), []string{"BOR"})
+			return timetables["BOR"], errs["BOR"]
+		},
+	}
+	// lessons starting at the same time are ordered by the name of their subject, then room, then id
+	want := []int{1, 2, 3, 4, 5, 6}
+	for name, fetch := range timetables {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 2; i++ {
+				lessons, err := fetch()
+				if err != nil {
+					t.Fatal(err)
+				}
+				ids := make([]int, 0, len(lessons))
+				for _, lesson := range lessons {
+					ids = append(ids, lesson.ID)
+				}
+				if !reflect.DeepEqual(ids, want) {
+					t.Errorf("request %d returned the lessons %v, want %v", i+1, ids, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSortLessons(t *testing.T) {
+	lessons := []Lesson{
+		{ID: 4, Start: at(8, 0), End: at(9, 40)},
+		{ID: 3, Start: at(8, 0), End: at(8, 50), Subjects: []string{"SEW"}},
+		{ID: 2, Start: at(8, 0), End: at(8, 50)},
+		{ID: 1, Start: at(7, 10), End: at(8, 0), Subjects: []string{"SEW"}},
+		{ID: 4, Start: at(8, 0), End: at(8, 50)},
+	}
+	sortLessons(lessons)
+	want := []struct {
+		id  int
+		end time.Time
+	}{{1, at(8, 0)}, {2, at(8, 50)}, {4, at(8, 50)}, {4, at(9, 40)}, {3, at(8, 50)}}
+	for i, lesson := range lessons {
+		if lesson.ID != want[i].id || !lesson.End.Equal(want[i].end) {
+			t.Errorf("lesson %d is %d ending at %v, want %d ending at %v", i, lesson.ID, lesson.End, want[i].id, want[i].end)
+		}
+	}
+}
+
+func TestLessonTextAndInfoAreCarriedThroughEveryTimetable(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	fake.serveTimetable(
+		fakeLesson{ID: 1, Date: 20210301, Start: 800, End: 850, Classes: []int{20}, Teachers: []int{1}, Subjects: []int{30}, Rooms: []int{10}, LsText: "Schularbeit", Info: "Mitbringen: Taschenrechner"},
+		fakeLesson{ID: 2, Date: 20210301, Start: 850, End: 940, Classes: []int{20}, Teachers: []int{1}, Subjects: []int{31}, Rooms: []int{10}},
+	)
+	client := newAuthenticatedClient(t, fake, "annotated")
+	timetables := map[string]func() ([]Lesson, error){
+		"teacher":          func() ([]Lesson, error) { return client.GetTimetableOfTeacher(day, day) },
+		"specific teacher": func() ([]Lesson, error) { return client.GetTimetableOfSpecificTeacher(day, day, "BOR") },
+		"class":            func() ([]Lesson, error) { return client.GetTimetableOfClass(day, day, "5AHIT") },
+		"room":             func() ([]Lesson, error) { return client.GetTimetableOfRoom(day, day, "H1102") },
+		"student":          func() ([]Lesson, error) { return client.GetTimetableOfStudent(day, day, 4711) },
+	}
+	for name, fetch := range timetables {
+		t.Run(name, func(t *testing.T) {
+			lessons, err := fetch()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(lessons) != 2 {
+				t.Fatalf("got %d lessons, want 2", len(lessons))
+			}
+			if lessons[0].LessonText != "Schularbeit" || lessons[0].Info != "Mitbringen: Taschenrechner" {
+				t.Errorf("the annotated lesson has the text %q and info %q", lessons[0].LessonText, lessons[0].Info)
+			}
+			if lessons[1].LessonText != "" || lessons[1].Info != "" {
+				t.Errorf("the lesson without annotations has the text %q and info %q", lessons[1].LessonText, lessons[1].Info)
+			}
+		})
+	}
+}
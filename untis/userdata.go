@@ -0,0 +1,178 @@
+package untis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// UserData is the profile of the account a client uses as provided by untis
+type UserData struct {
+	// ElementType is the type of the element of the account (e.g. TEACHER)
+	ElementType string `json:"element_type" example:"TEACHER"`
+	// ElementID is the id of the element of the account, e.g. the id of the teacher
+	ElementID int `json:"element_id" example:"42"`
+	// DisplayName is the name untis displays for the account
+	DisplayName string `json:"display_name" example:"Max Mustermann"`
+	// SchoolName is the name of the school of the account
+	SchoolName string `json:"school_name" example:"TGM"`
+	// DepartmentID is the id of the department of the account, 0 if it belongs to none
+	DepartmentID int `json:"department_id" example:"0"`
+	// ClassIDs are the ids of the classes the account belongs to
+	ClassIDs []int `json:"class_ids"`
+	// Classes are the names of the classes the account belongs to
+	Classes []string `json:"classes"`
+	// Rights are the permissions of the account in untis (e.g. R_MY_OWN_TIMETABLE)
+	Rights []string `json:"rights"`
+}
+
+// userDataResult is the result of a getUserData2017 request
+type userDataResult struct {
+	UserData struct {
+		ElemType     string   `json:"elemType"`
+		ElemID       int      `json:"elemId"`
+		DisplayName  string   `json:"displayName"`
+		SchoolName   string   `json:"schoolName"`
+		DepartmentID int      `json:"departmentId"`
+		KlassenIDs   []int    `json:"klassenIds"`
+		Rights       []string `json:"rights"`
+	} `json:"userData"`
+}
+
+// GetUserData returns the profile of the account the client uses and stores it as the Profile of the client
+// it is requested from the internal api of untis, which requires the app secret of the account; if the client has
+// no Secret, it is requested with its password first
+func (client *Client) GetUserData() (UserData, error) {
+	return client.GetUserDataContext(context.Background())
+}
+
+// GetUserDataContext is like GetUserData but uses ctx for the requests sent to the untis api
+func (client *Client) GetUserDataContext(ctx context.Context) (UserData, error) {
+	if !client.IsAuthenticated() {
+		return UserData{}, ErrNotAuthenticated
+	}
+	respBody, id, err := client.sendInternRequest(ctx, "getUserData2017", map[string]interface{}{
+		"masterDataTimestamp": client.now().UnixNano() / int64(time.Millisecond),
+	})
+	if err != nil {
+		return UserData{}, err
+	}
+	data, err := parseUserDataResponse(respBody, id)
+	if err != nil {
+		return UserData{}, err
+	}
+	if len(data.ClassIDs) > 0 {
+		data.Classes, err = client.ResolveClassesContext(ctx, data.ClassIDs)
+		if err != nil {
+			return UserData{}, err
+		}
+	}
+	client.setProfile(&data)
+	return data, nil
+}
+
+// parseUserDataResponse decodes the body of a getUserData2017 response into the profile of the account
+// only the ids of the classes are set, their names have to be resolved afterwards
+func parseUserDataResponse(respBody []byte, expectedID int) (UserData, error) {
+	r := struct {
+		JSONRPC string         `json:"jsonrpc"`
+		ID      string         `json:"id"`
+		Result  userDataResult `json:"result"`
+	}{}
+	err := json.Unmarshal(respBody, &r)
+	if err != nil {
+		return UserData{}, err
+	}
+	rid, _ := strconv.Atoi(r.ID)
+	if rid != expectedID {
+		return UserData{}, &IDMismatchError{Method: "getUserData2017", Expected: expectedID, Got: rid}
+	}
+	user := r.Result.UserData
+	data := UserData{
+		ElementType:  user.ElemType,
+		ElementID:    user.ElemID,
+		DisplayName:  user.DisplayName,
+		SchoolName:   user.SchoolName,
+		DepartmentID: user.DepartmentID,
+		ClassIDs:     user.KlassenIDs,
+		Classes:      make([]string, 0),
+		Rights:       user.Rights,
+	}
+	if data.ClassIDs == nil {
+		data.ClassIDs = make([]int, 0)
+	}
+	if data.Rights == nil {
+		data.Rights = make([]string, 0)
+	}
+	return data, nil
+}
+
+// sendInternRequest sends a request to the internal api of untis authenticated with a one-time password of the
+// app secret of the client, which is requested first if the client has none
+func (client *Client) sendInternRequest(ctx context.Context, method string, params map[string]interface{}) ([]byte, int, error) {
+	if client.secret() == "" {
+		if err := client.fetchAppSecret(ctx); err != nil {
+			return nil, 0, err
+		}
+	}
+	now := client.now()
+	otp, err := GenerateOTP(client.secret(), now)
+	if err != nil {
+		return nil, 0, err
+	}
+	params["auth"] = map[string]interface{}{
+		"user":       client.Username,
+		"otp":        otp,
+		"clientTime": now.UnixNano() / int64(time.Millisecond),
+	}
+	return client.sendInternRequestOnce(ctx, method, []interface{}{params})
+}
+
+// fetchAppSecret requests the app secret of the account with its password using getAppSharedSecret and stores it
+// as the Secret of the client
+func (client *Client) fetchAppSecret(ctx context.Context) error {
+	if client.Password == "" {
+		return fmt.Errorf("no secret or password set")
+	}
+	respBody, id, err := client.sendInternRequestOnce(ctx, "getAppSharedSecret", []interface{}{map[string]interface{}{
+		"userName": client.Username,
+		"password": client.Password,
+	}})
+	if err != nil {
+		return err
+	}
+	r := struct {
+		JSONRPC string `json:"jsonrpc"`
+		ID      string `json:"id"`
+		Result  string `json:"result"`
+	}{}
+	err = json.Unmarshal(respBody, &r)
+	if err != nil {
+		return err
+	}
+	rid, _ := strconv.Atoi(r.ID)
+	if rid != id {
+		return &IDMismatchError{Method: "getAppSharedSecret", Expected: id, Got: rid}
+	}
+	if r.Result == "" {
+		return fmt.Errorf("no secret returned")
+	}
+	client.setSecret(r.Result)
+	return nil
+}
+
+// sendInternRequestOnce sends a single request to the internal api of untis, observing and logging it like sendRequest
+func (client *Client) sendInternRequestOnce(ctx context.Context, method string, params interface{}) ([]byte, int, error) {
+	client.touch()
+	start := client.now()
+	respBody, id, err := client.doRequestTo(ctx, client.internEndpoint(), client.session(), method, params)
+	if ObserveRequest != nil {
+		ObserveRequest(method, client.now().Sub(start), err)
+	}
+	if err != nil {
+		client.logf("level=error request_id=%v untis_method=%v msg=%q", RequestIDFromContext(ctx), method, err.Error())
+	}
+	return respBody, id, err
+}
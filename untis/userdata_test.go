@@ -0,0 +1,101 @@
+package untis
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// userData is a getUserData2017 result of a teacher in two classes
+const userData = `{
+	"masterData": {"timeStamp": 1614585600000},
+	"userData": {
+		"elemType": "TEACHER",
+		"elemId": 1,
+		"displayName": "Michael Borko",
+		"schoolName": "TGM",
+		"departmentId": 3,
+		"klassenIds": [21, 20],
+		"rights": ["R_MY_OWN_TIMETABLE", "R_TIMETABLE_CLASSES"]
+	}
+}`
+
+func TestParseUserDataResponse(t *testing.T) {
+	var result interface{}
+	if err := json.Unmarshal([]byte(userData), &result); err != nil {
+		t.Fatal(err)
+	}
+	data, err := parseUserDataResponse(rpcResponse(t, 3, result), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := UserData{
+		ElementType:  "TEACHER",
+		ElementID:    1,
+		DisplayName:  "Michael Borko",
+		SchoolName:   "TGM",
+		DepartmentID: 3,
+		ClassIDs:     []int{21, 20},
+		Classes:      []string{},
+		Rights:       []string{"R_MY_OWN_TIMETABLE", "R_TIMETABLE_CLASSES"},
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("got %+v, want %+v", data, want)
+	}
+
+	empty, err := parseUserDataResponse(rpcResponse(t, 4, map[string]interface{}{"userData": map[string]interface{}{"elemType": "STUDENT"}}), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if empty.ClassIDs == nil || empty.Classes == nil || empty.Rights == nil {
+		t.Errorf("got %+v for an account without classes and rights, want empty lists", empty)
+	}
+	if _, err := parseUserDataResponse(rpcResponse(t, 5, result), 3); !errors.Is(err, ErrIDMismatch) {
+		t.Errorf("got %v for a response to another request, want ErrIDMismatch", err)
+	}
+}
+
+func TestGetUserDataResolvesTheClassesAndStoresTheProfile(t *testing.T) {
+	fake := newFakeUntis(t, nil)
+	fake.serveMasterData()
+	fake.handle("getAppSharedSecret", func(call fakeCall) (interface{}, *UntisError) {
+		return "JBSWY3DPEHPK3PXP", nil
+	})
+	var params []struct {
+		Auth struct {
+			User string `json:"user"`
+			OTP  string `json:"otp"`
+		} `json:"auth"`
+	}
+	var result interface{}
+	if err := json.Unmarshal([]byte(userData), &result); err != nil {
+		t.Fatal(err)
+	}
+	fake.handle("getUserData2017", func(call fakeCall) (interface{}, *UntisError) {
+		_ = json.Unmarshal(call.Params, &params)
+		return result, nil
+	})
+	client := newAuthenticatedClient(t, fake, "profiled")
+
+	data, err := client.GetUserData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.callsOf("getAppSharedSecret") != 1 || client.secret() != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("the secret %q was requested %d times, want it requested once with the password", client.secret(), fake.callsOf("getAppSharedSecret"))
+	}
+	if len(params) != 1 || params[0].Auth.User != "profiled" || params[0].Auth.OTP == "" {
+		t.Errorf("the user data was requested with %+v, want a one-time password of the account", params)
+	}
+	if data.DisplayName != "Michael Borko" || !reflect.DeepEqual(data.Classes, []string{"4BHIT", "5AHIT"}) {
+		t.Errorf("got %+v, want the profile with the names of the classes", data)
+	}
+	if client.Profile == nil || !reflect.DeepEqual(*client.Profile, data) {
+		t.Errorf("the client stored the profile %+v, want %+v", client.Profile, data)
+	}
+
+	if _, err := newTestClient(t, fake, "anonymous").GetUserData(); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("got %v without a session, want ErrNotAuthenticated", err)
+	}
+}